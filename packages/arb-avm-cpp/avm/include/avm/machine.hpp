@@ -56,6 +56,9 @@ class Machine {
     std::vector<unsigned char> marshalForProof() {
         return machine_state.marshalForProof();
     }
+    std::vector<unsigned char> marshalState() const {
+        return machine_state.marshalState();
+    }
 
     TuplePool& getPool() { return *machine_state.pool; }
 
@@ -72,6 +72,7 @@ struct MachineState {
     bool initialize_machinestate(const std::string& contract_filename);
 
     std::vector<unsigned char> marshalForProof();
+    std::vector<unsigned char> marshalState() const;
     BlockReason runOp(OpCode opcode);
     uint256_t hash() const;
     BlockReason isBlocked(uint256_t currentTime, bool newMessages) const;
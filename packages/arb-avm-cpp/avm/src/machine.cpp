@@ -19,6 +19,7 @@
 #include <iostream>
 
 #include <avm/machine.hpp>
+#include <avm_values/gascost.hpp>
 #include <avm_values/opcodes.hpp>
 #include <avm_values/util.hpp>
 #include <bigint_utils.hpp>
@@ -150,6 +150,33 @@ std::vector<unsigned char> MachineState::marshalForProof() {
     return buf;
 }
 
+namespace {
+void marshalStack(const Datastack& stack, std::vector<unsigned char>& buf) {
+    uint64_t count = boost::endian::native_to_big(
+        static_cast<uint64_t>(stack.values.size()));
+    auto countData = reinterpret_cast<const unsigned char*>(&count);
+    buf.insert(buf.end(), countData, countData + sizeof(count));
+    for (auto it = stack.values.rbegin(); it != stack.values.rend(); ++it) {
+        marshal_value(*it, buf);
+    }
+}
+}  // namespace
+
+// Writes the full machine state so that it can be compared with another
+// machine: the current code point, the error handler, the register, the
+// static value and then the data and aux stacks, each as a big endian item
+// count followed by the items from the top of the stack down
+std::vector<unsigned char> MachineState::marshalState() const {
+    std::vector<unsigned char> buf;
+    marshal_value(pc < code.size() ? code[pc] : CodePoint(), buf);
+    marshal_value(errpc, buf);
+    marshal_value(registerVal, buf);
+    marshal_value(staticVal, buf);
+    marshalStack(stack, buf);
+    marshalStack(auxstack, buf);
+    return buf;
+}
+
 SaveResults MachineState::checkpointState(CheckpointStorage& storage) {
     auto stateSaver = MachineStateSaver(storage.makeTransaction());
 
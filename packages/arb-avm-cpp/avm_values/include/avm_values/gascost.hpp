@@ -0,0 +1,85 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Code generated by arb-avm-go/cmd/gen-gas-schedule from
+// arb-avm-go/code/gas.go. DO NOT EDIT.
+
+#ifndef gascost_hpp
+#define gascost_hpp
+
+#include <avm_values/opcodes.hpp>
+
+#include <cstdint>
+#include <unordered_map>
+
+const std::unordered_map<OpCode, uint64_t> InstructionArbGasCost = {
+    {OpCode::ADD, 3},
+    {OpCode::MUL, 3},
+    {OpCode::SUB, 3},
+    {OpCode::DIV, 4},
+    {OpCode::SDIV, 7},
+    {OpCode::MOD, 4},
+    {OpCode::SMOD, 7},
+    {OpCode::ADDMOD, 4},
+    {OpCode::MULMOD, 4},
+    {OpCode::EXP, 25},
+    {OpCode::LT, 2},
+    {OpCode::GT, 2},
+    {OpCode::SLT, 2},
+    {OpCode::SGT, 2},
+    {OpCode::EQ, 2},
+    {OpCode::ISZERO, 1},
+    {OpCode::BITWISE_AND, 2},
+    {OpCode::BITWISE_OR, 2},
+    {OpCode::BITWISE_XOR, 2},
+    {OpCode::BITWISE_NOT, 1},
+    {OpCode::BYTE, 4},
+    {OpCode::SIGNEXTEND, 7},
+    {OpCode::HASH, 7},
+    {OpCode::TYPE, 3},
+    {OpCode::ETHHASH2, 8},
+    {OpCode::POP, 1},
+    {OpCode::SPUSH, 1},
+    {OpCode::RPUSH, 1},
+    {OpCode::RSET, 2},
+    {OpCode::JUMP, 4},
+    {OpCode::CJUMP, 4},
+    {OpCode::STACKEMPTY, 2},
+    {OpCode::PCPUSH, 1},
+    {OpCode::AUXPUSH, 1},
+    {OpCode::AUXPOP, 1},
+    {OpCode::AUXSTACKEMPTY, 2},
+    {OpCode::NOP, 1},
+    {OpCode::ERRPUSH, 1},
+    {OpCode::ERRSET, 1},
+    {OpCode::DUP0, 1},
+    {OpCode::DUP1, 1},
+    {OpCode::DUP2, 1},
+    {OpCode::SWAP1, 1},
+    {OpCode::SWAP2, 1},
+    {OpCode::TGET, 2},
+    {OpCode::TSET, 40},
+    {OpCode::TLEN, 2},
+    {OpCode::BREAKPOINT, 100},
+    {OpCode::LOG, 100},
+    {OpCode::SEND, 100},
+    {OpCode::GETTIME, 40},
+    {OpCode::INBOX, 40},
+    {OpCode::ERROR, 5},
+    {OpCode::HALT, 10},
+    {OpCode::DEBUG, 1}};
+
+#endif /* gascost_hpp */
@@ -296,68 +296,4 @@ const std::unordered_map<OpCode, std::vector<bool>> InstructionAuxStackPops = {
     {OpCode::HALT, {}},
     {OpCode::DEBUG, {}}};
 
-const std::unordered_map<OpCode, uint64_t> InstructionArbGasCost = {
-    {OpCode::ADD, 3},
-    {OpCode::MUL, 3},
-    {OpCode::SUB, 3},
-    {OpCode::DIV, 4},
-    {OpCode::SDIV, 7},
-    {OpCode::MOD, 4},
-    {OpCode::SMOD, 7},
-    {OpCode::ADDMOD, 4},
-    {OpCode::MULMOD, 4},
-    {OpCode::EXP, 25},
-
-    {OpCode::LT, 2},
-    {OpCode::GT, 2},
-    {OpCode::SLT, 2},
-    {OpCode::SGT, 2},
-    {OpCode::EQ, 2},
-    {OpCode::ISZERO, 1},
-    {OpCode::BITWISE_AND, 2},
-    {OpCode::BITWISE_OR, 2},
-    {OpCode::BITWISE_XOR, 2},
-    {OpCode::BITWISE_NOT, 1},
-    {OpCode::BYTE, 4},
-    {OpCode::SIGNEXTEND, 7},
-
-    {OpCode::HASH, 7},
-    {OpCode::TYPE, 3},
-    {OpCode::ETHHASH2, 8},
-
-    {OpCode::POP, 1},
-    {OpCode::SPUSH, 1},
-    {OpCode::RPUSH, 1},
-    {OpCode::RSET, 2},
-    {OpCode::JUMP, 4},
-    {OpCode::CJUMP, 4},
-    {OpCode::STACKEMPTY, 2},
-    {OpCode::PCPUSH, 1},
-    {OpCode::AUXPUSH, 1},
-    {OpCode::AUXPOP, 1},
-    {OpCode::AUXSTACKEMPTY, 2},
-    {OpCode::NOP, 1},
-    {OpCode::ERRPUSH, 1},
-    {OpCode::ERRSET, 1},
-
-    {OpCode::DUP0, 1},
-    {OpCode::DUP1, 1},
-    {OpCode::DUP2, 1},
-    {OpCode::SWAP1, 1},
-    {OpCode::SWAP2, 1},
-
-    {OpCode::TGET, 2},
-    {OpCode::TSET, 40},
-    {OpCode::TLEN, 2},
-
-    {OpCode::BREAKPOINT, 100},
-    {OpCode::LOG, 100},
-
-    {OpCode::SEND, 100},
-    {OpCode::GETTIME, 40},
-    {OpCode::INBOX, 40},
-    {OpCode::ERROR, 5},
-    {OpCode::HALT, 10},
-    {OpCode::DEBUG, 1}};
-
 #endif /* opcodes_hpp */
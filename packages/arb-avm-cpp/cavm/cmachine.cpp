@@ -144,7 +144,17 @@ ByteSlice machineMarshallForProof(CMachine* m) {
     return {voidData, static_cast<int>(proof.size())};
 }
 
-RawAssertion machineExecuteAssertion(CMachine* m,
+ByteSlice machineMarshalState(CMachine* m) {
+    assert(m);
+    Machine* mach = static_cast<Machine*>(m);
+    auto state = mach->marshalState();
+    auto stateData = (unsigned char*)malloc(state.size());
+    std::copy(state.begin(), state.end(), stateData);
+    auto voidData = reinterpret_cast<void*>(stateData);
+    return {voidData, static_cast<int>(state.size())};
+}
+
+CMachine* m,
                                      uint64_t maxSteps,
                                      void* timeboundStartData,
                                      void* timeboundEndData,
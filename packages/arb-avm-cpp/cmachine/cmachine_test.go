@@ -53,3 +53,17 @@ func TestMachineCreation(t *testing.T) {
 		log.Fatal(err)
 	}
 }
+
+func TestMachineSnapshot(t *testing.T) {
+	mach, err := New("contract.ao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := mach.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Hash() != mach.Hash() {
+		t.Error("snapshot hash doesn't match machine hash")
+	}
+}
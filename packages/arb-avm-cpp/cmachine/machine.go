@@ -28,6 +28,7 @@ import "C"
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"log"
 	"runtime"
@@ -194,6 +195,57 @@ func (m *Machine) MarshalForProof() ([]byte, error) {
 	return C.GoBytes(unsafe.Pointer(rawProof.data), rawProof.length), nil
 }
 
+// Snapshot returns the machine's full state so that it can be compared with
+// another machine's
+func (m *Machine) Snapshot() (*machine.Snapshot, error) {
+	rawState := C.machineMarshalState(m.c)
+	data := C.GoBytes(unsafe.Pointer(rawState.data), rawState.length)
+	C.free(unsafe.Pointer(rawState.data))
+
+	rd := bytes.NewReader(data)
+	vals := make([]value.Value, 0, 4)
+	for i := 0; i < 4; i++ {
+		val, err := value.UnmarshalValue(rd)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	stack, err := readStackSnapshot(rd)
+	if err != nil {
+		return nil, err
+	}
+	auxStack, err := readStackSnapshot(rd)
+	if err != nil {
+		return nil, err
+	}
+	return &machine.Snapshot{
+		Status:     m.CurrentStatus(),
+		PC:         vals[0],
+		ErrHandler: vals[1],
+		Register:   vals[2],
+		Static:     vals[3],
+		Stack:      stack,
+		AuxStack:   auxStack,
+	}, nil
+}
+
+func readStackSnapshot(rd *bytes.Reader) (machine.StackSnapshot, error) {
+	var count uint64
+	if err := binary.Read(rd, binary.BigEndian, &count); err != nil {
+		return machine.StackSnapshot{}, err
+	}
+	items := make([]value.Value, 0, count)
+	for i := uint64(0); i < count; i++ {
+		val, err := value.UnmarshalValue(rd)
+		if err != nil {
+			return machine.StackSnapshot{}, err
+		}
+		items = append(items, val)
+	}
+	return machine.NewStackSnapshot(items), nil
+}
+
 func (m *Machine) Checkpoint(storage machine.CheckpointStorage) bool {
 	cCheckpointStorage := storage.(*CheckpointStorage)
 	success := C.checkpointMachine(m.c, cCheckpointStorage.c)
@@ -137,6 +137,15 @@ TEST_CASE("SDIV opcode is correct") {
     SECTION("Negative divided by negative") {
         testBinaryOp(-12, -3, 4, OpCode::SDIV);
     }
+    SECTION("Inexact quotients truncate toward zero") {
+        testBinaryOp(-7, 2, -3, OpCode::SDIV);
+        testBinaryOp(7, -2, -3, OpCode::SDIV);
+        testBinaryOp(-7, -2, 3, OpCode::SDIV);
+    }
+    SECTION("Min divided by -1") {
+        uint256_t min = uint256_t(1) << 255;
+        testBinaryOp(min, -1, min, OpCode::SDIV);
+    }
     SECTION("Divide by zero") {
         MachineState m = runBinaryOp(3, 0, OpCode::SDIV);
         REQUIRE(m.state == Status::Error);
@@ -743,7 +752,22 @@ TEST_CASE("INBOX opcode is correct") {
 
 TEST_CASE("ERROR opcode is correct") {
     SECTION("error") {
-        // TODO: fill in error test
+        MachineState m;
+        m.runOp(OpCode::ERROR);
+        REQUIRE(m.state == Status::Error);
+    }
+    SECTION("jumps to handler at first instruction") {
+        std::vector<CodePoint> code{CodePoint(0, OpCode::NOP, 0),
+                                    CodePoint(1, OpCode::ERROR, 0),
+                                    CodePoint(2, OpCode::HALT, 0)};
+        MachineState state(code, uint256_t(0),
+                           std::make_shared<TuplePool>());
+        state.pc = 1;
+        state.errpc = code[0];
+        Machine machine;
+        machine.initializeMachine(state);
+        machine.run(1, 0, 0, Tuple(), std::chrono::seconds(0));
+        REQUIRE(machine.currentStatus() == Status::Extensive);
     }
 }
 
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aoasm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func TestRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("../../arb-validator/proofmachine/*.ao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, "../../arb-validator/contract.ao")
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rd := bytes.NewReader(data)
+		prog, err := ReadProgram(rd)
+		if err != nil {
+			t.Fatal(file, err)
+		}
+		if rd.Len() != 0 {
+			t.Fatal(file, "has trailing data")
+		}
+
+		var text bytes.Buffer
+		if err := Disassemble(prog, &text); err != nil {
+			t.Fatal(file, err)
+		}
+		assembled, err := Assemble(&text)
+		if err != nil {
+			t.Fatal(file, err)
+		}
+		var out bytes.Buffer
+		if err := assembled.Write(&out); err != nil {
+			t.Fatal(file, err)
+		}
+		if !bytes.Equal(data, out.Bytes()) {
+			t.Error(file, "didn't round trip")
+		}
+	}
+}
+
+func TestAssembleLabels(t *testing.T) {
+	src := `
+.code
+    nop @end        ; push the code point of the last instruction
+    jump
+    error
+end:
+    halt
+.static Tuple(5, @end)
+`
+	prog, err := Assemble(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prog.Code) != 4 {
+		t.Fatal("wrong instruction count", len(prog.Code))
+	}
+	end := prog.CodePoints()[3]
+	if prog.Code[3].GetOp() != code.HALT {
+		t.Error("wrong final instruction")
+	}
+	imm, ok := prog.Code[0].(value.ImmediateOperation)
+	if !ok || !value.Eq(imm.Val, end) || imm.Val.Hash() != end.Hash() {
+		t.Error("label didn't resolve to the code point of its instruction")
+	}
+	expectedStatic := value.NewTuple2(value.NewInt64Value(5), end)
+	if prog.Static.Hash() != expectedStatic.Hash() {
+		t.Error("wrong static value", prog.Static)
+	}
+
+	if _, err := Assemble(strings.NewReader("start:\n nop @start\n")); err == nil {
+		t.Error("assembled code point refering to its own instruction")
+	}
+	if _, err := Assemble(strings.NewReader("nop @missing\n")); err == nil {
+		t.Error("assembled undefined label")
+	}
+}
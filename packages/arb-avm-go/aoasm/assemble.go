@@ -0,0 +1,512 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aoasm
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// The assembler parses the whole file before building any values since a
+// code point can only be constructed once every instruction after the one it
+// refers to is known
+
+type valueNode interface{}
+
+type intNode struct {
+	val *big.Int
+}
+
+type tupleNode struct {
+	items []valueNode
+}
+
+type labelNode struct {
+	name string
+}
+
+type codePointNode struct {
+	insnNum  int64
+	op       opNode
+	nextHash common.Hash
+}
+
+type hashOnlyNode struct {
+	hash common.Hash
+	size int64
+}
+
+type opNode struct {
+	op  value.Opcode
+	imm valueNode
+}
+
+type sourceLine struct {
+	line int
+	op   opNode
+}
+
+type assembler struct {
+	version    uint32
+	extensions []Extension
+	insns      []sourceLine
+	static     valueNode
+	labels     map[string]int64
+
+	codePoints []value.CodePointValue
+}
+
+var opcodesByName map[string]value.Opcode
+
+func init() {
+	opcodesByName = make(map[string]value.Opcode)
+	for op, name := range code.InstructionNames {
+		opcodesByName[name] = op
+	}
+}
+
+// Assemble parses a program in the text format written by Disassemble.
+// Labels may be given any name, but a code point in an instruction's
+// immediate value may only refer to a later instruction.
+func Assemble(rd io.Reader) (*Program, error) {
+	a := &assembler{
+		version: CurrentAOVersion,
+		labels:  make(map[string]int64),
+	}
+	scanner := bufio.NewScanner(rd)
+	scanner.Buffer(nil, 1<<30)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if err := a.parseLine(scanner.Text(), lineNum); err != nil {
+			return nil, fmt.Errorf("line %v: %v", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a.build()
+}
+
+func (a *assembler) parseLine(line string, lineNum int) error {
+	if idx := strings.IndexByte(line, ';'); idx >= 0 {
+		line = line[:idx]
+	}
+	p := &lineParser{s: strings.TrimSpace(line)}
+	if p.atEnd() {
+		return nil
+	}
+	if a.static != nil {
+		return errors.New("nothing may follow the static value")
+	}
+
+	if p.s[0] == '.' {
+		directive := p.ident()
+		switch directive {
+		case ".version":
+			version, err := strconv.ParseUint(p.ident(), 10, 32)
+			if err != nil {
+				return err
+			}
+			a.version = uint32(version)
+		case ".extension":
+			id, err := strconv.ParseUint(p.ident(), 10, 32)
+			if err != nil {
+				return err
+			}
+			if id == 0 {
+				return errors.New("extension id 0 is reserved")
+			}
+			data, err := parseHex(p.ident())
+			if err != nil {
+				return err
+			}
+			a.extensions = append(a.extensions, Extension{ID: uint32(id), Data: data})
+		case ".code":
+		case ".static":
+			static, err := p.value()
+			if err != nil {
+				return err
+			}
+			a.static = static
+		default:
+			return fmt.Errorf("unknown directive %v", directive)
+		}
+		return p.expectEnd()
+	}
+
+	if strings.HasSuffix(p.s, ":") {
+		name := strings.TrimSpace(p.s[:len(p.s)-1])
+		if !isIdent(name) {
+			return fmt.Errorf("invalid label %v", name)
+		}
+		if _, ok := a.labels[name]; ok {
+			return fmt.Errorf("label %v defined twice", name)
+		}
+		a.labels[name] = int64(len(a.insns))
+		return nil
+	}
+
+	op, err := p.operation()
+	if err != nil {
+		return err
+	}
+	if err := p.expectEnd(); err != nil {
+		return err
+	}
+	a.insns = append(a.insns, sourceLine{line: lineNum, op: op})
+	return nil
+}
+
+func (a *assembler) build() (*Program, error) {
+	for name, insnNum := range a.labels {
+		if insnNum >= int64(len(a.insns)) {
+			return nil, fmt.Errorf("label %v isn't followed by an instruction", name)
+		}
+	}
+
+	insns := make([]value.Operation, len(a.insns))
+	a.codePoints = make([]value.CodePointValue, len(a.insns))
+	var prevHash common.Hash
+	for i := len(a.insns) - 1; i >= 0; i-- {
+		insn, err := a.buildOperation(a.insns[i].op, int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", a.insns[i].line, err)
+		}
+		insns[i] = insn
+		a.codePoints[i] = value.CodePointValue{
+			InsnNum:  int64(i),
+			Op:       insn,
+			NextHash: prevHash,
+		}
+		prevHash = a.codePoints[i].Hash()
+	}
+
+	var static value.Value = value.NewEmptyTuple()
+	if a.static != nil {
+		var err error
+		static, err = a.buildValue(a.static, -1)
+		if err != nil {
+			return nil, fmt.Errorf("static: %v", err)
+		}
+	}
+	return &Program{
+		Version:    a.version,
+		Extensions: a.extensions,
+		Code:       insns,
+		Static:     static,
+	}, nil
+}
+
+func (a *assembler) buildOperation(op opNode, insnNum int64) (value.Operation, error) {
+	if op.imm == nil {
+		return value.BasicOperation{Op: op.op}, nil
+	}
+	val, err := a.buildValue(op.imm, insnNum)
+	if err != nil {
+		return nil, err
+	}
+	return value.ImmediateOperation{Op: op.op, Val: val}, nil
+}
+
+// buildValue constructs the value described by node. Since the code point of
+// an instruction depends on every instruction after it, labels used by
+// instruction insnNum must refer to later instructions.
+func (a *assembler) buildValue(node valueNode, insnNum int64) (value.Value, error) {
+	switch node := node.(type) {
+	case intNode:
+		return value.NewIntValue(node.val), nil
+	case tupleNode:
+		items := make([]value.Value, 0, len(node.items))
+		for _, item := range node.items {
+			val, err := a.buildValue(item, insnNum)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, val)
+		}
+		return value.NewTupleFromSlice(items)
+	case labelNode:
+		target, ok := a.labels[node.name]
+		if !ok {
+			return nil, fmt.Errorf("undefined label %v", node.name)
+		}
+		if target <= insnNum {
+			return nil, fmt.Errorf("label %v must refer to an instruction after the one using it", node.name)
+		}
+		return a.codePoints[target], nil
+	case codePointNode:
+		op, err := a.buildOperation(node.op, insnNum)
+		if err != nil {
+			return nil, err
+		}
+		return value.CodePointValue{InsnNum: node.insnNum, Op: op, NextHash: node.nextHash}, nil
+	case hashOnlyNode:
+		return value.NewHashOnlyValue(node.hash, node.size), nil
+	default:
+		return nil, fmt.Errorf("unexpected value node %T", node)
+	}
+}
+
+type lineParser struct {
+	s   string
+	pos int
+}
+
+func (p *lineParser) skipSpace() {
+	for p.pos < len(p.s) && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *lineParser) atEnd() bool {
+	p.skipSpace()
+	return p.pos >= len(p.s)
+}
+
+func (p *lineParser) expectEnd() error {
+	if !p.atEnd() {
+		return fmt.Errorf("unexpected %v", p.s[p.pos:])
+	}
+	return nil
+}
+
+func (p *lineParser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *lineParser) expect(c byte) error {
+	if p.peek() != c {
+		if p.pos >= len(p.s) {
+			return fmt.Errorf("expected '%c' but reached end of line", c)
+		}
+		return fmt.Errorf("expected '%c' at %v", c, p.s[p.pos:])
+	}
+	p.pos++
+	return nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isIdentChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *lineParser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.s) && isIdentChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *lineParser) operation() (opNode, error) {
+	name := p.ident()
+	if name == "" {
+		return opNode{}, fmt.Errorf("expected instruction at %v", p.s[p.pos:])
+	}
+	op, ok := opcodesByName[name]
+	if !ok {
+		raw, err := strconv.ParseUint(name, 0, 8)
+		if err != nil {
+			return opNode{}, fmt.Errorf("unknown instruction %v", name)
+		}
+		op = value.Opcode(raw)
+	}
+	if c := p.peek(); c == 0 || c == ',' || c == ')' {
+		return opNode{op: op}, nil
+	}
+	imm, err := p.value()
+	if err != nil {
+		return opNode{}, err
+	}
+	return opNode{op: op, imm: imm}, nil
+}
+
+func (p *lineParser) value() (valueNode, error) {
+	p.skipSpace()
+	if p.peek() == '@' {
+		p.pos++
+		name := p.ident()
+		if name == "" {
+			return nil, errors.New("expected label name after '@'")
+		}
+		return labelNode{name: name}, nil
+	}
+
+	word := p.ident()
+	switch word {
+	case "Tuple":
+		return p.tuple()
+	case "CodePoint":
+		return p.codePoint()
+	case "HashOnlyValue":
+		return p.hashOnly()
+	case "":
+		if p.pos >= len(p.s) {
+			return nil, errors.New("expected value but reached end of line")
+		}
+		return nil, fmt.Errorf("expected value at %v", p.s[p.pos:])
+	default:
+		val, err := parseInt(word)
+		if err != nil {
+			return nil, err
+		}
+		return intNode{val: val}, nil
+	}
+}
+
+func (p *lineParser) tuple() (valueNode, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	items := make([]valueNode, 0)
+	if p.peek() == ')' {
+		p.pos++
+		return tupleNode{items: items}, nil
+	}
+	for {
+		item, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	if len(items) > value.MaxTupleSize {
+		return nil, fmt.Errorf("tuple has %v items but the max is %v", len(items), value.MaxTupleSize)
+	}
+	return tupleNode{items: items}, nil
+}
+
+func (p *lineParser) codePoint() (valueNode, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	insnNum, err := strconv.ParseInt(p.ident(), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	op, err := p.operation()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	nextHash, err := parseHash(p.ident())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return codePointNode{insnNum: insnNum, op: op, nextHash: nextHash}, nil
+}
+
+func (p *lineParser) hashOnly() (valueNode, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	hash, err := parseHash(p.ident())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+	size, err := strconv.ParseInt(p.ident(), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return hashOnlyNode{hash: hash, size: size}, nil
+}
+
+func parseInt(s string) (*big.Int, error) {
+	val := new(big.Int)
+	var ok bool
+	if strings.HasPrefix(s, "0x") {
+		_, ok = val.SetString(s[2:], 16)
+	} else {
+		_, ok = val.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %v", s)
+	}
+	if val.Sign() < 0 || val.BitLen() > 256 {
+		return nil, fmt.Errorf("integer %v isn't a 256 bit unsigned value", s)
+	}
+	return val, nil
+}
+
+func parseHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("expected hex data starting with 0x but got %v", s)
+	}
+	return hex.DecodeString(s[2:])
+}
+
+func parseHash(s string) (common.Hash, error) {
+	var ret common.Hash
+	data, err := parseHex(s)
+	if err != nil {
+		return ret, err
+	}
+	if len(data) != len(ret) {
+		return ret, fmt.Errorf("hash %v must be 32 bytes", s)
+	}
+	copy(ret[:], data)
+	return ret, nil
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aoasm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/debuginfo"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+type disassembler struct {
+	codePoints []value.CodePointValue
+	encoded    [][]byte
+	labels     map[int64]bool
+}
+
+// Disassemble writes p in the text format read by Assemble. Code points that
+// refer to an instruction of the program are written as labels and all other
+// values are written in the form given by their String method.
+func Disassemble(p *Program, w io.Writer) error {
+	d := &disassembler{
+		codePoints: p.CodePoints(),
+		encoded:    make([][]byte, len(p.Code)),
+		labels:     make(map[int64]bool),
+	}
+	for _, insn := range p.Code {
+		if imm, ok := insn.(value.ImmediateOperation); ok {
+			d.findLabels(imm.Val)
+		}
+	}
+	d.findLabels(p.Static)
+
+	// Debug info is only used for comments so a program with a malformed
+	// extension can still be disassembled
+	debugInfo, _ := p.DebugInfo()
+
+	wr := bufio.NewWriter(w)
+	fmt.Fprintf(wr, ".version %v\n", p.Version)
+	for _, ext := range p.Extensions {
+		fmt.Fprintf(wr, ".extension %v 0x%v\n", ext.ID, hex.EncodeToString(ext.Data))
+	}
+	fmt.Fprintln(wr, ".code")
+	for i, insn := range p.Code {
+		if debugInfo != nil {
+			writeFunctionComment(wr, debugInfo, int64(i))
+		}
+		if d.labels[int64(i)] {
+			fmt.Fprintf(wr, "%v:\n", labelName(int64(i)))
+		}
+		fmt.Fprintf(wr, "    %v", d.operationString(insn))
+		if debugInfo != nil {
+			writeLocationComment(wr, debugInfo, int64(i))
+		}
+		fmt.Fprintln(wr)
+	}
+	fmt.Fprintf(wr, ".static %v\n", d.valueString(p.Static))
+	return wr.Flush()
+}
+
+func writeFunctionComment(wr io.Writer, debugInfo *debuginfo.DebugInfo, insnNum int64) {
+	for _, function := range debugInfo.Functions {
+		if function.Start == insnNum {
+			fmt.Fprintf(wr, "; %v()\n", function.Name)
+		}
+	}
+}
+
+func writeLocationComment(wr io.Writer, debugInfo *debuginfo.DebugInfo, insnNum int64) {
+	loc, ok := debugInfo.Location(insnNum)
+	if ok && loc.Start == insnNum {
+		fmt.Fprintf(wr, " ; %v:%v:%v", debugInfo.Files[loc.File], loc.Line, loc.Column)
+	}
+}
+
+func labelName(insnNum int64) string {
+	return fmt.Sprintf("L%v", insnNum)
+}
+
+func opcodeName(op value.Opcode) string {
+	if name, ok := code.InstructionNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%x", byte(op))
+}
+
+// isProgramCodePoint reports whether cp is exactly the code point of one of
+// the program's instructions so that it can be written as a label
+func (d *disassembler) isProgramCodePoint(cp value.CodePointValue) bool {
+	if cp.InsnNum < 0 || cp.InsnNum >= int64(len(d.codePoints)) {
+		return false
+	}
+	expected := d.codePoints[cp.InsnNum]
+	if cp.NextHash != expected.NextHash {
+		return false
+	}
+	if d.encoded[cp.InsnNum] == nil {
+		var buf bytes.Buffer
+		_ = value.MarshalOperationComplete(expected.Op, &buf)
+		d.encoded[cp.InsnNum] = buf.Bytes()
+	}
+	var buf bytes.Buffer
+	_ = value.MarshalOperationComplete(cp.Op, &buf)
+	return bytes.Equal(buf.Bytes(), d.encoded[cp.InsnNum])
+}
+
+func (d *disassembler) findLabels(val value.Value) {
+	switch val := val.(type) {
+	case value.CodePointValue:
+		if d.isProgramCodePoint(val) {
+			d.labels[val.InsnNum] = true
+		} else if imm, ok := val.Op.(value.ImmediateOperation); ok {
+			d.findLabels(imm.Val)
+		}
+	case value.TupleValue:
+		for _, v := range val.Contents() {
+			d.findLabels(v)
+		}
+	}
+}
+
+func (d *disassembler) operationString(op value.Operation) string {
+	if imm, ok := op.(value.ImmediateOperation); ok {
+		return fmt.Sprintf("%v %v", opcodeName(imm.Op), d.valueString(imm.Val))
+	}
+	return opcodeName(op.GetOp())
+}
+
+func (d *disassembler) valueString(val value.Value) string {
+	var sb strings.Builder
+	d.writeValue(val, &sb)
+	return sb.String()
+}
+
+func (d *disassembler) writeValue(val value.Value, sb *strings.Builder) {
+	switch val := val.(type) {
+	case value.CodePointValue:
+		if d.isProgramCodePoint(val) {
+			sb.WriteString("@" + labelName(val.InsnNum))
+			return
+		}
+		fmt.Fprintf(sb, "CodePoint(%v, %v, %v)", val.InsnNum, d.operationString(val.Op), val.NextHash)
+	case value.HashOnlyValue:
+		fmt.Fprintf(sb, "HashOnlyValue(%v, %v)", val.Hash(), val.Size())
+	case value.TupleValue:
+		sb.WriteString("Tuple(")
+		for i, v := range val.Contents() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			d.writeValue(v, sb)
+		}
+		sb.WriteString(")")
+	default:
+		fmt.Fprintf(sb, "%v", val)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aoasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/debuginfo"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+const CurrentAOVersion uint32 = 1
+
+type Extension struct {
+	ID   uint32
+	Data []byte
+}
+
+// Program is the decoded contents of an .ao file
+type Program struct {
+	Version    uint32
+	Extensions []Extension
+	Code       []value.Operation
+	Static     value.Value
+}
+
+func ReadProgram(rd io.Reader) (*Program, error) {
+	var aoVersion uint32
+	if err := binary.Read(rd, binary.BigEndian, &aoVersion); err != nil {
+		return nil, err
+	}
+	if aoVersion != CurrentAOVersion {
+		return nil, fmt.Errorf("AO file has unsupported version %v", aoVersion)
+	}
+
+	extensions := make([]Extension, 0)
+	for {
+		var extensionID uint32
+		if err := binary.Read(rd, binary.BigEndian, &extensionID); err != nil {
+			return nil, err
+		}
+		if extensionID == 0 {
+			break
+		}
+		var extensionLength uint32
+		if err := binary.Read(rd, binary.BigEndian, &extensionLength); err != nil {
+			return nil, err
+		}
+		extensionData := make([]byte, extensionLength)
+		if _, err := io.ReadFull(rd, extensionData); err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, Extension{
+			ID:   extensionID,
+			Data: extensionData,
+		})
+	}
+
+	var insnsLen uint64
+	if err := binary.Read(rd, binary.BigEndian, &insnsLen); err != nil {
+		return nil, err
+	}
+	insns := make([]value.Operation, 0, insnsLen)
+	for i := uint64(0); i < insnsLen; i++ {
+		insn, err := value.NewOperationFromReader(rd)
+		if err != nil {
+			return nil, err
+		}
+		insns = append(insns, insn)
+	}
+
+	static, err := value.UnmarshalValue(rd)
+	if err != nil {
+		return nil, err
+	}
+	return &Program{
+		Version:    aoVersion,
+		Extensions: extensions,
+		Code:       insns,
+		Static:     static,
+	}, nil
+}
+
+func (p *Program) Write(wr io.Writer) error {
+	if err := binary.Write(wr, binary.BigEndian, p.Version); err != nil {
+		return err
+	}
+	for _, ext := range p.Extensions {
+		if err := binary.Write(wr, binary.BigEndian, ext.ID); err != nil {
+			return err
+		}
+		if err := binary.Write(wr, binary.BigEndian, uint32(len(ext.Data))); err != nil {
+			return err
+		}
+		if _, err := wr.Write(ext.Data); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(wr, binary.BigEndian, uint32(0)); err != nil {
+		return err
+	}
+	if err := binary.Write(wr, binary.BigEndian, uint64(len(p.Code))); err != nil {
+		return err
+	}
+	for _, insn := range p.Code {
+		if err := value.MarshalOperationComplete(insn, wr); err != nil {
+			return err
+		}
+	}
+	return value.MarshalValueComplete(p.Static, wr)
+}
+
+// DebugInfo parses the program's debug info extension, returning nil if the
+// program doesn't have one
+func (p *Program) DebugInfo() (*debuginfo.DebugInfo, error) {
+	for _, ext := range p.Extensions {
+		if ext.ID == debuginfo.ExtensionID {
+			return debuginfo.Unmarshal(ext.Data)
+		}
+	}
+	return nil, nil
+}
+
+// CodePoints returns the code point for each instruction in the program,
+// matching the ones the machine pushes while running it
+func (p *Program) CodePoints() []value.CodePointValue {
+	codePoints := make([]value.CodePointValue, len(p.Code))
+	var prevHash common.Hash
+	for i := len(p.Code) - 1; i >= 0; i-- {
+		codePoints[i] = value.CodePointValue{
+			InsnNum:  int64(i),
+			Op:       p.Code[i],
+			NextHash: prevHash,
+		}
+		prevHash = codePoints[i].Hash()
+	}
+	return codePoints
+}
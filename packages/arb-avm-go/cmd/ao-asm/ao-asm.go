@@ -0,0 +1,61 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+)
+
+// Assembles the text format written by ao-dis into an .ao file:
+// ao-asm -o contract.ao input.s
+func main() {
+	output := flag.String("o", "contract.ao", "file to write the assembled program to")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatalln("usage: ao-asm [-o contract.ao] input.s")
+	}
+
+	in, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer in.Close()
+	prog, err := aoasm.Assemble(in)
+	if err != nil {
+		log.Fatalf("%v: %v", flag.Arg(0), err)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	wr := bufio.NewWriter(f)
+	if err := prog.Write(wr); err != nil {
+		log.Fatal(err)
+	}
+	if err := wr.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
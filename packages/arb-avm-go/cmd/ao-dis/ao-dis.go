@@ -0,0 +1,64 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+)
+
+// Disassembles an .ao file into the text format read by ao-asm:
+// ao-dis [-o output.s] contract.ao
+func main() {
+	output := flag.String("o", "", "file to write the disassembly to instead of stdout")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatalln("usage: ao-dis [-o output.s] contract.ao")
+	}
+
+	data, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	rd := bytes.NewReader(data)
+	prog, err := aoasm.ReadProgram(rd)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if rd.Len() != 0 {
+		log.Fatalf("%v has %v bytes of trailing data", flag.Arg(0), rd.Len())
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := aoasm.Disassemble(prog, w); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package code
+
+//go:generate go run ../cmd/gen-gas-schedule -o ../../arb-avm-cpp/avm_values/include/avm_values/gascost.hpp
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// GasSchedule is the arbgas charged for each instruction. It is the single
+// definition of the schedule: the Go AVM reads it directly and the C++ AVM's
+// gascost.hpp is generated from it, and both must agree with opGasCost in
+// the EthBridge one step proof contract.
+var GasSchedule = map[value.Opcode]uint64{
+	ADD:    3,
+	MUL:    3,
+	SUB:    3,
+	DIV:    4,
+	SDIV:   7,
+	MOD:    4,
+	SMOD:   7,
+	ADDMOD: 4,
+	MULMOD: 4,
+	EXP:    25,
+
+	LT:         2,
+	GT:         2,
+	SLT:        2,
+	SGT:        2,
+	EQ:         2,
+	ISZERO:     1,
+	AND:        2,
+	OR:         2,
+	XOR:        2,
+	NOT:        1,
+	BYTE:       4,
+	SIGNEXTEND: 7,
+
+	SHA3:     7,
+	TYPE:     3,
+	ETHHASH2: 8,
+
+	POP:           1,
+	SPUSH:         1,
+	RPUSH:         1,
+	RSET:          2,
+	JUMP:          4,
+	CJUMP:         4,
+	STACKEMPTY:    2,
+	PCPUSH:        1,
+	AUXPUSH:       1,
+	AUXPOP:        1,
+	AUXSTACKEMPTY: 2,
+	NOP:           1,
+	ERRPUSH:       1,
+	ERRSET:        1,
+
+	DUP0:  1,
+	DUP1:  1,
+	DUP2:  1,
+	SWAP1: 1,
+	SWAP2: 1,
+
+	TGET: 2,
+	TSET: 40,
+	TLEN: 2,
+
+	BREAKPOINT: 100,
+	LOG:        100,
+
+	SEND:    100,
+	GETTIME: 40,
+	INBOX:   40,
+	ERROR:   5,
+	HALT:    10,
+	// debug can't be proven on chain so it only needs to match the C++ AVM
+	DEBUG: 1,
+}
+
+// cppOpcodeName returns the name of the OpCode enum value that the C++ AVM
+// uses for op
+func cppOpcodeName(op value.Opcode) string {
+	name := InstructionNames[op]
+	switch name {
+	case "and", "or", "xor", "not":
+		return "BITWISE_" + strings.ToUpper(name)
+	default:
+		return strings.ToUpper(name)
+	}
+}
+
+// WriteCppGasSchedule writes the C++ header containing GasSchedule
+func WriteCppGasSchedule(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString(`/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Code generated by arb-avm-go/cmd/gen-gas-schedule from
+// arb-avm-go/code/gas.go. DO NOT EDIT.
+
+#ifndef gascost_hpp
+#define gascost_hpp
+
+#include <avm_values/opcodes.hpp>
+
+#include <cstdint>
+#include <unordered_map>
+
+const std::unordered_map<OpCode, uint64_t> InstructionArbGasCost = {
+`)
+	first := true
+	for op := value.Opcode(0); op < MaxOpcode; op++ {
+		gas, ok := GasSchedule[op]
+		if !ok {
+			continue
+		}
+		if !first {
+			sb.WriteString(",\n")
+		}
+		first = false
+		sb.WriteString(fmt.Sprintf("    {OpCode::%v, %v}", cppOpcodeName(op), gas))
+	}
+	sb.WriteString("};\n\n#endif /* gascost_hpp */\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
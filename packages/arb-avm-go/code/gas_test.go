@@ -0,0 +1,101 @@
+package code
+
+import (
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+const (
+	oneStepProofFile = "../../arb-bridge-eth/contracts/arch/OneStepProof.sol"
+	cppGasCostFile   = "../../arb-avm-cpp/avm_values/include/avm_values/gascost.hpp"
+)
+
+func TestGasScheduleComplete(t *testing.T) {
+	for op, name := range InstructionNames {
+		if _, ok := GasSchedule[op]; !ok {
+			t.Errorf("%v has no gas cost", name)
+		}
+	}
+	for op := range GasSchedule {
+		if _, ok := InstructionNames[op]; !ok {
+			t.Errorf("gas schedule includes unknown opcode 0x%x", uint8(op))
+		}
+	}
+}
+
+// loadOneStepProofGas reads the gas charged for each opcode by opGasCost in
+// the one step proof contract
+func loadOneStepProofGas(t *testing.T) map[value.Opcode]uint64 {
+	data, err := ioutil.ReadFile(oneStepProofFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opcodes := make(map[string]value.Opcode)
+	constRe := regexp.MustCompile(`uint8 constant internal (OP_\w+) = 0x([0-9a-fA-F]+);`)
+	for _, match := range constRe.FindAllSubmatch(data, -1) {
+		op, err := strconv.ParseUint(string(match[2]), 16, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opcodes[string(match[1])] = value.Opcode(op)
+	}
+
+	start := bytes.Index(data, []byte("function opGasCost"))
+	if start < 0 {
+		t.Fatal("couldn't find opGasCost")
+	}
+	end := bytes.Index(data[start:], []byte("require(false"))
+	if end < 0 {
+		t.Fatal("couldn't find the end of opGasCost")
+	}
+	costRe := regexp.MustCompile(`opCode == (OP_\w+)\)\s*\{\s*return (\d+);`)
+	costs := make(map[value.Opcode]uint64)
+	for _, match := range costRe.FindAllSubmatch(data[start:start+end], -1) {
+		op, ok := opcodes[string(match[1])]
+		if !ok {
+			t.Fatalf("unknown opcode %s", match[1])
+		}
+		gas, err := strconv.ParseUint(string(match[2]), 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		costs[op] = gas
+	}
+	return costs
+}
+
+func TestGasScheduleMatchesOneStepProof(t *testing.T) {
+	costs := loadOneStepProofGas(t)
+	if len(costs) == 0 {
+		t.Fatal("didn't find any gas costs in the one step proof")
+	}
+	for op, gas := range costs {
+		if GasSchedule[op] != gas {
+			t.Errorf("%v costs %v gas but the one step proof charges %v", InstructionNames[op], GasSchedule[op], gas)
+		}
+	}
+	for op := range GasSchedule {
+		if _, ok := costs[op]; !ok && op != DEBUG {
+			t.Errorf("%v isn't charged by the one step proof", InstructionNames[op])
+		}
+	}
+}
+
+func TestCppGasScheduleUpToDate(t *testing.T) {
+	existing, err := ioutil.ReadFile(cppGasCostFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteCppGasSchedule(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(existing, buf.Bytes()) {
+		t.Error("gascost.hpp is out of date, run go generate in arb-avm-go/code")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package debuginfo implements the AO extension which maps instructions back
+// to the source that they were compiled from.
+//
+// The extension data is a sequence of big endian fields:
+//
+//	uint32 file count, then for each file a uint32 length and the file name
+//	uint32 function count, then for each function a uint64 first instruction,
+//	  a uint32 length and the function name
+//	uint32 location count, then for each location a uint64 first instruction,
+//	  a uint32 file index, a uint32 line and a uint32 column
+//
+// Functions and locations must be sorted by first instruction and each one
+// covers every instruction up to the start of the next.
+package debuginfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const ExtensionID uint32 = 1
+
+type Function struct {
+	Start int64
+	Name  string
+}
+
+type Location struct {
+	Start  int64
+	File   uint32
+	Line   uint32
+	Column uint32
+}
+
+type DebugInfo struct {
+	Files     []string
+	Functions []Function
+	Locations []Location
+}
+
+func Unmarshal(data []byte) (*DebugInfo, error) {
+	rd := bytes.NewReader(data)
+	files, err := readStrings(rd)
+	if err != nil {
+		return nil, err
+	}
+
+	var functionCount uint32
+	if err := binary.Read(rd, binary.BigEndian, &functionCount); err != nil {
+		return nil, err
+	}
+	functions := make([]Function, 0)
+	for i := uint32(0); i < functionCount; i++ {
+		var start uint64
+		if err := binary.Read(rd, binary.BigEndian, &start); err != nil {
+			return nil, err
+		}
+		name, err := readString(rd)
+		if err != nil {
+			return nil, err
+		}
+		functions = append(functions, Function{Start: int64(start), Name: name})
+	}
+
+	var locationCount uint32
+	if err := binary.Read(rd, binary.BigEndian, &locationCount); err != nil {
+		return nil, err
+	}
+	locations := make([]Location, 0)
+	for i := uint32(0); i < locationCount; i++ {
+		var start uint64
+		if err := binary.Read(rd, binary.BigEndian, &start); err != nil {
+			return nil, err
+		}
+		var fields [3]uint32
+		if err := binary.Read(rd, binary.BigEndian, &fields); err != nil {
+			return nil, err
+		}
+		locations = append(locations, Location{
+			Start:  int64(start),
+			File:   fields[0],
+			Line:   fields[1],
+			Column: fields[2],
+		})
+	}
+	if rd.Len() != 0 {
+		return nil, errors.New("debug info has trailing data")
+	}
+
+	info := &DebugInfo{
+		Files:     files,
+		Functions: functions,
+		Locations: locations,
+	}
+	if err := info.validate(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (d *DebugInfo) Marshal() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(d.Files)))
+	for _, file := range d.Files {
+		writeString(&buf, file)
+	}
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(d.Functions)))
+	for _, function := range d.Functions {
+		_ = binary.Write(&buf, binary.BigEndian, uint64(function.Start))
+		writeString(&buf, function.Name)
+	}
+	_ = binary.Write(&buf, binary.BigEndian, uint32(len(d.Locations)))
+	for _, loc := range d.Locations {
+		_ = binary.Write(&buf, binary.BigEndian, uint64(loc.Start))
+		_ = binary.Write(&buf, binary.BigEndian, [3]uint32{loc.File, loc.Line, loc.Column})
+	}
+	return buf.Bytes()
+}
+
+func (d *DebugInfo) validate() error {
+	for i := 1; i < len(d.Functions); i++ {
+		if d.Functions[i].Start <= d.Functions[i-1].Start {
+			return errors.New("debug info functions aren't sorted")
+		}
+	}
+	for i, loc := range d.Locations {
+		if i > 0 && loc.Start <= d.Locations[i-1].Start {
+			return errors.New("debug info locations aren't sorted")
+		}
+		if int(loc.File) >= len(d.Files) {
+			return fmt.Errorf("debug info location refers to unknown file %v", loc.File)
+		}
+	}
+	return nil
+}
+
+// Function returns the name of the function containing instruction pc
+func (d *DebugInfo) Function(pc int64) (string, bool) {
+	idx := sort.Search(len(d.Functions), func(i int) bool {
+		return d.Functions[i].Start > pc
+	})
+	if idx == 0 {
+		return "", false
+	}
+	return d.Functions[idx-1].Name, true
+}
+
+// Location returns the source location that instruction pc was compiled from
+func (d *DebugInfo) Location(pc int64) (Location, bool) {
+	idx := sort.Search(len(d.Locations), func(i int) bool {
+		return d.Locations[i].Start > pc
+	})
+	if idx == 0 {
+		return Location{}, false
+	}
+	return d.Locations[idx-1], true
+}
+
+// Describe formats the source location of instruction pc, such as
+// "contract.sol:123 in transfer()". It falls back to the raw pc for
+// instructions that aren't covered.
+func (d *DebugInfo) Describe(pc int64) string {
+	if d == nil {
+		return fmt.Sprintf("pc %v", pc)
+	}
+	var desc string
+	if loc, ok := d.Location(pc); ok {
+		desc = fmt.Sprintf("%v:%v", d.Files[loc.File], loc.Line)
+	} else {
+		desc = fmt.Sprintf("pc %v", pc)
+	}
+	if function, ok := d.Function(pc); ok {
+		desc += fmt.Sprintf(" in %v()", function)
+	}
+	return desc
+}
+
+func readString(rd *bytes.Reader) (string, error) {
+	var length uint32
+	if err := binary.Read(rd, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if int64(length) > int64(rd.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(rd, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func readStrings(rd *bytes.Reader) ([]string, error) {
+	var count uint32
+	if err := binary.Read(rd, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	ret := make([]string, 0)
+	for i := uint32(0); i < count; i++ {
+		str, err := readString(rd)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, str)
+	}
+	return ret, nil
+}
+
+func writeString(buf *bytes.Buffer, str string) {
+	_ = binary.Write(buf, binary.BigEndian, uint32(len(str)))
+	buf.WriteString(str)
+}
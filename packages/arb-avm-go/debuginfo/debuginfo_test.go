@@ -0,0 +1,83 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debuginfo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	info := &DebugInfo{
+		Files: []string{"contract.sol"},
+		Functions: []Function{
+			{Start: 0, Name: "constructor"},
+			{Start: 10, Name: "transfer"},
+		},
+		Locations: []Location{
+			{Start: 5, File: 0, Line: 40, Column: 2},
+			{Start: 12, File: 0, Line: 123, Column: 8},
+		},
+	}
+
+	data := info.Marshal()
+	parsed, err := Unmarshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsed.Marshal(), data) {
+		t.Error("debug info didn't round trip")
+	}
+
+	cases := map[int64]string{
+		2:  "pc 2 in constructor()",
+		7:  "contract.sol:40 in constructor()",
+		11: "contract.sol:40 in transfer()",
+		50: "contract.sol:123 in transfer()",
+	}
+	for pc, expected := range cases {
+		if desc := parsed.Describe(pc); desc != expected {
+			t.Errorf("pc %v described as %v instead of %v", pc, desc, expected)
+		}
+	}
+
+	var missing *DebugInfo
+	if desc := missing.Describe(3); desc != "pc 3" {
+		t.Error("missing debug info described as", desc)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	info := &DebugInfo{
+		Files:     []string{"contract.sol"},
+		Locations: []Location{{Start: 5, File: 1, Line: 40}},
+	}
+	if _, err := Unmarshal(info.Marshal()); err == nil {
+		t.Error("accepted location with unknown file")
+	}
+
+	info = &DebugInfo{
+		Functions: []Function{{Start: 10, Name: "a"}, {Start: 3, Name: "b"}},
+	}
+	if _, err := Unmarshal(info.Marshal()); err == nil {
+		t.Error("accepted unsorted functions")
+	}
+
+	if _, err := Unmarshal([]byte{0, 0, 0, 1, 0, 0, 0, 9}); err == nil {
+		t.Error("accepted truncated data")
+	}
+}
@@ -17,20 +17,13 @@
 package goloader
 
 import (
-	"encoding/binary"
-	"fmt"
 	"io"
 	"os"
 
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
 	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
-	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 )
 
-type RawExtension struct {
-	id   uint32
-	data []byte
-}
-
 type Error struct {
 	str string
 }
@@ -49,62 +42,21 @@ func LoadMachineFromFile(fileName string, warnMode bool) (*vm.Machine, error) {
 	return LoadMachine(f, warnMode)
 }
 
-const CurrentAOVersion uint32 = 1
+const CurrentAOVersion = aoasm.CurrentAOVersion
 
 func LoadMachine(rd io.Reader, warnMode bool) (*vm.Machine, error) {
-	var aoVersion uint32
-	err := binary.Read(rd, binary.BigEndian, &aoVersion)
+	prog, err := aoasm.ReadProgram(rd)
 	if err != nil {
 		return nil, err
 	}
 
-	if aoVersion != CurrentAOVersion {
-		return nil, fmt.Errorf("AO file has unsupported version %v", aoVersion)
-	}
-
-	extensions := make([]RawExtension, 0)
-	var extensionID uint32 = 1
-	for extensionID != 0 {
-		err := binary.Read(rd, binary.BigEndian, &extensionID)
-		if err != nil {
-			return nil, err
-		}
-		if extensionID > 0 {
-			var extensionLength uint32
-			err := binary.Read(rd, binary.BigEndian, &extensionLength)
-			if err != nil {
-				return nil, err
-			}
-			extensionData := make([]byte, extensionLength)
-			_, err = rd.Read(extensionData)
-			if err != nil {
-				return nil, err
-			}
-			extensions = append(extensions, RawExtension{
-				id:   extensionID,
-				data: extensionData,
-			})
-		}
-	}
-
-	var insnsLen uint64
-	err = binary.Read(rd, binary.BigEndian, &insnsLen)
+	debugInfo, err := prog.DebugInfo()
 	if err != nil {
 		return nil, err
 	}
-	insns := make([]value.Operation, insnsLen)
-	for i := uint64(0); i < insnsLen; i++ {
-		insns[i], err = value.NewOperationFromReader(rd)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	static, err2 := value.UnmarshalValue(rd)
-	if err2 != nil {
-		return nil, err2
-	}
 
 	maxSize := int64(1) << 62
-	return vm.NewMachine(insns, static, warnMode, maxSize), nil
+	m := vm.NewMachine(prog.Code, prog.Static, warnMode, maxSize)
+	m.SetDebugInfo(debugInfo)
+	return m, nil
 }
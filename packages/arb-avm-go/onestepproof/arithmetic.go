@@ -0,0 +1,170 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package onestepproof
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var tt256 = math.BigPow(2, 256)
+
+// binaryIntOps implement the two argument integer instructions the same way
+// as the corresponding EVM opcodes. The result is false if the instruction
+// should jump to the error handler.
+var binaryIntOps = map[value.Opcode]func(a, b *big.Int) (*big.Int, bool){
+	code.ADD: func(a, b *big.Int) (*big.Int, bool) {
+		return math.U256(new(big.Int).Add(a, b)), true
+	},
+	code.MUL: func(a, b *big.Int) (*big.Int, bool) {
+		return math.U256(new(big.Int).Mul(a, b)), true
+	},
+	code.SUB: func(a, b *big.Int) (*big.Int, bool) {
+		return math.U256(new(big.Int).Sub(a, b)), true
+	},
+	code.DIV: func(a, b *big.Int) (*big.Int, bool) {
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		return new(big.Int).Div(a, b), true
+	},
+	code.SDIV: func(a, b *big.Int) (*big.Int, bool) {
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		return math.U256(new(big.Int).Quo(math.S256(a), math.S256(b))), true
+	},
+	code.MOD: func(a, b *big.Int) (*big.Int, bool) {
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		return new(big.Int).Mod(a, b), true
+	},
+	code.SMOD: func(a, b *big.Int) (*big.Int, bool) {
+		if b.Sign() == 0 {
+			return nil, false
+		}
+		return math.U256(new(big.Int).Rem(math.S256(a), math.S256(b))), true
+	},
+	code.EXP: func(a, b *big.Int) (*big.Int, bool) {
+		return new(big.Int).Exp(a, b, tt256), true
+	},
+	code.LT: func(a, b *big.Int) (*big.Int, bool) {
+		return boolInt(a.Cmp(b) < 0), true
+	},
+	code.GT: func(a, b *big.Int) (*big.Int, bool) {
+		return boolInt(a.Cmp(b) > 0), true
+	},
+	code.SLT: func(a, b *big.Int) (*big.Int, bool) {
+		return boolInt(math.S256(a).Cmp(math.S256(b)) < 0), true
+	},
+	code.SGT: func(a, b *big.Int) (*big.Int, bool) {
+		return boolInt(math.S256(a).Cmp(math.S256(b)) > 0), true
+	},
+	code.AND: func(a, b *big.Int) (*big.Int, bool) {
+		return new(big.Int).And(a, b), true
+	},
+	code.OR: func(a, b *big.Int) (*big.Int, bool) {
+		return new(big.Int).Or(a, b), true
+	},
+	code.XOR: func(a, b *big.Int) (*big.Int, bool) {
+		return new(big.Int).Xor(a, b), true
+	},
+	code.BYTE: func(x, n *big.Int) (*big.Int, bool) {
+		if !n.IsUint64() || n.Uint64() >= 32 {
+			return big.NewInt(0), true
+		}
+		return big.NewInt(int64(math.Byte(x, 32, int(n.Uint64())))), true
+	},
+	code.SIGNEXTEND: func(x, b *big.Int) (*big.Int, bool) {
+		if !b.IsUint64() || b.Uint64() >= 31 {
+			return x, true
+		}
+		signBit := uint(b.Uint64()*8 + 7)
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), signBit), big.NewInt(1))
+		if x.Bit(int(signBit)) == 0 {
+			return new(big.Int).And(x, mask), true
+		}
+		return math.U256(new(big.Int).Or(x, new(big.Int).Not(mask))), true
+	},
+	code.ETHHASH2: func(a, b *big.Int) (*big.Int, bool) {
+		hash := hashing.SoliditySHA3(hashing.Uint256(a), hashing.Uint256(b))
+		return new(big.Int).SetBytes(hash[:]), true
+	},
+}
+
+func boolInt(val bool) *big.Int {
+	if val {
+		return big.NewInt(1)
+	}
+	return big.NewInt(0)
+}
+
+func executeBinaryIntInsn(m *machineState, op value.Opcode, val1, val2 value.Value) bool {
+	a, ok1 := val1.(value.IntValue)
+	b, ok2 := val2.(value.IntValue)
+	if !ok1 || !ok2 {
+		return false
+	}
+	res, ok := binaryIntOps[op](a.BigInt(), b.BigInt())
+	if ok {
+		m.pushInt(res)
+	}
+	return ok
+}
+
+// executeModInsn runs ADDMOD or MULMOD. Like the contract, only the first
+// two arguments are type checked and the modulus is read from whatever
+// integer the third value holds.
+func executeModInsn(m *machineState, op value.Opcode, val1, val2, val3 value.Value) bool {
+	a, ok1 := val1.(value.IntValue)
+	b, ok2 := val2.(value.IntValue)
+	if !ok1 || !ok2 {
+		return false
+	}
+	modulus := intVal(val3)
+	if modulus.Sign() == 0 {
+		return false
+	}
+	var res *big.Int
+	if op == code.ADDMOD {
+		res = new(big.Int).Add(a.BigInt(), b.BigInt())
+	} else {
+		res = new(big.Int).Mul(a.BigInt(), b.BigInt())
+	}
+	m.pushInt(res.Mod(res, modulus))
+	return true
+}
+
+// intVal mirrors the intVal field of the contract's Value.Data, which holds
+// the hash for hash only values and zero for anything but an integer
+func intVal(val value.Value) *big.Int {
+	switch val := val.(type) {
+	case value.IntValue:
+		return val.BigInt()
+	case value.HashOnlyValue:
+		hash := val.Hash()
+		return new(big.Int).SetBytes(hash[:])
+	default:
+		return big.NewInt(0)
+	}
+}
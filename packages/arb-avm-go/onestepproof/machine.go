@@ -0,0 +1,88 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package onestepproof
+
+import (
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// machineState is the part of a machine that the contract can see, which is
+// only the hash of each of its components
+type machineState struct {
+	pc         common.Hash
+	stack      common.Hash
+	auxStack   common.Hash
+	register   common.Hash
+	static     common.Hash
+	errHandler common.Hash
+	status     machine.Status
+}
+
+func (m *machineState) clone() *machineState {
+	ret := *m
+	return &ret
+}
+
+func (m *machineState) hash() common.Hash {
+	switch m.status {
+	case machine.Halt:
+		return common.Hash{}
+	case machine.ErrorStop:
+		return value.NewInt64Value(1).ToBytes()
+	default:
+		return hashing.SoliditySHA3(
+			hashing.Bytes32(m.pc),
+			hashing.Bytes32(m.stack),
+			hashing.Bytes32(m.auxStack),
+			hashing.Bytes32(m.register),
+			hashing.Bytes32(m.static),
+			hashing.Bytes32(m.errHandler),
+		)
+	}
+}
+
+func (m *machineState) pushHash(hash common.Hash) {
+	m.stack = pushStackHash(m.stack, hash)
+}
+
+func (m *machineState) pushValues(vals ...value.Value) {
+	for _, val := range vals {
+		m.pushHash(val.Hash())
+	}
+}
+
+func (m *machineState) pushInt(val *big.Int) {
+	m.pushHash(value.NewIntValue(val).Hash())
+}
+
+func (m *machineState) pushBool(val bool) {
+	m.pushHash(value.NewBooleanValue(val).Hash())
+}
+
+// pushStackHash returns the hash of the stack with the given hash after a
+// value with hash valHash is pushed onto it
+func pushStackHash(stackHash common.Hash, valHash common.Hash) common.Hash {
+	return value.NewTuple2(
+		value.NewHashOnlyValue(valHash, 0),
+		value.NewHashOnlyValue(stackHash, 0),
+	).Hash()
+}
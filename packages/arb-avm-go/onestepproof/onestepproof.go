@@ -0,0 +1,370 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package onestepproof checks one step proofs the same way as the
+// OneStepProof contract in the EthBridge, without needing a chain. Proofs
+// are in the format written by MarshalForProof.
+package onestepproof
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// ProofData holds the arguments of OneStepProof.validateProof
+type ProofData struct {
+	BeforeHash       common.Hash
+	TimeBounds       *protocol.TimeBoundsBlocks
+	BeforeInbox      common.Hash
+	AfterHash        common.Hash
+	DidInboxInsn     bool
+	FirstMessageHash common.Hash
+	LastMessageHash  common.Hash
+	FirstLogHash     common.Hash
+	LastLogHash      common.Hash
+	NumGas           uint64
+	Proof            []byte
+}
+
+// ValidateProof returns nil if the contract would accept the proof and
+// otherwise an error with the reason the contract would revert with
+func ValidateProof(data *ProofData) error {
+	op, stackVals, start, end, rd, err := loadMachine(data.Proof)
+	if err != nil {
+		return err
+	}
+	if data.NumGas != code.GasSchedule[op] {
+		return errors.New("Invalid gas in proof")
+	}
+	if data.DidInboxInsn != (op == code.INBOX) {
+		return errors.New("Invalid didInboxInsn claim")
+	}
+
+	correct := true
+	var messageHash common.Hash
+	switch op {
+	case code.ADD, code.MUL, code.SUB, code.DIV, code.SDIV, code.MOD, code.SMOD,
+		code.EXP, code.LT, code.GT, code.SLT, code.SGT, code.AND, code.OR,
+		code.XOR, code.BYTE, code.SIGNEXTEND, code.ETHHASH2:
+		correct = executeBinaryIntInsn(end, op, stackVals[0], stackVals[1])
+	case code.ADDMOD, code.MULMOD:
+		correct = executeModInsn(end, op, stackVals[0], stackVals[1], stackVals[2])
+	case code.EQ:
+		end.pushBool(stackVals[0].Hash() == stackVals[1].Hash())
+	case code.ISZERO:
+		if val, ok := stackVals[0].(value.IntValue); ok {
+			end.pushBool(val.BigInt().Sign() == 0)
+		} else {
+			end.pushBool(false)
+		}
+	case code.NOT:
+		val, ok := stackVals[0].(value.IntValue)
+		if ok {
+			end.pushInt(math.U256(new(big.Int).Not(val.BigInt())))
+		}
+		correct = ok
+	case code.SHA3:
+		hash := stackVals[0].Hash()
+		end.pushInt(new(big.Int).SetBytes(hash[:]))
+	case code.TYPE:
+		if _, ok := stackVals[0].(value.HashOnlyValue); ok {
+			return errors.New("Value must have a valid type code")
+		}
+		end.pushInt(big.NewInt(int64(stackVals[0].TypeCode())))
+	case code.POP:
+	case code.SPUSH:
+		end.pushHash(end.static)
+	case code.RPUSH:
+		end.pushHash(end.register)
+	case code.RSET:
+		end.register = stackVals[0].Hash()
+	case code.JUMP:
+		end.pc = stackVals[0].Hash()
+	case code.CJUMP:
+		_, isCodePoint := stackVals[0].(value.CodePointValue)
+		cond, isInt := stackVals[1].(value.IntValue)
+		correct = isCodePoint && isInt
+		if correct && cond.BigInt().Sign() != 0 {
+			end.pc = stackVals[0].Hash()
+		}
+	case code.STACKEMPTY:
+		end.pushBool(end.stack == value.NewEmptyTuple().Hash())
+	case code.PCPUSH:
+		end.pushHash(start.pc)
+	case code.AUXPUSH:
+		end.auxStack = pushStackHash(end.auxStack, stackVals[0].Hash())
+	case code.AUXPOP:
+		auxVal, err := value.UnmarshalValueForProof(rd)
+		if err != nil {
+			return errors.New("Proof of auxpop had bad aux value")
+		}
+		start.auxStack = pushStackHash(start.auxStack, auxVal.Hash())
+		end.pushHash(auxVal.Hash())
+	case code.AUXSTACKEMPTY:
+		end.pushBool(end.auxStack == value.NewEmptyTuple().Hash())
+	case code.NOP:
+	case code.ERRPUSH:
+		end.pushHash(end.errHandler)
+	case code.ERRSET:
+		_, correct = stackVals[0].(value.CodePointValue)
+		if correct {
+			end.errHandler = stackVals[0].Hash()
+		}
+	case code.DUP0:
+		end.pushValues(stackVals[0], stackVals[0])
+	case code.DUP1:
+		end.pushValues(stackVals[1], stackVals[0], stackVals[1])
+	case code.DUP2:
+		end.pushValues(stackVals[2], stackVals[1], stackVals[0], stackVals[2])
+	case code.SWAP1:
+		end.pushValues(stackVals[0], stackVals[1])
+	case code.SWAP2:
+		end.pushValues(stackVals[0], stackVals[1], stackVals[2])
+	case code.TGET:
+		index, tup, ok := tupleArgs(stackVals[0], stackVals[1])
+		if ok {
+			item, _ := tup.GetByInt64(index)
+			end.pushHash(item.Hash())
+		}
+		correct = ok
+	case code.TSET:
+		index, tup, ok := tupleArgs(stackVals[0], stackVals[1])
+		if ok {
+			newTup, _ := tup.SetByInt64(index, stackVals[2])
+			end.pushHash(newTup.Hash())
+		}
+		correct = ok
+	case code.TLEN:
+		tup, ok := stackVals[0].(value.TupleValue)
+		if ok {
+			end.pushInt(big.NewInt(tup.Len()))
+		}
+		correct = ok
+	case code.BREAKPOINT:
+	case code.LOG:
+		messageHash = stackVals[0].Hash()
+		if accumulate(data.FirstLogHash, messageHash) != data.LastLogHash {
+			return errors.New("Logged value doesn't match output log")
+		}
+		if data.FirstMessageHash != data.LastMessageHash {
+			return errors.New("Send not called, but message is nonzero")
+		}
+	case code.SEND:
+		messageHash = stackVals[0].Hash()
+		if accumulate(data.FirstMessageHash, messageHash) != data.LastMessageHash {
+			return errors.New("sent message doesn't match output message")
+		}
+		if data.FirstLogHash != data.LastLogHash {
+			return errors.New("Log not called, but message is nonzero")
+		}
+	case code.GETTIME:
+		end.pushHash(data.TimeBounds.AsValue().Hash())
+	case code.INBOX:
+		// The contract only accepts the case where the machine would have
+		// blocked instead of reading the inbox, so proofs of an inbox
+		// instruction that actually ran are rejected
+		timeout, ok := stackVals[0].(value.IntValue)
+		if ok {
+			if data.TimeBounds.Start.AsInt().Cmp(timeout.BigInt()) >= 0 ||
+				data.BeforeInbox != value.NewEmptyTuple().Hash() {
+				return errors.New("Inbox instruction was blocked")
+			}
+			end.pushHash(data.BeforeInbox)
+		}
+		correct = ok
+	case code.ERROR:
+		correct = false
+	case code.HALT:
+		end.status = machine.Halt
+	}
+
+	if messageHash == (common.Hash{}) {
+		if data.FirstMessageHash != data.LastMessageHash {
+			return errors.New("Send not called, but message is nonzero")
+		}
+		if data.FirstLogHash != data.LastLogHash {
+			return errors.New("Log not called, but message is nonzero")
+		}
+	}
+
+	if !correct {
+		if end.errHandler == value.ErrorCodePoint.Hash() {
+			end.status = machine.ErrorStop
+		} else {
+			end.pc = end.errHandler
+		}
+	}
+
+	if data.BeforeHash != start.hash() {
+		return errors.New("Proof had non matching start state")
+	}
+	if data.AfterHash != end.hash() {
+		return errors.New("Proof had non matching end state")
+	}
+	return nil
+}
+
+// loadMachine reads the machine before and after the instruction from the
+// proof, with the values the instruction pops pushed back onto the stack of
+// the before machine. It also returns the popped values and a reader holding
+// the remainder of the proof.
+func loadMachine(proof []byte) (value.Opcode, []value.Value, *machineState, *machineState, io.Reader, error) {
+	rd := bytes.NewReader(proof)
+	start := &machineState{status: machine.Extensive}
+	for _, hash := range []*common.Hash{
+		&start.pc,
+		&start.stack,
+		&start.auxStack,
+		&start.register,
+		&start.static,
+		&start.errHandler,
+	} {
+		if _, err := io.ReadFull(rd, hash[:]); err != nil {
+			return 0, nil, nil, nil, nil, errors.New("Proof had bad machine")
+		}
+	}
+	end := start.clone()
+
+	var header [2]byte
+	if _, err := io.ReadFull(rd, header[:]); err != nil {
+		return 0, nil, nil, nil, nil, errors.New("Proof had bad operation type")
+	}
+	immediate := int(header[0])
+	op := value.Opcode(header[1])
+	popCount, ok := opPopCounts[op]
+	if !ok {
+		return 0, nil, nil, nil, nil, errors.New("Invalid opcode")
+	}
+	if immediate != 0 && immediate != 1 {
+		return 0, nil, nil, nil, nil, errors.New("Proof had bad operation type")
+	}
+	stackVals := make([]value.Value, popCount)
+	if immediate == 0 {
+		start.pc = value.CodePointValue{
+			Op:       value.BasicOperation{Op: op},
+			NextHash: start.pc,
+		}.Hash()
+	} else {
+		immediateVal, err := value.UnmarshalValueForProof(rd)
+		if err != nil {
+			return 0, nil, nil, nil, nil, errors.New("Proof had bad immediate value")
+		}
+		if popCount > 0 {
+			stackVals[0] = immediateVal
+		} else {
+			end.pushHash(immediateVal.Hash())
+		}
+		start.pc = value.CodePointValue{
+			Op:       value.ImmediateOperation{Op: op, Val: immediateVal},
+			NextHash: start.pc,
+		}.Hash()
+	}
+
+	for i := immediate; i < popCount; i++ {
+		val, err := value.UnmarshalValueForProof(rd)
+		if err != nil {
+			return 0, nil, nil, nil, nil, errors.New("Proof had bad stack value")
+		}
+		stackVals[i] = val
+	}
+	for i := popCount - 1; i >= immediate; i-- {
+		start.pushHash(stackVals[i].Hash())
+	}
+	return op, stackVals, start, end, rd, nil
+}
+
+func tupleArgs(indexVal, tupVal value.Value) (int64, value.TupleValue, bool) {
+	index, isInt := indexVal.(value.IntValue)
+	tup, isTuple := tupVal.(value.TupleValue)
+	if !isInt || !isTuple || index.BigInt().Cmp(big.NewInt(tup.Len())) >= 0 {
+		return 0, value.TupleValue{}, false
+	}
+	return index.BigInt().Int64(), tup, true
+}
+
+func accumulate(acc common.Hash, hash common.Hash) common.Hash {
+	return hashing.SoliditySHA3(hashing.Bytes32(acc), hashing.Bytes32(hash))
+}
+
+// opPopCounts holds the number of stack values popped by each instruction
+// supported by the contract
+var opPopCounts = map[value.Opcode]int{
+	code.ADD:           2,
+	code.MUL:           2,
+	code.SUB:           2,
+	code.DIV:           2,
+	code.SDIV:          2,
+	code.MOD:           2,
+	code.SMOD:          2,
+	code.ADDMOD:        3,
+	code.MULMOD:        3,
+	code.EXP:           2,
+	code.LT:            2,
+	code.GT:            2,
+	code.SLT:           2,
+	code.SGT:           2,
+	code.EQ:            2,
+	code.ISZERO:        1,
+	code.AND:           2,
+	code.OR:            2,
+	code.XOR:           2,
+	code.NOT:           1,
+	code.BYTE:          2,
+	code.SIGNEXTEND:    2,
+	code.SHA3:          1,
+	code.TYPE:          1,
+	code.ETHHASH2:      2,
+	code.POP:           1,
+	code.SPUSH:         0,
+	code.RPUSH:         0,
+	code.RSET:          1,
+	code.JUMP:          1,
+	code.CJUMP:         2,
+	code.STACKEMPTY:    0,
+	code.PCPUSH:        0,
+	code.AUXPUSH:       1,
+	code.AUXPOP:        0,
+	code.AUXSTACKEMPTY: 0,
+	code.NOP:           0,
+	code.ERRPUSH:       0,
+	code.ERRSET:        1,
+	code.DUP0:          1,
+	code.DUP1:          2,
+	code.DUP2:          3,
+	code.SWAP1:         2,
+	code.SWAP2:         3,
+	code.TGET:          2,
+	code.TSET:          3,
+	code.TLEN:          1,
+	code.BREAKPOINT:    0,
+	code.LOG:           1,
+	code.SEND:          1,
+	code.GETTIME:       0,
+	code.INBOX:         1,
+	code.ERROR:         0,
+	code.HALT:          0,
+}
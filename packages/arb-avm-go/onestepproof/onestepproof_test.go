@@ -0,0 +1,246 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package onestepproof
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+var testTimeBounds = &protocol.TimeBoundsBlocks{
+	Start: common.NewTimeBlocks(big.NewInt(10)),
+	End:   common.NewTimeBlocks(big.NewInt(20)),
+}
+
+func push(val value.Value) value.Operation {
+	return value.ImmediateOperation{Op: code.NOP, Val: val}
+}
+
+func pushInt(val int64) value.Operation {
+	return push(value.NewInt64Value(val))
+}
+
+func basic(op value.Opcode) value.Operation {
+	return value.BasicOperation{Op: op}
+}
+
+func tuple(t *testing.T, vals ...value.Value) value.TupleValue {
+	tup, err := value.NewTupleFromSlice(vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tup
+}
+
+// proveStep runs setup on a fresh machine and then returns the data needed
+// to check the proof of the step after it
+func proveStep(t *testing.T, setup []value.Operation, op value.Operation, inbox value.TupleValue) *ProofData {
+	insns := append(append([]value.Operation{}, setup...), op, basic(code.HALT))
+	m := vm.NewMachine(insns, value.NewInt64Value(5), false, 100)
+	if _, steps := m.ExecuteAssertion(uint64(len(setup)), testTimeBounds, value.NewEmptyTuple(), 0); steps != uint64(len(setup)) {
+		t.Fatalf("setup ran %v steps instead of %v", steps, len(setup))
+	}
+	beforeHash := m.Hash()
+	proof, err := m.MarshalForProof()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertion, steps := m.ExecuteAssertion(1, testTimeBounds, inbox, 0)
+	if steps != 1 {
+		t.Fatalf("%v ran %v steps", op, steps)
+	}
+	data := &ProofData{
+		BeforeHash:   beforeHash,
+		TimeBounds:   testTimeBounds,
+		BeforeInbox:  inbox.Hash(),
+		AfterHash:    assertion.AfterHash,
+		DidInboxInsn: assertion.DidInboxInsn,
+		NumGas:       assertion.NumGas,
+		Proof:        proof,
+	}
+	for _, msg := range assertion.OutMsgs {
+		data.LastMessageHash = accumulate(data.LastMessageHash, msg.Hash())
+	}
+	for _, logVal := range assertion.Logs {
+		data.LastLogHash = accumulate(data.LastLogHash, logVal.Hash())
+	}
+	return data
+}
+
+func checkStep(t *testing.T, name string, setup []value.Operation, op value.Operation) {
+	t.Helper()
+	if err := ValidateProof(proveStep(t, setup, op, value.NewEmptyTuple())); err != nil {
+		t.Errorf("%v: %v", name, err)
+	}
+}
+
+func TestValidateInstructions(t *testing.T) {
+	tup := tuple(t, value.NewInt64Value(1), tuple(t, value.NewInt64Value(2)), value.NewInt64Value(3))
+	errHandler := []value.Operation{basic(code.PCPUSH), basic(code.ERRSET)}
+	tests := []struct {
+		name  string
+		setup []value.Operation
+		op    value.Operation
+	}{
+		{"add immediate", []value.Operation{pushInt(3)}, value.ImmediateOperation{Op: code.ADD, Val: value.NewInt64Value(4)}},
+		{"push immediate", nil, push(tup)},
+		{"add tuple", []value.Operation{push(tup), pushInt(3)}, basic(code.ADD)},
+		{"div by zero", []value.Operation{pushInt(0), pushInt(3)}, basic(code.DIV)},
+		{"div by zero with handler", append(errHandler, pushInt(0), pushInt(3)), basic(code.DIV)},
+		{"addmod", []value.Operation{pushInt(5), pushInt(4), pushInt(3)}, basic(code.ADDMOD)},
+		{"mulmod by zero", []value.Operation{pushInt(0), pushInt(4), pushInt(3)}, basic(code.MULMOD)},
+		{"eq", []value.Operation{push(tup), push(tup)}, basic(code.EQ)},
+		{"iszero", []value.Operation{pushInt(0)}, basic(code.ISZERO)},
+		{"not", []value.Operation{pushInt(7)}, basic(code.NOT)},
+		{"sha3", []value.Operation{push(tup)}, basic(code.SHA3)},
+		{"type int", []value.Operation{pushInt(7)}, basic(code.TYPE)},
+		{"type tuple", []value.Operation{push(tup)}, basic(code.TYPE)},
+		{"type codepoint", []value.Operation{basic(code.PCPUSH)}, basic(code.TYPE)},
+		{"pop", []value.Operation{push(tup)}, basic(code.POP)},
+		{"spush", nil, basic(code.SPUSH)},
+		{"rpush", nil, basic(code.RPUSH)},
+		{"rset", []value.Operation{push(tup)}, basic(code.RSET)},
+		{"jump", []value.Operation{basic(code.PCPUSH)}, basic(code.JUMP)},
+		{"cjump taken", []value.Operation{pushInt(1), basic(code.PCPUSH)}, basic(code.CJUMP)},
+		{"cjump not taken", []value.Operation{pushInt(0), basic(code.PCPUSH)}, basic(code.CJUMP)},
+		{"cjump bad destination", []value.Operation{pushInt(1), pushInt(2)}, basic(code.CJUMP)},
+		{"stackempty", nil, basic(code.STACKEMPTY)},
+		{"stackempty with values", []value.Operation{pushInt(1)}, basic(code.STACKEMPTY)},
+		{"pcpush", nil, basic(code.PCPUSH)},
+		{"auxpush", []value.Operation{push(tup)}, basic(code.AUXPUSH)},
+		{"auxpop", []value.Operation{push(tup), basic(code.AUXPUSH)}, basic(code.AUXPOP)},
+		{"auxstackempty", nil, basic(code.AUXSTACKEMPTY)},
+		{"auxstackempty with values", []value.Operation{pushInt(1), basic(code.AUXPUSH)}, basic(code.AUXSTACKEMPTY)},
+		{"nop", nil, basic(code.NOP)},
+		{"errpush", errHandler, basic(code.ERRPUSH)},
+		{"errset", []value.Operation{basic(code.PCPUSH)}, basic(code.ERRSET)},
+		{"errset int", []value.Operation{pushInt(1)}, basic(code.ERRSET)},
+		{"dup0", []value.Operation{push(tup)}, basic(code.DUP0)},
+		{"dup1", []value.Operation{pushInt(1), pushInt(2)}, basic(code.DUP1)},
+		{"dup2", []value.Operation{pushInt(1), pushInt(2), pushInt(3)}, basic(code.DUP2)},
+		{"swap1", []value.Operation{pushInt(1), pushInt(2)}, basic(code.SWAP1)},
+		{"swap2", []value.Operation{pushInt(1), pushInt(2), pushInt(3)}, basic(code.SWAP2)},
+		{"tget", []value.Operation{push(tup), pushInt(1)}, basic(code.TGET)},
+		{"tget out of range", []value.Operation{push(tup), pushInt(3)}, basic(code.TGET)},
+		{"tset", []value.Operation{pushInt(9), push(tup), pushInt(1)}, basic(code.TSET)},
+		{"tset out of range", []value.Operation{pushInt(9), push(tup), pushInt(5)}, basic(code.TSET)},
+		{"tlen", []value.Operation{push(tup)}, basic(code.TLEN)},
+		{"tlen int", []value.Operation{pushInt(1)}, basic(code.TLEN)},
+		{"log", []value.Operation{push(tup)}, basic(code.LOG)},
+		{"send", []value.Operation{push(tup)}, basic(code.SEND)},
+		{"gettime", nil, basic(code.GETTIME)},
+		{"error", nil, basic(code.ERROR)},
+		{"error with handler", errHandler, basic(code.ERROR)},
+		{"halt", nil, basic(code.HALT)},
+	}
+	for _, test := range tests {
+		checkStep(t, test.name, test.setup, test.op)
+	}
+}
+
+func randomInt(rng *rand.Rand) *big.Int {
+	switch rng.Intn(4) {
+	case 0:
+		return big.NewInt(rng.Int63n(64))
+	case 1:
+		return math.U256(big.NewInt(-rng.Int63n(64)))
+	default:
+		return new(big.Int).Rand(rng, math.BigPow(2, 256))
+	}
+}
+
+func TestValidateIntInstructions(t *testing.T) {
+	ops := []value.Opcode{
+		code.ADD, code.MUL, code.SUB, code.DIV, code.SDIV, code.MOD,
+		code.SMOD, code.ADDMOD, code.MULMOD, code.EXP, code.LT, code.GT,
+		code.SLT, code.SGT, code.EQ, code.ISZERO, code.AND, code.OR,
+		code.XOR, code.NOT, code.BYTE, code.SIGNEXTEND, code.SHA3,
+		code.ETHHASH2,
+	}
+	rng := rand.New(rand.NewSource(0))
+	for _, op := range ops {
+		for i := 0; i < 20; i++ {
+			setup := make([]value.Operation, 0, opPopCounts[op])
+			for j := 0; j < opPopCounts[op]; j++ {
+				setup = append(setup, push(value.NewIntValue(randomInt(rng))))
+			}
+			checkStep(t, code.InstructionNames[op], setup, basic(op))
+		}
+	}
+}
+
+func TestSignedDivisionTruncates(t *testing.T) {
+	// -7/2 must round towards zero to -3 like the contract does
+	setup := []value.Operation{pushInt(2), push(value.NewIntValue(math.U256(big.NewInt(-7))))}
+	checkStep(t, "sdiv", setup, basic(code.SDIV))
+
+	m := vm.NewMachine(append(setup, basic(code.SDIV), basic(code.HALT)), value.NewInt64Value(5), false, 100)
+	m.ExecuteAssertion(3, testTimeBounds, value.NewEmptyTuple(), 0)
+	res, err := m.Stack().PopInt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.BigInt().Cmp(math.U256(big.NewInt(-3))) != 0 {
+		t.Error("-7/2 gave", math.S256(res.BigInt()))
+	}
+}
+
+func TestInboxProofRejected(t *testing.T) {
+	// The contract only accepts inbox proofs for a machine that would have
+	// blocked, which never counts as a step, so an inbox read can't be proven
+	inbox := tuple(t, value.NewEmptyTuple(), value.NewInt64Value(1))
+	data := proveStep(t, []value.Operation{pushInt(100)}, basic(code.INBOX), inbox)
+	if !data.DidInboxInsn {
+		t.Fatal("inbox instruction wasn't recorded")
+	}
+	if err := ValidateProof(data); err == nil {
+		t.Error("proof of an inbox read was accepted")
+	}
+}
+
+func TestInvalidProofs(t *testing.T) {
+	setup := []value.Operation{pushInt(3), pushInt(4)}
+	tests := []struct {
+		name   string
+		tamper func(*ProofData)
+		reason string
+	}{
+		{"gas", func(data *ProofData) { data.NumGas++ }, "Invalid gas in proof"},
+		{"inbox", func(data *ProofData) { data.DidInboxInsn = true }, "Invalid didInboxInsn claim"},
+		{"before", func(data *ProofData) { data.BeforeHash[0]++ }, "Proof had non matching start state"},
+		{"after", func(data *ProofData) { data.AfterHash[0]++ }, "Proof had non matching end state"},
+		{"log", func(data *ProofData) { data.LastLogHash[0]++ }, "Log not called, but message is nonzero"},
+		{"truncated", func(data *ProofData) { data.Proof = data.Proof[:len(data.Proof)-1] }, "Proof had bad stack value"},
+		{"opcode", func(data *ProofData) { data.Proof[6*32+1] = byte(code.DEBUG) }, "Invalid opcode"},
+	}
+	for _, test := range tests {
+		data := proveStep(t, setup, basic(code.ADD), value.NewEmptyTuple())
+		test.tamper(data)
+		if err := ValidateProof(data); err == nil || err.Error() != test.reason {
+			t.Errorf("%v: expected %q but got %v", test.name, test.reason, err)
+		}
+	}
+}
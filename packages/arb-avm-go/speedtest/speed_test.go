@@ -0,0 +1,108 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package speedtest runs the instruction benchmarks from arb-avm-cpp/speedtest
+// against the Go machine so the two can be compared directly
+package speedtest
+
+import (
+	"io/ioutil"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/goloader"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+const aoDir = "../../arb-avm-cpp/speedtest/aos/"
+
+func getInsnMultiplier(filePath string) uint64 {
+	ll := len(filePath)
+	numPops, err := strconv.Atoi(filePath[ll-4 : ll-3])
+	if err != nil {
+		log.Fatal(err)
+	}
+	numPushes, err := strconv.Atoi(filePath[ll-6 : ll-5])
+	if err != nil {
+		log.Fatal(err)
+	}
+	numExtraUnderscores := strings.Count(filePath, "_") - 2
+	return uint64(1 + numExtraUnderscores + numPops + numPushes)
+}
+
+func runAoFile(b *testing.B, filePath string) {
+	insnMultiplier := getInsnMultiplier(filePath)
+	mach, err := goloader.LoadMachineFromFile(filePath, false)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	unusedTimeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(0)),
+		End:   common.NewTimeBlocks(big.NewInt(0)),
+	}
+	b.ResetTimer()
+	_, _ = mach.ExecuteAssertion(uint64(b.N)*insnMultiplier, unusedTimeBounds, value.NewEmptyTuple(), time.Hour)
+}
+
+func nameFromFn(fn string) string {
+	ll := len(fn)
+	fnSlices := strings.Split(fn[:ll-7], "/")
+	ret := fnSlices[len(fnSlices)-1]
+	numPops, err := strconv.Atoi(fn[ll-4 : ll-3])
+	if err != nil {
+		log.Fatal(err)
+	}
+	numPushes, err := strconv.Atoi(fn[ll-6 : ll-5])
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i := 0; i < numPushes; i++ {
+		ret = "push_" + ret
+	}
+	for i := 0; i < numPops; i++ {
+		ret = ret + "_pop"
+	}
+	return ret
+}
+
+func BenchmarkInsns(b *testing.B) {
+	for _, fn := range getAos() {
+		b.Run(nameFromFn(fn), func(b *testing.B) {
+			runAoFile(b, fn)
+		})
+	}
+}
+
+func getAos() []string {
+	ret := []string{}
+	fileInfos, err := ioutil.ReadDir(aoDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() && strings.HasSuffix(fileInfo.Name(), ".ao") {
+			ret = append(ret, aoDir+fileInfo.Name())
+		}
+	}
+	return ret
+}
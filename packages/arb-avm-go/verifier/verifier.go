@@ -0,0 +1,532 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package verifier statically checks AVM programs for problems that would
+// otherwise only show up once the machine is running.
+//
+// Control flow is only followed through jumps whose target is an immediate
+// code point. Code points that appear anywhere else in the program are treated
+// as possible targets of dynamic jumps.
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+type Issue struct {
+	Severity Severity
+	InsnNum  int64
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%v at instruction %v: %v", i.Severity, i.InsnNum, i.Message)
+}
+
+// BasicBlock is a straight line run of instructions [Start, End) along with
+// the gas it uses if every instruction is executed
+type BasicBlock struct {
+	Start int64
+	End   int64
+	Gas   uint64
+}
+
+type Report struct {
+	Issues []Issue
+	Blocks []BasicBlock
+}
+
+func (r *Report) Errors() []Issue {
+	ret := make([]Issue, 0)
+	for _, issue := range r.Issues {
+		if issue.Severity == Error {
+			ret = append(ret, issue)
+		}
+	}
+	return ret
+}
+
+// Err returns an error describing the first problem that makes the program
+// invalid, or nil if there weren't any
+func (r *Report) Err() error {
+	errs := r.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("program failed verification with %v errors, first %v", len(errs), errs[0])
+}
+
+// MaxBlockGas returns the basic block with the highest gas cost
+func (r *Report) MaxBlockGas() BasicBlock {
+	var ret BasicBlock
+	for _, block := range r.Blocks {
+		if block.Gas > ret.Gas {
+			ret = block
+		}
+	}
+	return ret
+}
+
+type operandType int
+
+const (
+	anyOperand operandType = iota
+	intOperand
+	tupleOperand
+	codePointOperand
+)
+
+func (o operandType) matches(val value.Value) bool {
+	switch o {
+	case intOperand:
+		return val.TypeCode() == value.TypeCodeInt
+	case tupleOperand:
+		return val.TypeCode() == value.TypeCodeTuple
+	case codePointOperand:
+		return val.TypeCode() == value.TypeCodeCodePoint
+	default:
+		return true
+	}
+}
+
+func (o operandType) String() string {
+	switch o {
+	case intOperand:
+		return value.TypeCodeName(value.TypeCodeInt)
+	case tupleOperand:
+		return value.TypeCodeName(value.TypeCodeTuple)
+	case codePointOperand:
+		return value.TypeCodeName(value.TypeCodeCodePoint)
+	default:
+		return "any"
+	}
+}
+
+// stackEffect lists the types of the values an instruction pops in the order
+// it pops them, along with how many values it pushes
+type stackEffect struct {
+	pops   []operandType
+	pushes int
+}
+
+var (
+	noArgs       = []operandType{}
+	oneAny       = []operandType{anyOperand}
+	oneInt       = []operandType{intOperand}
+	twoInts      = []operandType{intOperand, intOperand}
+	threeInts    = []operandType{intOperand, intOperand, intOperand}
+	twoAny       = []operandType{anyOperand, anyOperand}
+	threeAny     = []operandType{anyOperand, anyOperand, anyOperand}
+	oneCodePoint = []operandType{codePointOperand}
+)
+
+var stackEffects = map[value.Opcode]stackEffect{
+	code.ADD:    {twoInts, 1},
+	code.MUL:    {twoInts, 1},
+	code.SUB:    {twoInts, 1},
+	code.DIV:    {twoInts, 1},
+	code.SDIV:   {twoInts, 1},
+	code.MOD:    {twoInts, 1},
+	code.SMOD:   {twoInts, 1},
+	code.ADDMOD: {threeInts, 1},
+	code.MULMOD: {threeInts, 1},
+	code.EXP:    {twoInts, 1},
+
+	code.LT:         {twoInts, 1},
+	code.GT:         {twoInts, 1},
+	code.SLT:        {twoInts, 1},
+	code.SGT:        {twoInts, 1},
+	code.EQ:         {twoAny, 1},
+	code.ISZERO:     {oneInt, 1},
+	code.AND:        {twoInts, 1},
+	code.OR:         {twoInts, 1},
+	code.XOR:        {twoInts, 1},
+	code.NOT:        {oneInt, 1},
+	code.BYTE:       {twoInts, 1},
+	code.SIGNEXTEND: {twoInts, 1},
+
+	code.SHA3:     {oneAny, 1},
+	code.TYPE:     {oneAny, 1},
+	code.ETHHASH2: {twoInts, 1},
+
+	code.POP:           {oneAny, 0},
+	code.SPUSH:         {noArgs, 1},
+	code.RPUSH:         {noArgs, 1},
+	code.RSET:          {oneAny, 0},
+	code.JUMP:          {oneCodePoint, 0},
+	code.CJUMP:         {[]operandType{codePointOperand, intOperand}, 0},
+	code.STACKEMPTY:    {noArgs, 1},
+	code.PCPUSH:        {noArgs, 1},
+	code.AUXPUSH:       {oneAny, 0},
+	code.AUXPOP:        {noArgs, 1},
+	code.AUXSTACKEMPTY: {noArgs, 1},
+	code.NOP:           {noArgs, 0},
+	code.ERRPUSH:       {noArgs, 1},
+	code.ERRSET:        {oneCodePoint, 0},
+
+	code.DUP0:  {oneAny, 2},
+	code.DUP1:  {twoAny, 3},
+	code.DUP2:  {threeAny, 4},
+	code.SWAP1: {twoAny, 2},
+	code.SWAP2: {threeAny, 3},
+
+	code.TGET: {[]operandType{intOperand, tupleOperand}, 1},
+	code.TSET: {[]operandType{intOperand, tupleOperand, anyOperand}, 1},
+	code.TLEN: {[]operandType{tupleOperand}, 1},
+
+	code.BREAKPOINT: {noArgs, 0},
+	code.LOG:        {oneAny, 0},
+
+	code.SEND:    {oneAny, 0},
+	code.GETTIME: {noArgs, 1},
+	code.INBOX:   {oneInt, 1},
+	code.ERROR:   {noArgs, 0},
+	code.HALT:    {noArgs, 0},
+	code.DEBUG:   {noArgs, 0},
+}
+
+// stackState is the stack depth at an instruction relative to the depth at
+// root, the instruction that the analysis reaching it started from
+type stackState struct {
+	root  int64
+	depth int
+	from  int64
+}
+
+type verifier struct {
+	insns      []value.Operation
+	codePoints []value.CodePointValue
+	report     *Report
+
+	// jumpTargets holds the target of each instruction that jumps to an
+	// immediate code point
+	jumpTargets map[int64]int64
+	// references holds the code points each instruction refers to other than
+	// its jump target
+	references map[int64][]int64
+	roots      []int64
+	leaders    []bool
+	reachable  []bool
+}
+
+// Verify checks the given program and estimates the gas used by each of its
+// basic blocks. Programs with issues of Error severity shouldn't be deployed.
+func Verify(insns []value.Operation, static value.Value) *Report {
+	prog := &aoasm.Program{Code: insns}
+	v := &verifier{
+		insns:       insns,
+		codePoints:  prog.CodePoints(),
+		report:      &Report{Issues: make([]Issue, 0), Blocks: make([]BasicBlock, 0)},
+		jumpTargets: make(map[int64]int64),
+		references:  make(map[int64][]int64),
+		roots:       []int64{0},
+		leaders:     make([]bool, len(insns)+1),
+		reachable:   make([]bool, len(insns)),
+	}
+	if len(insns) == 0 {
+		v.addIssue(Error, 0, "program has no instructions")
+		return v.report
+	}
+
+	for i, insn := range insns {
+		v.checkInstruction(int64(i), insn)
+	}
+	v.findCodePoints(static, func(target int64) {
+		v.roots = append(v.roots, target)
+	}, func(cp value.CodePointValue) {
+		v.checkForeignCodePoint(-1, cp)
+	})
+
+	v.findReachable()
+	v.checkStackDepths()
+	v.findBlocks()
+	return v.report
+}
+
+func (v *verifier) addIssue(severity Severity, insnNum int64, message string) {
+	v.report.Issues = append(v.report.Issues, Issue{
+		Severity: severity,
+		InsnNum:  insnNum,
+		Message:  message,
+	})
+}
+
+// programCodePoint returns the instruction that cp refers to if it is exactly
+// one of the program's code points
+func (v *verifier) programCodePoint(cp value.CodePointValue) (int64, bool) {
+	if cp.InsnNum < 0 || cp.InsnNum >= int64(len(v.codePoints)) {
+		return 0, false
+	}
+	if cp.Hash() != v.codePoints[cp.InsnNum].Hash() {
+		return 0, false
+	}
+	return cp.InsnNum, true
+}
+
+func (v *verifier) findCodePoints(val value.Value, found func(int64), invalid func(value.CodePointValue)) {
+	switch val := val.(type) {
+	case value.CodePointValue:
+		if target, ok := v.programCodePoint(val); ok {
+			found(target)
+		} else {
+			invalid(val)
+		}
+	case value.TupleValue:
+		for _, item := range val.Contents() {
+			v.findCodePoints(item, found, invalid)
+		}
+	}
+}
+
+// fallsThrough reports whether the instruction after op can be run next
+func fallsThrough(op value.Opcode) bool {
+	return op == code.CJUMP || !isTerminator(op)
+}
+
+func isTerminator(op value.Opcode) bool {
+	switch op {
+	case code.JUMP, code.CJUMP, code.HALT, code.ERROR:
+		return true
+	}
+	_, valid := code.InstructionNames[op]
+	return !valid
+}
+
+func (v *verifier) checkInstruction(insnNum int64, insn value.Operation) {
+	op := insn.GetOp()
+	effect, ok := stackEffects[op]
+	if !ok {
+		v.addIssue(Error, insnNum, fmt.Sprintf("invalid opcode 0x%x", byte(op)))
+		v.leaders[insnNum+1] = true
+		return
+	}
+	if isTerminator(op) {
+		v.leaders[insnNum+1] = true
+	}
+
+	imm, ok := insn.(value.ImmediateOperation)
+	if !ok {
+		return
+	}
+
+	if len(effect.pops) > 0 && !effect.pops[0].matches(imm.Val) {
+		v.addIssue(Error, insnNum, fmt.Sprintf(
+			"immediate value of %v must be a %v but is a %v",
+			code.InstructionNames[op],
+			effect.pops[0],
+			value.TypeCodeName(imm.Val.TypeCode()),
+		))
+		return
+	}
+
+	if cp, isCodePoint := imm.Val.(value.CodePointValue); isCodePoint && op != code.ERRSET && len(effect.pops) > 0 && effect.pops[0] == codePointOperand {
+		target, ok := v.programCodePoint(cp)
+		if !ok {
+			v.addIssue(Error, insnNum, fmt.Sprintf("%v target %v isn't a code point in the program", code.InstructionNames[op], cp.InsnNum))
+			return
+		}
+		v.jumpTargets[insnNum] = target
+		v.leaders[target] = true
+		return
+	}
+
+	v.findCodePoints(imm.Val, func(target int64) {
+		v.references[insnNum] = append(v.references[insnNum], target)
+		v.leaders[target] = true
+	}, func(cp value.CodePointValue) {
+		v.checkForeignCodePoint(insnNum, cp)
+	})
+}
+
+// checkForeignCodePoint warns about a code point that isn't in the program,
+// unless it's one of the placeholders compiled code uses for an unset error
+// handler
+func (v *verifier) checkForeignCodePoint(insnNum int64, cp value.CodePointValue) {
+	if cp.InsnNum < 0 || cp.Hash() == value.ErrorCodePoint.Hash() {
+		return
+	}
+	v.addIssue(Warning, insnNum, fmt.Sprintf("code point %v isn't in the program", cp.InsnNum))
+}
+
+// constantCondition returns the condition of a cjump at insnNum if it was
+// pushed as an immediate by the previous instruction
+func (v *verifier) constantCondition(insnNum int64) (bool, bool) {
+	if insnNum == 0 || v.leaders[insnNum] {
+		return false, false
+	}
+	prev, ok := v.insns[insnNum-1].(value.ImmediateOperation)
+	if !ok || prev.Op != code.NOP {
+		return false, false
+	}
+	cond, ok := prev.Val.(value.IntValue)
+	if !ok {
+		return false, false
+	}
+	return cond.BigInt().Sign() != 0, true
+}
+
+// successors returns the instructions that can be run directly after insnNum
+// without going through a dynamic jump
+func (v *verifier) successors(insnNum int64) []int64 {
+	op := v.insns[insnNum].GetOp()
+	ret := make([]int64, 0, 2)
+	jumps, continues := true, fallsThrough(op)
+	if op == code.CJUMP {
+		if cond, ok := v.constantCondition(insnNum); ok {
+			jumps, continues = cond, !cond
+		}
+	}
+	if target, ok := v.jumpTargets[insnNum]; ok && jumps {
+		ret = append(ret, target)
+	}
+	if continues {
+		if insnNum+1 < int64(len(v.insns)) {
+			ret = append(ret, insnNum+1)
+		}
+	}
+	return ret
+}
+
+func (v *verifier) findReachable() {
+	queue := make([]int64, 0, len(v.roots))
+	visit := func(insnNum int64) {
+		if !v.reachable[insnNum] {
+			v.reachable[insnNum] = true
+			queue = append(queue, insnNum)
+		}
+	}
+	for _, root := range v.roots {
+		if root < int64(len(v.insns)) {
+			visit(root)
+		}
+	}
+	for len(queue) > 0 {
+		insnNum := queue[0]
+		queue = queue[1:]
+		for _, next := range v.successors(insnNum) {
+			visit(next)
+		}
+		for _, ref := range v.references[insnNum] {
+			visit(ref)
+		}
+		if fallsThrough(v.insns[insnNum].GetOp()) && insnNum == int64(len(v.insns))-1 {
+			v.addIssue(Warning, insnNum, "execution can run past the end of the program")
+		}
+	}
+
+	for i := 0; i < len(v.insns); i++ {
+		if v.reachable[i] {
+			continue
+		}
+		start := i
+		for i+1 < len(v.insns) && !v.reachable[i+1] {
+			i++
+		}
+		if start == i {
+			v.addIssue(Warning, int64(start), "instruction is unreachable")
+		} else {
+			v.addIssue(Warning, int64(start), fmt.Sprintf("instructions %v to %v are unreachable", start, i))
+		}
+	}
+}
+
+// checkStackDepths follows the stack depth along conditional jumps and fall
+// through edges. Code points can only refer to later instructions so visiting
+// instructions in order sees every predecessor of an instruction before the
+// instruction.
+func (v *verifier) checkStackDepths() {
+	states := make([]*stackState, len(v.insns))
+	for i, insn := range v.insns {
+		insnNum := int64(i)
+		if !v.reachable[i] {
+			continue
+		}
+		state := states[i]
+		if state == nil {
+			// Only reached through a dynamic jump, so we know nothing about
+			// the stack coming in
+			state = &stackState{root: insnNum, from: insnNum}
+		}
+		effect, ok := stackEffects[insn.GetOp()]
+		if !ok {
+			continue
+		}
+		depth := state.depth - len(effect.pops) + effect.pushes
+		if _, ok := insn.(value.ImmediateOperation); ok {
+			depth++
+		}
+		for _, next := range v.successors(insnNum) {
+			if insn.GetOp() == code.JUMP {
+				// Compiled code enters functions with an unconditional jump
+				// from call sites that can have any depth, so the target
+				// is checked as if it were entered dynamically
+				continue
+			}
+			existing := states[next]
+			if existing == nil {
+				states[next] = &stackState{root: state.root, depth: depth, from: insnNum}
+				continue
+			}
+			if existing.root == state.root && existing.depth != depth {
+				v.addIssue(Error, next, fmt.Sprintf(
+					"stack depth mismatch, %v coming from instruction %v but %v coming from instruction %v",
+					existing.depth-state.depth,
+					existing.from,
+					depth-state.depth,
+					insnNum,
+				))
+			}
+		}
+	}
+}
+
+func (v *verifier) findBlocks() {
+	var block *BasicBlock
+	for i, insn := range v.insns {
+		if block == nil || v.leaders[i] {
+			if block != nil {
+				v.report.Blocks = append(v.report.Blocks, *block)
+			}
+			block = &BasicBlock{Start: int64(i)}
+		}
+		if op := insn.GetOp(); int(op) < len(vm.Instructions) {
+			block.Gas += vm.Instructions[op].GetGas()
+		}
+		block.End = int64(i) + 1
+	}
+	if block != nil {
+		v.report.Blocks = append(v.report.Blocks, *block)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package verifier
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+)
+
+func verifySource(t *testing.T, src string) *Report {
+	t.Helper()
+	prog, err := aoasm.Assemble(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Verify(prog.Code, prog.Static)
+}
+
+func TestVerifyValidProgram(t *testing.T) {
+	report := verifySource(t, `
+    stackempty
+    cjump @else
+    nop 2
+    jump @end
+else:
+    nop 5
+end:
+    halt
+`)
+	if err := report.Err(); err != nil {
+		t.Error(err)
+	}
+	if len(report.Blocks) != 4 {
+		t.Fatal("wrong number of blocks", report.Blocks)
+	}
+	if report.Blocks[0].Gas != 6 || report.Blocks[1].Gas != 5 || report.MaxBlockGas().Start != 5 {
+		t.Error("wrong block gas", report.Blocks)
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	cases := map[string]string{
+		"invalid opcode":     "0x7e\nhalt\n",
+		"jump to int":        "jump 5\nhalt\n",
+		"wrong immediate":    "tlen 5\nhalt\n",
+		"stack depth":        "stackempty\ncjump @merge\nnop 4\nmerge:\nhalt\n",
+		"static jump target": "jump CodePoint(1, halt, 0x0000000000000000000000000000000000000000000000000000000000000001)\nhalt\n",
+	}
+	for name, src := range cases {
+		if verifySource(t, src).Err() == nil {
+			t.Error("didn't catch", name)
+		}
+	}
+}
+
+func TestVerifyUnreachable(t *testing.T) {
+	report := verifySource(t, "jump @end\nadd\nadd\nend:\nhalt\n")
+	if report.Err() != nil {
+		t.Fatal(report.Err())
+	}
+	if len(report.Issues) != 1 || report.Issues[0].InsnNum != 1 {
+		t.Error("didn't warn about unreachable code", report.Issues)
+	}
+}
+
+func TestVerifyContract(t *testing.T) {
+	f, err := os.Open("../../arb-validator/contract.ao")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	prog, err := aoasm.ReadProgram(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(prog.Code, prog.Static).Err(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,513 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm/stack"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// wallTimeCheckInterval is the number of steps run between checks of the
+// clock when an assertion has a time limit
+const wallTimeCheckInterval = 1000
+
+// typeAny matches a stack value of any type in hasTypes
+const typeAny = 0xff
+
+// fastInsn runs an instruction directly on the flat data stack without
+// tracking StackMods. If the stack doesn't hold the arguments it expects it
+// returns false without modifying the machine, and the instruction is run by
+// RunInstruction instead so that stack errors are all handled in one place.
+// A returned error is raised by the instruction after its arguments were
+// popped.
+type fastInsn func(m *Machine, s *stack.Flat) (bool, error)
+
+// compiledInsn is an instruction resolved ahead of time into what the fast
+// interpreter loop needs to run it
+type compiledInsn struct {
+	op        value.Operation
+	immediate value.Value
+	impl      fastInsn
+	gas       uint64
+}
+
+var fastInsns = make(map[value.Opcode]fastInsn)
+
+func init() {
+	for op, intOp := range map[value.Opcode]func(value.IntValue) (value.IntValue, error){
+		code.ISZERO: intIszero,
+		code.NOT:    intNot,
+	} {
+		fastInsns[op] = fastUnaryIntOp(intOp)
+	}
+	for op, intOp := range map[value.Opcode]func(value.IntValue, value.IntValue) (value.IntValue, error){
+		code.ADD:        intAdd,
+		code.MUL:        intMul,
+		code.SUB:        intSub,
+		code.DIV:        intDiv,
+		code.SDIV:       intSdiv,
+		code.MOD:        intMod,
+		code.SMOD:       intSmod,
+		code.EXP:        intExp,
+		code.LT:         intLt,
+		code.GT:         intGt,
+		code.SLT:        intSlt,
+		code.SGT:        intSgt,
+		code.AND:        intAnd,
+		code.OR:         intOr,
+		code.XOR:        intXor,
+		code.BYTE:       intByte,
+		code.SIGNEXTEND: intSignextend,
+		code.ETHHASH2:   intEthhash2,
+	} {
+		fastInsns[op] = fastBinaryIntOp(intOp)
+	}
+	for op, intOp := range map[value.Opcode]func(value.IntValue, value.IntValue, value.IntValue) (value.IntValue, error){
+		code.ADDMOD: intAddmod,
+		code.MULMOD: intMulmod,
+	} {
+		fastInsns[op] = fastTrinaryIntOp(intOp)
+	}
+
+	fastInsns[code.EQ] = fastEq
+	fastInsns[code.SHA3] = fastHash
+	fastInsns[code.TYPE] = fastType
+	fastInsns[code.POP] = fastPop
+	fastInsns[code.SPUSH] = fastSpush
+	fastInsns[code.RPUSH] = fastRpush
+	fastInsns[code.RSET] = fastRset
+	fastInsns[code.JUMP] = fastJump
+	fastInsns[code.CJUMP] = fastCjump
+	fastInsns[code.STACKEMPTY] = fastStackempty
+	fastInsns[code.PCPUSH] = fastPcpush
+	fastInsns[code.AUXPUSH] = fastAuxpush
+	fastInsns[code.AUXPOP] = fastAuxpop
+	fastInsns[code.AUXSTACKEMPTY] = fastAuxStackempty
+	fastInsns[code.NOP] = fastNop
+	fastInsns[code.ERRPUSH] = fastErrPush
+	fastInsns[code.DUP0] = fastDup0
+	fastInsns[code.DUP1] = fastDup1
+	fastInsns[code.DUP2] = fastDup2
+	fastInsns[code.SWAP1] = fastSwap1
+	fastInsns[code.SWAP2] = fastSwap2
+	fastInsns[code.TGET] = fastTget
+	fastInsns[code.TSET] = fastTset
+	fastInsns[code.TLEN] = fastTlen
+	fastInsns[code.LOG] = fastLog
+	fastInsns[code.SEND] = fastSend
+}
+
+// compileProgram builds the dispatch table for a program. Instructions
+// without a fast implementation, including invalid opcodes, are left for
+// RunInstruction.
+func compileProgram(insns []value.Operation) []compiledInsn {
+	program := make([]compiledInsn, len(insns))
+	for i, op := range insns {
+		program[i].op = op
+		opcode := op.GetOp()
+		if _, ok := code.InstructionNames[opcode]; !ok {
+			continue
+		}
+		program[i].impl = fastInsns[opcode]
+		program[i].gas = Instructions[opcode].gas
+		if immediate, ok := op.(value.ImmediateOperation); ok {
+			program[i].immediate = immediate.Val
+		}
+	}
+	return program
+}
+
+// run executes up to maxSteps instructions, stopping early if the machine
+// halts, errors or blocks. It has the same effect as calling RunInstruction
+// for each step.
+func (m *Machine) run(ctx *MachineAssertionContext, maxSteps uint64, maxWallTime time.Duration) {
+	s, isFlat := m.stack.(*stack.Flat)
+	program := m.pc.program
+	hasTimeLimit := maxWallTime.Nanoseconds() != 0
+	deadline := time.Now().Add(maxWallTime)
+	untilTimeCheck := wallTimeCheckInterval
+	for ctx.numSteps < maxSteps {
+		if m.IsHalted() || m.IsErrored() || m.HaveSizeException() {
+			break
+		}
+		insn := &program[m.pc.pc]
+		if !isFlat || !m.runCompiled(insn, s) {
+			if _, blocked := RunInstruction(m, insn.op); blocked != nil {
+				break
+			}
+		}
+		if hasTimeLimit {
+			untilTimeCheck--
+			if untilTimeCheck == 0 {
+				if time.Now().After(deadline) {
+					break
+				}
+				untilTimeCheck = wallTimeCheckInterval
+			}
+		}
+	}
+}
+
+// runCompiled runs insn with its fast implementation, returning false if it
+// has none or its arguments aren't on the stack
+func (m *Machine) runCompiled(insn *compiledInsn, s *stack.Flat) bool {
+	if insn.impl == nil {
+		return false
+	}
+	if insn.immediate != nil {
+		s.Push(insn.immediate)
+	}
+	handled, err := insn.impl(m, s)
+	if !handled {
+		if insn.immediate != nil {
+			_, _ = s.Pop()
+		}
+		return false
+	}
+	m.notifyStep(insn.gas)
+	if err != nil {
+		m.handleError(err)
+	}
+	return true
+}
+
+// hasTypes checks that the values at the top of the stack have the given
+// types, starting with the top value
+func hasTypes(s *stack.Flat, types ...byte) bool {
+	for i, tipe := range types {
+		actual, ok := s.PeekType(i)
+		if !ok || (tipe != typeAny && actual != tipe) {
+			return false
+		}
+	}
+	return true
+}
+
+func fastUnaryIntOp(intOp func(value.IntValue) (value.IntValue, error)) fastInsn {
+	return func(m *Machine, s *stack.Flat) (bool, error) {
+		if !hasTypes(s, value.TypeCodeInt) {
+			return false, nil
+		}
+		x, _ := s.PopInt()
+		r, err := intOp(x)
+		if err != nil {
+			return true, err
+		}
+		s.PushInt(r)
+		m.IncrPC()
+		return true, nil
+	}
+}
+
+func fastBinaryIntOp(intOp func(value.IntValue, value.IntValue) (value.IntValue, error)) fastInsn {
+	return func(m *Machine, s *stack.Flat) (bool, error) {
+		if !hasTypes(s, value.TypeCodeInt, value.TypeCodeInt) {
+			return false, nil
+		}
+		x, _ := s.PopInt()
+		y, _ := s.PopInt()
+		r, err := intOp(x, y)
+		if err != nil {
+			return true, err
+		}
+		s.PushInt(r)
+		m.IncrPC()
+		return true, nil
+	}
+}
+
+func fastTrinaryIntOp(intOp func(value.IntValue, value.IntValue, value.IntValue) (value.IntValue, error)) fastInsn {
+	return func(m *Machine, s *stack.Flat) (bool, error) {
+		if !hasTypes(s, value.TypeCodeInt, value.TypeCodeInt, value.TypeCodeInt) {
+			return false, nil
+		}
+		x, _ := s.PopInt()
+		y, _ := s.PopInt()
+		z, _ := s.PopInt()
+		r, err := intOp(x, y, z)
+		if err != nil {
+			return true, err
+		}
+		s.PushInt(r)
+		m.IncrPC()
+		return true, nil
+	}
+}
+
+func fastEq(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	y, _ := s.Pop()
+	s.PushInt(value.NewBooleanValue(value.Eq(x, y)))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastHash(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	hashVal := x.Hash()
+	s.PushInt(value.NewIntValue(new(big.Int).SetBytes(hashVal[:])))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastType(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	s.PushInt(value.NewInt64Value(int64(x.TypeCode())))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastPop(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	_, _ = s.Pop()
+	m.IncrPC()
+	return true, nil
+}
+
+func fastSpush(m *Machine, s *stack.Flat) (bool, error) {
+	s.Push(m.static.Get())
+	m.IncrPC()
+	return true, nil
+}
+
+func fastRpush(m *Machine, s *stack.Flat) (bool, error) {
+	s.Push(m.register.Get())
+	m.IncrPC()
+	return true, nil
+}
+
+func fastRset(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	m.register.Set(x)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastJump(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	target, _ := s.Pop()
+	return true, m.SetPC(target)
+}
+
+func fastCjump(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, value.TypeCodeInt) {
+		return false, nil
+	}
+	target, _ := s.Pop()
+	cond, _ := s.PopInt()
+	if cond.BigInt().Sign() != 0 {
+		return true, m.SetPC(target)
+	}
+	m.IncrPC()
+	return true, nil
+}
+
+func fastStackempty(m *Machine, s *stack.Flat) (bool, error) {
+	s.PushInt(value.NewBooleanValue(s.IsEmpty()))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastPcpush(m *Machine, s *stack.Flat) (bool, error) {
+	s.PushCodePoint(m.pc.GetPC())
+	m.IncrPC()
+	return true, nil
+}
+
+func fastAuxpush(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	m.auxstack.Push(x)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastAuxpop(m *Machine, s *stack.Flat) (bool, error) {
+	var x value.Value = value.NewEmptyTuple()
+	if !m.auxstack.IsEmpty() {
+		x, _ = m.auxstack.Pop()
+	}
+	s.Push(x)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastAuxStackempty(m *Machine, s *stack.Flat) (bool, error) {
+	s.PushInt(value.NewBooleanValue(m.auxstack.IsEmpty()))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastNop(m *Machine, s *stack.Flat) (bool, error) {
+	m.IncrPC()
+	return true, nil
+}
+
+func fastErrPush(m *Machine, s *stack.Flat) (bool, error) {
+	s.PushCodePoint(m.errHandler)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastDup0(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	s.Push(x)
+	s.Push(x)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastDup1(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	y, _ := s.Pop()
+	s.Push(y)
+	s.Push(x)
+	s.Push(y)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastDup2(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, typeAny, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	y, _ := s.Pop()
+	z, _ := s.Pop()
+	s.Push(z)
+	s.Push(y)
+	s.Push(x)
+	s.Push(z)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastSwap1(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	y, _ := s.Pop()
+	s.Push(x)
+	s.Push(y)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastSwap2(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny, typeAny, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	y, _ := s.Pop()
+	z, _ := s.Pop()
+	s.Push(x)
+	s.Push(y)
+	s.Push(z)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastTget(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, value.TypeCodeInt, value.TypeCodeTuple) {
+		return false, nil
+	}
+	index, _ := s.PopInt()
+	tup, _ := s.PopTuple()
+	val, err := tup.Get(index)
+	if err != nil {
+		return true, fmt.Errorf("insn_tget: index %v out of range %v", index.BigInt(), tup.Len())
+	}
+	s.Push(val)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastTset(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, value.TypeCodeInt, value.TypeCodeTuple, typeAny) {
+		return false, nil
+	}
+	index, _ := s.PopInt()
+	tup, _ := s.PopTuple()
+	newVal, _ := s.Pop()
+	newTup, err := tup.Set(index, newVal)
+	if err != nil {
+		return true, fmt.Errorf("insn_tset: index %v out of range of tuple %v", index, tup)
+	}
+	s.PushTuple(newTup)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastTlen(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, value.TypeCodeTuple) {
+		return false, nil
+	}
+	tup, _ := s.PopTuple()
+	s.PushInt(value.NewInt64Value(tup.Len()))
+	m.IncrPC()
+	return true, nil
+}
+
+func fastLog(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	m.Log(x)
+	m.IncrPC()
+	return true, nil
+}
+
+func fastSend(m *Machine, s *stack.Flat) (bool, error) {
+	if !hasTypes(s, typeAny) {
+		return false, nil
+	}
+	x, _ := s.Pop()
+	m.Send(x)
+	m.IncrPC()
+	return true, nil
+}
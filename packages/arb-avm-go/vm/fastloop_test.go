@@ -0,0 +1,126 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func randomValue(rng *rand.Rand) value.Value {
+	switch rng.Intn(6) {
+	case 0:
+		return value.NewEmptyTuple()
+	case 1:
+		return value.NewTuple2(value.NewInt64Value(rng.Int63n(3)), value.NewEmptyTuple())
+	case 2:
+		return value.NewIntValue(math.U256(big.NewInt(-rng.Int63n(5))))
+	default:
+		return value.NewInt64Value(rng.Int63n(4))
+	}
+}
+
+func randomProgram(rng *rand.Rand, length int) []value.Operation {
+	var opcodes []value.Opcode
+	for op := range code.InstructionNames {
+		if op != code.DEBUG {
+			opcodes = append(opcodes, op)
+		}
+	}
+	insns := make([]value.Operation, 0, length)
+	for len(insns) < length {
+		op := opcodes[rng.Intn(len(opcodes))]
+		if op == code.HALT && rng.Intn(4) != 0 {
+			continue
+		}
+		switch rng.Intn(4) {
+		case 0:
+			insns = append(insns, value.ImmediateOperation{Op: op, Val: randomValue(rng)})
+		case 1:
+			// Keep a steady supply of arguments on the stack
+			insns = append(insns, value.ImmediateOperation{Op: code.NOP, Val: randomValue(rng)})
+		default:
+			insns = append(insns, value.BasicOperation{Op: op})
+		}
+	}
+	return append(insns, value.BasicOperation{Op: code.HALT})
+}
+
+// referenceStep runs a single step with RunInstruction the way
+// ExecuteAssertion did before the fast loop was added
+func referenceStep(m *Machine, timeBounds *protocol.TimeBoundsBlocks, inbox value.TupleValue) (*protocol.ExecutionAssertion, uint64) {
+	ctx := NewMachineAssertionContext(m, timeBounds, inbox)
+	RunInstruction(m, m.pc.GetCurrentInsn())
+	return ctx.Finalize(m)
+}
+
+func TestFastLoopMatchesRunInstruction(t *testing.T) {
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(2)),
+		End:   common.NewTimeBlocks(big.NewInt(5)),
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		insns := randomProgram(rng, 40)
+		fast := NewMachine(insns, value.NewInt64Value(7), false, 1000)
+		reference := NewMachine(insns, value.NewInt64Value(7), false, 1000)
+		inbox := value.NewEmptyTuple()
+		if rng.Intn(2) == 0 {
+			inbox = value.NewTuple2(value.NewEmptyTuple(), value.NewInt64Value(1))
+		}
+		for step := 0; step < 100; step++ {
+			fastAssertion, fastSteps := fast.ExecuteAssertion(1, timeBounds, inbox, 0)
+			refAssertion, refSteps := referenceStep(reference, timeBounds, inbox)
+			if fastSteps != refSteps || !fastAssertion.Equals(refAssertion) {
+				t.Fatalf("program %v step %v: assertion %v (%v steps) but expected %v (%v steps)", i, step, fastAssertion, fastSteps, refAssertion, refSteps)
+			}
+			if fast.GasUsed() != reference.GasUsed() {
+				t.Fatalf("program %v step %v: used %v gas but expected %v", i, step, fast.GasUsed(), reference.GasUsed())
+			}
+			if fastSteps == 0 {
+				break
+			}
+			if fastAssertion.DidInboxInsn {
+				inbox = value.NewEmptyTuple()
+			}
+		}
+	}
+}
+
+func TestFastLoopTimeLimit(t *testing.T) {
+	insns := []value.Operation{
+		value.BasicOperation{Op: code.PCPUSH},
+		value.BasicOperation{Op: code.JUMP},
+	}
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(0)),
+		End:   common.NewTimeBlocks(big.NewInt(0)),
+	}
+	_, steps := m.ExecuteAssertion(1<<62, timeBounds, value.NewEmptyTuple(), 1)
+	if steps == 0 || steps%wallTimeCheckInterval != 0 {
+		t.Error("expected the time limit to be checked every", wallTimeCheckInterval, "steps but ran", steps)
+	}
+}
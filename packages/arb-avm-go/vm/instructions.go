@@ -36,69 +36,74 @@ type Instruction struct {
 	gas  uint64
 }
 
-var allInsns = []Instruction{ // code, not necessarily in order
-	{code.ADD, insnAdd, 3},
-	{code.MUL, insnMul, 3},
-	{code.SUB, insnSub, 3},
-	{code.DIV, insnDiv, 4},
-	{code.SDIV, insnSdiv, 7},
-	{code.MOD, insnMod, 4},
-	{code.SMOD, insnSmod, 7},
-	{code.ADDMOD, insnAddmod, 4},
-	{code.MULMOD, insnMulmod, 4},
-	{code.EXP, insnExp, 25},
-
-	{code.LT, insnLt, 2},
-	{code.GT, insnGt, 2},
-	{code.SLT, insnSlt, 2},
-	{code.SGT, insnSgt, 2},
-	{code.EQ, insnEq, 2},
-	{code.ISZERO, insnIszero, 1},
-	{code.AND, insnAnd, 2},
-	{code.OR, insnOr, 2},
-	{code.XOR, insnXor, 2},
-	{code.NOT, insnNot, 1},
-	{code.BYTE, insnByte, 4},
-	{code.SIGNEXTEND, insnSignextend, 7},
-
-	{code.SHA3, insnHash, 7},
-	{code.TYPE, insnType, 3},
-	{code.ETHHASH2, insnEthhash2, 8},
-
-	{code.POP, insnPop, 1},
-	{code.SPUSH, insnSpush, 1},
-	{code.RPUSH, insnRpush, 1},
-	{code.RSET, insnRset, 2},
-	{code.JUMP, insnJump, 4},
-	{code.CJUMP, insnCjump, 4},
-	{code.STACKEMPTY, insnStackempty, 2},
-	{code.PCPUSH, insnPcpush, 1},
-	{code.AUXPUSH, insnAuxpush, 1},
-	{code.AUXPOP, insnAuxpop, 1},
-	{code.AUXSTACKEMPTY, insnAuxStackempty, 2},
-	{code.NOP, insnNop, 1},
-	{code.ERRPUSH, insnErrPush, 1},
-	{code.ERRSET, insnErrSet, 1},
-
-	{code.DUP0, insnDup0, 1},
-	{code.DUP1, insnDup1, 1},
-	{code.DUP2, insnDup2, 1},
-	{code.SWAP1, insnSwap1, 1},
-	{code.SWAP2, insnSwap2, 1},
-
-	{code.TGET, insnTget, 2},
-	{code.TSET, insnTset, 40},
-	{code.TLEN, insnTlen, 2},
-
-	{code.BREAKPOINT, insnBreakpoint, 100},
-	{code.LOG, insnLog, 100},
-
-	{code.SEND, insnSend, 100},
-	{code.GETTIME, insnGettime, 40},
-	{code.INBOX, insnInbox, 40},
-	{code.ERROR, insnError, 5},
-	{code.HALT, insnHalt, 10},
-	{code.DEBUG, insnDebug, 100},
+type instructionImpl struct {
+	code value.Opcode
+	impl func(*Machine) (StackMods, error)
+}
+
+var allInsns = []instructionImpl{ // code, not necessarily in order
+	{code.ADD, insnAdd},
+	{code.MUL, insnMul},
+	{code.SUB, insnSub},
+	{code.DIV, insnDiv},
+	{code.SDIV, insnSdiv},
+	{code.MOD, insnMod},
+	{code.SMOD, insnSmod},
+	{code.ADDMOD, insnAddmod},
+	{code.MULMOD, insnMulmod},
+	{code.EXP, insnExp},
+
+	{code.LT, insnLt},
+	{code.GT, insnGt},
+	{code.SLT, insnSlt},
+	{code.SGT, insnSgt},
+	{code.EQ, insnEq},
+	{code.ISZERO, insnIszero},
+	{code.AND, insnAnd},
+	{code.OR, insnOr},
+	{code.XOR, insnXor},
+	{code.NOT, insnNot},
+	{code.BYTE, insnByte},
+	{code.SIGNEXTEND, insnSignextend},
+
+	{code.SHA3, insnHash},
+	{code.TYPE, insnType},
+	{code.ETHHASH2, insnEthhash2},
+
+	{code.POP, insnPop},
+	{code.SPUSH, insnSpush},
+	{code.RPUSH, insnRpush},
+	{code.RSET, insnRset},
+	{code.JUMP, insnJump},
+	{code.CJUMP, insnCjump},
+	{code.STACKEMPTY, insnStackempty},
+	{code.PCPUSH, insnPcpush},
+	{code.AUXPUSH, insnAuxpush},
+	{code.AUXPOP, insnAuxpop},
+	{code.AUXSTACKEMPTY, insnAuxStackempty},
+	{code.NOP, insnNop},
+	{code.ERRPUSH, insnErrPush},
+	{code.ERRSET, insnErrSet},
+
+	{code.DUP0, insnDup0},
+	{code.DUP1, insnDup1},
+	{code.DUP2, insnDup2},
+	{code.SWAP1, insnSwap1},
+	{code.SWAP2, insnSwap2},
+
+	{code.TGET, insnTget},
+	{code.TSET, insnTset},
+	{code.TLEN, insnTlen},
+
+	{code.BREAKPOINT, insnBreakpoint},
+	{code.LOG, insnLog},
+
+	{code.SEND, insnSend},
+	{code.GETTIME, insnGettime},
+	{code.INBOX, insnInbox},
+	{code.ERROR, insnError},
+	{code.HALT, insnHalt},
+	{code.DEBUG, insnDebug},
 }
 
 var (
@@ -113,7 +118,11 @@ var Instructions = []Instruction(nil)
 func init() {
 	Instructions = make([]Instruction, code.MaxOpcode)
 	for _, ins := range allInsns {
-		Instructions[ins.code] = ins
+		gas, ok := code.GasSchedule[ins.code]
+		if !ok {
+			log.Fatalf("instruction %v is missing from the gas schedule", code.InstructionNames[ins.code])
+		}
+		Instructions[ins.code] = Instruction{ins.code, ins.impl, gas}
 	}
 }
 
@@ -149,7 +158,7 @@ func RunInstruction(m *Machine, op value.Operation) (StackMods, machine.BlockRea
 	}()
 
 	if err == nil {
-		m.context.NotifyStep(gas)
+		m.notifyStep(gas)
 		return mods, nil
 	}
 
@@ -159,14 +168,13 @@ func RunInstruction(m *Machine, op value.Operation) (StackMods, machine.BlockRea
 		}
 		return mods, blocked.reason
 	}
-	m.context.NotifyStep(gas)
+	m.notifyStep(gas)
 
 	//fmt.Printf("error running instruction %v: %v\n", code.InstructionNames[op.GetOp()], err)
 
 	// in case of any errors from operation
 	// pop remaining stack values and set
 	// PC to errHandler
-	m.Warn(err.Error())
 	for mods.popsRemaining > 0 {
 		var poperr error
 		_, mods, poperr = PopStackBox(m, mods)
@@ -174,15 +182,22 @@ func RunInstruction(m *Machine, op value.Operation) (StackMods, machine.BlockRea
 			break
 		}
 	}
+	m.handleError(err)
+	return mods, nil
+}
 
-	// Clear the error by jumping to the error handler
-	if !m.errHandler.Equal(value.ErrorCodePoint) {
+// handleError clears an error raised by an instruction by jumping to the
+// error handler, or error stops the machine if no handler is set
+func (m *Machine) handleError(err error) {
+	m.Warn(err.Error())
+	// The handler is compared by hash since Equal only compares instruction
+	// numbers and a handler at the first instruction would look unset.
+	if m.errHandler.Hash() != value.ErrorCodePoint.Hash() {
 		err = m.pc.SetPCForced(m.errHandler)
 	}
 	if err != nil {
 		m.ErrorStop()
 	}
-	return mods, nil
 }
 
 func (insn Instruction) GetName() string {
@@ -193,6 +208,10 @@ func (insn Instruction) GetCode() value.Opcode {
 	return insn.code
 }
 
+func (insn Instruction) GetGas() uint64 {
+	return insn.gas
+}
+
 const MaxStackPops = 3
 const MaxAuxStackPops = 1
 
@@ -396,27 +415,30 @@ func insnHalt(state *Machine) (StackMods, error) {
 // BEGIN STUB OPS
 
 func insnAdd(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Add(x.BigInt(), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intAdd)
+}
+
+func intAdd(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Add(x.BigInt(), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnMul(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Mul(x.BigInt(), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intMul)
+}
+
+func intMul(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Mul(x.BigInt(), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnSub(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Sub(new(big.Int).Add(x.BigInt(), tt256), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intSub)
+}
+
+func intSub(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Sub(new(big.Int).Add(x.BigInt(), tt256), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 type DivideByZeroError struct {
@@ -427,193 +449,211 @@ func (w DivideByZeroError) Error() string {
 }
 
 func insnDiv(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			yBig := y.BigInt()
-			if yBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := new(big.Int).Div(x.BigInt(), yBig)
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intDiv)
+}
+
+func intDiv(x, y value.IntValue) (value.IntValue, error) {
+	yBig := y.BigInt()
+	if yBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := new(big.Int).Div(x.BigInt(), yBig)
+	return value.NewIntValue(ret), nil
 }
 
 func insnSdiv(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			yBig := y.BigInt()
-			if yBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := math.U256(new(big.Int).Div(math.S256(x.BigInt()), math.S256(yBig)))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intSdiv)
+}
+
+func intSdiv(x, y value.IntValue) (value.IntValue, error) {
+	yBig := y.BigInt()
+	if yBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := math.U256(new(big.Int).Quo(math.S256(x.BigInt()), math.S256(yBig)))
+	return value.NewIntValue(ret), nil
 }
 
 func insnMod(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			yBig := y.BigInt()
-			if yBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := new(big.Int).Mod(x.BigInt(), yBig)
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intMod)
+}
+
+func intMod(x, y value.IntValue) (value.IntValue, error) {
+	yBig := y.BigInt()
+	if yBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := new(big.Int).Mod(x.BigInt(), yBig)
+	return value.NewIntValue(ret), nil
 }
 
 func insnSmod(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			xBig := math.S256(x.BigInt())
-			yBig := math.S256(y.BigInt())
-			if yBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := new(big.Int).Mul(big.NewInt(int64(xBig.Sign())), new(big.Int).Mod(new(big.Int).Abs(xBig), new(big.Int).Abs(yBig)))
-			return value.NewIntValue(math.U256(ret)), nil
-		})
+	return binaryIntOp(state, intSmod)
+}
+
+func intSmod(x, y value.IntValue) (value.IntValue, error) {
+	xBig := math.S256(x.BigInt())
+	yBig := math.S256(y.BigInt())
+	if yBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := new(big.Int).Mul(big.NewInt(int64(xBig.Sign())), new(big.Int).Mod(new(big.Int).Abs(xBig), new(big.Int).Abs(yBig)))
+	return value.NewIntValue(math.U256(ret)), nil
 }
 
 func insnAddmod(state *Machine) (StackMods, error) {
-	return trinaryIntOp(state,
-		func(x, y, z value.IntValue) (value.IntValue, error) {
-			zBig := z.BigInt()
-			if zBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := math.U256(new(big.Int).Mod(new(big.Int).Add(x.BigInt(), y.BigInt()), zBig))
-			return value.NewIntValue(ret), nil
-		})
+	return trinaryIntOp(state, intAddmod)
+}
+
+func intAddmod(x, y, z value.IntValue) (value.IntValue, error) {
+	zBig := z.BigInt()
+	if zBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := math.U256(new(big.Int).Mod(new(big.Int).Add(x.BigInt(), y.BigInt()), zBig))
+	return value.NewIntValue(ret), nil
 }
 
 func insnMulmod(state *Machine) (StackMods, error) {
-	return trinaryIntOp(state,
-		func(x, y, z value.IntValue) (value.IntValue, error) {
-			zBig := z.BigInt()
-			if zBig.Sign() == 0 {
-				return value.IntegerZero, DivideByZeroError{}
-			}
-			ret := math.U256(new(big.Int).Mod(new(big.Int).Mul(x.BigInt(), y.BigInt()), zBig))
-			return value.NewIntValue(ret), nil
-		})
+	return trinaryIntOp(state, intMulmod)
+}
+
+func intMulmod(x, y, z value.IntValue) (value.IntValue, error) {
+	zBig := z.BigInt()
+	if zBig.Sign() == 0 {
+		return value.IntegerZero, DivideByZeroError{}
+	}
+	ret := math.U256(new(big.Int).Mod(new(big.Int).Mul(x.BigInt(), y.BigInt()), zBig))
+	return value.NewIntValue(ret), nil
 }
 
 func insnExp(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(base, exponent value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Exp(base.BigInt(), exponent.BigInt(), tt256))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intExp)
+}
+
+func intExp(base, exponent value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Exp(base.BigInt(), exponent.BigInt(), tt256))
+	return value.NewIntValue(ret), nil
 }
 
 func insnSignextend(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(num, back value.IntValue) (value.IntValue, error) {
-			bBig := back.BigInt()
-			if !bBig.IsInt64() {
-				return num, nil
-			}
-			b64 := bBig.Int64()
-			if b64 > 31 {
-				return num, nil
-			}
-			t := 248 - 8*b64
-			numBi := num.BigInt()
-			signBit := numBi.Bit(int(255 - t))
-			mask := new(big.Int).Sub(math.BigPow(2, 255-t), big.NewInt(1))
-			var ret *big.Int
-			if signBit == 0 {
-				ret = new(big.Int).And(num.BigInt(), mask)
-			} else {
-				mask = new(big.Int).Xor(tt256m1, mask)
-				ret = new(big.Int).Or(num.BigInt(), mask)
-			}
-			return value.NewIntValue(math.U256(ret)), nil
-		})
+	return binaryIntOp(state, intSignextend)
+}
+
+func intSignextend(num, back value.IntValue) (value.IntValue, error) {
+	bBig := back.BigInt()
+	if !bBig.IsInt64() {
+		return num, nil
+	}
+	b64 := bBig.Int64()
+	if b64 > 31 {
+		return num, nil
+	}
+	t := 248 - 8*b64
+	numBi := num.BigInt()
+	signBit := numBi.Bit(int(255 - t))
+	mask := new(big.Int).Sub(math.BigPow(2, 255-t), big.NewInt(1))
+	var ret *big.Int
+	if signBit == 0 {
+		ret = new(big.Int).And(num.BigInt(), mask)
+	} else {
+		mask = new(big.Int).Xor(tt256m1, mask)
+		ret = new(big.Int).Or(num.BigInt(), mask)
+	}
+	return value.NewIntValue(math.U256(ret)), nil
 }
 
 func insnLt(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			return value.NewBooleanValue(x.BigInt().Cmp(y.BigInt()) == -1), nil
-		})
+	return binaryIntOp(state, intLt)
+}
+
+func intLt(x, y value.IntValue) (value.IntValue, error) {
+	return value.NewBooleanValue(x.BigInt().Cmp(y.BigInt()) == -1), nil
 }
 
 func insnGt(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			return value.NewBooleanValue(x.BigInt().Cmp(y.BigInt()) == 1), nil
-		})
+	return binaryIntOp(state, intGt)
+}
+
+func intGt(x, y value.IntValue) (value.IntValue, error) {
+	return value.NewBooleanValue(x.BigInt().Cmp(y.BigInt()) == 1), nil
 }
 
 func insnSlt(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			return value.NewBooleanValue(math.S256(x.BigInt()).Cmp(math.S256(y.BigInt())) == -1), nil
-		})
+	return binaryIntOp(state, intSlt)
+}
+
+func intSlt(x, y value.IntValue) (value.IntValue, error) {
+	return value.NewBooleanValue(math.S256(x.BigInt()).Cmp(math.S256(y.BigInt())) == -1), nil
 }
 
 func insnSgt(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			return value.NewBooleanValue(math.S256(x.BigInt()).Cmp(math.S256(y.BigInt())) == 1), nil
-		})
+	return binaryIntOp(state, intSgt)
+}
+
+func intSgt(x, y value.IntValue) (value.IntValue, error) {
+	return value.NewBooleanValue(math.S256(x.BigInt()).Cmp(math.S256(y.BigInt())) == 1), nil
 }
 
 func insnIszero(state *Machine) (StackMods, error) {
-	return unaryIntOp(state,
-		func(x value.IntValue) (value.IntValue, error) {
-			return value.NewBooleanValue(x.BigInt().Sign() == 0), nil
-		})
+	return unaryIntOp(state, intIszero)
+}
+
+func intIszero(x value.IntValue) (value.IntValue, error) {
+	return value.NewBooleanValue(x.BigInt().Sign() == 0), nil
 }
 
 func insnAnd(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).And(x.BigInt(), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intAnd)
+}
+
+func intAnd(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).And(x.BigInt(), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnOr(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Or(x.BigInt(), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intOr)
+}
+
+func intOr(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Or(x.BigInt(), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnXor(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(x, y value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Xor(x.BigInt(), y.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return binaryIntOp(state, intXor)
+}
+
+func intXor(x, y value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Xor(x.BigInt(), y.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnNot(state *Machine) (StackMods, error) {
-	return unaryIntOp(state,
-		func(x value.IntValue) (value.IntValue, error) {
-			ret := math.U256(new(big.Int).Not(x.BigInt()))
-			return value.NewIntValue(ret), nil
-		})
+	return unaryIntOp(state, intNot)
+}
+
+func intNot(x value.IntValue) (value.IntValue, error) {
+	ret := math.U256(new(big.Int).Not(x.BigInt()))
+	return value.NewIntValue(ret), nil
 }
 
 func insnByte(state *Machine) (StackMods, error) {
-	return binaryIntOp(state,
-		func(val, th value.IntValue) (value.IntValue, error) {
-			thBig := th.BigInt()
-			if !thBig.IsUint64() {
-				return value.IntegerZero, nil
-			}
-			th64 := thBig.Uint64()
-			if th64 >= 32 {
-				return value.IntegerZero, nil
-			}
-			ret := math.Byte(val.BigInt(), value.BytesPerInt, int(th64))
-			return value.NewInt64Value(int64(ret)), nil
-		})
+	return binaryIntOp(state, intByte)
+}
+
+func intByte(val, th value.IntValue) (value.IntValue, error) {
+	thBig := th.BigInt()
+	if !thBig.IsUint64() {
+		return value.IntegerZero, nil
+	}
+	th64 := thBig.Uint64()
+	if th64 >= 32 {
+		return value.IntegerZero, nil
+	}
+	ret := math.Byte(val.BigInt(), value.BytesPerInt, int(th64))
+	return value.NewInt64Value(int64(ret)), nil
 }
 
 // END STUB OPS
@@ -648,25 +688,15 @@ func insnHash(state *Machine) (StackMods, error) {
 }
 
 func insnEthhash2(state *Machine) (StackMods, error) {
-	mods := NewStackMods(2, 1)
-	arg1, mods, err := PopStackInt(state, mods)
-	if err != nil {
-		return mods, err
-	}
-	arg2, mods, err := PopStackInt(state, mods)
-	if err != nil {
-		return mods, err
-	}
+	return binaryIntOp(state, intEthhash2)
+}
+
+func intEthhash2(x, y value.IntValue) (value.IntValue, error) {
 	hashAsBytes := hashing.SoliditySHA3(
-		hashing.Uint256(arg1.BigInt()),
-		hashing.Uint256(arg2.BigInt()),
+		hashing.Uint256(x.BigInt()),
+		hashing.Uint256(y.BigInt()),
 	)
-	hashAsBigInt := new(big.Int).SetBytes(hashAsBytes[:])
-	fmt.Println(hashAsBigInt.Text(10))
-	hashAsInt := value.NewIntValue(hashAsBigInt)
-	mods = PushStackInt(state, mods, hashAsInt)
-	state.IncrPC()
-	return mods, nil
+	return value.NewIntValue(new(big.Int).SetBytes(hashAsBytes[:])), nil
 }
 
 func insnPop(state *Machine) (StackMods, error) {
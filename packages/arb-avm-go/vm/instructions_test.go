@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/math"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/debuginfo"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
@@ -51,6 +52,33 @@ func TestMachineAdd(t *testing.T) {
 	m.ExecuteAssertion(80000, tb, protocol.NewMessageStack().GetValue(), 0)
 }
 
+func TestErrorLocation(t *testing.T) {
+	insns := []value.Operation{
+		value.BasicOperation{Op: code.NOP},
+		value.BasicOperation{Op: code.NOP},
+		value.BasicOperation{Op: code.ERROR},
+		value.BasicOperation{Op: code.HALT},
+	}
+
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	m.SetDebugInfo(&debuginfo.DebugInfo{
+		Files:     []string{"contract.sol"},
+		Functions: []debuginfo.Function{{Start: 0, Name: "transfer"}},
+		Locations: []debuginfo.Location{{Start: 2, File: 0, Line: 123}},
+	})
+	tb := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(0)),
+		End:   common.NewTimeBlocks(big.NewInt(100000)),
+	}
+	m.ExecuteAssertion(80000, tb, protocol.NewMessageStack().GetValue(), 0)
+	if !m.IsErrored() {
+		t.Fatal("machine should have error stopped")
+	}
+	if loc := m.ErrorLocation(); loc != "contract.sol:123 in transfer()" {
+		t.Error("wrong error location", loc)
+	}
+}
+
 func runInstOpNoFault(m *Machine, oper value.Operation) (bool, string) {
 	if _, blockReason := RunInstruction(m, oper); blockReason != nil {
 		return false, fmt.Sprintf("RunInstruction blocked: %#v", blockReason)
@@ -254,6 +282,27 @@ func TestSDiv(t *testing.T) {
 	if !res {
 		t.Error(err)
 	}
+	// test -7/2=-3
+	res, err = binaryIntOpTest(math.U256(big.NewInt(-7)), big.NewInt(2), math.U256(big.NewInt(-3)), code.SDIV)
+	if !res {
+		t.Error(err)
+	}
+	// test 7/-2=-3
+	res, err = binaryIntOpTest(big.NewInt(7), math.U256(big.NewInt(-2)), math.U256(big.NewInt(-3)), code.SDIV)
+	if !res {
+		t.Error(err)
+	}
+	// test -7/-2=3
+	res, err = binaryIntOpTest(math.U256(big.NewInt(-7)), math.U256(big.NewInt(-2)), big.NewInt(3), code.SDIV)
+	if !res {
+		t.Error(err)
+	}
+	// test min/-1=min
+	minInt := math.U256(new(big.Int).Neg(math.BigPow(2, 255)))
+	res, err = binaryIntOpTest(minInt, math.U256(big.NewInt(-1)), minInt, code.SDIV)
+	if !res {
+		t.Error(err)
+	}
 	// test 6/0=0
 	res, err = binaryIntOpTest(big.NewInt(6), big.NewInt(0), big.NewInt(0), code.SDIV)
 	if res {
@@ -1201,6 +1250,33 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestErrorHandlerAtFirstInstruction(t *testing.T) {
+	insns := []value.Operation{
+		value.BasicOperation{Op: code.NOP},
+		value.BasicOperation{Op: code.ERROR},
+		value.BasicOperation{Op: code.HALT},
+	}
+
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	handler := m.pc.GetPC()
+	if handler.InsnNum != 0 {
+		t.Fatalf("expected handler at instruction 0 but got %v", handler.InsnNum)
+	}
+	m.Stack().Push(handler)
+	if succeeded, reason := runInstNoFault(m, code.ERRSET); !succeeded {
+		t.Fatal(reason)
+	}
+
+	// Like the C++ AVM, a handler is only unset if it's the error code
+	// point itself, so erroring jumps back to the first instruction
+	if succeeded, reason := runInstNoFault(m, code.ERROR); !succeeded {
+		t.Fatal(reason)
+	}
+	if pc := m.pc.GetPC(); pc.InsnNum != 0 {
+		t.Errorf("expected jump to instruction 0 but pc is %v", pc.InsnNum)
+	}
+}
+
 func TestDup0(t *testing.T) {
 	// test
 	insns := []value.Operation{value.BasicOperation{Op: code.NOP}, value.BasicOperation{Op: code.HALT}}
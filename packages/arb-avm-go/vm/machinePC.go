@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/debuginfo"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 )
@@ -34,7 +35,9 @@ type MachinePC struct {
 	warn        WarningHandler
 	flat        []value.Operation
 	savedValues []value.CodePointValue
+	program     []compiledInsn
 	pc          int64 // -1 if machine has halted, otherwise index into code
+	debug       *debuginfo.DebugInfo
 }
 
 func NewMachinePC(insns []value.Operation, handler WarningHandler) *MachinePC {
@@ -51,7 +54,7 @@ func NewMachinePC(insns []value.Operation, handler WarningHandler) *MachinePC {
 			savedValues[i/CodeSaveFrequency] = codePoint
 		}
 	}
-	return &MachinePC{handler, flat, savedValues, 0}
+	return &MachinePC{handler, flat, savedValues, compileProgram(flat), 0, nil}
 }
 
 func (m *MachinePC) Equal(y *MachinePC) (bool, string) {
@@ -86,6 +89,12 @@ func (m MachinePC) GetCurrentInsnName() string {
 	return code.InstructionNames[m.flat[m.pc].GetOp()]
 }
 
+// Location describes the current pc, using the source location from the
+// program's debug info if it has any
+func (m MachinePC) Location() string {
+	return m.debug.Describe(m.pc)
+}
+
 func (m MachinePC) GetPC() value.CodePointValue {
 	if m.pc >= int64(len(m.flat)) || m.pc < 0 {
 		panic(fmt.Sprintf("Invalid pc: %v", m.pc))
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/debuginfo"
 	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm/stack"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
@@ -46,6 +47,12 @@ type Machine struct {
 	sizeException bool
 
 	warnHandler WarningHandler
+
+	// errorLocation describes where the machine was when it error stopped
+	errorLocation string
+
+	// gasUsed is the total arbgas charged since the machine was created
+	gasUsed uint64
 }
 
 func (m *Machine) Checkpoint(storage machine.CheckpointStorage) bool {
@@ -79,6 +86,36 @@ func Equal(x, y *Machine) (bool, string) {
 	return true, ""
 }
 
+// Diff returns every difference between the states of two machines, unlike
+// Equal which stops at the first one
+func Diff(x, y *Machine) []value.Difference {
+	xs, _ := x.Snapshot()
+	ys, _ := y.Snapshot()
+	return machine.DiffSnapshots(xs, ys)
+}
+
+func stackItems(s stack.Stack) []value.Value {
+	c := s.Clone()
+	items := make([]value.Value, 0, c.Count())
+	for !c.IsEmpty() {
+		val, _ := c.Pop()
+		items = append(items, val)
+	}
+	return items
+}
+
+func (m *Machine) Snapshot() (*machine.Snapshot, error) {
+	return &machine.Snapshot{
+		Status:     m.status,
+		PC:         m.pc.GetPC(),
+		ErrHandler: m.errHandler,
+		Register:   m.register.Get(),
+		Static:     m.static.Get(),
+		Stack:      machine.NewStackSnapshot(stackItems(m.stack)),
+		AuxStack:   machine.NewStackSnapshot(stackItems(m.auxstack)),
+	}, nil
+}
+
 func NewMachine(opCodes []value.Operation, staticVal value.Value, warn bool, sizeLimit int64) *Machine {
 	datastack := stack.NewEmptyFlat()
 	auxstack := stack.NewEmptyFlat()
@@ -107,6 +144,8 @@ func NewMachine(opCodes []value.Operation, staticVal value.Value, warn bool, siz
 		sizeLimit,
 		false,
 		wh,
+		"",
+		0,
 	}
 	ret.checkSize()
 	return ret
@@ -160,7 +199,7 @@ func (m *Machine) IncrPC() {
 	if !m.HaveSizeException() {
 		err := m.pc.IncrPC()
 		if err != nil {
-			m.status = machine.ErrorStop
+			m.ErrorStop()
 		}
 	}
 }
@@ -196,6 +235,26 @@ func (m *Machine) Halt() {
 
 func (m *Machine) ErrorStop() {
 	m.status = machine.ErrorStop
+	m.errorLocation = m.pc.Location()
+}
+
+// ErrorLocation describes where the machine error stopped, or returns an
+// empty string if it hasn't
+func (m *Machine) ErrorLocation() string {
+	if m.status != machine.ErrorStop {
+		return ""
+	}
+	return m.errorLocation
+}
+
+// SetDebugInfo attaches source mapping for the machine's code which is then
+// used when reporting warnings and errors
+func (m *Machine) SetDebugInfo(info *debuginfo.DebugInfo) {
+	m.pc.debug = info
+}
+
+func (m *Machine) DebugInfo() *debuginfo.DebugInfo {
+	return m.pc.debug
 }
 
 func (m *Machine) IsHalted() bool {
@@ -264,29 +323,25 @@ func (m *Machine) ExecuteAssertion(
 	inbox value.TupleValue,
 	maxWallTime time.Duration,
 ) (*protocol.ExecutionAssertion, uint64) {
-	hasTimeLimit := maxWallTime.Nanoseconds() != 0
-	startTime := time.Now()
 	assCtx := NewMachineAssertionContext(
 		m,
 		timeBounds,
 		inbox,
 	)
-	for assCtx.StepCount() < maxSteps {
-		_, blocked := RunInstruction(m, m.pc.GetCurrentInsn())
-		if blocked != nil {
-			break
-		}
-		if hasTimeLimit && assCtx.StepCount()%10000 == 0 {
-			endTime := time.Now()
-			runTime := endTime.Sub(startTime)
-			if runTime > maxWallTime {
-				break
-			}
-		}
-	}
+	m.run(assCtx, maxSteps, maxWallTime)
 	return assCtx.Finalize(m)
 }
 
+func (m *Machine) notifyStep(gas uint64) {
+	m.gasUsed += gas
+	m.context.NotifyStep(gas)
+}
+
+// GasUsed returns the total arbgas charged since the machine was created
+func (m *Machine) GasUsed() uint64 {
+	return m.gasUsed
+}
+
 func (m *Machine) Send(message value.Value) {
 	m.context.Send(message)
 }
@@ -326,12 +381,18 @@ func (m *Machine) PrintState() {
 	staticHash := m.static.StateValue().Hash()
 	errHandlerHash := m.errHandler.Hash()
 	fmt.Println("machine state", m.status)
+	if m.status == machine.ErrorStop {
+		fmt.Println("ERROR at", m.errorLocation)
+	} else {
+		fmt.Println("location", m.pc.Location())
+	}
 	fmt.Println("codePointHash", codePointHash)
 	fmt.Println("stackHash", stackHash)
 	fmt.Println("auxStackHash", auxStackHash)
 	fmt.Println("registerHash", registerHash)
 	fmt.Println("staticHash", staticHash)
 	fmt.Println("errHandlerHash", errHandlerHash)
+	fmt.Println("gasUsed", m.gasUsed)
 }
 
 func (m *Machine) MarshalForProof() ([]byte, error) {
@@ -417,6 +478,8 @@ func (m *Machine) Clone() machine.Machine { // clone machine state--new machine
 		m.sizeLimit,
 		m.sizeException,
 		newWarnHandler,
+		m.errorLocation,
+		m.gasUsed,
 	}
 	// WARNING: risk of bug here, because of shallow copy of stack, callstack
 	return ret
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// ParseProof rebuilds as much of a machine's state as is contained in a one
+// step proof written by MarshalForProof. Components that the proof only
+// includes the hash of are returned as hash only values.
+func ParseProof(data []byte) (*machine.Snapshot, error) {
+	rd := bytes.NewReader(data)
+	var hashes [6]common.Hash
+	for i := range hashes {
+		if _, err := io.ReadFull(rd, hashes[i][:]); err != nil {
+			return nil, err
+		}
+	}
+	nextHash, stackBase, auxStackBase := hashes[0], hashes[1], hashes[2]
+	registerHash, staticHash, errHandlerHash := hashes[3], hashes[4], hashes[5]
+
+	op, err := value.UnmarshalOperationForProof(rd)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := code.InstructionNames[op.GetOp()]; !ok {
+		return nil, errors.New("proof contains an invalid opcode")
+	}
+	stackPops := code.InstructionStackPops[op.GetOp()]
+	if _, ok := op.(value.ImmediateOperation); ok && len(stackPops) > 0 {
+		stackPops = stackPops[1:]
+	}
+	stackItems, err := readProofValues(rd, len(stackPops))
+	if err != nil {
+		return nil, err
+	}
+	auxStackItems, err := readProofValues(rd, len(code.InstructionAuxStackPops[op.GetOp()]))
+	if err != nil {
+		return nil, err
+	}
+	if rd.Len() != 0 {
+		return nil, errors.New("proof has trailing data")
+	}
+
+	return &machine.Snapshot{
+		Status:     machine.Extensive,
+		PC:         value.CodePointValue{Op: op, NextHash: nextHash},
+		ErrHandler: value.NewHashOnlyValue(errHandlerHash, 0),
+		Register:   value.NewHashOnlyValue(registerHash, 0),
+		Static:     value.NewHashOnlyValue(staticHash, 0),
+		Stack:      machine.StackSnapshot{Items: stackItems, Base: stackBase},
+		AuxStack:   machine.StackSnapshot{Items: auxStackItems, Base: auxStackBase},
+	}, nil
+}
+
+func readProofValues(rd io.Reader, count int) ([]value.Value, error) {
+	vals := make([]value.Value, 0, count)
+	for i := 0; i < count; i++ {
+		val, err := value.UnmarshalValueForProof(rd)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+	}
+	return vals, nil
+}
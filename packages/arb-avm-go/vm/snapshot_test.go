@@ -0,0 +1,104 @@
+package vm
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+func snapshotTestMachine(t *testing.T, stackVal int64, tupleVal int64) *Machine {
+	tup, err := value.NewTupleFromSlice([]value.Value{
+		value.NewInt64Value(1),
+		value.NewInt64Value(tupleVal),
+		value.NewInt64Value(3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	insns := []value.Operation{
+		value.ImmediateOperation{Op: code.NOP, Val: value.NewInt64Value(stackVal)},
+		value.ImmediateOperation{Op: code.NOP, Val: tup},
+		value.BasicOperation{Op: code.AUXPUSH},
+		value.ImmediateOperation{Op: code.NOP, Val: value.NewInt64Value(9)},
+		value.BasicOperation{Op: code.RSET},
+		value.ImmediateOperation{Op: code.ADD, Val: value.NewInt64Value(1)},
+		value.BasicOperation{Op: code.HALT},
+	}
+	m := NewMachine(insns, value.NewInt64Value(1), false, 100)
+	tb := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(0)),
+		End:   common.NewTimeBlocks(big.NewInt(100)),
+	}
+	m.ExecuteAssertion(5, tb, value.NewEmptyTuple(), 0)
+	return m
+}
+
+func diffPaths(diffs []value.Difference) []string {
+	paths := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		paths = append(paths, diff.Path)
+	}
+	return paths
+}
+
+func TestSnapshotHash(t *testing.T) {
+	m := snapshotTestMachine(t, 7, 2)
+	snapshot, err := m.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Hash() != m.Hash() {
+		t.Error("snapshot hash doesn't match machine hash")
+	}
+
+	proof, err := m.MarshalForProof()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proofSnapshot, err := ParseProof(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proofSnapshot.Hash() != m.Hash() {
+		t.Error("snapshot from proof doesn't match machine hash")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	m1 := snapshotTestMachine(t, 7, 2)
+	m2 := snapshotTestMachine(t, 8, 4)
+	if diffs := Diff(m1, m1.Clone().(*Machine)); len(diffs) != 0 {
+		t.Error("identical machines had differences", diffs)
+	}
+	expected := []string{"stack[0]", "auxstack[0][1]"}
+	if paths := diffPaths(Diff(m1, m2)); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected differences at %v but found %v", expected, paths)
+	}
+
+	proof1, err := m1.MarshalForProof()
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof2, err := m2.MarshalForProof()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, err := ParseProof(proof1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := ParseProof(proof2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = []string{"stack[0]", "auxstack[0:]"}
+	if paths := diffPaths(machine.DiffSnapshots(s1, s2)); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected proof differences at %v but found %v", expected, paths)
+	}
+}
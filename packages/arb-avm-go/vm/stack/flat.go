@@ -238,6 +238,15 @@ func (f *Flat) Pop() (value.Value, error) {
 	}
 }
 
+// PeekType returns the type code of the value depth items below the top of
+// the stack, or false if the stack isn't that deep
+func (f *Flat) PeekType(depth int) (byte, bool) {
+	if depth >= len(f.itemTypes) {
+		return 0, false
+	}
+	return f.itemTypes[len(f.itemTypes)-1-depth], true
+}
+
 func (f *Flat) IsEmpty() bool {
 	return len(f.itemTypes) == 0
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
@@ -36,8 +37,13 @@ type ArbConnection struct {
 }
 
 func Dial(url string, auth *bind.TransactOpts, ethclint *ethclient.Client) (*ArbConnection, error) {
+	return DialProxy(NewValidatorProxyImpl(url), auth, ethclint)
+}
+
+// DialProxy connects to the rollup chain served by the given proxy, letting
+// callers choose between the JSON-RPC and gRPC validator interfaces
+func DialProxy(proxy ValidatorProxy, auth *bind.TransactOpts, ethclint *ethclient.Client) (*ArbConnection, error) {
 	client := ethbridge.NewEthAuthClient(ethclint, auth)
-	proxy := NewValidatorProxyImpl(url)
 	vmIdStr, err := proxy.GetVMInfo()
 	if err != nil {
 		return nil, err
@@ -109,16 +115,7 @@ func (conn *ArbConnection) CodeAt(
 	}, contract)
 }
 
-func (conn *ArbConnection) call(
-	ctx context.Context,
-	call ethereum.CallMsg,
-	blockNumber *big.Int,
-) ([]byte, error) {
-	retValue, err := conn.proxy.CallMessage(*call.To, call.From, call.Data)
-	if err != nil {
-		return nil, err
-	}
-
+func (conn *ArbConnection) callResult(retValue value.Value) ([]byte, error) {
 	logVal, err := evm.ProcessLog(retValue, conn.vmId)
 	if err != nil {
 		return nil, err
@@ -142,7 +139,11 @@ func (conn *ArbConnection) CallContract(
 	call ethereum.CallMsg,
 	blockNumber *big.Int,
 ) ([]byte, error) {
-	return conn.call(ctx, call, blockNumber)
+	retValue, err := conn.proxy.CallMessage(*call.To, call.From, call.Data, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return conn.callResult(retValue)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -162,9 +163,14 @@ func (conn *ArbConnection) PendingCodeAt(
 	}, account)
 }
 
-// PendingCallContract executes an Ethereum contract call against the pending state.
+// PendingCallContract executes an Ethereum contract call against the pending
+// state, which includes messages delivered on L1 that haven't been asserted yet.
 func (conn *ArbConnection) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
-	return conn.call(ctx, call, nil)
+	retValue, err := conn.proxy.PendingCallMessage(*call.To, call.From, call.Data)
+	if err != nil {
+		return nil, err
+	}
+	return conn.callResult(retValue)
 }
 
 // PendingNonceAt retrieves the current pending nonce associated with an account.
@@ -422,7 +428,7 @@ func (sub *subscription) Err() <-chan error {
 // TransactionReceipt returns the receipt of a transaction by transaction hash.
 // Note that the receipt is not available for pending transactions.
 func (conn *ArbConnection) TransactionReceipt(ctx context.Context, txHash ethcommon.Hash) (*types.Receipt, error) {
-	result, ok, err := conn.proxy.GetMessageResult(txHash.Bytes())
+	result, _, ok, err := conn.proxy.GetMessageResult(txHash.Bytes())
 	if err != nil {
 		log.Println("TransactionReceipt error:", err)
 		return nil, err
@@ -490,6 +496,19 @@ func (conn *ArbConnection) TransactionReceipt(ctx context.Context, txHash ethcom
 	}, nil
 }
 
+// TransactionFinality reports how settled the result of a transaction is,
+// from co-signed by every unanimous validator or asserted on chain through to
+// confirmed beyond any likely L1 reorg
+func (conn *ArbConnection) TransactionFinality(ctx context.Context, txHash ethcommon.Hash) (validatorserver.Finality, error) {
+	_, finality, ok, err := conn.proxy.GetMessageResult(txHash.Bytes())
+	if err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, ethereum.NotFound
+	}
+	return finality, nil
+}
+
 func (conn *ArbConnection) TxToMessage(tx *types.Transaction, from common.Address) message.Transaction {
 	return message.Transaction{
 		Chain:       conn.vmId,
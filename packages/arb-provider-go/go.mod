@@ -7,6 +7,7 @@ require (
 	github.com/gorilla/rpc v1.2.0
 	github.com/offchainlabs/arbitrum/packages/arb-util v0.4.3
 	github.com/offchainlabs/arbitrum/packages/arb-validator-core v0.4.3
+	google.golang.org/grpc v1.23.1
 )
 
 replace github.com/offchainlabs/arbitrum/packages/arb-validator-core => ../arb-validator-core
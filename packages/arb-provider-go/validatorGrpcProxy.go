@@ -0,0 +1,139 @@
+package goarbitrum
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+)
+
+// ValidatorGrpcProxy implements ValidatorProxy using the validator's
+// RollupValidator gRPC service
+type ValidatorGrpcProxy struct {
+	conn   *grpc.ClientConn
+	client validatorserver.RollupValidatorClient
+}
+
+type bearerToken struct {
+	token  string
+	secure bool
+}
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.token}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return t.secure
+}
+
+// NewValidatorGrpcProxy connects to the gRPC server at addr. If certFile is
+// set, the connection uses TLS and the server's certificate must be signed by
+// the given certificate. If token is set, it's sent as a bearer token with
+// every request.
+func NewValidatorGrpcProxy(addr string, certFile string, token string) (*ValidatorGrpcProxy, error) {
+	if addr == "" {
+		addr = "localhost:1236"
+	}
+	var opts []grpc.DialOption
+	if certFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(certFile, "")
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken{token: token, secure: certFile != ""}))
+	}
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorGrpcProxy{
+		conn:   conn,
+		client: validatorserver.NewRollupValidatorClient(conn),
+	}, nil
+}
+
+// Close shuts down the connection to the validator
+func (vp *ValidatorGrpcProxy) Close() error {
+	return vp.conn.Close()
+}
+
+func (vp *ValidatorGrpcProxy) GetMessageResult(txHash []byte) (value.Value, validatorserver.Finality, bool, error) {
+	response, err := vp.client.GetMessageResult(context.Background(), &validatorserver.GetMessageResultArgs{
+		TxHash: hexutil.Encode(txHash),
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if !response.Found {
+		return nil, 0, false, nil
+	}
+	buf, err := hexutil.Decode(response.RawVal)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	val, err := value.UnmarshalValue(bytes.NewReader(buf))
+	return val, response.Finality, true, err
+}
+
+func (vp *ValidatorGrpcProxy) GetAssertionCount() (int, error) {
+	response, err := vp.client.GetAssertionCount(context.Background(), &validatorserver.GetAssertionCountArgs{})
+	if err != nil {
+		return 0, err
+	}
+	return int(response.AssertionCount), nil
+}
+
+func (vp *ValidatorGrpcProxy) GetVMInfo() (string, error) {
+	response, err := vp.client.GetVMInfo(context.Background(), &validatorserver.GetVMInfoArgs{})
+	if err != nil {
+		return "", err
+	}
+	return response.VmID, nil
+}
+
+func (vp *ValidatorGrpcProxy) FindLogs(fromHeight, toHeight int64, address []byte, topics [][32]byte) ([]*validatorserver.LogInfo, error) {
+	response, err := vp.client.FindLogs(context.Background(), &validatorserver.FindLogsArgs{
+		FromHeight: _encodeInt(fromHeight),
+		ToHeight:   _encodeInt(toHeight),
+		Address:    hexutil.Encode(address),
+		Topics:     _encodeByteArraySlice(topics),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Logs, nil
+}
+
+func (vp *ValidatorGrpcProxy) CallMessage(contract common.Address, sender common.Address, data []byte, blockNumber *big.Int) (value.Value, error) {
+	return vp.callMessage(callMessageArgs(contract, sender, data, encodeBlockHeight(blockNumber)))
+}
+
+func (vp *ValidatorGrpcProxy) PendingCallMessage(contract common.Address, sender common.Address, data []byte) (value.Value, error) {
+	return vp.callMessage(callMessageArgs(contract, sender, data, "pending"))
+}
+
+func (vp *ValidatorGrpcProxy) callMessage(request *validatorserver.CallMessageArgs) (value.Value, error) {
+	response, err := vp.client.CallMessage(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+	retBuf, err := hexutil.Decode(response.RawVal)
+	if err != nil {
+		return nil, err
+	}
+	return value.UnmarshalValue(bytes.NewReader(retBuf))
+}
@@ -3,6 +3,7 @@ package goarbitrum
 import (
 	"bytes"
 	"log"
+	"math/big"
 	"net/http"
 	"strconv"
 
@@ -17,22 +18,34 @@ import (
 
 type ValidatorProxy interface {
 	//SendMessage(val value.Value, hexPubkey string, signature []byte) ([]byte, error)
-	GetMessageResult(txHash []byte) (value.Value, bool, error)
+	GetMessageResult(txHash []byte) (value.Value, validatorserver.Finality, bool, error)
 	GetAssertionCount() (int, error)
 	GetVMInfo() (string, error)
 	FindLogs(fromHeight, toHeight int64, address []byte, topics [][32]byte) ([]*validatorserver.LogInfo, error)
-	CallMessage(contract common.Address, sender common.Address, data []byte) (value.Value, error)
+	// CallMessage executes a call at the given L1 block height, or at the
+	// latest state if blockNumber is nil
+	CallMessage(contract common.Address, sender common.Address, data []byte, blockNumber *big.Int) (value.Value, error)
+	// PendingCallMessage executes a call after applying messages which have
+	// been delivered on L1 but not yet asserted
+	PendingCallMessage(contract common.Address, sender common.Address, data []byte) (value.Value, error)
 }
 
 type ValidatorProxyImpl struct {
-	url string
+	url   string
+	token string
 }
 
 func NewValidatorProxyImpl(url string) ValidatorProxy {
+	return NewValidatorProxyImplWithToken(url, "")
+}
+
+// NewValidatorProxyImplWithToken returns a JSON-RPC proxy which sends the
+// given bearer token with every request
+func NewValidatorProxyImplWithToken(url string, token string) ValidatorProxy {
 	if url == "" {
 		url = "http://localhost:1235"
 	}
-	return &ValidatorProxyImpl{url}
+	return &ValidatorProxyImpl{url, token}
 }
 
 func _encodeInt(i int64) string {
@@ -58,6 +71,9 @@ func (vp *ValidatorProxyImpl) doCall(methodName string, request interface{}, res
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if vp.token != "" {
+		req.Header.Set("Authorization", "Bearer "+vp.token)
+	}
 	client := new(http.Client)
 	resp, err := client.Do(req)
 	if err != nil {
@@ -96,28 +112,28 @@ func (vp *ValidatorProxyImpl) doCall(methodName string, request interface{}, res
 //	return bs, err
 //}
 
-func (vp *ValidatorProxyImpl) GetMessageResult(txHash []byte) (value.Value, bool, error) {
+func (vp *ValidatorProxyImpl) GetMessageResult(txHash []byte) (value.Value, validatorserver.Finality, bool, error) {
 	request := &validatorserver.GetMessageResultArgs{
 		TxHash: hexutil.Encode(txHash),
 	}
 	var response validatorserver.GetMessageResultReply
 	if err := vp.doCall("GetMessageResult", request, &response); err != nil {
 		log.Println("ValProxy.GetMessageResult: doCall returned error:", err)
-		return nil, false, err
+		return nil, 0, false, err
 	}
 	if response.Found {
 		buf, err := hexutil.Decode(response.RawVal)
 		if err != nil {
 			log.Println("GetMessageResult error:", err)
-			return nil, false, err
+			return nil, 0, false, err
 		}
 		val, err := value.UnmarshalValue(bytes.NewReader(buf))
 		if err != nil {
 			log.Println("ValProxy.GetMessageResult: UnmarshalValue returned error:", err)
 		}
-		return val, true, err
+		return val, response.Finality, true, err
 	} else {
-		return nil, false, nil
+		return nil, 0, false, nil
 	}
 }
 
@@ -153,12 +169,15 @@ func (vp *ValidatorProxyImpl) FindLogs(fromHeight, toHeight int64, address []byt
 	return response.Logs, nil
 }
 
-func (vp *ValidatorProxyImpl) CallMessage(contract common.Address, sender common.Address, data []byte) (value.Value, error) {
-	request := &validatorserver.CallMessageArgs{
-		ContractAddress: hexutil.Encode(contract[:]),
-		Sender:          hexutil.Encode(sender[:]),
-		Data:            hexutil.Encode(data),
-	}
+func (vp *ValidatorProxyImpl) CallMessage(contract common.Address, sender common.Address, data []byte, blockNumber *big.Int) (value.Value, error) {
+	return vp.callMessage(callMessageArgs(contract, sender, data, encodeBlockHeight(blockNumber)))
+}
+
+func (vp *ValidatorProxyImpl) PendingCallMessage(contract common.Address, sender common.Address, data []byte) (value.Value, error) {
+	return vp.callMessage(callMessageArgs(contract, sender, data, "pending"))
+}
+
+func (vp *ValidatorProxyImpl) callMessage(request *validatorserver.CallMessageArgs) (value.Value, error) {
 	var response validatorserver.CallMessageReply
 	if err := vp.doCall("CallMessage", request, &response); err != nil {
 		return nil, err
@@ -174,3 +193,19 @@ func (vp *ValidatorProxyImpl) CallMessage(contract common.Address, sender common
 	}
 	return retVal, err
 }
+
+func callMessageArgs(contract common.Address, sender common.Address, data []byte, blockHeight string) *validatorserver.CallMessageArgs {
+	return &validatorserver.CallMessageArgs{
+		ContractAddress: hexutil.Encode(contract[:]),
+		Sender:          hexutil.Encode(sender[:]),
+		Data:            hexutil.Encode(data),
+		BlockHeight:     blockHeight,
+	}
+}
+
+func encodeBlockHeight(blockNumber *big.Int) string {
+	if blockNumber == nil {
+		return ""
+	}
+	return hexutil.EncodeBig(blockNumber)
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// StackSnapshot holds the top items of a stack along with the hash of the
+// rest of it. Snapshots taken directly from a machine contain the whole
+// stack, while ones rebuilt from a proof only contain the values the proof
+// included.
+type StackSnapshot struct {
+	// Items starts from the top of the stack
+	Items []value.Value
+	Base  common.Hash
+}
+
+func NewStackSnapshot(items []value.Value) StackSnapshot {
+	return StackSnapshot{Items: items, Base: value.NewEmptyTuple().Hash()}
+}
+
+// hashFrom returns the hash of the stack with the top depth items removed
+func (s StackSnapshot) hashFrom(depth int) common.Hash {
+	hash := s.Base
+	for i := len(s.Items) - 1; i >= depth; i-- {
+		hash = value.NewTuple2(s.Items[i], value.NewHashOnlyValue(hash, 0)).Hash()
+	}
+	return hash
+}
+
+func (s StackSnapshot) Hash() common.Hash {
+	return s.hashFrom(0)
+}
+
+// Complete reports whether the snapshot contains every item on the stack
+func (s StackSnapshot) Complete() bool {
+	return s.Base == value.NewEmptyTuple().Hash()
+}
+
+// Snapshot is the state of a machine broken out into its components so that
+// two machines can be compared piece by piece
+type Snapshot struct {
+	Status     Status
+	PC         value.Value
+	ErrHandler value.Value
+	Register   value.Value
+	Static     value.Value
+	Stack      StackSnapshot
+	AuxStack   StackSnapshot
+}
+
+// Snapshotter is implemented by machines which can expose their full state
+type Snapshotter interface {
+	Snapshot() (*Snapshot, error)
+}
+
+// Hash computes the hash of the machine that the snapshot was taken from
+func (s *Snapshot) Hash() common.Hash {
+	switch s.Status {
+	case ErrorStop:
+		return value.NewInt64Value(1).ToBytes()
+	case Halt:
+		return value.NewInt64Value(0).ToBytes()
+	}
+	return hashing.SoliditySHA3(
+		hashing.Bytes32(s.PC.Hash()),
+		hashing.Bytes32(s.Stack.Hash()),
+		hashing.Bytes32(s.AuxStack.Hash()),
+		hashing.Bytes32(s.Register.Hash()),
+		hashing.Bytes32(s.Static.Hash()),
+		hashing.Bytes32(s.ErrHandler.Hash()),
+	)
+}
+
+// DiffSnapshots returns every difference between the two machine states,
+// down to the tuple slot or int which differs
+func DiffSnapshots(a, b *Snapshot) []value.Difference {
+	var diffs []value.Difference
+	if a.Status != b.Status {
+		diffs = append(diffs, value.Difference{
+			Path:   "status",
+			Reason: fmt.Sprintf("%v != %v", a.Status, b.Status),
+		})
+	}
+	diffs = append(diffs, value.Diff("pc", a.PC, b.PC)...)
+	diffs = append(diffs, value.Diff("errHandler", a.ErrHandler, b.ErrHandler)...)
+	diffs = append(diffs, value.Diff("register", a.Register, b.Register)...)
+	diffs = append(diffs, value.Diff("static", a.Static, b.Static)...)
+	diffs = append(diffs, diffStacks("stack", a.Stack, b.Stack)...)
+	diffs = append(diffs, diffStacks("auxstack", a.AuxStack, b.AuxStack)...)
+	return diffs
+}
+
+func diffStacks(name string, a, b StackSnapshot) []value.Difference {
+	if a.Hash() == b.Hash() {
+		return nil
+	}
+	var diffs []value.Difference
+	depth := len(a.Items)
+	if len(b.Items) < depth {
+		depth = len(b.Items)
+	}
+	for i := 0; i < depth; i++ {
+		diffs = append(diffs, value.Diff(fmt.Sprintf("%v[%v]", name, i), a.Items[i], b.Items[i])...)
+	}
+	restA := a.hashFrom(depth)
+	restB := b.hashFrom(depth)
+	if restA == restB {
+		return diffs
+	}
+	diff := value.Difference{Path: fmt.Sprintf("%v[%v:]", name, depth)}
+	if a.Complete() && b.Complete() {
+		diff.Reason = fmt.Sprintf("stack has %v items != %v items", len(a.Items), len(b.Items))
+	} else {
+		diff.Reason = fmt.Sprintf("hash %v != %v", restA.ShortString(), restB.ShortString())
+	}
+	return append(diffs, diff)
+}
+
+func (s Status) String() string {
+	switch s {
+	case Extensive:
+		return "Extensive"
+	case ErrorStop:
+		return "ErrorStop"
+	case Halt:
+		return "Halt"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package value
+
+import (
+	"fmt"
+)
+
+// Difference describes one place where two values disagree. Path locates
+// the differing value inside the values that were compared, such as
+// "register[1][0]".
+type Difference struct {
+	Path   string
+	First  Value
+	Second Value
+	Reason string
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%v: %v", d.Path, d.Reason)
+}
+
+// Diff compares two values and returns every innermost position where they
+// differ. Subtrees with equal hashes are skipped, so comparing large values
+// which are mostly the same is cheap. When either side is only known by its
+// hash the difference is reported at that position since the comparison
+// can't go any deeper.
+func Diff(path string, a, b Value) []Difference {
+	return appendDiff(nil, path, a, b)
+}
+
+func appendDiff(diffs []Difference, path string, a, b Value) []Difference {
+	if a.Hash() == b.Hash() {
+		return diffs
+	}
+	diff := Difference{Path: path, First: a, Second: b}
+	if a.TypeCode() == TypeCodeHashOnly || b.TypeCode() == TypeCodeHashOnly {
+		diff.Reason = fmt.Sprintf("hash %v != %v", a.Hash().ShortString(), b.Hash().ShortString())
+		return append(diffs, diff)
+	}
+	if a.TypeCode() != b.TypeCode() {
+		diff.Reason = fmt.Sprintf("%v != %v", TypeCodeName(a.TypeCode()), TypeCodeName(b.TypeCode()))
+		return append(diffs, diff)
+	}
+	switch a := a.(type) {
+	case IntValue:
+		diff.Reason = fmt.Sprintf("%v != %v", a.BigInt(), b.(IntValue).BigInt())
+		return append(diffs, diff)
+	case TupleValue:
+		b := b.(TupleValue)
+		if a.Len() != b.Len() {
+			diff.Reason = fmt.Sprintf("Tuple of %v items != Tuple of %v items", a.Len(), b.Len())
+			return append(diffs, diff)
+		}
+		for i, item := range a.Contents() {
+			diffs = appendDiff(diffs, fmt.Sprintf("%v[%v]", path, i), item, b.contentsArr[i])
+		}
+		return diffs
+	case CodePointValue:
+		b := b.(CodePointValue)
+		if a.Op.GetOp() != b.Op.GetOp() || a.Op.TypeCode() != b.Op.TypeCode() {
+			diff.Reason = fmt.Sprintf("operation %v != %v", a.Op, b.Op)
+			return append(diffs, diff)
+		}
+		if aImm, ok := a.Op.(ImmediateOperation); ok {
+			diffs = appendDiff(diffs, path+".immediate", aImm.Val, b.Op.(ImmediateOperation).Val)
+		}
+		if a.NextHash != b.NextHash {
+			diff.Reason = fmt.Sprintf("next hash %v != %v", a.NextHash.ShortString(), b.NextHash.ShortString())
+			if a.InsnNum != b.InsnNum {
+				diff.Reason = fmt.Sprintf("CodePoint %v != %v (%v)", a.InsnNum, b.InsnNum, diff.Reason)
+			}
+			diffs = append(diffs, diff)
+		}
+		return diffs
+	default:
+		diff.Reason = fmt.Sprintf("%v != %v", a, b)
+		return append(diffs, diff)
+	}
+}
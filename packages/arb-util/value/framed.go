@@ -0,0 +1,120 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package value
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// FramedEncodingVersion is written before each framed value. A framed value
+// is the version byte, the length of the encoded value as a big endian
+// uint64 and then the value in the same form read by UnmarshalValue.
+const FramedEncodingVersion uint8 = 1
+
+func MarshalValueFramed(v Value, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := MarshalValueComplete(v, &buf); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{FramedEncodingVersion}); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint64(buf.Len())); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// UnmarshalValueFramed reads a single framed value, consuming exactly the
+// frame from r so that several values can be read from one stream
+func UnmarshalValueFramed(r io.Reader) (Value, error) {
+	var version uint8
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != FramedEncodingVersion {
+		return nil, UnmarshalError{fmt.Sprintf("unsupported framed value version %v", version)}
+	}
+	var length uint64
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(length)); err != nil {
+		return nil, err
+	}
+	rd := bytes.NewReader(buf.Bytes())
+	val, err := UnmarshalValue(rd)
+	if err != nil {
+		return nil, err
+	}
+	if rd.Len() != 0 {
+		return nil, UnmarshalError{"framed value is longer than its contents"}
+	}
+	return val, nil
+}
+
+// MarshalValueComplete writes v so that UnmarshalValue produces the same
+// value. MarshalValue leaves out the operation type of code points and the
+// size of hash only values, which is the form used when building proofs.
+func MarshalValueComplete(v Value, w io.Writer) error {
+	if _, err := w.Write([]byte{v.InternalTypeCode()}); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case CodePointValue:
+		if err := binary.Write(w, binary.BigEndian, v.InsnNum); err != nil {
+			return err
+		}
+		if err := MarshalOperationComplete(v.Op, w); err != nil {
+			return err
+		}
+		_, err := w.Write(v.NextHash[:])
+		return err
+	case HashOnlyValue:
+		if err := binary.Write(w, binary.LittleEndian, v.size); err != nil {
+			return err
+		}
+		_, err := w.Write(v.hash[:])
+		return err
+	case TupleValue:
+		for _, item := range v.Contents() {
+			if err := MarshalValueComplete(item, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return v.Marshal(w)
+	}
+}
+
+// MarshalOperationComplete writes op so that NewOperationFromReader produces
+// the same operation
+func MarshalOperationComplete(op Operation, w io.Writer) error {
+	if _, err := w.Write([]byte{op.TypeCode(), byte(op.GetOp())}); err != nil {
+		return err
+	}
+	if imm, ok := op.(ImmediateOperation); ok {
+		return MarshalValueComplete(imm.Val, w)
+	}
+	return nil
+}
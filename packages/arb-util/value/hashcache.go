@@ -0,0 +1,143 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package value
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// DefaultHashCacheSize is the number of tuple hashes kept by the cache that
+// tuples use unless SetTupleHashCache is called
+const DefaultHashCacheSize = 1 << 16
+
+// tupleHashKey identifies a tuple by its contents. Since a tuple's hash only
+// depends on the hashes of its items, tuples that were built separately from
+// the same contents share a cache entry.
+type tupleHashKey struct {
+	itemCount int8
+	items     [MaxTupleSize]common.Hash
+}
+
+type hashCacheEntry struct {
+	key  tupleHashKey
+	hash common.Hash
+}
+
+// HashCache is a size bounded, least recently used cache of tuple hashes
+// keyed by the hashes of the tuple's items. It is safe for concurrent use.
+type HashCache struct {
+	sync.Mutex
+	capacity int
+	entries  map[tupleHashKey]*list.Element
+	order    *list.List
+
+	hits   uint64
+	misses uint64
+}
+
+type HashCacheStats struct {
+	Entries int
+	Hits    uint64
+	Misses  uint64
+}
+
+// NewHashCache creates a cache holding at most capacity hashes. A capacity
+// of zero or less disables caching.
+func NewHashCache(capacity int) *HashCache {
+	return &HashCache{
+		capacity: capacity,
+		entries:  make(map[tupleHashKey]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *HashCache) get(key tupleHashKey) (common.Hash, bool) {
+	c.Lock()
+	defer c.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return common.Hash{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*hashCacheEntry).hash, true
+}
+
+func (c *HashCache) add(key tupleHashKey, hash common.Hash) {
+	if c.capacity <= 0 {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*hashCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&hashCacheEntry{key: key, hash: hash})
+}
+
+func (c *HashCache) Stats() HashCacheStats {
+	c.Lock()
+	defer c.Unlock()
+	return HashCacheStats{
+		Entries: c.order.Len(),
+		Hits:    c.hits,
+		Misses:  c.misses,
+	}
+}
+
+var tupleHashCache = struct {
+	sync.RWMutex
+	cache *HashCache
+}{cache: NewHashCache(DefaultHashCacheSize)}
+
+// SetTupleHashCache replaces the cache used when hashing tuples. Passing nil
+// disables the shared cache, though each tuple still remembers its own hash.
+func SetTupleHashCache(cache *HashCache) {
+	tupleHashCache.Lock()
+	tupleHashCache.cache = cache
+	tupleHashCache.Unlock()
+}
+
+// TupleHashCache returns the cache currently used when hashing tuples
+func TupleHashCache() *HashCache {
+	tupleHashCache.RLock()
+	defer tupleHashCache.RUnlock()
+	return tupleHashCache.cache
+}
+
+// tupleHash is shared by every copy of a tuple so that the hash is only
+// computed once no matter which copy asks for it first
+type tupleHash struct {
+	once sync.Once
+	hash common.Hash
+}
+
+func newComputedTupleHash(hash common.Hash) *tupleHash {
+	th := &tupleHash{hash: hash}
+	th.once.Do(func() {})
+	return th
+}
@@ -0,0 +1,111 @@
+package value
+
+import (
+	"testing"
+)
+
+func buildInbox(count int) TupleValue {
+	inbox := NewEmptyTuple()
+	for i := 0; i < count; i++ {
+		msg := NewTuple2(NewInt64Value(int64(i)), NewInt64Value(int64(i*2)))
+		inbox = NewTuple2(inbox, msg)
+	}
+	return inbox
+}
+
+func TestHashCacheEviction(t *testing.T) {
+	cache := NewHashCache(2)
+	keys := make([]tupleHashKey, 3)
+	for i := range keys {
+		keys[i] = tupleHashKey{itemCount: 1}
+		keys[i].items[0] = NewInt64Value(int64(i)).Hash()
+		cache.add(keys[i], keys[i].items[0])
+	}
+	if _, ok := cache.get(keys[0]); ok {
+		t.Error("oldest entry should have been evicted")
+	}
+	for _, key := range keys[1:] {
+		hash, ok := cache.get(key)
+		if !ok || hash != key.items[0] {
+			t.Error("recent entry missing from cache")
+		}
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("unexpected cache stats %+v", stats)
+	}
+}
+
+func TestHashCacheMatchesUncached(t *testing.T) {
+	defer SetTupleHashCache(TupleHashCache())
+
+	SetTupleHashCache(nil)
+	expected := buildInbox(50).Hash()
+
+	cache := NewHashCache(1000)
+	SetTupleHashCache(cache)
+	if buildInbox(50).Hash() != expected {
+		t.Error("cached hash differs from uncached hash")
+	}
+	// Rebuilding the inbox from the same messages should reuse cached hashes
+	if buildInbox(50).Hash() != expected {
+		t.Error("cached hash differs from uncached hash")
+	}
+	if cache.Stats().Hits == 0 {
+		t.Error("expected rebuilt tuples to hit the cache")
+	}
+}
+
+func TestTupleCopiesShareHash(t *testing.T) {
+	defer SetTupleHashCache(TupleHashCache())
+	SetTupleHashCache(nil)
+
+	tup := NewTuple2(NewInt64Value(1), NewTuple2(NewInt64Value(2), NewInt64Value(3)))
+	shallow := tup.CloneShallow().(TupleValue)
+	deep := tup.Clone().(TupleValue)
+	if shallow.hash != tup.hash || deep.hash != tup.hash {
+		t.Fatal("clones should share the tuple's hash")
+	}
+	hash := tup.Hash()
+	if shallow.Hash() != hash || deep.Hash() != hash {
+		t.Error("clones have a different hash")
+	}
+
+	set, err := tup.SetByInt64(0, NewInt64Value(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set.hash == tup.hash || set.Hash() == hash {
+		t.Error("modified tuple reused the original hash")
+	}
+}
+
+func benchmarkInboxHash(b *testing.B, cache *HashCache) {
+	defer SetTupleHashCache(TupleHashCache())
+	SetTupleHashCache(cache)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		inbox := NewEmptyTuple()
+		for i := 0; i < 10000; i++ {
+			msg := NewTuple2(NewInt64Value(int64(i)), NewInt64Value(int64(i*2)))
+			inbox = NewTuple2(inbox, msg)
+			_ = inbox.Hash()
+		}
+	}
+}
+
+func BenchmarkInboxHashUncached(b *testing.B) {
+	benchmarkInboxHash(b, nil)
+}
+
+func BenchmarkInboxHashCached(b *testing.B) {
+	benchmarkInboxHash(b, NewHashCache(DefaultHashCacheSize))
+}
+
+func BenchmarkRehashInbox(b *testing.B) {
+	inbox := buildInbox(10000)
+	_ = inbox.Hash()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = inbox.CloneShallow().Hash()
+	}
+}
@@ -20,9 +20,8 @@ import (
 	"io"
 	"math/big"
 
-	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
-
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 )
@@ -60,7 +59,7 @@ func NewBooleanValue(val bool) IntValue {
 
 func NewIntValueFromReader(rd io.Reader) (IntValue, error) {
 	var data common.Hash
-	_, err := rd.Read(data[:])
+	_, err := io.ReadFull(rd, data[:])
 	if err != nil {
 		return IntValue{}, err
 	}
@@ -107,21 +106,27 @@ func (iv IntValue) String() string {
 }
 
 func (iv IntValue) hashImpl() common.Hash {
-	return hashing.SoliditySHA3(
-		hashing.Uint256(iv.BigInt()),
-	)
+	data := iv.ToBytes()
+	var ret common.Hash
+	copy(ret[:], crypto.Keccak256(data[:]))
+	return ret
 }
 
 func (iv IntValue) ToBytes() [32]byte {
 	var data [32]byte
-	copy(data[:], math.PaddedBigBytes(math.U256(new(big.Int).Set(iv.val)), 32))
+	if iv.val.Sign() >= 0 && iv.val.BitLen() <= 256 {
+		// Already in range so there's no need to copy before wrapping
+		math.ReadBits(iv.val, data[:])
+	} else {
+		math.ReadBits(math.U256(new(big.Int).Set(iv.val)), data[:])
+	}
 	return data
 }
 
 func (iv IntValue) Hash() common.Hash {
-	if iv.val.Cmp(big.NewInt(0)) == 0 {
+	if iv.val.Sign() == 0 {
 		return hashOfZero
-	} else if iv.val.Cmp(big.NewInt(1)) == 0 {
+	} else if iv.val.IsInt64() && iv.val.Int64() == 1 {
 		return hashOfOne
 	} else {
 		return iv.hashImpl()
@@ -0,0 +1,391 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package value
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// The JSON encoding represents each value as an object with a single key
+// naming its type:
+//   {"int":"10"}
+//   {"tuple":[{"int":"1"},{"int":"2"}]}
+//   {"codepoint":{"insn":4,"op":{"opcode":1,"immediate":{"int":"2"}},"nextHash":"0x..."}}
+//   {"hashOnly":{"hash":"0x...","size":1}}
+// The canonical form written by MarshalValueJSON has no whitespace, writes
+// ints in decimal and omits "immediate" for basic operations. When decoding,
+// ints may also be given as 0x prefixed hex and keys may be in any order.
+
+const (
+	jsonKeyInt       = "int"
+	jsonKeyTuple     = "tuple"
+	jsonKeyCodePoint = "codepoint"
+	jsonKeyHashOnly  = "hashOnly"
+)
+
+// MarshalValueJSON writes the canonical JSON encoding of v. Values are
+// written as they are visited so large nested tuples aren't built up in
+// memory first.
+func MarshalValueJSON(v Value, w io.Writer) error {
+	wr := bufio.NewWriter(w)
+	if err := writeValueJSON(v, wr); err != nil {
+		return err
+	}
+	return wr.Flush()
+}
+
+func MarshalValueToJSON(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := MarshalValueJSON(v, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeValueJSON(v Value, wr *bufio.Writer) error {
+	switch v := v.(type) {
+	case IntValue:
+		_, err := fmt.Fprintf(wr, `{"%v":"%v"}`, jsonKeyInt, v.BigInt())
+		return err
+	case TupleValue:
+		if _, err := fmt.Fprintf(wr, `{"%v":[`, jsonKeyTuple); err != nil {
+			return err
+		}
+		for i, item := range v.Contents() {
+			if i > 0 {
+				if err := wr.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if err := writeValueJSON(item, wr); err != nil {
+				return err
+			}
+		}
+		_, err := wr.WriteString("]}")
+		return err
+	case CodePointValue:
+		if _, err := fmt.Fprintf(wr, `{"%v":{"insn":%v,"op":{"opcode":%v`, jsonKeyCodePoint, v.InsnNum, uint8(v.Op.GetOp())); err != nil {
+			return err
+		}
+		if imm, ok := v.Op.(ImmediateOperation); ok {
+			if _, err := wr.WriteString(`,"immediate":`); err != nil {
+				return err
+			}
+			if err := writeValueJSON(imm.Val, wr); err != nil {
+				return err
+			}
+		}
+		_, err := fmt.Fprintf(wr, `},"nextHash":"%v"}}`, v.NextHash)
+		return err
+	case HashOnlyValue:
+		_, err := fmt.Fprintf(wr, `{"%v":{"hash":"%v","size":%v}}`, jsonKeyHashOnly, v.Hash(), v.Size())
+		return err
+	default:
+		return fmt.Errorf("can't encode value of type %T as JSON", v)
+	}
+}
+
+// JSONDecoder reads a stream of JSON encoded values
+type JSONDecoder struct {
+	dec *json.Decoder
+}
+
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &JSONDecoder{dec: dec}
+}
+
+// More reports whether there is another value in the stream
+func (d *JSONDecoder) More() bool {
+	return d.dec.More()
+}
+
+// Decode reads the next value from the stream
+func (d *JSONDecoder) Decode() (Value, error) {
+	return d.value()
+}
+
+func UnmarshalValueJSON(r io.Reader) (Value, error) {
+	return NewJSONDecoder(r).Decode()
+}
+
+func UnmarshalValueFromJSON(data []byte) (Value, error) {
+	dec := NewJSONDecoder(bytes.NewReader(data))
+	val, err := dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.dec.Token(); err != io.EOF {
+		return nil, UnmarshalError{"JSON value has trailing data"}
+	}
+	return val, nil
+}
+
+func (d *JSONDecoder) expectDelim(delim json.Delim) error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return UnmarshalError{fmt.Sprintf("expected '%v' but found %v", delim, tok)}
+	}
+	return nil
+}
+
+// object reads a JSON object, calling field with each key so that it can
+// consume the corresponding value
+func (d *JSONDecoder) object(field func(key string) error) error {
+	if err := d.expectDelim('{'); err != nil {
+		return err
+	}
+	for d.dec.More() {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return UnmarshalError{fmt.Sprintf("expected object key but found %v", tok)}
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+	return d.expectDelim('}')
+}
+
+func (d *JSONDecoder) string() (string, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return "", err
+	}
+	str, ok := tok.(string)
+	if !ok {
+		return "", UnmarshalError{fmt.Sprintf("expected string but found %v", tok)}
+	}
+	return str, nil
+}
+
+func (d *JSONDecoder) int64() (int64, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return 0, err
+	}
+	num, ok := tok.(json.Number)
+	if !ok {
+		return 0, UnmarshalError{fmt.Sprintf("expected number but found %v", tok)}
+	}
+	return num.Int64()
+}
+
+func (d *JSONDecoder) hash() (common.Hash, error) {
+	var ret common.Hash
+	str, err := d.string()
+	if err != nil {
+		return ret, err
+	}
+	data, err := hexutil.Decode(str)
+	if err != nil {
+		return ret, err
+	}
+	if len(data) != len(ret) {
+		return ret, UnmarshalError{fmt.Sprintf("hash %v must be 32 bytes", str)}
+	}
+	copy(ret[:], data)
+	return ret, nil
+}
+
+func (d *JSONDecoder) value() (Value, error) {
+	var ret Value
+	err := d.object(func(key string) error {
+		if ret != nil {
+			return UnmarshalError{"JSON value must have exactly one key"}
+		}
+		var err error
+		switch key {
+		case jsonKeyInt:
+			ret, err = d.intValue()
+		case jsonKeyTuple:
+			ret, err = d.tupleValue()
+		case jsonKeyCodePoint:
+			ret, err = d.codePointValue()
+		case jsonKeyHashOnly:
+			ret, err = d.hashOnlyValue()
+		default:
+			err = UnmarshalError{fmt.Sprintf("unknown value type %v", key)}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, UnmarshalError{"JSON value must have exactly one key"}
+	}
+	return ret, nil
+}
+
+func (d *JSONDecoder) intValue() (Value, error) {
+	str, err := d.string()
+	if err != nil {
+		return nil, err
+	}
+	val := new(big.Int)
+	var ok bool
+	if strings.HasPrefix(str, "0x") {
+		_, ok = val.SetString(str[2:], 16)
+	} else {
+		_, ok = val.SetString(str, 10)
+	}
+	if !ok || val.Sign() < 0 || val.BitLen() > 256 {
+		return nil, UnmarshalError{fmt.Sprintf("invalid int %v", str)}
+	}
+	return NewIntValue(val), nil
+}
+
+func (d *JSONDecoder) tupleValue() (Value, error) {
+	if err := d.expectDelim('['); err != nil {
+		return nil, err
+	}
+	items := make([]Value, 0, MaxTupleSize)
+	for d.dec.More() {
+		if len(items) == MaxTupleSize {
+			return nil, UnmarshalError{fmt.Sprintf("tuple has more than %v items", MaxTupleSize)}
+		}
+		item, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := d.expectDelim(']'); err != nil {
+		return nil, err
+	}
+	return NewTupleFromSlice(items)
+}
+
+func (d *JSONDecoder) operation() (Operation, error) {
+	var opcode *Opcode
+	var imm Value
+	err := d.object(func(key string) error {
+		switch key {
+		case "opcode":
+			raw, err := d.int64()
+			if err != nil {
+				return err
+			}
+			if raw < 0 || raw > 255 {
+				return UnmarshalError{fmt.Sprintf("invalid opcode %v", raw)}
+			}
+			op := Opcode(raw)
+			opcode = &op
+			return nil
+		case "immediate":
+			var err error
+			imm, err = d.value()
+			return err
+		default:
+			return UnmarshalError{fmt.Sprintf("unknown operation field %v", key)}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opcode == nil {
+		return nil, UnmarshalError{"operation is missing its opcode"}
+	}
+	if imm != nil {
+		return ImmediateOperation{Op: *opcode, Val: imm}, nil
+	}
+	return BasicOperation{Op: *opcode}, nil
+}
+
+func (d *JSONDecoder) codePointValue() (Value, error) {
+	var ret CodePointValue
+	err := d.object(func(key string) error {
+		var err error
+		switch key {
+		case "insn":
+			ret.InsnNum, err = d.int64()
+		case "op":
+			ret.Op, err = d.operation()
+		case "nextHash":
+			ret.NextHash, err = d.hash()
+		default:
+			err = UnmarshalError{fmt.Sprintf("unknown codepoint field %v", key)}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ret.Op == nil {
+		return nil, UnmarshalError{"codepoint is missing its operation"}
+	}
+	return ret, nil
+}
+
+func (d *JSONDecoder) hashOnlyValue() (Value, error) {
+	var hash common.Hash
+	var size int64
+	err := d.object(func(key string) error {
+		var err error
+		switch key {
+		case "hash":
+			hash, err = d.hash()
+		case "size":
+			size, err = d.int64()
+		default:
+			err = UnmarshalError{fmt.Sprintf("unknown hashOnly field %v", key)}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewHashOnlyValue(hash, size), nil
+}
+
+// JSONValue wraps a Value so that it can be embedded in types that are
+// encoded with encoding/json, such as test fixtures
+type JSONValue struct {
+	Value
+}
+
+func (v JSONValue) MarshalJSON() ([]byte, error) {
+	return MarshalValueToJSON(v.Value)
+}
+
+func (v *JSONValue) UnmarshalJSON(data []byte) error {
+	val, err := UnmarshalValueFromJSON(data)
+	if err != nil {
+		return err
+	}
+	v.Value = val
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package value
+
+import (
+	"io"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// UnmarshalValueForProof reads a value written by MarshalValueForProof. Hash
+// only values are written for proofs without their size so they're read
+// with a size of 0.
+func UnmarshalValueForProof(r io.Reader) (Value, error) {
+	tipe := make([]byte, 1)
+	if _, err := io.ReadFull(r, tipe); err != nil {
+		return nil, err
+	}
+	switch {
+	case tipe[0] == TypeCodeInt:
+		return NewIntValueFromReader(r)
+	case tipe[0] == TypeCodeCodePoint:
+		op, err := UnmarshalOperationForProof(r)
+		if err != nil {
+			return nil, err
+		}
+		var nextHash common.Hash
+		if _, err := io.ReadFull(r, nextHash[:]); err != nil {
+			return nil, err
+		}
+		return CodePointValue{0, op, nextHash}, nil
+	case tipe[0] == TypeCodeHashOnly:
+		var hash common.Hash
+		if _, err := io.ReadFull(r, hash[:]); err != nil {
+			return nil, err
+		}
+		return NewHashOnlyValue(hash, 0), nil
+	case tipe[0] <= TypeCodeTuple+MaxTupleSize:
+		var contents [MaxTupleSize]Value
+		size := int8(tipe[0] - TypeCodeTuple)
+		for i := int8(0); i < size; i++ {
+			item, err := UnmarshalValueForProof(r)
+			if err != nil {
+				return nil, err
+			}
+			contents[i] = item
+		}
+		return NewTupleOfSizeWithContents(contents, size)
+	default:
+		return nil, UnmarshalError{"UnmarshalValueForProof: invalid value type"}
+	}
+}
+
+// UnmarshalOperationForProof reads an operation written by
+// MarshalOperationProof
+func UnmarshalOperationForProof(r io.Reader) (Operation, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	switch header[0] {
+	case 0:
+		return BasicOperation{Opcode(header[1])}, nil
+	case 1:
+		val, err := UnmarshalValueForProof(r)
+		if err != nil {
+			return nil, err
+		}
+		return ImmediateOperation{Opcode(header[1]), val}, nil
+	default:
+		return nil, UnmarshalError{"immediate count must be 0 or 1"}
+	}
+}
@@ -36,22 +36,23 @@ func init() {
 }
 
 type TupleValue struct {
-	contentsArr     [MaxTupleSize]Value
-	itemCount       int8
-	cachedHash      common.Hash
-	size            int64
-	deferredHashing bool
+	contentsArr [MaxTupleSize]Value
+	itemCount   int8
+	size        int64
+	// hash is shared with copies of the tuple, including ones made by Clone
+	// and CloneShallow, so that it's computed at most once
+	hash *tupleHash
 }
 
 func NewEmptyTuple() TupleValue {
-	return TupleValue{[MaxTupleSize]Value{}, 0, hashOfNone, 1, false}
+	return TupleValue{[MaxTupleSize]Value{}, 0, 1, newComputedTupleHash(hashOfNone)}
 }
 
 func NewTupleOfSizeWithContents(contents [MaxTupleSize]Value, size int8) (TupleValue, error) {
 	if !IsValidTupleSizeI64(int64(size)) {
 		return TupleValue{}, errors.New("requested empty tuple size is too big")
 	}
-	ret := TupleValue{contents, size, common.Hash{}, 0, true}
+	ret := TupleValue{contents, size, 0, &tupleHash{}}
 	ret.size = ret.internalSize()
 	return ret, nil
 }
@@ -61,7 +62,7 @@ func NewRepeatedTuple(value Value, size int64) (TupleValue, error) {
 		return TupleValue{}, errors.New("requested tuple size is too big")
 	}
 
-	ret := TupleValue{[MaxTupleSize]Value{}, int8(size), common.Hash{}, 0, true}
+	ret := TupleValue{[MaxTupleSize]Value{}, int8(size), 0, &tupleHash{}}
 	for i := int64(0); i < size; i++ {
 		ret.contentsArr[i] = value
 	}
@@ -81,7 +82,7 @@ func NewTupleFromSlice(slice []Value) (TupleValue, error) {
 }
 
 func NewTuple2(value1 Value, value2 Value) TupleValue {
-	ret := TupleValue{[MaxTupleSize]Value{value1, value2}, 2, common.Hash{}, 0, true}
+	ret := TupleValue{[MaxTupleSize]Value{value1, value2}, 2, 0, &tupleHash{}}
 	ret.size = ret.internalSize()
 	return ret
 }
@@ -91,7 +92,7 @@ func (tv TupleValue) init2(value1 Value, value2 Value) {
 	tv.contentsArr[1] = value2
 	tv.itemCount = 2
 	tv.size = tv.internalSize()
-	tv.deferredHashing = true
+	tv.hash = &tupleHash{}
 }
 
 func NewSizedTupleFromReader(rd io.Reader, size byte) (TupleValue, error) {
@@ -180,7 +181,7 @@ func (tv TupleValue) Clone() Value {
 	for i, b := range tv.Contents() {
 		newContents[i] = b.Clone()
 	}
-	return TupleValue{newContents, tv.itemCount, tv.cachedHash, tv.size, tv.deferredHashing}
+	return TupleValue{newContents, tv.itemCount, tv.size, tv.hash}
 }
 
 func (tv TupleValue) CloneShallow() Value {
@@ -192,7 +193,7 @@ func (tv TupleValue) CloneShallow() Value {
 			newContents[i] = NewHashOnlyValueFromValue(b)
 		}
 	}
-	return TupleValue{newContents, tv.itemCount, tv.cachedHash, tv.size, tv.deferredHashing}
+	return TupleValue{newContents, tv.itemCount, tv.size, tv.hash}
 }
 
 func (tv TupleValue) Equal(val Value) bool {
@@ -231,21 +232,35 @@ func (tv TupleValue) String() string {
 }
 
 func (tv TupleValue) internalHash() common.Hash {
-	hashes := make([]common.Hash, 0, tv.itemCount)
-	for _, v := range tv.Contents() {
-		hashes = append(hashes, v.Hash())
+	key := tupleHashKey{itemCount: tv.itemCount}
+	for i, v := range tv.Contents() {
+		key.items[i] = v.Hash()
+	}
+	cache := TupleHashCache()
+	if cache != nil {
+		if hash, ok := cache.get(key); ok {
+			return hash
+		}
 	}
 
-	return hashing.SoliditySHA3(
+	hash := hashing.SoliditySHA3(
 		hashing.Uint8(tv.InternalTypeCode()),
-		hashing.Bytes32ArrayEncoded(hashes),
+		hashing.Bytes32ArrayEncoded(key.items[:tv.itemCount]),
 	)
+	if cache != nil {
+		cache.add(key, hash)
+	}
+	return hash
 }
 
 func (tv TupleValue) Hash() common.Hash {
-	if tv.deferredHashing {
-		tv.cachedHash = tv.internalHash()
-		tv.deferredHashing = false
+	if tv.hash == nil {
+		// The zero TupleValue returned alongside errors has nowhere to
+		// remember its hash
+		return tv.internalHash()
 	}
-	return tv.cachedHash
+	tv.hash.once.Do(func() {
+		tv.hash.hash = tv.internalHash()
+	})
+	return tv.hash.hash
 }
@@ -5,38 +5,60 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type TestCase struct {
-	Value string `json:"value"`
-	Hash  string `json:"hash"`
-	Name  string `json:"name"`
+	Value string     `json:"value"`
+	JSON  *JSONValue `json:"json,omitempty"`
+	Hash  string     `json:"hash"`
+	Name  string     `json:"name"`
 }
 
-func TestTupleHash(t *testing.T) {
-	jsonFile, err := os.Open("test_cases.json")
+// loadTestCases reads the cases generated by
+// arb-compiler-evm/generate_value_tests.py along with the hand written cases
+// for the JSON encoding
+func loadTestCases(t *testing.T) []TestCase {
+	var testCases []TestCase
+	for _, fileName := range []string{"test_cases.json", "json_test_cases.json"} {
+		jsonFile, err := os.Open(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		byteValue, _ := ioutil.ReadAll(jsonFile)
+		jsonFile.Close()
+		var fileCases []TestCase
+		err = json.Unmarshal(byteValue, &fileCases)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testCases = append(testCases, fileCases...)
+	}
+	return testCases
+}
+
+func (testCase TestCase) value(t *testing.T) Value {
+	if testCase.JSON != nil {
+		return testCase.JSON.Value
+	}
+	valBytes, err := hexutil.Decode("0x" + testCase.Value)
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	byteValue, _ := ioutil.ReadAll(jsonFile)
-	var testCases []TestCase
-	err = json.Unmarshal(byteValue, &testCases)
+	val, err := UnmarshalValue(bytes.NewReader(valBytes))
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
-	for _, testCase := range testCases {
+	return val
+}
+
+func TestTupleHash(t *testing.T) {
+	for _, testCase := range loadTestCases(t) {
 		t.Run(testCase.Name, func(t *testing.T) {
-			valBytes, err := hexutil.Decode("0x" + testCase.Value)
-			if err != nil {
-				t.Error(err)
-			}
-			val, err := UnmarshalValue(bytes.NewReader(valBytes))
-			if err != nil {
-				t.Error(err)
-			}
+			val := testCase.value(t)
 			valHash := val.Hash()
 
 			hashBytes, err := hexutil.Decode("0x" + testCase.Hash)
@@ -50,3 +72,109 @@ func TestTupleHash(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, testCase := range loadTestCases(t) {
+		t.Run(testCase.Name, func(t *testing.T) {
+			val := testCase.value(t)
+			data, err := MarshalValueToJSON(val)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := UnmarshalValueFromJSON(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded.Hash() != val.Hash() {
+				t.Errorf("JSON round trip changed hash of %s", data)
+			}
+			reencoded, err := MarshalValueToJSON(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, reencoded) {
+				t.Errorf("JSON encoding isn't canonical: %s != %s", data, reencoded)
+			}
+		})
+	}
+}
+
+func TestJSONHashOnly(t *testing.T) {
+	val := NewHashOnlyValue(NewInt64Value(5).Hash(), 1)
+	data, err := MarshalValueToJSON(val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalValueFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash() != val.Hash() || decoded.Size() != val.Size() {
+		t.Error("hash only value changed after round trip")
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	cases := []string{
+		`{}`,
+		`{"int":"1","tuple":[]}`,
+		`{"int":"-1"}`,
+		`{"int":"0x1` + strings.Repeat("0", 64) + `"}`,
+		`{"float":"1"}`,
+		`{"tuple":[{"int":"1"},{"int":"2"},{"int":"3"},{"int":"4"},{"int":"5"},{"int":"6"},{"int":"7"},{"int":"8"},{"int":"9"}]}`,
+		`{"codepoint":{"insn":0,"nextHash":"0x00"}}`,
+		`{"int":"1"} {"int":"2"}`,
+	}
+	for _, data := range cases {
+		if _, err := UnmarshalValueFromJSON([]byte(data)); err == nil {
+			t.Errorf("expected %v to be rejected", data)
+		}
+	}
+}
+
+func TestFramedStream(t *testing.T) {
+	testCases := loadTestCases(t)
+	var buf bytes.Buffer
+	for _, testCase := range testCases {
+		if err := MarshalValueFramed(testCase.value(t), &buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hashOnly := NewHashOnlyValue(NewInt64Value(5).Hash(), 1)
+	if err := MarshalValueFramed(hashOnly, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rd := bytes.NewReader(buf.Bytes())
+	for _, testCase := range testCases {
+		val, err := UnmarshalValueFramed(rd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val.Hash() != testCase.value(t).Hash() {
+			t.Errorf("framed encoding changed hash of %v", testCase.Name)
+		}
+	}
+	val, err := UnmarshalValueFramed(rd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val.Hash() != hashOnly.Hash() || val.Size() != hashOnly.Size() {
+		t.Error("framed encoding changed hash only value")
+	}
+	if rd.Len() != 0 {
+		t.Error("framed values left unread data")
+	}
+}
+
+func TestFramedInvalidVersion(t *testing.T) {
+	var buf bytes.Buffer
+	if err := MarshalValueFramed(NewInt64Value(1), &buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	data[0] = FramedEncodingVersion + 1
+	if _, err := UnmarshalValueFramed(bytes.NewReader(data)); err == nil {
+		t.Error("expected unknown version to be rejected")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package arbbridge
+
+import (
+	"context"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+type deadlineKey struct{}
+
+// WithDeadline marks the transactions sent with the returned context as
+// needing to be included before the given deadline, such as the end of a
+// challenge period, so that the bridge can price them accordingly
+func WithDeadline(ctx context.Context, deadline common.TimeTicks) context.Context {
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// DeadlineFromContext returns the deadline set by WithDeadline, if any
+func DeadlineFromContext(ctx context.Context) (common.TimeTicks, bool) {
+	deadline, ok := ctx.Value(deadlineKey{}).(common.TimeTicks)
+	return deadline, ok
+}
@@ -42,29 +42,56 @@ type GlobalInbox interface {
 		signatures [][65]byte,
 	) error
 
+	// The deposit functions wait for the deposit to be mined and return the
+	// message it delivered to the chain's inbox. Token deposits first approve
+	// the inbox to take the tokens if it can't already.
 	DepositEthMessage(
 		ctx context.Context,
 		vmAddress common.Address,
 		destination common.Address,
 		value *big.Int,
-	) error
+	) (MessageDeliveredEvent, error)
 	DepositERC20Message(
 		ctx context.Context,
 		vmAddress common.Address,
 		tokenAddress common.Address,
 		destination common.Address,
 		value *big.Int,
-	) error
+	) (MessageDeliveredEvent, error)
 	DepositERC721Message(
 		ctx context.Context,
 		vmAddress common.Address,
 		tokenAddress common.Address,
 		destination common.Address,
 		value *big.Int,
-	) error
+	) (MessageDeliveredEvent, error)
+
+	// Funds withdrawn from a chain are held by the inbox until their owner
+	// withdraws them
+	GetEthBalance(
+		ctx context.Context,
+		user common.Address,
+	) (*big.Int, error)
 	GetTokenBalance(
 		ctx context.Context,
 		user common.Address,
 		tokenContract common.Address,
 	) (*big.Int, error)
+	GetERC721Tokens(
+		ctx context.Context,
+		user common.Address,
+		tokenContract common.Address,
+	) ([]*big.Int, error)
+	GetOwnedERC20s(
+		ctx context.Context,
+		user common.Address,
+	) ([]common.Address, error)
+	GetOwnedERC721s(
+		ctx context.Context,
+		user common.Address,
+	) ([]common.Address, error)
+
+	WithdrawEth(ctx context.Context) error
+	WithdrawERC20(ctx context.Context, tokenContract common.Address) error
+	WithdrawERC721(ctx context.Context, tokenContract common.Address, id *big.Int) error
 }
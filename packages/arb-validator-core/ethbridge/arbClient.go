@@ -19,12 +19,10 @@ package ethbridge
 import (
 	"context"
 	"errors"
-	"log"
 	"math/big"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -37,15 +35,26 @@ import (
 
 type EthArbClient struct {
 	client *ethclient.Client
+	reader *l1Reader
 }
 
 func NewEthClient(client *ethclient.Client) *EthArbClient {
-	return &EthArbClient{client}
+	return &EthArbClient{client: client, reader: newL1Reader(client)}
+}
+
+// AddFallbackClients adds providers which headers and logs are read from
+// when the primary provider fails
+func (c *EthArbClient) AddFallbackClients(clients ...*ethclient.Client) {
+	c.reader.addClients(clients...)
+}
+
+// SetFetchConfig controls how headers and logs are read
+func (c *EthArbClient) SetFetchConfig(config FetchConfig) {
+	c.reader.setConfig(config)
 }
 
 var reorgError = errors.New("reorg occured")
 var headerRetryDelay = time.Second * 2
-var maxFetchAttempts = 5
 
 func (c *EthArbClient) SubscribeBlockHeaders(ctx context.Context, startBlockId *common.BlockId) (<-chan arbbridge.MaybeBlockId, error) {
 	blockIdChan := make(chan arbbridge.MaybeBlockId, 100)
@@ -55,69 +64,114 @@ func (c *EthArbClient) SubscribeBlockHeaders(ctx context.Context, startBlockId *
 	go func() {
 		defer close(blockIdChan)
 
+		var head *big.Int
 		for {
-			var nextHeader *types.Header
-			fetchErrorCount := 0
-			for {
-				var err error
-				nextHeader, err = c.client.HeaderByNumber(ctx, new(big.Int).Add(prevBlockId.Height.AsInt(), big.NewInt(1)))
-				if err == nil {
-					// Got next header
-					break
+			next := new(big.Int).Add(prevBlockId.Height.AsInt(), big.NewInt(1))
+			if head == nil || next.Cmp(head) > 0 {
+				header, err := c.reader.headerByNumber(ctx, nil)
+				if err != nil {
+					if ctx.Err() == nil {
+						blockIdChan <- arbbridge.MaybeBlockId{Err: err}
+					}
+					return
 				}
+				head = header.Number
+			}
 
+			if next.Cmp(head) > 0 {
+				// The next block hasn't been mined yet so wait before
+				// checking again
 				select {
 				case <-ctx.Done():
-					// Getting header must have failed due to context cancellation
 					return
-				default:
-				}
-
-				if err != nil && err.Error() != ethereum.NotFound.Error() {
-					log.Printf("Failed to fetch next header on attempt %v with error: %v", fetchErrorCount, err)
-					fetchErrorCount++
+				case <-time.After(headerRetryDelay):
 				}
+				continue
+			}
 
-				if fetchErrorCount >= maxFetchAttempts {
+			count := int64(c.reader.fetchConfig().HeaderBatch)
+			if remaining := new(big.Int).Sub(head, next).Int64() + 1; remaining < count {
+				count = remaining
+			}
+			headers, err := c.fetchHeaders(ctx, next, count)
+			if err != nil {
+				if ctx.Err() == nil {
 					blockIdChan <- arbbridge.MaybeBlockId{Err: err}
+				}
+				return
+			}
+			if len(headers) == 0 {
+				// The provider isn't at the head we saw yet
+				head = nil
+				select {
+				case <-ctx.Done():
 					return
+				case <-time.After(headerRetryDelay):
 				}
-
-				// Header was not found so wait before checking again
-				time.Sleep(headerRetryDelay)
+				continue
 			}
 
-			if nextHeader.ParentHash != prevBlockId.HeaderHash.ToEthHash() {
-				blockIdChan <- arbbridge.MaybeBlockId{Err: reorgError}
-				return
+			for _, header := range headers {
+				if header.ParentHash != prevBlockId.HeaderHash.ToEthHash() {
+					blockIdChan <- arbbridge.MaybeBlockId{Err: reorgError}
+					return
+				}
+				prevBlockId = getBlockID(header)
+				blockIdChan <- arbbridge.MaybeBlockId{BlockId: prevBlockId}
 			}
-
-			prevBlockId = getBlockID(nextHeader)
-			blockIdChan <- arbbridge.MaybeBlockId{BlockId: prevBlockId}
 		}
 	}()
 
 	return blockIdChan, nil
 }
 
+// fetchHeaders concurrently fetches count headers starting at height
+// start. It returns the headers fetched before the first which couldn't be,
+// and only returns an error if the first one failed.
+func (c *EthArbClient) fetchHeaders(ctx context.Context, start *big.Int, count int64) ([]*types.Header, error) {
+	headers := make([]*types.Header, count)
+	errs := make([]error, count)
+	wg := sync.WaitGroup{}
+	for i := int64(0); i < count; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			height := new(big.Int).Add(start, big.NewInt(i))
+			headers[i], errs[i] = c.reader.headerByNumber(ctx, height)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if i == 0 && !isNotFound(err) {
+			return nil, err
+		}
+		return headers[:i], nil
+	}
+	return headers, nil
+}
+
 func (c *EthArbClient) NewArbFactoryWatcher(address common.Address) (arbbridge.ArbFactoryWatcher, error) {
 	return newArbFactoryWatcher(address.ToEthAddress(), c.client)
 }
 
 func (c *EthArbClient) NewRollupWatcher(address common.Address) (arbbridge.ArbRollupWatcher, error) {
-	return newRollupWatcher(address.ToEthAddress(), c.client)
+	return newRollupWatcher(address.ToEthAddress(), c.client, c.reader)
 }
 
 func (c *EthArbClient) NewExecutionChallengeWatcher(address common.Address) (arbbridge.ExecutionChallengeWatcher, error) {
-	return newExecutionChallengeWatcher(address.ToEthAddress(), c.client)
+	return newExecutionChallengeWatcher(address.ToEthAddress(), c.client, c.reader)
 }
 
 func (c *EthArbClient) NewMessagesChallengeWatcher(address common.Address) (arbbridge.MessagesChallengeWatcher, error) {
-	return newMessagesChallengeWatcher(address.ToEthAddress(), c.client)
+	return newMessagesChallengeWatcher(address.ToEthAddress(), c.client, c.reader)
 }
 
 func (c *EthArbClient) NewInboxTopChallengeWatcher(address common.Address) (arbbridge.InboxTopChallengeWatcher, error) {
-	return newInboxTopChallengeWatcher(address.ToEthAddress(), c.client)
+	return newInboxTopChallengeWatcher(address.ToEthAddress(), c.client, c.reader)
 }
 
 func (c *EthArbClient) NewOneStepProof(address common.Address) (arbbridge.OneStepProof, error) {
@@ -129,7 +183,7 @@ func (c *EthArbClient) GetBalance(ctx context.Context, account common.Address) (
 }
 
 func (c *EthArbClient) CurrentBlockId(ctx context.Context) (*common.BlockId, error) {
-	header, err := c.client.HeaderByNumber(ctx, nil)
+	header, err := c.reader.headerByNumber(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +191,7 @@ func (c *EthArbClient) CurrentBlockId(ctx context.Context) (*common.BlockId, err
 }
 
 func (c *EthArbClient) BlockIdForHeight(ctx context.Context, height *common.TimeBlocks) (*common.BlockId, error) {
-	header, err := c.client.HeaderByNumber(ctx, height.AsInt())
+	header, err := c.reader.headerByNumber(ctx, height.AsInt())
 	if err != nil {
 		return nil, err
 	}
@@ -146,19 +200,36 @@ func (c *EthArbClient) BlockIdForHeight(ctx context.Context, height *common.Time
 
 type TransactAuth struct {
 	sync.Mutex
-	auth *bind.TransactOpts
+	auth   *bind.TransactOpts
+	client *ethclient.Client
+	oracle GasPriceOracle
 }
 
-func (t *TransactAuth) getAuth(ctx context.Context) *bind.TransactOpts {
+// getAuth returns the options for a transaction with the given priority,
+// priced for any deadline set on the context with arbbridge.WithDeadline
+func (t *TransactAuth) getAuth(ctx context.Context, priority Priority) (*bind.TransactOpts, error) {
+	urgency := Urgency{Priority: priority}
+	if deadline, ok := arbbridge.DeadlineFromContext(ctx); ok && deadline.Val != nil {
+		header, err := t.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		now := common.TicksFromBlockNum(common.NewTimeBlocks(header.Number))
+		urgency.TicksRemaining = new(big.Int).Sub(deadline.Val, now.Val)
+	}
+	gasPrice, err := t.oracle.GasPrice(ctx, urgency)
+	if err != nil {
+		return nil, err
+	}
 	return &bind.TransactOpts{
 		From:     t.auth.From,
 		Nonce:    t.auth.Nonce,
 		Signer:   t.auth.Signer,
 		Value:    t.auth.Value,
-		GasPrice: t.auth.GasPrice,
+		GasPrice: gasPrice,
 		GasLimit: t.auth.GasLimit,
 		Context:  ctx,
-	}
+	}, nil
 }
 
 type EthArbAuthClient struct {
@@ -166,13 +237,30 @@ type EthArbAuthClient struct {
 	auth *TransactAuth
 }
 
+// NewEthAuthClient returns a client which sends transactions from the given
+// account. They use the account's gas price if it's set and the price
+// suggested by the node otherwise, until SetGasPriceOracle is called.
 func NewEthAuthClient(client *ethclient.Client, auth *bind.TransactOpts) *EthArbAuthClient {
+	var oracle GasPriceOracle
+	if auth.GasPrice != nil {
+		oracle = NewFixedGasPrice(auth.GasPrice)
+	} else {
+		oracle = NewSuggestedGasPrice(client)
+	}
 	return &EthArbAuthClient{
 		EthArbClient: NewEthClient(client),
-		auth:         &TransactAuth{auth: auth},
+		auth:         &TransactAuth{auth: auth, client: client, oracle: oracle},
 	}
 }
 
+// SetGasPriceOracle changes how the gas price of the client's transactions
+// is picked
+func (c *EthArbAuthClient) SetGasPriceOracle(oracle GasPriceOracle) {
+	c.auth.Lock()
+	defer c.auth.Unlock()
+	c.auth.oracle = oracle
+}
+
 func (c *EthArbAuthClient) Address() common.Address {
 	return common.NewAddressFromEth(c.auth.auth.From)
 }
@@ -23,13 +23,13 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/rollup"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
 )
@@ -53,14 +53,13 @@ func newRollup(address ethcommon.Address, client *ethclient.Client, auth *Transa
 func (vm *arbRollup) PlaceStake(ctx context.Context, stakeAmount *big.Int, proof1 []common.Hash, proof2 []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
-	call := &bind.TransactOpts{
-		From:    vm.auth.auth.From,
-		Signer:  vm.auth.auth.Signer,
-		Value:   stakeAmount,
-		Context: ctx,
+	auth, err := vm.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
 	}
+	auth.Value = stakeAmount
 	tx, err := vm.ArbRollup.PlaceStake(
-		call,
+		auth,
 		hashSliceToRaw(proof1),
 		hashSliceToRaw(proof2),
 	)
@@ -73,8 +72,12 @@ func (vm *arbRollup) PlaceStake(ctx context.Context, stakeAmount *big.Int, proof
 func (vm *arbRollup) RecoverStakeConfirmed(ctx context.Context, proof []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	auth, err := vm.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.RecoverStakeConfirmed(
-		vm.auth.getAuth(ctx),
+		auth,
 		hashSliceToRaw(proof),
 	)
 	if err != nil {
@@ -86,8 +89,12 @@ func (vm *arbRollup) RecoverStakeConfirmed(ctx context.Context, proof []common.H
 func (vm *arbRollup) RecoverStakeOld(ctx context.Context, staker common.Address, proof []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	auth, err := vm.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.RecoverStakeOld(
-		vm.auth.getAuth(ctx),
+		auth,
 		staker.ToEthAddress(),
 		hashSliceToRaw(proof),
 	)
@@ -100,8 +107,12 @@ func (vm *arbRollup) RecoverStakeOld(ctx context.Context, staker common.Address,
 func (vm *arbRollup) RecoverStakeMooted(ctx context.Context, nodeHash common.Hash, staker common.Address, latestConfirmedProof []common.Hash, stakerProof []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	auth, err := vm.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.RecoverStakeMooted(
-		vm.auth.getAuth(ctx),
+		auth,
 		staker.ToEthAddress(),
 		nodeHash,
 		hashSliceToRaw(latestConfirmedProof),
@@ -116,8 +127,12 @@ func (vm *arbRollup) RecoverStakeMooted(ctx context.Context, nodeHash common.Has
 func (vm *arbRollup) RecoverStakePassedDeadline(ctx context.Context, stakerAddress common.Address, deadlineTicks *big.Int, disputableNodeHashVal common.Hash, childType uint64, vmProtoStateHash common.Hash, proof []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	auth, err := vm.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.RecoverStakePassedDeadline(
-		vm.auth.getAuth(ctx),
+		auth,
 		stakerAddress.ToEthAddress(),
 		deadlineTicks,
 		disputableNodeHashVal,
@@ -134,8 +149,12 @@ func (vm *arbRollup) RecoverStakePassedDeadline(ctx context.Context, stakerAddre
 func (vm *arbRollup) MoveStake(ctx context.Context, proof1 []common.Hash, proof2 []common.Hash) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	auth, err := vm.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.MoveStake(
-		vm.auth.getAuth(ctx),
+		auth,
 		hashSliceToRaw(proof1),
 		hashSliceToRaw(proof2),
 	)
@@ -162,8 +181,12 @@ func (vm *arbRollup) PruneLeaves(ctx context.Context, opps []valprotocol.PrunePa
 		confProofLengths = append(confProofLengths, big.NewInt(int64(len(opp.AncProof))))
 	}
 
+	auth, err := vm.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.PruneLeaves(
-		vm.auth.getAuth(ctx),
+		auth,
 		hashSliceToRaw(fromNodes),
 		hashSliceToRaw(leafProofs),
 		leafProofLengths,
@@ -202,8 +225,12 @@ func (vm *arbRollup) MakeAssertion(
 		assertionClaim.AssertionStub.LastMessageHash,
 		assertionClaim.AssertionStub.LastLogHash,
 	}
+	auth, err := vm.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.MakeAssertion(
-		vm.auth.getAuth(ctx),
+		auth,
 		extraParams,
 		beforeState.InboxCount,
 		prevDeadline.Val,
@@ -280,8 +307,12 @@ func (vm *arbRollup) Confirm(ctx context.Context, opp *valprotocol.ConfirmOpport
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
 
+	auth, err := vm.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.Confirm(
-		vm.auth.getAuth(ctx),
+		auth,
 		initalProtoStateHash,
 		branchesNums,
 		deadlineTicks,
@@ -334,8 +365,17 @@ func (vm *arbRollup) StartChallenge(
 ) error {
 	vm.auth.Lock()
 	defer vm.auth.Unlock()
+	if _, ok := arbbridge.DeadlineFromContext(ctx); !ok {
+		// Once the disputed node's deadline passes it can be confirmed and
+		// the challenge can no longer be started
+		ctx = arbbridge.WithDeadline(ctx, common.TimeTicks{Val: disputableDeadline})
+	}
+	auth, err := vm.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := vm.ArbRollup.StartChallenge(
-		vm.auth.getAuth(ctx),
+		auth,
 		asserterAddress.ToEthAddress(),
 		challengerAddress.ToEthAddress(),
 		prevNode,
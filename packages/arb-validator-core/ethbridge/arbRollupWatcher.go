@@ -89,9 +89,11 @@ type ethRollupWatcher struct {
 	rollupAddress ethcommon.Address
 	inboxAddress  ethcommon.Address
 	client        *ethclient.Client
+	inboxLogs     *logFetcher
+	rollupLogs    *logFetcher
 }
 
-func newRollupWatcher(rollupAddress ethcommon.Address, client *ethclient.Client) (*ethRollupWatcher, error) {
+func newRollupWatcher(rollupAddress ethcommon.Address, client *ethclient.Client, reader *l1Reader) (*ethRollupWatcher, error) {
 	arbitrumRollupContract, err := rollup.NewArbRollup(rollupAddress, client)
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to arbRollup")
@@ -109,22 +111,10 @@ func newRollupWatcher(rollupAddress ethcommon.Address, client *ethclient.Client)
 		return nil, errors2.Wrap(err, "Failed to connect to GlobalInbox")
 	}
 
-	return &ethRollupWatcher{
-		ArbRollup:     arbitrumRollupContract,
-		GlobalInbox:   globalInboxContract,
-		rollupAddress: rollupAddress,
-		inboxAddress:  globalInboxAddress,
-		client:        client,
-	}, nil
-}
-
-func (vm *ethRollupWatcher) GetEvents(ctx context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
-	bh := blockId.HeaderHash.ToEthHash()
 	addressIndex := ethcommon.Hash{}
-	copy(addressIndex[:], ethcommon.LeftPadBytes(vm.rollupAddress.Bytes(), 32))
-	inboxLogs, err := vm.client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &bh,
-		Addresses: []ethcommon.Address{vm.inboxAddress},
+	copy(addressIndex[:], ethcommon.LeftPadBytes(rollupAddress.Bytes(), 32))
+	inboxLogs := newLogFetcher(reader, ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{globalInboxAddress},
 		Topics: [][]ethcommon.Hash{
 			{
 				transactionMessageDeliveredID,
@@ -136,12 +126,8 @@ func (vm *ethRollupWatcher) GetEvents(ctx context.Context, blockId *common.Block
 			},
 		},
 	})
-	if err != nil {
-		return nil, err
-	}
-	rollupLogs, err := vm.client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &bh,
-		Addresses: []ethcommon.Address{vm.rollupAddress},
+	rollupLogs := newLogFetcher(reader, ethereum.FilterQuery{
+		Addresses: []ethcommon.Address{rollupAddress},
 		Topics: [][]ethcommon.Hash{
 			{
 				rollupStakeCreatedID,
@@ -156,6 +142,24 @@ func (vm *ethRollupWatcher) GetEvents(ctx context.Context, blockId *common.Block
 			},
 		},
 	})
+
+	return &ethRollupWatcher{
+		ArbRollup:     arbitrumRollupContract,
+		GlobalInbox:   globalInboxContract,
+		rollupAddress: rollupAddress,
+		inboxAddress:  globalInboxAddress,
+		client:        client,
+		inboxLogs:     inboxLogs,
+		rollupLogs:    rollupLogs,
+	}, nil
+}
+
+func (vm *ethRollupWatcher) GetEvents(ctx context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
+	inboxLogs, err := vm.inboxLogs.blockLogs(ctx, blockId)
+	if err != nil {
+		return nil, err
+	}
+	rollupLogs, err := vm.rollupLogs.blockLogs(ctx, blockId)
 	if err != nil {
 		return nil, err
 	}
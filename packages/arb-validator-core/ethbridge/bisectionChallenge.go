@@ -72,8 +72,12 @@ func (c *bisectionChallenge) chooseSegment(
 	c.auth.Lock()
 	defer c.auth.Unlock()
 	tree := NewMerkleTree(segments)
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.BisectionChallenge.ChooseSegment(
-		c.auth.getAuth(ctx),
+		auth,
 		big.NewInt(int64(segmentToChallenge)),
 		tree.GetProofFlat(int(segmentToChallenge)),
 		tree.GetRoot(),
@@ -67,7 +67,11 @@ func newChallenge(address ethcommon.Address, client *ethclient.Client, auth *Tra
 func (c *challenge) TimeoutChallenge(ctx context.Context) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
-	tx, err := c.Challenge.TimeoutChallenge(c.auth.getAuth(ctx))
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := c.Challenge.TimeoutChallenge(auth)
 	if err != nil {
 		return c.Challenge.TimeoutChallengeCall(
 			ctx,
@@ -53,8 +53,12 @@ func (con *challengeFactory) CreateChallenge(
 ) (common.Address, error) {
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return common.Address{}, err
+	}
 	tx, err := con.contract.CreateChallenge(
-		con.auth.getAuth(ctx),
+		auth,
 		asserter.ToEthAddress(),
 		challenger.ToEthAddress(),
 		challengePeriod.Val,
@@ -53,8 +53,12 @@ func (con *ChallengeTester) StartChallenge(
 ) (common.Address, *common.BlockId, error) {
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
 	tx, err := con.contract.StartChallenge(
-		con.auth.getAuth(ctx),
+		auth,
 		asserter.ToEthAddress(),
 		challenger.ToEthAddress(),
 		challengePeriod.Val,
@@ -69,8 +69,12 @@ func (c *executionChallenge) BisectAssertion(
 	}
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.challenge.BisectAssertion(
-		c.auth.getAuth(ctx),
+		auth,
 		precondition.BeforeInbox.Hash(),
 		precondition.TimeBounds.AsIntArray(),
 		machineHashes,
@@ -107,8 +111,12 @@ func (c *executionChallenge) OneStepProof(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.challenge.OneStepProof(
-		c.auth.getAuth(ctx),
+		auth,
 		precondition.BeforeHash,
 		precondition.BeforeInbox.Hash(),
 		precondition.TimeBounds.AsIntArray(),
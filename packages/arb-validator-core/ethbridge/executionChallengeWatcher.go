@@ -51,10 +51,10 @@ type executionChallengeWatcher struct {
 	challenge *executionchallenge.ExecutionChallenge
 	client    *ethclient.Client
 	address   ethcommon.Address
-	topics    [][]ethcommon.Hash
+	logs      *logFetcher
 }
 
-func newExecutionChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*executionChallengeWatcher, error) {
+func newExecutionChallengeWatcher(address ethcommon.Address, client *ethclient.Client, reader *l1Reader) (*executionChallengeWatcher, error) {
 	bisectionChallenge, err := newBisectionChallengeWatcher(address, client)
 	if err != nil {
 		return nil, err
@@ -73,17 +73,15 @@ func newExecutionChallengeWatcher(address ethcommon.Address, client *ethclient.C
 		challenge:                 executionContract,
 		client:                    client,
 		address:                   address,
-		topics:                    [][]ethcommon.Hash{tops},
+		logs: newLogFetcher(reader, ethereum.FilterQuery{
+			Addresses: []ethcommon.Address{address},
+			Topics:    [][]ethcommon.Hash{tops},
+		}),
 	}, nil
 }
 
 func (c *executionChallengeWatcher) GetEvents(ctx context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
-	bh := blockId.HeaderHash.ToEthHash()
-	logs, err := c.client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &bh,
-		Addresses: []ethcommon.Address{c.address},
-		Topics:    c.topics,
-	})
+	logs, err := c.logs.blockLogs(ctx, blockId)
 	if err != nil {
 		return nil, err
 	}
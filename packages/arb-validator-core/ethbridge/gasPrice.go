@@ -0,0 +1,175 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// Priority is how much a transaction is worth paying for independent of
+// any deadline it has
+type Priority int
+
+const (
+	// LowPriority transactions, such as stake recovery, can wait for
+	// congestion to clear
+	LowPriority Priority = iota
+	NormalPriority
+	// CriticalPriority transactions, such as challenge moves and
+	// confirmations, protect a validator's stake
+	CriticalPriority
+)
+
+// Urgency describes a transaction that's about to be sent
+type Urgency struct {
+	Priority Priority
+	// TicksRemaining is how long is left until the transaction's deadline,
+	// or nil if it doesn't have one. It's negative once the deadline has
+	// passed.
+	TicksRemaining *big.Int
+}
+
+// GasPriceOracle picks the gas price of each transaction sent to L1
+type GasPriceOracle interface {
+	GasPrice(ctx context.Context, urgency Urgency) (*big.Int, error)
+}
+
+type fixedGasPrice struct {
+	price *big.Int
+}
+
+// NewFixedGasPrice returns an oracle which always uses the given price
+func NewFixedGasPrice(price *big.Int) GasPriceOracle {
+	return fixedGasPrice{price: new(big.Int).Set(price)}
+}
+
+func (o fixedGasPrice) GasPrice(context.Context, Urgency) (*big.Int, error) {
+	return new(big.Int).Set(o.price), nil
+}
+
+type suggestedGasPrice struct {
+	client *ethclient.Client
+}
+
+// NewSuggestedGasPrice returns an oracle which uses the price suggested by
+// the L1 node
+func NewSuggestedGasPrice(client *ethclient.Client) GasPriceOracle {
+	return suggestedGasPrice{client: client}
+}
+
+func (o suggestedGasPrice) GasPrice(ctx context.Context, _ Urgency) (*big.Int, error) {
+	return o.client.SuggestGasPrice(ctx)
+}
+
+type cappedGasPrice struct {
+	oracle GasPriceOracle
+	max    *big.Int
+}
+
+// NewCappedGasPrice limits the prices picked by another oracle to max
+func NewCappedGasPrice(oracle GasPriceOracle, max *big.Int) GasPriceOracle {
+	return cappedGasPrice{oracle: oracle, max: new(big.Int).Set(max)}
+}
+
+func (o cappedGasPrice) GasPrice(ctx context.Context, urgency Urgency) (*big.Int, error) {
+	price, err := o.oracle.GasPrice(ctx, urgency)
+	if err != nil {
+		return nil, err
+	}
+	if price.Cmp(o.max) > 0 {
+		return new(big.Int).Set(o.max), nil
+	}
+	return price, nil
+}
+
+// UrgencyConfig controls how an urgency aware oracle scales its base price.
+// Scales are percentages of the base price.
+type UrgencyConfig struct {
+	LowPercent      uint64
+	NormalPercent   uint64
+	CriticalPercent uint64
+	// DeadlinePercent is the scale used for a transaction whose deadline is
+	// about to pass. Within DeadlineWindow of a deadline the scale rises
+	// linearly from the transaction's priority scale to DeadlinePercent.
+	DeadlinePercent uint64
+	DeadlineWindow  *common.TimeBlocks
+}
+
+// DefaultUrgencyConfig returns the scales used unless configured otherwise
+func DefaultUrgencyConfig() UrgencyConfig {
+	return UrgencyConfig{
+		LowPercent:      80,
+		NormalPercent:   100,
+		CriticalPercent: 125,
+		DeadlinePercent: 300,
+		DeadlineWindow:  common.NewTimeBlocksInt(20),
+	}
+}
+
+// Percent returns the scale applied to the base price of a transaction
+// with the given urgency
+func (c UrgencyConfig) Percent(urgency Urgency) uint64 {
+	var percent uint64
+	switch urgency.Priority {
+	case LowPriority:
+		percent = c.LowPercent
+	case CriticalPriority:
+		percent = c.CriticalPercent
+	default:
+		percent = c.NormalPercent
+	}
+	if urgency.TicksRemaining == nil || c.DeadlinePercent <= percent {
+		return percent
+	}
+	window := common.TicksFromBlockNum(c.DeadlineWindow).Val
+	if window.Sign() <= 0 || urgency.TicksRemaining.Cmp(window) >= 0 {
+		return percent
+	}
+	if urgency.TicksRemaining.Sign() <= 0 {
+		return c.DeadlinePercent
+	}
+	// percent + (DeadlinePercent - percent) * (window - remaining) / window
+	elapsed := new(big.Int).Sub(window, urgency.TicksRemaining)
+	extra := new(big.Int).Mul(new(big.Int).SetUint64(c.DeadlinePercent-percent), elapsed)
+	extra.Div(extra, window)
+	return percent + extra.Uint64()
+}
+
+type urgentGasPrice struct {
+	base   GasPriceOracle
+	config UrgencyConfig
+}
+
+// NewUrgentGasPrice returns an oracle which scales the price picked by base
+// according to each transaction's priority and how close its deadline is
+func NewUrgentGasPrice(base GasPriceOracle, config UrgencyConfig) GasPriceOracle {
+	return urgentGasPrice{base: base, config: config}
+}
+
+func (o urgentGasPrice) GasPrice(ctx context.Context, urgency Urgency) (*big.Int, error) {
+	price, err := o.base.GasPrice(ctx, urgency)
+	if err != nil {
+		return nil, err
+	}
+	price = new(big.Int).Mul(price, new(big.Int).SetUint64(o.config.Percent(urgency)))
+	return price.Div(price, big.NewInt(100)), nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestUrgencyPercent(t *testing.T) {
+	config := DefaultUrgencyConfig()
+	window := common.TicksFromBlockNum(config.DeadlineWindow).Val
+	half := new(big.Int).Div(window, big.NewInt(2))
+
+	cases := []struct {
+		name     string
+		urgency  Urgency
+		expected uint64
+	}{
+		{"low", Urgency{Priority: LowPriority}, 80},
+		{"normal", Urgency{Priority: NormalPriority}, 100},
+		{"critical", Urgency{Priority: CriticalPriority}, 125},
+		{"outside window", Urgency{Priority: NormalPriority, TicksRemaining: new(big.Int).Add(window, big.NewInt(1))}, 100},
+		{"half window", Urgency{Priority: NormalPriority, TicksRemaining: half}, 200},
+		{"deadline passed", Urgency{Priority: LowPriority, TicksRemaining: big.NewInt(-1)}, 300},
+	}
+	for _, c := range cases {
+		if percent := config.Percent(c.urgency); percent != c.expected {
+			t.Errorf("%v: expected %v percent but got %v", c.name, c.expected, percent)
+		}
+	}
+}
+
+func TestUrgentGasPrice(t *testing.T) {
+	base := NewFixedGasPrice(big.NewInt(1000))
+	oracle := NewCappedGasPrice(NewUrgentGasPrice(base, DefaultUrgencyConfig()), big.NewInt(2000))
+
+	price, err := oracle.GasPrice(context.Background(), Urgency{Priority: CriticalPriority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(1250)) != 0 {
+		t.Errorf("expected critical price 1250 but got %v", price)
+	}
+
+	price, err = oracle.GasPrice(context.Background(), Urgency{Priority: CriticalPriority, TicksRemaining: big.NewInt(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("expected price capped at 2000 but got %v", price)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"math/big"
 
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge/globalinbox"
@@ -36,6 +37,7 @@ import (
 
 type globalInbox struct {
 	GlobalInbox *globalinbox.GlobalInbox
+	address     ethcommon.Address
 	client      *ethclient.Client
 	auth        *TransactAuth
 }
@@ -45,14 +47,18 @@ func newGlobalInbox(address ethcommon.Address, client *ethclient.Client, auth *T
 	if err != nil {
 		return nil, errors2.Wrap(err, "Failed to connect to GlobalInbox")
 	}
-	return &globalInbox{globalInboxContract, client, auth}, nil
+	return &globalInbox{globalInboxContract, address, client, auth}, nil
 }
 
 func (con *globalInbox) SendTransactionMessage(ctx context.Context, data []byte, vmAddress common.Address, contactAddress common.Address, amount *big.Int, seqNumber *big.Int) error {
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := con.GlobalInbox.SendTransactionMessage(
-		con.auth.getAuth(ctx),
+		auth,
 		vmAddress.ToEthAddress(),
 		contactAddress.ToEthAddress(),
 		seqNumber,
@@ -87,8 +93,12 @@ func (con *globalInbox) DeliverTransactionBatch(
 	}
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := con.GlobalInbox.DeliverTransactionBatch(
-		con.auth.getAuth(ctx),
+		auth,
 		chain.ToEthAddress(),
 		tos,
 		seqNums,
@@ -108,25 +118,38 @@ func (con *globalInbox) DepositEthMessage(
 	vmAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
-
+) (arbbridge.MessageDeliveredEvent, error) {
+	con.auth.Lock()
+	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
+	auth.Value = value
 	tx, err := con.GlobalInbox.DepositEthMessage(
-		&bind.TransactOpts{
-			From:     con.auth.auth.From,
-			Signer:   con.auth.auth.Signer,
-			GasLimit: con.auth.auth.GasLimit,
-			Value:    value,
-			Context:  ctx,
-		},
+		auth,
 		vmAddress.ToEthAddress(),
 		destination.ToEthAddress(),
 	)
-
 	if err != nil {
-		return err
+		return arbbridge.MessageDeliveredEvent{}, err
 	}
 
-	return con.waitForReceipt(ctx, tx, "DepositEthMessage")
+	return con.waitForDelivery(ctx, tx, "DepositEthMessage", ethDepositMessageDeliveredID, func(ethLog types.Log) (message.InboxMessage, error) {
+		val, err := con.GlobalInbox.ParseEthDepositMessageDelivered(ethLog)
+		if err != nil {
+			return nil, err
+		}
+		return message.DeliveredEth{
+			Eth: message.Eth{
+				To:    common.NewAddressFromEth(val.To),
+				From:  common.NewAddressFromEth(val.From),
+				Value: val.Value,
+			},
+			BlockNum:   common.NewTimeBlocks(new(big.Int).SetUint64(ethLog.BlockNumber)),
+			MessageNum: val.MessageNum,
+		}, nil
+	})
 }
 
 func (con *globalInbox) DepositERC20Message(
@@ -135,11 +158,18 @@ func (con *globalInbox) DepositERC20Message(
 	tokenAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
+) (arbbridge.MessageDeliveredEvent, error) {
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	if err := con.approveERC20(ctx, tokenAddress, value); err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
 	tx, err := con.GlobalInbox.DepositERC20Message(
-		con.auth.getAuth(ctx),
+		auth,
 		vmAddress.ToEthAddress(),
 		tokenAddress.ToEthAddress(),
 		destination.ToEthAddress(),
@@ -147,10 +177,25 @@ func (con *globalInbox) DepositERC20Message(
 	)
 
 	if err != nil {
-		return err
+		return arbbridge.MessageDeliveredEvent{}, err
 	}
 
-	return con.waitForReceipt(ctx, tx, "DepositERC20Message")
+	return con.waitForDelivery(ctx, tx, "DepositERC20Message", depositERC20MessageDeliveredID, func(ethLog types.Log) (message.InboxMessage, error) {
+		val, err := con.GlobalInbox.ParseERC20DepositMessageDelivered(ethLog)
+		if err != nil {
+			return nil, err
+		}
+		return message.DeliveredERC20{
+			ERC20: message.ERC20{
+				To:           common.NewAddressFromEth(val.To),
+				From:         common.NewAddressFromEth(val.From),
+				TokenAddress: common.NewAddressFromEth(val.Erc20),
+				Value:        val.Value,
+			},
+			BlockNum:   common.NewTimeBlocks(new(big.Int).SetUint64(ethLog.BlockNumber)),
+			MessageNum: val.MessageNum,
+		}, nil
+	})
 }
 
 func (con *globalInbox) DepositERC721Message(
@@ -159,11 +204,18 @@ func (con *globalInbox) DepositERC721Message(
 	tokenAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
+) (arbbridge.MessageDeliveredEvent, error) {
 	con.auth.Lock()
 	defer con.auth.Unlock()
+	if err := con.approveERC721(ctx, tokenAddress, value); err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
 	tx, err := con.GlobalInbox.DepositERC721Message(
-		con.auth.getAuth(ctx),
+		auth,
 		vmAddress.ToEthAddress(),
 		tokenAddress.ToEthAddress(),
 		destination.ToEthAddress(),
@@ -171,10 +223,35 @@ func (con *globalInbox) DepositERC721Message(
 	)
 
 	if err != nil {
-		return err
+		return arbbridge.MessageDeliveredEvent{}, err
 	}
 
-	return con.waitForReceipt(ctx, tx, "DepositERC721Message")
+	return con.waitForDelivery(ctx, tx, "DepositERC721Message", depositERC721MessageDeliveredID, func(ethLog types.Log) (message.InboxMessage, error) {
+		val, err := con.GlobalInbox.ParseERC721DepositMessageDelivered(ethLog)
+		if err != nil {
+			return nil, err
+		}
+		return message.DeliveredERC721{
+			ERC721: message.ERC721{
+				To:           common.NewAddressFromEth(val.To),
+				From:         common.NewAddressFromEth(val.From),
+				TokenAddress: common.NewAddressFromEth(val.Erc721),
+				Id:           val.Id,
+			},
+			BlockNum:   common.NewTimeBlocks(new(big.Int).SetUint64(ethLog.BlockNumber)),
+			MessageNum: val.MessageNum,
+		}, nil
+	})
+}
+
+func (con *globalInbox) GetEthBalance(
+	ctx context.Context,
+	user common.Address,
+) (*big.Int, error) {
+	return con.GlobalInbox.GetEthBalance(
+		&bind.CallOpts{Context: ctx},
+		user.ToEthAddress(),
+	)
 }
 
 func (con *globalInbox) GetTokenBalance(
@@ -189,6 +266,163 @@ func (con *globalInbox) GetTokenBalance(
 	)
 }
 
+func (con *globalInbox) GetERC721Tokens(
+	ctx context.Context,
+	user common.Address,
+	tokenContract common.Address,
+) ([]*big.Int, error) {
+	return con.GlobalInbox.GetERC721Tokens(
+		&bind.CallOpts{Context: ctx},
+		tokenContract.ToEthAddress(),
+		user.ToEthAddress(),
+	)
+}
+
+func (con *globalInbox) GetOwnedERC20s(
+	ctx context.Context,
+	user common.Address,
+) ([]common.Address, error) {
+	addresses, err := con.GlobalInbox.OwnedERC20s(&bind.CallOpts{Context: ctx}, user.ToEthAddress())
+	return addressSliceToAddresses(addresses), err
+}
+
+func (con *globalInbox) GetOwnedERC721s(
+	ctx context.Context,
+	user common.Address,
+) ([]common.Address, error) {
+	addresses, err := con.GlobalInbox.OwnedERC721s(&bind.CallOpts{Context: ctx}, user.ToEthAddress())
+	return addressSliceToAddresses(addresses), err
+}
+
+func (con *globalInbox) WithdrawEth(ctx context.Context) error {
+	con.auth.Lock()
+	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := con.GlobalInbox.WithdrawEth(auth)
+	if err != nil {
+		return err
+	}
+	return con.waitForReceipt(ctx, tx, "WithdrawEth")
+}
+
+func (con *globalInbox) WithdrawERC20(ctx context.Context, tokenContract common.Address) error {
+	con.auth.Lock()
+	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := con.GlobalInbox.WithdrawERC20(auth, tokenContract.ToEthAddress())
+	if err != nil {
+		return err
+	}
+	return con.waitForReceipt(ctx, tx, "WithdrawERC20")
+}
+
+func (con *globalInbox) WithdrawERC721(ctx context.Context, tokenContract common.Address, id *big.Int) error {
+	con.auth.Lock()
+	defer con.auth.Unlock()
+	auth, err := con.auth.getAuth(ctx, LowPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := con.GlobalInbox.WithdrawERC721(auth, tokenContract.ToEthAddress(), id)
+	if err != nil {
+		return err
+	}
+	return con.waitForReceipt(ctx, tx, "WithdrawERC721")
+}
+
+// approveERC20 allows the inbox to take value tokens from the sender if it
+// can't already. Must be called with con.auth locked.
+func (con *globalInbox) approveERC20(ctx context.Context, tokenAddress common.Address, value *big.Int) error {
+	token, err := globalinbox.NewIERC20(tokenAddress.ToEthAddress(), con.client)
+	if err != nil {
+		return errors2.Wrap(err, "Failed to connect to ERC20")
+	}
+	allowance, err := token.Allowance(&bind.CallOpts{Context: ctx}, con.auth.auth.From, con.address)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(value) >= 0 {
+		return nil
+	}
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := token.Approve(auth, con.address, value)
+	if err != nil {
+		return err
+	}
+	return con.waitForReceipt(ctx, tx, "Approve")
+}
+
+// approveERC721 allows the inbox to take the given token from the sender if
+// it can't already. Must be called with con.auth locked.
+func (con *globalInbox) approveERC721(ctx context.Context, tokenAddress common.Address, id *big.Int) error {
+	token, err := globalinbox.NewIERC721(tokenAddress.ToEthAddress(), con.client)
+	if err != nil {
+		return errors2.Wrap(err, "Failed to connect to ERC721")
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	approvedForAll, err := token.IsApprovedForAll(opts, con.auth.auth.From, con.address)
+	if err != nil {
+		return err
+	}
+	if approvedForAll {
+		return nil
+	}
+	approved, err := token.GetApproved(opts, id)
+	if err != nil {
+		return err
+	}
+	if approved == con.address {
+		return nil
+	}
+	auth, err := con.auth.getAuth(ctx, NormalPriority)
+	if err != nil {
+		return err
+	}
+	tx, err := token.Approve(auth, con.address, id)
+	if err != nil {
+		return err
+	}
+	return con.waitForReceipt(ctx, tx, "Approve")
+}
+
+// waitForDelivery waits for a deposit to be mined and parses the message it
+// delivered out of the receipt
+func (con *globalInbox) waitForDelivery(
+	ctx context.Context,
+	tx *types.Transaction,
+	methodName string,
+	eventID ethcommon.Hash,
+	parse func(types.Log) (message.InboxMessage, error),
+) (arbbridge.MessageDeliveredEvent, error) {
+	receipt, err := WaitForReceiptWithResults(ctx, con.client, con.auth.auth.From, tx, methodName)
+	if err != nil {
+		return arbbridge.MessageDeliveredEvent{}, err
+	}
+	for _, ethLog := range receipt.Logs {
+		if len(ethLog.Topics) == 0 || ethLog.Topics[0] != eventID {
+			continue
+		}
+		msg, err := parse(*ethLog)
+		if err != nil {
+			return arbbridge.MessageDeliveredEvent{}, err
+		}
+		return arbbridge.MessageDeliveredEvent{
+			ChainInfo: getLogChainInfo(*ethLog),
+			Message:   msg,
+		}, nil
+	}
+	return arbbridge.MessageDeliveredEvent{}, errors2.Errorf("%v didn't deliver a message", methodName)
+}
+
 func (con *globalInbox) waitForReceipt(ctx context.Context, tx *types.Transaction, methodName string) error {
 	return waitForReceipt(ctx, con.client, con.auth.auth.From, tx, methodName)
 }
@@ -56,8 +56,12 @@ func (c *inboxTopChallenge) Bisect(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.Bisect(
-		c.auth.getAuth(ctx),
+		auth,
 		hashSliceToRaw(chainHashes),
 		chainLength,
 	)
@@ -81,8 +85,12 @@ func (c *inboxTopChallenge) OneStepProof(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProof(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		value,
 	)
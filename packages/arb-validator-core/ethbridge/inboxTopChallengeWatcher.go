@@ -50,10 +50,10 @@ type inboxTopChallengeWatcher struct {
 	contract *inboxtopchallenge.InboxTopChallenge
 	client   *ethclient.Client
 	address  ethcommon.Address
-	topics   [][]ethcommon.Hash
+	logs     *logFetcher
 }
 
-func newInboxTopChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*inboxTopChallengeWatcher, error) {
+func newInboxTopChallengeWatcher(address ethcommon.Address, client *ethclient.Client, reader *l1Reader) (*inboxTopChallengeWatcher, error) {
 	bisectionChallenge, err := newBisectionChallengeWatcher(address, client)
 	if err != nil {
 		return nil, err
@@ -73,17 +73,15 @@ func newInboxTopChallengeWatcher(address ethcommon.Address, client *ethclient.Cl
 		contract:                  inboxTopContract,
 		client:                    client,
 		address:                   address,
-		topics:                    [][]ethcommon.Hash{tops},
+		logs: newLogFetcher(reader, ethereum.FilterQuery{
+			Addresses: []ethcommon.Address{address},
+			Topics:    [][]ethcommon.Hash{tops},
+		}),
 	}, nil
 }
 
 func (c *inboxTopChallengeWatcher) GetEvents(ctx context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
-	bh := blockId.HeaderHash.ToEthHash()
-	logs, err := c.client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &bh,
-		Addresses: []ethcommon.Address{c.address},
-		Topics:    c.topics,
-	})
+	logs, err := c.logs.blockLogs(ctx, blockId)
 	if err != nil {
 		return nil, err
 	}
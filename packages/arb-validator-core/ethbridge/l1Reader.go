@@ -0,0 +1,211 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// FetchConfig controls how headers and logs are read from L1
+type FetchConfig struct {
+	// MaxBlockRange is the most blocks covered by a single log query. The
+	// range used shrinks whenever a provider rejects a query as too large
+	// and grows back as queries succeed.
+	MaxBlockRange uint64
+	// CatchUpDepth is how far behind the L1 head a block must be before
+	// its logs are fetched as part of a range rather than by block hash.
+	// Blocks this deep are assumed not to be reorged.
+	CatchUpDepth uint64
+	// HeaderBatch is how many headers are fetched concurrently while
+	// catching up to the L1 head
+	HeaderBatch int
+	// MaxAttempts is how many times a failing request is tried, across
+	// all providers, before its error is returned
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultFetchConfig returns the settings used unless configured otherwise
+func DefaultFetchConfig() FetchConfig {
+	return FetchConfig{
+		MaxBlockRange:  2000,
+		CatchUpDepth:   100,
+		HeaderBatch:    20,
+		MaxAttempts:    8,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+func (c FetchConfig) Validate() error {
+	if c.MaxBlockRange == 0 {
+		return errors.New("max block range must be positive")
+	}
+	if c.HeaderBatch <= 0 {
+		return errors.New("header batch must be positive")
+	}
+	if c.MaxAttempts <= 0 {
+		return errors.New("max attempts must be positive")
+	}
+	if c.InitialBackoff <= 0 || c.MaxBackoff < c.InitialBackoff {
+		return errors.New("backoff must be positive and no more than the max backoff")
+	}
+	return nil
+}
+
+// l1Reader makes read requests to one of a list of redundant L1 providers,
+// retrying failed requests with backoff and failing over to the next
+// provider after each failure
+type l1Reader struct {
+	sync.Mutex
+	clients []*ethclient.Client
+	active  int
+	config  FetchConfig
+}
+
+func newL1Reader(client *ethclient.Client) *l1Reader {
+	return &l1Reader{
+		clients: []*ethclient.Client{client},
+		config:  DefaultFetchConfig(),
+	}
+}
+
+func (r *l1Reader) addClients(clients ...*ethclient.Client) {
+	r.Lock()
+	defer r.Unlock()
+	r.clients = append(r.clients, clients...)
+}
+
+func (r *l1Reader) setConfig(config FetchConfig) {
+	r.Lock()
+	defer r.Unlock()
+	r.config = config
+}
+
+func (r *l1Reader) fetchConfig() FetchConfig {
+	r.Lock()
+	defer r.Unlock()
+	return r.config
+}
+
+func (r *l1Reader) current() (int, *ethclient.Client, FetchConfig) {
+	r.Lock()
+	defer r.Unlock()
+	return r.active, r.clients[r.active], r.config
+}
+
+// failover moves to the provider after the failed one unless another
+// request has already done so
+func (r *l1Reader) failover(failed int) {
+	r.Lock()
+	defer r.Unlock()
+	if r.active != failed || len(r.clients) == 1 {
+		return
+	}
+	r.active = (failed + 1) % len(r.clients)
+	log.Println("Failing over to L1 provider", r.active)
+}
+
+// do runs the request f against the active provider until it succeeds, it
+// fails with an error that retrying can't fix or it runs out of attempts
+func (r *l1Reader) do(ctx context.Context, f func(client *ethclient.Client) error) error {
+	var backoff time.Duration
+	for attempt := 1; ; attempt++ {
+		index, client, config := r.current()
+		err := f(client)
+		if err == nil || !isRetryable(err) || ctx.Err() != nil || attempt >= config.MaxAttempts {
+			return err
+		}
+		log.Printf("L1 request to provider %v failed on attempt %v with error: %v", index, attempt, err)
+		r.failover(index)
+
+		if backoff == 0 {
+			backoff = config.InitialBackoff
+		} else {
+			backoff *= 2
+		}
+		if backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+func (r *l1Reader) headerByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := r.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		header, err = client.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+func (r *l1Reader) filterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := r.do(ctx, func(client *ethclient.Client) error {
+		var err error
+		logs, err = client.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+func isNotFound(err error) bool {
+	return err.Error() == ethereum.NotFound.Error()
+}
+
+// Messages used by common providers to reject log queries which cover too
+// many blocks or match too many logs
+var rangeTooLargeMessages = []string{
+	"query returned more than",
+	"response size exceeded",
+	"block range",
+	"range is too",
+	"is limited to",
+}
+
+func isRangeTooLarge(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, tooLarge := range rangeTooLargeMessages {
+		if strings.Contains(msg, tooLarge) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryable reports whether retrying the same request could succeed.
+// Missing blocks and oversized log queries are left to the caller.
+func isRetryable(err error) bool {
+	return !isNotFound(err) && !isRangeTooLarge(err)
+}
@@ -18,262 +18,169 @@ package ethbridge
 
 import (
 	"context"
-	"errors"
 	"log"
 	"math/big"
-
-	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
-
-	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"sync"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 )
 
-type maybeLog struct {
-	log types.Log
-	err error
+// logFetcher returns the logs matching a query block by block. Blocks far
+// enough behind the L1 head are served from logs fetched a range of blocks
+// at a time, so catching up takes one query per range instead of one per
+// block.
+type logFetcher struct {
+	sync.Mutex
+	reader *l1Reader
+	query  ethereum.FilterQuery
+
+	// blockRange is the number of blocks currently fetched at once
+	blockRange uint64
+	// head is the L1 height last seen, which is refreshed once it's no
+	// longer CatchUpDepth ahead of the block being fetched
+	head          uint64
+	nextHeadCheck uint64
+
+	cached    bool
+	cacheFrom uint64
+	cacheTo   uint64
+	cache     map[uint64][]types.Log
 }
 
-func getLogs(
-	ctx context.Context,
-	client *ethclient.Client,
-	filter ethereum.FilterQuery,
-	startHeight *common.TimeBlocks,
-	startIndex uint,
-) (<-chan maybeLog, error) {
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	streamingLogChan := make(chan types.Log)
-	logSub, err := client.SubscribeFilterLogs(ctx, filter, streamingLogChan)
-	if err != nil {
-		return nil, err
+func newLogFetcher(reader *l1Reader, query ethereum.FilterQuery) *logFetcher {
+	return &logFetcher{
+		reader:     reader,
+		query:      query,
+		blockRange: reader.fetchConfig().MaxBlockRange,
 	}
-	logChan := make(chan maybeLog, 1024)
-	go func() {
-		defer close(logChan)
-		defer logSub.Unsubscribe()
-
-		// Get initial old logs
-		filter.FromBlock = startHeight.AsInt()
-		filter.ToBlock = header.Number
-		log.Println("Filter1 from", filter.FromBlock, "to", filter.ToBlock)
-		logs, err := client.FilterLogs(ctx, filter)
-		if err != nil {
-			logChan <- maybeLog{err: err}
-			return
-		}
-		for _, ethLog := range logs {
-			if ethLog.BlockNumber > startHeight.AsInt().Uint64() || ethLog.Index >= startIndex {
-				logChan <- maybeLog{log: ethLog}
-			}
-		}
-
-		// Retrieve for log from stream
-		var ethStreamLog types.Log
-		var ok bool
-		select {
-		case <-ctx.Done():
-			return
-		case ethStreamLog, ok = <-streamingLogChan:
-			if !ok {
-				logChan <- maybeLog{err: errors.New("streamingLogChan terminated early1")}
-				return
-			}
-			log.Println("First stream log", ethStreamLog.BlockNumber)
-		case err := <-logSub.Err():
-			logChan <- maybeLog{err: err}
-			return
-		}
-
-		// If there was a gap between initial retrieval and the stream, fill it in
-		if ethStreamLog.BlockNumber > header.Number.Uint64()+1 {
-			filter.FromBlock = new(big.Int).Add(header.Number, big.NewInt(1))
-			filter.ToBlock = new(big.Int).Sub(new(big.Int).SetUint64(ethStreamLog.BlockNumber), big.NewInt(1))
-			log.Println("Filter2 from", filter.FromBlock, "to", filter.ToBlock)
-			logs, err := client.FilterLogs(ctx, filter)
-			if err != nil {
-				logChan <- maybeLog{err: err}
-				return
-			}
-			for _, ethLog := range logs {
-				logChan <- maybeLog{log: ethLog}
-			}
-		}
-
-		logChan <- maybeLog{log: ethStreamLog}
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case ethLog, ok := <-streamingLogChan:
-				if !ok {
-					logChan <- maybeLog{err: errors.New("streamingLogChan terminated early2")}
-					return
-				}
-				logChan <- maybeLog{log: ethLog}
-			case err := <-logSub.Err():
-				logChan <- maybeLog{err: err}
-				return
-			}
-		}
-	}()
+}
 
-	return logChan, nil
+func (f *logFetcher) covers(height uint64) bool {
+	return f.cached && height >= f.cacheFrom && height <= f.cacheTo
 }
 
-func getEvents(
-	ctx context.Context,
-	client *ethclient.Client,
-	filter ethereum.FilterQuery,
-	startHeight *common.TimeBlocks,
-	startIndex uint,
-	logTransformer func(types.Log) (arbbridge.Event, error),
-) (<-chan arbbridge.MaybeEvent, error) {
-	header, err := client.HeaderByNumber(ctx, nil)
-	if err != nil {
-		return nil, err
-	}
-	streamingLogChan := make(chan types.Log)
-	logSub, err := client.SubscribeFilterLogs(ctx, filter, streamingLogChan)
-	if err != nil {
-		return nil, err
-	}
-	logChan := make(chan arbbridge.MaybeEvent, 1024)
-	go func() {
-		defer close(logChan)
-		defer logSub.Unsubscribe()
+// blockLogs returns the logs in the given block which match the query
+func (f *logFetcher) blockLogs(ctx context.Context, blockId *common.BlockId) ([]types.Log, error) {
+	f.Lock()
+	defer f.Unlock()
 
-		// Get initial old logs
-		filter.FromBlock = startHeight.AsInt()
-		filter.ToBlock = header.Number
-		log.Println("Filter1 from", filter.FromBlock, "to", filter.ToBlock)
-		logs, err := client.FilterLogs(ctx, filter)
-		if err != nil {
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
+	height := blockId.Height.AsInt().Uint64()
+	if !f.covers(height) {
+		f.cached = false
+		f.cache = nil
+		if err := f.prefetch(ctx, height); err != nil {
+			return nil, err
 		}
-		for _, ethLog := range logs {
-			if ethLog.BlockNumber > startHeight.AsInt().Uint64() || ethLog.Index >= startIndex {
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			}
+	}
+	if f.covers(height) {
+		logs := f.cache[height]
+		if logsInBlock(logs, blockId) {
+			return logs, nil
 		}
+		log.Println("Fetched logs for block", height, "don't match its hash, refetching")
+		f.cached = false
+		f.cache = nil
+	}
 
-		// Retrieve for log from stream
-		var ethStreamLog types.Log
-		var ok bool
-		select {
-		case <-ctx.Done():
-			return
-		case ethStreamLog, ok = <-streamingLogChan:
-			if !ok {
-				logChan <- arbbridge.MaybeEvent{Err: errors.New("streamingLogChan terminated early1")}
-				return
-			}
-			log.Println("First stream log", ethStreamLog.BlockNumber)
-		case err := <-logSub.Err():
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
-		}
+	bh := blockId.HeaderHash.ToEthHash()
+	query := f.query
+	query.BlockHash = &bh
+	return f.reader.filterLogs(ctx, query)
+}
 
-		// If there was a gap between initial retrieval and the stream, fill it in
-		if ethStreamLog.BlockNumber > header.Number.Uint64()+1 {
-			filter.FromBlock = new(big.Int).Add(header.Number, big.NewInt(1))
-			filter.ToBlock = new(big.Int).Sub(new(big.Int).SetUint64(ethStreamLog.BlockNumber), big.NewInt(1))
-			log.Println("Filter2 from", filter.FromBlock, "to", filter.ToBlock)
-			logs, err := client.FilterLogs(ctx, filter)
-			if err != nil {
-				logChan <- arbbridge.MaybeEvent{Err: err}
-				return
-			}
-			for _, ethLog := range logs {
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			}
+// prefetch fills the cache with the logs of a range of blocks starting at
+// height, provided they're all at least CatchUpDepth behind the L1 head
+func (f *logFetcher) prefetch(ctx context.Context, height uint64) error {
+	config := f.reader.fetchConfig()
+	if height+config.CatchUpDepth > f.head {
+		if height < f.nextHeadCheck {
+			return nil
 		}
-
-		event, err := logTransformer(ethStreamLog)
+		header, err := f.reader.headerByNumber(ctx, nil)
 		if err != nil {
-			logChan <- arbbridge.MaybeEvent{Err: err}
-			return
+			return err
 		}
-		logChan <- arbbridge.MaybeEvent{Event: event}
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case ethLog, ok := <-streamingLogChan:
-				if !ok {
-					logChan <- arbbridge.MaybeEvent{Err: errors.New("streamingLogChan terminated early2")}
-					return
-				}
-				event, err := logTransformer(ethLog)
-				if err != nil {
-					logChan <- arbbridge.MaybeEvent{Err: err}
-					return
-				}
-				logChan <- arbbridge.MaybeEvent{Event: event}
-			case err := <-logSub.Err():
-				logChan <- arbbridge.MaybeEvent{Err: err}
-				return
-			}
+		f.head = header.Number.Uint64()
+		// Near the head, only look again once enough blocks have passed
+		// that this one could have fallen behind
+		f.nextHeadCheck = height + config.CatchUpDepth
+		if height+config.CatchUpDepth > f.head {
+			return nil
 		}
-	}()
-
-	return logChan, nil
-}
+	}
 
-func nextBlockHash(
-	ctx context.Context,
-	client *ethclient.Client,
-	prevBlock *common.BlockId,
-) (*common.BlockId, error) {
-	if prevBlock == nil {
-		header, err := client.HeaderByNumber(ctx, big.NewInt(0))
-		if err != nil {
-			return nil, err
-		}
-		return getBlockID(header), nil
-	} else {
-		header, err := client.HeaderByNumber(ctx, new(big.Int).Add(prevBlock.Height.AsInt(), big.NewInt(1)))
-		if err != nil {
-			return nil, err
-		}
-		if header.ParentHash != prevBlock.HeaderHash.ToEthHash() {
-			return nil, err
+	if f.blockRange == 0 || f.blockRange > config.MaxBlockRange {
+		f.blockRange = config.MaxBlockRange
+	}
+	to := f.head - config.CatchUpDepth
+	if height+f.blockRange-1 < to {
+		to = height + f.blockRange - 1
+	}
+	blockRange := f.blockRange
+	logs, err := f.filterRange(ctx, height, to)
+	if err != nil {
+		return err
+	}
+	// Grow the range back after a full sized query succeeds without
+	// being split
+	if f.blockRange == blockRange && to-height+1 == blockRange {
+		f.blockRange *= 2
+		if f.blockRange > config.MaxBlockRange {
+			f.blockRange = config.MaxBlockRange
 		}
-		return getBlockID(header), nil
 	}
+	f.cache = make(map[uint64][]types.Log)
+	for _, ethLog := range logs {
+		f.cache[ethLog.BlockNumber] = append(f.cache[ethLog.BlockNumber], ethLog)
+	}
+	f.cached = true
+	f.cacheFrom = height
+	f.cacheTo = to
+	return nil
 }
 
-func getNextLogs(
-	ctx context.Context,
-	client *ethclient.Client,
-	filter ethereum.FilterQuery,
-	prevBlock *common.BlockId,
-) ([]types.Log, error) {
-	nextBlock, err := nextBlockHash(ctx, client, prevBlock)
+// filterRange returns the logs matching the query between from and to
+// inclusive, splitting the range in half whenever the provider rejects it
+// as too large
+func (f *logFetcher) filterRange(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	query := f.query
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+	logs, err := f.reader.filterLogs(ctx, query)
+	if err == nil {
+		return logs, nil
+	}
+	if !isRangeTooLarge(err) || from == to {
+		return nil, err
+	}
+
+	log.Println("Splitting log query for blocks", from, "to", to, "after error:", err)
+	mid := from + (to-from)/2
+	if mid-from+1 < f.blockRange {
+		f.blockRange = mid - from + 1
+	}
+	first, err := f.filterRange(ctx, from, mid)
 	if err != nil {
 		return nil, err
 	}
-	blockHash := nextBlock.HeaderHash.ToEthHash()
-	return client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &blockHash,
-		Addresses: filter.Addresses,
-		Topics:    filter.Topics,
-	})
+	second, err := f.filterRange(ctx, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(first, second...), nil
+}
+
+// logsInBlock checks that logs fetched by height came from the block
+// they're being returned for
+func logsInBlock(logs []types.Log, blockId *common.BlockId) bool {
+	bh := blockId.HeaderHash.ToEthHash()
+	for _, ethLog := range logs {
+		if ethLog.BlockHash != bh {
+			return false
+		}
+	}
+	return true
 }
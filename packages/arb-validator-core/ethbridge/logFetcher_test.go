@@ -0,0 +1,258 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ethbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// fakeProvider serves a chain with one log every ten blocks, rejecting log
+// queries which cover more than maxRange blocks
+type fakeProvider struct {
+	sync.Mutex
+	head     uint64
+	maxRange uint64
+	down     bool
+	rangeLog []uint64
+	calls    int
+}
+
+func blockHash(height uint64) ethcommon.Hash {
+	return ethcommon.BigToHash(new(big.Int).SetUint64(height + 1))
+}
+
+func (p *fakeProvider) logs(from, to uint64) []types.Log {
+	var logs []types.Log
+	for height := from; height <= to; height++ {
+		if height%10 == 0 {
+			logs = append(logs, types.Log{
+				BlockNumber: height,
+				BlockHash:   blockHash(height),
+				Topics:      []ethcommon.Hash{},
+				Data:        []byte{},
+			})
+		}
+	}
+	return logs
+}
+
+func (p *fakeProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	result, err := p.handle(req.Method, req.Params)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+	if err != nil {
+		resp["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		resp["result"] = result
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (p *fakeProvider) handle(method string, params []json.RawMessage) (interface{}, error) {
+	p.Lock()
+	defer p.Unlock()
+	p.calls++
+	if p.down {
+		return nil, errors.New("service unavailable")
+	}
+	switch method {
+	case "eth_getBlockByNumber":
+		var number string
+		if err := json.Unmarshal(params[0], &number); err != nil {
+			return nil, err
+		}
+		height := p.head
+		if number != "latest" {
+			height = hexutil.MustDecodeUint64(number)
+		}
+		return &types.Header{
+			ParentHash: blockHash(height - 1),
+			Number:     new(big.Int).SetUint64(height),
+			Difficulty: big.NewInt(0),
+		}, nil
+	case "eth_getLogs":
+		var query struct {
+			BlockHash *ethcommon.Hash `json:"blockHash"`
+			FromBlock string          `json:"fromBlock"`
+			ToBlock   string          `json:"toBlock"`
+		}
+		if err := json.Unmarshal(params[0], &query); err != nil {
+			return nil, err
+		}
+		if query.BlockHash != nil {
+			height := query.BlockHash.Big().Uint64() - 1
+			return p.logs(height, height), nil
+		}
+		from := hexutil.MustDecodeUint64(query.FromBlock)
+		to := hexutil.MustDecodeUint64(query.ToBlock)
+		if to-from+1 > p.maxRange {
+			return nil, errors.New("query returned more than 10000 results")
+		}
+		p.rangeLog = append(p.rangeLog, from)
+		return p.logs(from, to), nil
+	}
+	return nil, errors.New("unsupported method " + method)
+}
+
+func dialFake(t *testing.T, p *fakeProvider) *ethclient.Client {
+	server := httptest.NewServer(p)
+	t.Cleanup(server.Close)
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func testFetchConfig() FetchConfig {
+	config := DefaultFetchConfig()
+	config.MaxBlockRange = 64
+	config.CatchUpDepth = 50
+	config.MaxAttempts = 3
+	config.InitialBackoff = time.Millisecond
+	config.MaxBackoff = time.Millisecond
+	return config
+}
+
+func blockIdAt(height uint64) *common.BlockId {
+	return &common.BlockId{
+		Height:     common.NewTimeBlocks(new(big.Int).SetUint64(height)),
+		HeaderHash: common.NewHashFromEth(blockHash(height)),
+	}
+}
+
+func TestLogFetcherCatchUp(t *testing.T) {
+	provider := &fakeProvider{head: 300, maxRange: 16}
+	reader := newL1Reader(dialFake(t, provider))
+	reader.setConfig(testFetchConfig())
+	fetcher := newLogFetcher(reader, ethereum.FilterQuery{})
+
+	ctx := context.Background()
+	for height := uint64(0); height <= 300; height++ {
+		logs, err := fetcher.blockLogs(ctx, blockIdAt(height))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := 0
+		if height%10 == 0 {
+			expected = 1
+		}
+		if len(logs) != expected {
+			t.Fatalf("expected %v logs at block %v but got %v", expected, height, len(logs))
+		}
+		if expected == 1 && logs[0].BlockNumber != height {
+			t.Fatalf("got log from block %v for block %v", logs[0].BlockNumber, height)
+		}
+	}
+
+	// Blocks up to 250 are behind the catch up depth, and after being
+	// rejected once the range settles at 16 blocks
+	if fetcher.blockRange > 16 {
+		t.Errorf("expected range to shrink to 16 blocks but it's %v", fetcher.blockRange)
+	}
+	if len(provider.rangeLog) != 16 {
+		t.Errorf("expected 16 range queries but got %v", len(provider.rangeLog))
+	}
+	if provider.calls > 120 {
+		t.Errorf("catching up took %v calls", provider.calls)
+	}
+}
+
+func TestLogFetcherReorgedRange(t *testing.T) {
+	provider := &fakeProvider{head: 300, maxRange: 1000}
+	reader := newL1Reader(dialFake(t, provider))
+	reader.setConfig(testFetchConfig())
+	fetcher := newLogFetcher(reader, ethereum.FilterQuery{})
+
+	// A block whose hash doesn't match the prefetched logs is fetched by
+	// its hash instead
+	reorged := blockIdAt(10)
+	reorged.HeaderHash = common.NewHashFromEth(blockHash(11))
+	logs, err := fetcher.blockLogs(context.Background(), reorged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 0 {
+		t.Errorf("expected no logs from reorged block but got %v", len(logs))
+	}
+}
+
+func TestL1ReaderFailover(t *testing.T) {
+	primary := &fakeProvider{head: 100, down: true}
+	fallback := &fakeProvider{head: 100}
+	reader := newL1Reader(dialFake(t, primary))
+	reader.addClients(dialFake(t, fallback))
+	reader.setConfig(testFetchConfig())
+
+	header, err := reader.headerByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Number.Uint64() != 100 {
+		t.Errorf("expected head 100 but got %v", header.Number)
+	}
+	if reader.active != 1 {
+		t.Errorf("expected to fail over to provider 1 but using %v", reader.active)
+	}
+
+	fallback.Lock()
+	fallback.down = true
+	fallback.Unlock()
+	if _, err := reader.headerByNumber(context.Background(), nil); err == nil {
+		t.Error("expected error once every provider is down")
+	}
+}
+
+func TestRangeTooLarge(t *testing.T) {
+	if !isRangeTooLarge(errors.New("query returned more than 10000 results")) {
+		t.Error("expected result limit to be a range error")
+	}
+	if isRetryable(errors.New("Log response size exceeded.")) {
+		t.Error("expected size limit not to be retried")
+	}
+	if !isRetryable(errors.New("429 Too Many Requests")) {
+		t.Error("expected rate limit to be retried")
+	}
+	if isRetryable(ethereum.NotFound) {
+		t.Error("expected missing block not to be retried")
+	}
+}
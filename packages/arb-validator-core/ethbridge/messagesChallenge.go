@@ -56,8 +56,12 @@ func (c *messagesChallenge) Bisect(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.Bisect(
-		c.auth.getAuth(ctx),
+		auth,
 		hashSliceToRaw(chainHashes),
 		hashSliceToRaw(segmentHashes),
 		chainLength,
@@ -76,8 +80,12 @@ func (c *messagesChallenge) OneStepProofTransactionMessage(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProofTransactionMessage(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		lowerHashB,
 		msg.Chain.ToEthAddress(),
@@ -102,8 +110,12 @@ func (c *messagesChallenge) OneStepProofEthMessage(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProofEthMessage(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		lowerHashB,
 		msg.To.ToEthAddress(),
@@ -138,8 +150,12 @@ func (c *messagesChallenge) OneStepProofERC20Message(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProofERC20Message(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		lowerHashB,
 		msg.To.ToEthAddress(),
@@ -163,8 +179,12 @@ func (c *messagesChallenge) OneStepProofERC721Message(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProofERC721Message(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		lowerHashB,
 		msg.To.ToEthAddress(),
@@ -188,8 +208,12 @@ func (c *messagesChallenge) OneStepProofContractTransactionMessage(
 ) error {
 	c.auth.Lock()
 	defer c.auth.Unlock()
+	auth, err := c.auth.getAuth(ctx, CriticalPriority)
+	if err != nil {
+		return err
+	}
 	tx, err := c.contract.OneStepProofContractTransactionMessage(
-		c.auth.getAuth(ctx),
+		auth,
 		lowerHashA,
 		lowerHashB,
 		msg.To.ToEthAddress(),
@@ -50,10 +50,10 @@ type messagesChallengeWatcher struct {
 	contract *messageschallenge.MessagesChallenge
 	client   *ethclient.Client
 	address  ethcommon.Address
-	topics   [][]ethcommon.Hash
+	logs     *logFetcher
 }
 
-func newMessagesChallengeWatcher(address ethcommon.Address, client *ethclient.Client) (*messagesChallengeWatcher, error) {
+func newMessagesChallengeWatcher(address ethcommon.Address, client *ethclient.Client, reader *l1Reader) (*messagesChallengeWatcher, error) {
 	bisectionChallenge, err := newBisectionChallengeWatcher(address, client)
 	if err != nil {
 		return nil, err
@@ -74,17 +74,15 @@ func newMessagesChallengeWatcher(address ethcommon.Address, client *ethclient.Cl
 		contract:                  messagesContract,
 		client:                    client,
 		address:                   address,
-		topics:                    [][]ethcommon.Hash{tops},
+		logs: newLogFetcher(reader, ethereum.FilterQuery{
+			Addresses: []ethcommon.Address{address},
+			Topics:    [][]ethcommon.Hash{tops},
+		}),
 	}, nil
 }
 
 func (c *messagesChallengeWatcher) GetEvents(ctx context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
-	bh := blockId.HeaderHash.ToEthHash()
-	logs, err := c.client.FilterLogs(ctx, ethereum.FilterQuery{
-		BlockHash: &bh,
-		Addresses: []ethcommon.Address{c.address},
-		Topics:    c.topics,
-	})
+	logs, err := c.logs.blockLogs(ctx, blockId)
 	if err != nil {
 		return nil, err
 	}
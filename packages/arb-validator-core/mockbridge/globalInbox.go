@@ -18,7 +18,10 @@ package mockbridge
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 
@@ -26,9 +29,19 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 )
 
+// GlobalInbox keeps the inbox's balances in memory. Like the contract,
+// deposits are held by the chain they were sent to until the chain pays
+// them out with DeliverOutgoing, and withdrawals pay the sender.
 type GlobalInbox struct {
 	//GlobalInbox *globalinbox.GlobalInbox
 	client arbbridge.ArbClient
+
+	sync.Mutex
+	sender        common.Address
+	messageCounts map[common.Address]*big.Int
+	eth           map[common.Address]*big.Int
+	erc20s        map[common.Address]map[common.Address]*big.Int
+	erc721s       map[common.Address]map[common.Address][]*big.Int
 }
 
 func NewGlobalInbox(address common.Address, client arbbridge.ArbClient) (*GlobalInbox, error) {
@@ -36,7 +49,18 @@ func NewGlobalInbox(address common.Address, client arbbridge.ArbClient) (*Global
 	//if err != nil {
 	//	return nil, errors2.Wrap(err, "Failed to connect to GlobalInbox")
 	//}
-	return &GlobalInbox{client}, nil
+	var sender common.Address
+	if authClient, ok := client.(arbbridge.ArbAuthClient); ok {
+		sender = authClient.Address()
+	}
+	return &GlobalInbox{
+		client:        client,
+		sender:        sender,
+		messageCounts: make(map[common.Address]*big.Int),
+		eth:           make(map[common.Address]*big.Int),
+		erc20s:        make(map[common.Address]map[common.Address]*big.Int),
+		erc721s:       make(map[common.Address]map[common.Address][]*big.Int),
+	}, nil
 }
 
 func (con *GlobalInbox) SendTransactionMessage(
@@ -59,13 +83,37 @@ func (con *GlobalInbox) DeliverTransactionBatch(
 	return nil
 }
 
+// nextMessageNum numbers a chain's messages from 1 like the contract does.
+// Must be called with con locked.
+func (con *GlobalInbox) nextMessageNum(chain common.Address) *big.Int {
+	count, ok := con.messageCounts[chain]
+	if !ok {
+		count = big.NewInt(0)
+		con.messageCounts[chain] = count
+	}
+	count.Add(count, big.NewInt(1))
+	return new(big.Int).Set(count)
+}
+
 func (con *GlobalInbox) DepositEthMessage(
 	ctx context.Context,
 	vmAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
-	return nil
+) (arbbridge.MessageDeliveredEvent, error) {
+	con.Lock()
+	defer con.Unlock()
+	con.addEth(vmAddress, value)
+	return arbbridge.MessageDeliveredEvent{
+		Message: message.DeliveredEth{
+			Eth: message.Eth{
+				To:    destination,
+				From:  con.sender,
+				Value: value,
+			},
+			MessageNum: con.nextMessageNum(vmAddress),
+		},
+	}, nil
 }
 
 func (con *GlobalInbox) DepositERC20Message(
@@ -74,8 +122,21 @@ func (con *GlobalInbox) DepositERC20Message(
 	tokenAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
-	return nil
+) (arbbridge.MessageDeliveredEvent, error) {
+	con.Lock()
+	defer con.Unlock()
+	con.addERC20(vmAddress, tokenAddress, value)
+	return arbbridge.MessageDeliveredEvent{
+		Message: message.DeliveredERC20{
+			ERC20: message.ERC20{
+				To:           destination,
+				From:         con.sender,
+				TokenAddress: tokenAddress,
+				Value:        value,
+			},
+			MessageNum: con.nextMessageNum(vmAddress),
+		},
+	}, nil
 }
 
 func (con *GlobalInbox) DepositERC721Message(
@@ -84,19 +145,207 @@ func (con *GlobalInbox) DepositERC721Message(
 	tokenAddress common.Address,
 	destination common.Address,
 	value *big.Int,
-) error {
+) (arbbridge.MessageDeliveredEvent, error) {
+	con.Lock()
+	defer con.Unlock()
+	con.addERC721(vmAddress, tokenAddress, value)
+	return arbbridge.MessageDeliveredEvent{
+		Message: message.DeliveredERC721{
+			ERC721: message.ERC721{
+				To:           destination,
+				From:         con.sender,
+				TokenAddress: tokenAddress,
+				Id:           value,
+			},
+			MessageNum: con.nextMessageNum(vmAddress),
+		},
+	}, nil
+}
+
+// DeliverOutgoing pays out the messages sent by a chain from the funds it
+// holds, as the rollup does when it confirms an assertion
+func (con *GlobalInbox) DeliverOutgoing(chain common.Address, msgs []message.UnsentMessage) error {
+	con.Lock()
+	defer con.Unlock()
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case message.Eth:
+			if err := con.removeEth(chain, msg.Value); err != nil {
+				return err
+			}
+			con.addEth(msg.To, msg.Value)
+		case message.ERC20:
+			if err := con.removeERC20(chain, msg.TokenAddress, msg.Value); err != nil {
+				return err
+			}
+			con.addERC20(msg.To, msg.TokenAddress, msg.Value)
+		case message.ERC721:
+			if err := con.removeERC721(chain, msg.TokenAddress, msg.Id); err != nil {
+				return err
+			}
+			con.addERC721(msg.To, msg.TokenAddress, msg.Id)
+		default:
+			return fmt.Errorf("can't pay out message %T", msg)
+		}
+	}
 	return nil
 }
 
+func (con *GlobalInbox) addEth(owner common.Address, value *big.Int) {
+	balance, ok := con.eth[owner]
+	if !ok {
+		balance = big.NewInt(0)
+		con.eth[owner] = balance
+	}
+	balance.Add(balance, value)
+}
+
+func (con *GlobalInbox) removeEth(owner common.Address, value *big.Int) error {
+	balance, ok := con.eth[owner]
+	if !ok || balance.Cmp(value) < 0 {
+		return errors.New("insufficient eth balance")
+	}
+	balance.Sub(balance, value)
+	return nil
+}
+
+func (con *GlobalInbox) addERC20(owner common.Address, token common.Address, value *big.Int) {
+	tokens, ok := con.erc20s[owner]
+	if !ok {
+		tokens = make(map[common.Address]*big.Int)
+		con.erc20s[owner] = tokens
+	}
+	balance, ok := tokens[token]
+	if !ok {
+		balance = big.NewInt(0)
+		tokens[token] = balance
+	}
+	balance.Add(balance, value)
+}
+
+func (con *GlobalInbox) removeERC20(owner common.Address, token common.Address, value *big.Int) error {
+	balance, ok := con.erc20s[owner][token]
+	if !ok || balance.Cmp(value) < 0 {
+		return errors.New("insufficient token balance")
+	}
+	balance.Sub(balance, value)
+	if balance.Sign() == 0 {
+		delete(con.erc20s[owner], token)
+	}
+	return nil
+}
+
+func (con *GlobalInbox) addERC721(owner common.Address, token common.Address, id *big.Int) {
+	tokens, ok := con.erc721s[owner]
+	if !ok {
+		tokens = make(map[common.Address][]*big.Int)
+		con.erc721s[owner] = tokens
+	}
+	tokens[token] = append(tokens[token], id)
+}
+
+func (con *GlobalInbox) removeERC721(owner common.Address, token common.Address, id *big.Int) error {
+	ids := con.erc721s[owner][token]
+	for i, owned := range ids {
+		if owned.Cmp(id) == 0 {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			if len(ids) == 0 {
+				delete(con.erc721s[owner], token)
+			} else {
+				con.erc721s[owner][token] = ids
+			}
+			return nil
+		}
+	}
+	return errors.New("token isn't owned")
+}
+
 func (con *GlobalInbox) GetTokenBalance(
 	ctx context.Context,
 	user common.Address,
 	tokenContract common.Address,
 ) (*big.Int, error) {
-	//return con.GlobalInbox.GetTokenBalance(
-	//	auth,
-	//	tokenContract,
-	//	user,
-	//)
-	return big.NewInt(0), nil
+	con.Lock()
+	defer con.Unlock()
+	balance, ok := con.erc20s[user][tokenContract]
+	if !ok {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).Set(balance), nil
+}
+
+func (con *GlobalInbox) GetEthBalance(
+	ctx context.Context,
+	user common.Address,
+) (*big.Int, error) {
+	con.Lock()
+	defer con.Unlock()
+	balance, ok := con.eth[user]
+	if !ok {
+		return big.NewInt(0), nil
+	}
+	return new(big.Int).Set(balance), nil
+}
+
+func (con *GlobalInbox) GetERC721Tokens(
+	ctx context.Context,
+	user common.Address,
+	tokenContract common.Address,
+) ([]*big.Int, error) {
+	con.Lock()
+	defer con.Unlock()
+	return append([]*big.Int(nil), con.erc721s[user][tokenContract]...), nil
+}
+
+func (con *GlobalInbox) GetOwnedERC20s(
+	ctx context.Context,
+	user common.Address,
+) ([]common.Address, error) {
+	con.Lock()
+	defer con.Unlock()
+	tokens := make([]common.Address, 0, len(con.erc20s[user]))
+	for token := range con.erc20s[user] {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (con *GlobalInbox) GetOwnedERC721s(
+	ctx context.Context,
+	user common.Address,
+) ([]common.Address, error) {
+	con.Lock()
+	defer con.Unlock()
+	tokens := make([]common.Address, 0, len(con.erc721s[user]))
+	for token := range con.erc721s[user] {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (con *GlobalInbox) WithdrawEth(ctx context.Context) error {
+	con.Lock()
+	defer con.Unlock()
+	balance, ok := con.eth[con.sender]
+	if !ok || balance.Sign() == 0 {
+		return errors.New("no eth to withdraw")
+	}
+	delete(con.eth, con.sender)
+	return nil
+}
+
+func (con *GlobalInbox) WithdrawERC20(ctx context.Context, tokenContract common.Address) error {
+	con.Lock()
+	defer con.Unlock()
+	if _, ok := con.erc20s[con.sender][tokenContract]; !ok {
+		return errors.New("no tokens to withdraw")
+	}
+	delete(con.erc20s[con.sender], tokenContract)
+	return nil
+}
+
+func (con *GlobalInbox) WithdrawERC721(ctx context.Context, tokenContract common.Address, id *big.Int) error {
+	con.Lock()
+	defer con.Unlock()
+	return con.removeERC721(con.sender, tokenContract, id)
 }
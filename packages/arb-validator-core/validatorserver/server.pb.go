@@ -24,6 +24,47 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// How settled a result is, from least to most final
+type Finality int32
+
+const (
+	// Computed locally but not part of any assertion on chain
+	Finality_PENDING Finality = 0
+	// Part of an assertion co-signed by every unanimous validator which
+	// isn't on chain yet
+	Finality_UNANIMOUS Finality = 1
+	// Part of an assertion on chain that hasn't been confirmed
+	Finality_ASSERTED Finality = 2
+	// Part of an assertion that was confirmed after its grace period
+	Finality_CONFIRMED Finality = 3
+	// Confirmed in an L1 block deep enough that it won't be reorged
+	Finality_L1_FINAL Finality = 4
+)
+
+var Finality_name = map[int32]string{
+	0: "PENDING",
+	1: "UNANIMOUS",
+	2: "ASSERTED",
+	3: "CONFIRMED",
+	4: "L1_FINAL",
+}
+
+var Finality_value = map[string]int32{
+	"PENDING":   0,
+	"UNANIMOUS": 1,
+	"ASSERTED":  2,
+	"CONFIRMED": 3,
+	"L1_FINAL":  4,
+}
+
+func (x Finality) String() string {
+	return proto.EnumName(Finality_name, int32(x))
+}
+
+func (Finality) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{0}
+}
+
 type LogInfo struct {
 	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	BlockHash            string   `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
@@ -33,6 +74,7 @@ type LogInfo struct {
 	Topics               []string `protobuf:"bytes,6,rep,name=topics,proto3" json:"topics,omitempty"`
 	TransactionIndex     string   `protobuf:"bytes,7,opt,name=transactionIndex,proto3" json:"transactionIndex,omitempty"`
 	TransactionHash      string   `protobuf:"bytes,8,opt,name=transactionHash,proto3" json:"transactionHash,omitempty"`
+	Finality             Finality `protobuf:"varint,9,opt,name=finality,proto3,enum=validatorserver.Finality" json:"finality,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -119,6 +161,13 @@ func (m *LogInfo) GetTransactionHash() string {
 	return ""
 }
 
+func (m *LogInfo) GetFinality() Finality {
+	if m != nil {
+		return m.Finality
+	}
+	return Finality_PENDING
+}
+
 type FindLogsArgs struct {
 	FromHeight           string   `protobuf:"bytes,1,opt,name=fromHeight,proto3" json:"fromHeight,omitempty"`
 	ToHeight             string   `protobuf:"bytes,2,opt,name=toHeight,proto3" json:"toHeight,omitempty"`
@@ -267,6 +316,7 @@ type GetMessageResultReply struct {
 	LogPostHash          string   `protobuf:"bytes,4,opt,name=logPostHash,proto3" json:"logPostHash,omitempty"`
 	LogValHashes         []string `protobuf:"bytes,5,rep,name=logValHashes,proto3" json:"logValHashes,omitempty"`
 	OnChainTxHash        string   `protobuf:"bytes,6,opt,name=onChainTxHash,proto3" json:"onChainTxHash,omitempty"`
+	Finality             Finality `protobuf:"varint,7,opt,name=finality,proto3,enum=validatorserver.Finality" json:"finality,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -339,6 +389,13 @@ func (m *GetMessageResultReply) GetOnChainTxHash() string {
 	return ""
 }
 
+func (m *GetMessageResultReply) GetFinality() Finality {
+	if m != nil {
+		return m.Finality
+	}
+	return Finality_PENDING
+}
+
 type GetAssertionCountArgs struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -479,10 +536,107 @@ func (m *GetVMInfoReply) GetVmID() string {
 	return ""
 }
 
+type GetInboxCountArgs struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInboxCountArgs) Reset()         { *m = GetInboxCountArgs{} }
+func (m *GetInboxCountArgs) String() string { return proto.CompactTextString(m) }
+func (*GetInboxCountArgs) ProtoMessage()    {}
+func (*GetInboxCountArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{9}
+}
+
+func (m *GetInboxCountArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInboxCountArgs.Unmarshal(m, b)
+}
+func (m *GetInboxCountArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInboxCountArgs.Marshal(b, m, deterministic)
+}
+func (m *GetInboxCountArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInboxCountArgs.Merge(m, src)
+}
+func (m *GetInboxCountArgs) XXX_Size() int {
+	return xxx_messageInfo_GetInboxCountArgs.Size(m)
+}
+func (m *GetInboxCountArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInboxCountArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInboxCountArgs proto.InternalMessageInfo
+
+// Message counts of the chain's inbox as hex numbers. A message with number
+// n (counting from 1) has been read by the chain once asserted reaches n.
+type GetInboxCountReply struct {
+	Delivered            string   `protobuf:"bytes,1,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Asserted             string   `protobuf:"bytes,2,opt,name=asserted,proto3" json:"asserted,omitempty"`
+	Confirmed            string   `protobuf:"bytes,3,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInboxCountReply) Reset()         { *m = GetInboxCountReply{} }
+func (m *GetInboxCountReply) String() string { return proto.CompactTextString(m) }
+func (*GetInboxCountReply) ProtoMessage()    {}
+func (*GetInboxCountReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ad098daeda4239f7, []int{10}
+}
+
+func (m *GetInboxCountReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInboxCountReply.Unmarshal(m, b)
+}
+func (m *GetInboxCountReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInboxCountReply.Marshal(b, m, deterministic)
+}
+func (m *GetInboxCountReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInboxCountReply.Merge(m, src)
+}
+func (m *GetInboxCountReply) XXX_Size() int {
+	return xxx_messageInfo_GetInboxCountReply.Size(m)
+}
+func (m *GetInboxCountReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInboxCountReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInboxCountReply proto.InternalMessageInfo
+
+func (m *GetInboxCountReply) GetDelivered() string {
+	if m != nil {
+		return m.Delivered
+	}
+	return ""
+}
+
+func (m *GetInboxCountReply) GetAsserted() string {
+	if m != nil {
+		return m.Asserted
+	}
+	return ""
+}
+
+func (m *GetInboxCountReply) GetConfirmed() string {
+	if m != nil {
+		return m.Confirmed
+	}
+	return ""
+}
+
 type CallMessageArgs struct {
-	ContractAddress      string   `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
-	Sender               string   `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
-	Data                 string   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	ContractAddress string `protobuf:"bytes,1,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	Sender          string `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Data            string `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// "latest" (the default if empty), "pending" to first apply messages
+	// delivered on L1 but not yet asserted, or a hex L1 block height
+	BlockHeight string `protobuf:"bytes,4,opt,name=blockHeight,proto3" json:"blockHeight,omitempty"`
+	// If set, the call runs against the machine after the assertion that
+	// created this node instead
+	NodeHash string `protobuf:"bytes,5,opt,name=nodeHash,proto3" json:"nodeHash,omitempty"`
+	// Limits the call to this many steps. Zero, or anything above the
+	// validator's own limit, uses the validator's limit.
+	MaxSteps             uint64   `protobuf:"varint,6,opt,name=maxSteps,proto3" json:"maxSteps,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -492,7 +646,7 @@ func (m *CallMessageArgs) Reset()         { *m = CallMessageArgs{} }
 func (m *CallMessageArgs) String() string { return proto.CompactTextString(m) }
 func (*CallMessageArgs) ProtoMessage()    {}
 func (*CallMessageArgs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{9}
+	return fileDescriptor_ad098daeda4239f7, []int{11}
 }
 
 func (m *CallMessageArgs) XXX_Unmarshal(b []byte) error {
@@ -534,8 +688,30 @@ func (m *CallMessageArgs) GetData() string {
 	return ""
 }
 
+func (m *CallMessageArgs) GetBlockHeight() string {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return ""
+}
+
+func (m *CallMessageArgs) GetNodeHash() string {
+	if m != nil {
+		return m.NodeHash
+	}
+	return ""
+}
+
+func (m *CallMessageArgs) GetMaxSteps() uint64 {
+	if m != nil {
+		return m.MaxSteps
+	}
+	return 0
+}
+
 type CallMessageReply struct {
 	RawVal               string   `protobuf:"bytes,1,opt,name=rawVal,proto3" json:"rawVal,omitempty"`
+	Finality             Finality `protobuf:"varint,2,opt,name=finality,proto3,enum=validatorserver.Finality" json:"finality,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -545,7 +721,7 @@ func (m *CallMessageReply) Reset()         { *m = CallMessageReply{} }
 func (m *CallMessageReply) String() string { return proto.CompactTextString(m) }
 func (*CallMessageReply) ProtoMessage()    {}
 func (*CallMessageReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ad098daeda4239f7, []int{10}
+	return fileDescriptor_ad098daeda4239f7, []int{12}
 }
 
 func (m *CallMessageReply) XXX_Unmarshal(b []byte) error {
@@ -573,7 +749,15 @@ func (m *CallMessageReply) GetRawVal() string {
 	return ""
 }
 
+func (m *CallMessageReply) GetFinality() Finality {
+	if m != nil {
+		return m.Finality
+	}
+	return Finality_PENDING
+}
+
 func init() {
+	proto.RegisterEnum("validatorserver.Finality", Finality_name, Finality_value)
 	proto.RegisterType((*LogInfo)(nil), "validatorserver.LogInfo")
 	proto.RegisterType((*FindLogsArgs)(nil), "validatorserver.FindLogsArgs")
 	proto.RegisterType((*FindLogsReply)(nil), "validatorserver.FindLogsReply")
@@ -583,6 +767,8 @@ func init() {
 	proto.RegisterType((*GetAssertionCountReply)(nil), "validatorserver.GetAssertionCountReply")
 	proto.RegisterType((*GetVMInfoArgs)(nil), "validatorserver.GetVMInfoArgs")
 	proto.RegisterType((*GetVMInfoReply)(nil), "validatorserver.GetVMInfoReply")
+	proto.RegisterType((*GetInboxCountArgs)(nil), "validatorserver.GetInboxCountArgs")
+	proto.RegisterType((*GetInboxCountReply)(nil), "validatorserver.GetInboxCountReply")
 	proto.RegisterType((*CallMessageArgs)(nil), "validatorserver.CallMessageArgs")
 	proto.RegisterType((*CallMessageReply)(nil), "validatorserver.CallMessageReply")
 }
@@ -590,48 +776,62 @@ func init() {
 func init() { proto.RegisterFile("server.proto", fileDescriptor_ad098daeda4239f7) }
 
 var fileDescriptor_ad098daeda4239f7 = []byte{
-	// 656 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0x56, 0x9a, 0x8f, 0x26, 0xd3, 0x8f, 0xf4, 0x5d, 0xf5, 0x2d, 0x56, 0x04, 0x25, 0x58, 0xa5,
-	0x44, 0x15, 0x4d, 0xa4, 0x72, 0x46, 0xa2, 0x14, 0xd1, 0x56, 0x4a, 0x2b, 0x64, 0xa1, 0x1c, 0xb8,
-	0x6d, 0xec, 0x8d, 0x63, 0x75, 0xe3, 0x8d, 0x76, 0xd7, 0xa5, 0x48, 0x5c, 0xf8, 0x75, 0x9c, 0xf9,
-	0x47, 0x68, 0x67, 0x1d, 0xc7, 0xb1, 0x53, 0x7a, 0xf3, 0x3c, 0xf3, 0xb5, 0xcf, 0x33, 0x33, 0x32,
-	0x6c, 0x2b, 0x26, 0xef, 0x99, 0xec, 0xcf, 0xa5, 0xd0, 0x82, 0xb4, 0xef, 0x29, 0x8f, 0x02, 0xaa,
-	0x85, 0xb4, 0xb0, 0xfb, 0x6b, 0x03, 0x36, 0x87, 0x22, 0xbc, 0x8e, 0x27, 0x82, 0x38, 0xb0, 0x49,
-	0x83, 0x40, 0x32, 0xa5, 0x9c, 0x4a, 0xb7, 0xd2, 0x6b, 0x79, 0x0b, 0x93, 0x3c, 0x87, 0xd6, 0x98,
-	0x0b, 0xff, 0xee, 0x8a, 0xaa, 0xa9, 0xb3, 0x81, 0xbe, 0x25, 0x40, 0xba, 0xb0, 0x85, 0xc6, 0x6d,
-	0x32, 0x1b, 0x33, 0xe9, 0x54, 0xd1, 0x9f, 0x87, 0x08, 0x81, 0x5a, 0x40, 0x35, 0x75, 0x6a, 0xe8,
-	0xc2, 0x6f, 0xd2, 0x81, 0x26, 0x37, 0x8d, 0x03, 0xf6, 0xe0, 0xd4, 0x11, 0xcf, 0x6c, 0x72, 0x00,
-	0x0d, 0x2d, 0xe6, 0x91, 0xaf, 0x9c, 0x46, 0xb7, 0xda, 0x6b, 0x79, 0xa9, 0x45, 0x4e, 0x60, 0x4f,
-	0x4b, 0x1a, 0x2b, 0xea, 0xeb, 0x48, 0xc4, 0x36, 0x77, 0x13, 0x73, 0x4b, 0x38, 0xe9, 0x41, 0x3b,
-	0x87, 0xe1, 0xcb, 0x9b, 0x18, 0x5a, 0x84, 0xdd, 0x9f, 0xb0, 0xfd, 0x39, 0x8a, 0x83, 0xa1, 0x08,
-	0xd5, 0xb9, 0x0c, 0x15, 0x39, 0x04, 0x98, 0x48, 0x31, 0xbb, 0x62, 0x51, 0x38, 0xd5, 0xa9, 0x14,
-	0x39, 0xc4, 0xbc, 0x5c, 0x8b, 0xd4, 0x6b, 0xc5, 0xc8, 0xec, 0xbc, 0x86, 0xd5, 0x55, 0x0d, 0x97,
-	0x9c, 0x6a, 0x79, 0x4e, 0xee, 0x7b, 0xd8, 0x59, 0x74, 0xf7, 0xd8, 0x9c, 0xff, 0x20, 0x6f, 0xa1,
-	0xc6, 0x45, 0x68, 0xc3, 0xb6, 0xce, 0x9c, 0x7e, 0x61, 0x64, 0xfd, 0x74, 0x5c, 0x1e, 0x46, 0xb9,
-	0x7d, 0xd8, 0xbf, 0x64, 0xfa, 0x86, 0x29, 0x45, 0x43, 0xe6, 0x31, 0x95, 0x70, 0x8d, 0x24, 0x4c,
-	0xbb, 0x07, 0x64, 0x6d, 0x09, 0xa4, 0x96, 0xfb, 0xa7, 0x02, 0xff, 0x17, 0x13, 0x6c, 0xdf, 0x7d,
-	0xa8, 0x4f, 0x44, 0x12, 0x07, 0x98, 0xd0, 0xf4, 0xac, 0x61, 0xea, 0x48, 0xfa, 0x7d, 0x44, 0x79,
-	0x4a, 0x35, 0xb5, 0x8c, 0x48, 0x5c, 0x84, 0x5f, 0x24, 0xc3, 0x1e, 0x96, 0x6b, 0x0e, 0x31, 0x4b,
-	0x61, 0x2c, 0xa1, 0x34, 0x06, 0xd8, 0xc9, 0xe7, 0x21, 0xe2, 0xc2, 0x36, 0x17, 0xe1, 0x88, 0x72,
-	0x63, 0x31, 0xe5, 0xd4, 0x51, 0x96, 0x15, 0x8c, 0x1c, 0xc1, 0x8e, 0x88, 0x2f, 0xa6, 0x34, 0x8a,
-	0xbf, 0x5a, 0x32, 0x0d, 0xac, 0xb3, 0x0a, 0xba, 0xcf, 0x90, 0xd2, 0xb9, 0x52, 0x4c, 0x9a, 0xa1,
-	0x5e, 0x88, 0x24, 0x46, 0x11, 0xdc, 0x0f, 0x70, 0x50, 0x72, 0x58, 0xb2, 0xc7, 0xb0, 0x4b, 0x57,
-	0x60, 0x64, 0x5d, 0xf7, 0x0a, 0xa8, 0xdb, 0x86, 0x9d, 0x4b, 0xa6, 0x47, 0x37, 0x46, 0x71, 0x2c,
-	0x79, 0x04, 0xbb, 0x19, 0x60, 0x4b, 0x11, 0xa8, 0xdd, 0xcf, 0xae, 0x3f, 0xa5, 0x3a, 0xe3, 0xb7,
-	0x1b, 0x42, 0xfb, 0x82, 0x72, 0x9e, 0xaa, 0x8c, 0x03, 0xe9, 0x41, 0xdb, 0x17, 0xb1, 0x96, 0xd4,
-	0xd7, 0xe7, 0x2b, 0x57, 0x56, 0x84, 0x8d, 0xe4, 0x8a, 0xc5, 0x01, 0x93, 0x0b, 0xc9, 0xad, 0x95,
-	0x5d, 0x51, 0x75, 0x79, 0x45, 0xee, 0x09, 0xec, 0xe5, 0x1a, 0xd9, 0x07, 0x2d, 0x47, 0x56, 0xc9,
-	0x8f, 0xec, 0xec, 0x77, 0x15, 0xda, 0x9e, 0xe0, 0x3c, 0x99, 0x8f, 0x16, 0x2b, 0x45, 0x28, 0xec,
-	0x15, 0xb7, 0x81, 0xbc, 0x2e, 0xad, 0xdc, 0xba, 0x0d, 0xeb, 0x1c, 0x3f, 0x19, 0x66, 0x9f, 0xe3,
-	0xc1, 0x56, 0xee, 0x89, 0xa4, 0x5b, 0x4a, 0x2b, 0x28, 0xd5, 0x79, 0xf5, 0xaf, 0x08, 0x5b, 0xf3,
-	0x1a, 0x9a, 0x8b, 0xa3, 0x21, 0x2f, 0x4a, 0xe1, 0xf9, 0x6b, 0xee, 0x1c, 0x3e, 0xea, 0xb6, 0xa5,
-	0x02, 0xf8, 0xaf, 0xb4, 0x23, 0x64, 0x2d, 0xb7, 0xf2, 0x82, 0x75, 0xde, 0x3c, 0x1d, 0x67, 0xbb,
-	0x0c, 0xa1, 0x95, 0xad, 0x0d, 0x39, 0x5c, 0x97, 0xb5, 0xdc, 0xb1, 0xce, 0xcb, 0xc7, 0xfd, 0x58,
-	0xed, 0xe3, 0xed, 0xb7, 0x61, 0x18, 0xe9, 0x69, 0x32, 0xee, 0xfb, 0x62, 0x36, 0x10, 0x93, 0x89,
-	0x6f, 0xae, 0x81, 0xd3, 0xb1, 0x1a, 0x50, 0x39, 0x8e, 0xb4, 0x4c, 0x66, 0x83, 0x39, 0xf5, 0xef,
-	0x68, 0xc8, 0x10, 0x39, 0xcd, 0xea, 0x9d, 0xfa, 0x42, 0xb2, 0x41, 0xa1, 0xfc, 0xb8, 0x81, 0x7f,
-	0x87, 0x77, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xa6, 0x2c, 0xe5, 0xf9, 0x2d, 0x06, 0x00, 0x00,
+	// 867 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xc6, 0xf6, 0x26, 0xb6, 0x4f, 0xe2, 0xd8, 0x1d, 0x4a, 0x59, 0xac, 0x12, 0xcc, 0x52, 0x8a,
+	0x55, 0x51, 0x47, 0x04, 0x71, 0x89, 0x84, 0xc9, 0x5f, 0x57, 0x72, 0xb6, 0x65, 0xd3, 0x46, 0x88,
+	0x1b, 0x34, 0xde, 0x1d, 0x6f, 0x56, 0x1d, 0xef, 0x58, 0x33, 0xe3, 0x90, 0x4a, 0x3c, 0x00, 0xaf,
+	0xc1, 0x5b, 0x70, 0xcb, 0x9b, 0x55, 0x33, 0xb3, 0xff, 0x76, 0x7f, 0xee, 0x7c, 0xbe, 0xf3, 0xb7,
+	0xe7, 0x9b, 0xef, 0x1c, 0x19, 0xf6, 0x05, 0xe1, 0xb7, 0x84, 0x4f, 0x56, 0x9c, 0x49, 0x86, 0xfa,
+	0xb7, 0x98, 0xc6, 0x21, 0x96, 0x8c, 0x1b, 0xd8, 0xf9, 0xaf, 0x09, 0xed, 0x19, 0x8b, 0xdc, 0x64,
+	0xc1, 0x90, 0x0d, 0x6d, 0x1c, 0x86, 0x9c, 0x08, 0x61, 0x37, 0x46, 0x8d, 0x71, 0xd7, 0xcf, 0x4c,
+	0xf4, 0x10, 0xba, 0x73, 0xca, 0x82, 0xd7, 0xcf, 0xb0, 0xb8, 0xb1, 0x9b, 0xda, 0x57, 0x00, 0x68,
+	0x04, 0x7b, 0xda, 0xf0, 0xd6, 0xcb, 0x39, 0xe1, 0x76, 0x4b, 0xfb, 0xcb, 0x10, 0x42, 0x60, 0x85,
+	0x58, 0x62, 0xdb, 0xd2, 0x2e, 0xfd, 0x1b, 0x0d, 0xa1, 0x43, 0x55, 0xe3, 0x90, 0xdc, 0xd9, 0x3b,
+	0x1a, 0xcf, 0x6d, 0xf4, 0x00, 0x76, 0x25, 0x5b, 0xc5, 0x81, 0xb0, 0x77, 0x47, 0xad, 0x71, 0xd7,
+	0x4f, 0x2d, 0xf4, 0x04, 0x06, 0x92, 0xe3, 0x44, 0xe0, 0x40, 0xc6, 0x2c, 0x31, 0xb9, 0x6d, 0x9d,
+	0xbb, 0x81, 0xa3, 0x31, 0xf4, 0x4b, 0x98, 0xfe, 0xf2, 0x8e, 0x0e, 0xad, 0xc3, 0xe8, 0x27, 0xe8,
+	0x2c, 0xe2, 0x04, 0xd3, 0x58, 0xbe, 0xb1, 0xbb, 0xa3, 0xc6, 0xf8, 0xe0, 0xf8, 0x8b, 0x49, 0x8d,
+	0xa7, 0xc9, 0x79, 0x1a, 0xe0, 0xe7, 0xa1, 0xce, 0xdf, 0xb0, 0x7f, 0x1e, 0x27, 0xe1, 0x8c, 0x45,
+	0x62, 0xca, 0x23, 0x81, 0x0e, 0x01, 0x16, 0x9c, 0x2d, 0x9f, 0x91, 0x38, 0xba, 0x91, 0x29, 0x83,
+	0x25, 0x44, 0x0d, 0x2c, 0x59, 0xea, 0x35, 0x1c, 0xe6, 0x76, 0x99, 0xfa, 0x56, 0x95, 0xfa, 0x82,
+	0x0a, 0xab, 0x4c, 0x85, 0xf3, 0x33, 0xf4, 0xb2, 0xee, 0x3e, 0x59, 0xd1, 0x37, 0xe8, 0x7b, 0xb0,
+	0x28, 0x8b, 0x4c, 0xd8, 0xde, 0xb1, 0xbd, 0x31, 0x41, 0xfa, 0xca, 0xbe, 0x8e, 0x72, 0x26, 0x70,
+	0xff, 0x82, 0xc8, 0x4b, 0x22, 0x04, 0x8e, 0x88, 0x4f, 0xc4, 0x9a, 0x4a, 0x3d, 0x84, 0x6a, 0x77,
+	0xa7, 0xc9, 0x32, 0x03, 0xa4, 0x96, 0xf3, 0x4f, 0x13, 0x3e, 0xab, 0x27, 0x98, 0xbe, 0xf7, 0x61,
+	0x67, 0xc1, 0xd6, 0x49, 0xa8, 0x13, 0x3a, 0xbe, 0x31, 0x54, 0x1d, 0x8e, 0xff, 0xba, 0xc6, 0x34,
+	0x1d, 0x35, 0xb5, 0x14, 0x49, 0x94, 0x45, 0x2f, 0x38, 0xd1, 0x3d, 0xcc, 0xac, 0x25, 0x44, 0x69,
+	0x49, 0x59, 0x4c, 0x48, 0x1d, 0x60, 0x04, 0x53, 0x86, 0x90, 0x03, 0xfb, 0x94, 0x45, 0xd7, 0x98,
+	0x2a, 0x8b, 0x08, 0x7b, 0x47, 0xd3, 0x52, 0xc1, 0xd0, 0x23, 0xe8, 0xb1, 0xe4, 0xe4, 0x06, 0xc7,
+	0xc9, 0x4b, 0x33, 0xcc, 0xae, 0xae, 0x53, 0x05, 0x2b, 0xef, 0xde, 0xfe, 0xf8, 0x77, 0xff, 0x5c,
+	0x33, 0x31, 0x15, 0x82, 0x70, 0x25, 0xa1, 0x13, 0xb6, 0x4e, 0x34, 0x77, 0xce, 0x2f, 0xf0, 0x60,
+	0xc3, 0x61, 0x38, 0x7a, 0x0c, 0x07, 0xb8, 0x02, 0x6b, 0xb2, 0x76, 0xfc, 0x1a, 0xea, 0xf4, 0xa1,
+	0x77, 0x41, 0xe4, 0xf5, 0xa5, 0x7a, 0x28, 0x5d, 0xf2, 0x11, 0x1c, 0xe4, 0x80, 0x29, 0x85, 0xc0,
+	0xba, 0x5d, 0xba, 0xa7, 0xe9, 0xf3, 0xe8, 0xdf, 0xce, 0xa7, 0x70, 0xef, 0x82, 0x48, 0x37, 0x99,
+	0xb3, 0xbb, 0xe2, 0x6b, 0x28, 0xa0, 0x0a, 0x68, 0xd2, 0x1f, 0x42, 0x37, 0x24, 0x34, 0xbe, 0x25,
+	0x9c, 0x84, 0x69, 0x8d, 0x02, 0x50, 0x12, 0x35, 0x5f, 0x44, 0xc2, 0x4c, 0xa2, 0x99, 0xad, 0x32,
+	0x03, 0x96, 0x2c, 0x62, 0xbe, 0x24, 0x61, 0xfa, 0x70, 0x05, 0xe0, 0xfc, 0xdf, 0x80, 0xfe, 0x09,
+	0xa6, 0x34, 0x15, 0x88, 0xd6, 0xd2, 0x18, 0xfa, 0x01, 0x4b, 0x24, 0xc7, 0x81, 0x9c, 0x56, 0xee,
+	0x4a, 0x1d, 0x56, 0x6a, 0x11, 0x24, 0x09, 0x09, 0xcf, 0xd4, 0x62, 0xac, 0xfc, 0x6e, 0xb4, 0x4a,
+	0x77, 0x23, 0xbb, 0x36, 0xe9, 0x26, 0x59, 0xa5, 0x6b, 0x53, 0x2c, 0x5a, 0xc2, 0x42, 0xa3, 0xb0,
+	0xf4, 0xb2, 0x64, 0xb6, 0xf2, 0x2d, 0xf1, 0xdd, 0x95, 0x24, 0x2b, 0xa1, 0x45, 0x61, 0xf9, 0xb9,
+	0xed, 0x60, 0x18, 0x94, 0x46, 0x30, 0x7c, 0x15, 0x3a, 0x6e, 0x54, 0x74, 0x5c, 0xd6, 0x4e, 0xf3,
+	0xa3, 0xb5, 0xf3, 0xe4, 0x37, 0xe8, 0x64, 0x28, 0xda, 0x83, 0xf6, 0x8b, 0x33, 0xef, 0xd4, 0xf5,
+	0x2e, 0x06, 0x9f, 0xa0, 0x1e, 0x74, 0x5f, 0x79, 0x53, 0xcf, 0xbd, 0x7c, 0xfe, 0xea, 0x6a, 0xd0,
+	0x40, 0xfb, 0xd0, 0x99, 0x5e, 0x5d, 0x9d, 0xf9, 0x2f, 0xcf, 0x4e, 0x07, 0x4d, 0xe5, 0x3c, 0x79,
+	0xee, 0x9d, 0xbb, 0xfe, 0xe5, 0xd9, 0xe9, 0xa0, 0xa5, 0x9c, 0xb3, 0x1f, 0xfe, 0x3c, 0x77, 0xbd,
+	0xe9, 0x6c, 0x60, 0x1d, 0xff, 0x6b, 0x41, 0xdf, 0x67, 0x94, 0xae, 0x57, 0xd7, 0x59, 0x7f, 0x84,
+	0x61, 0x50, 0x5f, 0x56, 0xf4, 0xed, 0xc6, 0xf7, 0x6d, 0x3b, 0x00, 0xc3, 0xc7, 0x1f, 0x0c, 0x33,
+	0xc4, 0xf8, 0xb0, 0x57, 0x22, 0x0b, 0x8d, 0x36, 0xd2, 0x6a, 0x6a, 0x18, 0x7e, 0xfd, 0xbe, 0x08,
+	0x53, 0xd3, 0x85, 0x4e, 0x76, 0xd3, 0xd0, 0x97, 0xdb, 0xe8, 0xcc, 0x8f, 0xed, 0xf0, 0xf0, 0x9d,
+	0x6e, 0x53, 0x2a, 0x84, 0x7b, 0x1b, 0xbb, 0x88, 0xb6, 0xce, 0xb6, 0xb9, 0xc8, 0xc3, 0xef, 0x3e,
+	0x1c, 0x67, 0xba, 0xcc, 0xa0, 0x9b, 0xaf, 0x27, 0x3a, 0xdc, 0x96, 0x55, 0xec, 0xf2, 0xf0, 0xab,
+	0x77, 0xfb, 0x4d, 0xb5, 0xdf, 0xa1, 0x57, 0xd9, 0x58, 0xe4, 0x6c, 0xcb, 0xa8, 0xae, 0xf9, 0xf0,
+	0x9b, 0xf7, 0xc7, 0xe8, 0xca, 0xbf, 0x7a, 0x7f, 0xcc, 0xa2, 0x58, 0xde, 0xac, 0xe7, 0x93, 0x80,
+	0x2d, 0x8f, 0xd8, 0x62, 0x11, 0xa8, 0x33, 0x48, 0xf1, 0x5c, 0x1c, 0x61, 0x3e, 0x8f, 0x25, 0x5f,
+	0x2f, 0x8f, 0x56, 0x38, 0x78, 0x8d, 0x23, 0xa2, 0x91, 0xa7, 0x79, 0xcd, 0xa7, 0x01, 0xe3, 0xe4,
+	0xa8, 0xd6, 0x62, 0xbe, 0xab, 0xff, 0x4d, 0xfc, 0xf8, 0x76, 0x00, 0xc9, 0xc9, 0x77, 0x88, 0x5d,
+	0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -651,6 +851,7 @@ type RollupValidatorClient interface {
 	FindLogs(ctx context.Context, in *FindLogsArgs, opts ...grpc.CallOption) (*FindLogsReply, error)
 	GetAssertionCount(ctx context.Context, in *GetAssertionCountArgs, opts ...grpc.CallOption) (*GetAssertionCountReply, error)
 	GetVMInfo(ctx context.Context, in *GetVMInfoArgs, opts ...grpc.CallOption) (*GetVMInfoReply, error)
+	GetInboxCount(ctx context.Context, in *GetInboxCountArgs, opts ...grpc.CallOption) (*GetInboxCountReply, error)
 }
 
 type rollupValidatorClient struct {
@@ -706,6 +907,15 @@ func (c *rollupValidatorClient) GetVMInfo(ctx context.Context, in *GetVMInfoArgs
 	return out, nil
 }
 
+func (c *rollupValidatorClient) GetInboxCount(ctx context.Context, in *GetInboxCountArgs, opts ...grpc.CallOption) (*GetInboxCountReply, error) {
+	out := new(GetInboxCountReply)
+	err := c.cc.Invoke(ctx, "/validatorserver.RollupValidator/GetInboxCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RollupValidatorServer is the server API for RollupValidator service.
 type RollupValidatorServer interface {
 	GetMessageResult(context.Context, *GetMessageResultArgs) (*GetMessageResultReply, error)
@@ -713,6 +923,7 @@ type RollupValidatorServer interface {
 	FindLogs(context.Context, *FindLogsArgs) (*FindLogsReply, error)
 	GetAssertionCount(context.Context, *GetAssertionCountArgs) (*GetAssertionCountReply, error)
 	GetVMInfo(context.Context, *GetVMInfoArgs) (*GetVMInfoReply, error)
+	GetInboxCount(context.Context, *GetInboxCountArgs) (*GetInboxCountReply, error)
 }
 
 // UnimplementedRollupValidatorServer can be embedded to have forward compatible implementations.
@@ -734,6 +945,9 @@ func (*UnimplementedRollupValidatorServer) GetAssertionCount(ctx context.Context
 func (*UnimplementedRollupValidatorServer) GetVMInfo(ctx context.Context, req *GetVMInfoArgs) (*GetVMInfoReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetVMInfo not implemented")
 }
+func (*UnimplementedRollupValidatorServer) GetInboxCount(ctx context.Context, req *GetInboxCountArgs) (*GetInboxCountReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInboxCount not implemented")
+}
 
 func RegisterRollupValidatorServer(s *grpc.Server, srv RollupValidatorServer) {
 	s.RegisterService(&_RollupValidator_serviceDesc, srv)
@@ -829,6 +1043,24 @@ func _RollupValidator_GetVMInfo_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RollupValidator_GetInboxCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInboxCountArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RollupValidatorServer).GetInboxCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/validatorserver.RollupValidator/GetInboxCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RollupValidatorServer).GetInboxCount(ctx, req.(*GetInboxCountArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RollupValidator_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "validatorserver.RollupValidator",
 	HandlerType: (*RollupValidatorServer)(nil),
@@ -853,6 +1085,10 @@ var _RollupValidator_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetVMInfo",
 			Handler:    _RollupValidator_GetVMInfo_Handler,
 		},
+		{
+			MethodName: "GetInboxCount",
+			Handler:    _RollupValidator_GetInboxCount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "server.proto",
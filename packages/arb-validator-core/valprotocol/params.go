@@ -17,6 +17,8 @@
 package valprotocol
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
@@ -30,6 +32,9 @@ type ChainParams struct {
 	ArbGasSpeedLimitPerTick uint64 // in ArbGas per tick
 }
 
+// maxUint128 bounds the parameters which the rollup contract stores as uint128
+var maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
 func (cp ChainParams) WithStakeRequirement(amountInWei *big.Int) ChainParams {
 	ret := cp
 	ret.StakeRequirement = amountInWei
@@ -64,6 +69,63 @@ func (cp ChainParams) WithArbGasSpeedLimitPerTick(limit uint64) ChainParams {
 	return ret
 }
 
+// Validate checks that a rollup chain created with these parameters can make
+// progress. The rollup contract only checks the parameters when an assertion
+// is made, so a bad chain would otherwise only be discovered once it's stuck.
+func (cp ChainParams) Validate() error {
+	if cp.StakeRequirement == nil || cp.StakeRequirement.Sign() <= 0 {
+		return errors.New("stake requirement must be positive")
+	}
+	if cp.StakeRequirement.Cmp(maxUint128) > 0 {
+		return errors.New("stake requirement must fit in a uint128")
+	}
+	if cp.GracePeriod.Val == nil || cp.GracePeriod.Val.Sign() <= 0 {
+		return errors.New("grace period must be positive")
+	}
+	if cp.GracePeriod.Val.Cmp(maxUint128) > 0 {
+		return errors.New("grace period must fit in a uint128")
+	}
+	if cp.MaxExecutionSteps == 0 {
+		return errors.New("max execution steps must be positive")
+	}
+	if cp.MaxTimeBoundsWidth == 0 {
+		return errors.New("max time bounds width must be at least one block")
+	}
+	if cp.ArbGasSpeedLimitPerTick == 0 {
+		// The contract divides by the speed limit in every assertion
+		return errors.New("ArbGas speed limit must be positive")
+	}
+
+	// An assertion can be made at any point in its time bounds, so stakers
+	// need a grace period at least that long to respond to it
+	widthTicks := new(big.Int).Mul(
+		new(big.Int).SetUint64(cp.MaxTimeBoundsWidth),
+		big.NewInt(common.TicksPerBlock),
+	)
+	if cp.GracePeriod.Val.Cmp(widthTicks) < 0 {
+		return fmt.Errorf(
+			"grace period of %v ticks is shorter than the max time bounds width of %v blocks",
+			cp.GracePeriod.Val,
+			cp.MaxTimeBoundsWidth,
+		)
+	}
+
+	// Every step uses at least one ArbGas, and the time to check an
+	// assertion at the speed limit is added to its deadline. A full size
+	// assertion mustn't delay confirmation by more than the grace period.
+	minCheckTicks := new(big.Int).SetUint64(cp.MaxExecutionSteps / cp.ArbGasSpeedLimitPerTick)
+	if minCheckTicks.Cmp(cp.GracePeriod.Val) > 0 {
+		return fmt.Errorf(
+			"checking %v steps at %v ArbGas per tick takes at least %v ticks, longer than the grace period of %v ticks",
+			cp.MaxExecutionSteps,
+			cp.ArbGasSpeedLimitPerTick,
+			minCheckTicks,
+			cp.GracePeriod.Val,
+		)
+	}
+	return nil
+}
+
 func (params ChainParams) MarshalToBuf() *ChainParamsBuf {
 	return &ChainParamsBuf{
 		StakeRequirement:        common.MarshalBigInt(params.StakeRequirement),
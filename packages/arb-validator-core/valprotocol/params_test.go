@@ -0,0 +1,61 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package valprotocol
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func generateTestParams() ChainParams {
+	return ChainParams{
+		StakeRequirement:        big.NewInt(10000000000000000),
+		GracePeriod:             common.TicksFromBlockNum(common.NewTimeBlocksInt(30)),
+		MaxExecutionSteps:       10000000000,
+		MaxTimeBoundsWidth:      20,
+		ArbGasSpeedLimitPerTick: 80000000,
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	if err := generateTestParams().Validate(); err != nil {
+		t.Fatal("default params are invalid:", err)
+	}
+
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 128)
+	invalid := map[string]ChainParams{
+		"zero stake":      generateTestParams().WithStakeRequirement(big.NewInt(0)),
+		"huge stake":      generateTestParams().WithStakeRequirement(tooBig),
+		"zero grace":      generateTestParams().WithGracePeriod(common.TimeTicks{Val: big.NewInt(0)}),
+		"zero steps":      generateTestParams().WithMaxExecutionSteps(0),
+		"zero width":      generateTestParams().WithMaxTimeBoundsWidth(0),
+		"zero speed":      generateTestParams().WithArbGasSpeedLimitPerTick(0),
+		"width > grace":   generateTestParams().WithMaxTimeBoundsWidth(31),
+		"slow assertions": generateTestParams().WithArbGasSpeedLimitPerTick(100),
+	}
+	for name, params := range invalid {
+		if err := params.Validate(); err == nil {
+			t.Error(name, "params should be invalid")
+		}
+	}
+
+	if err := generateTestParams().WithMaxTimeBoundsWidth(30).Validate(); err != nil {
+		t.Error("time bounds width equal to the grace period should be valid:", err)
+	}
+}
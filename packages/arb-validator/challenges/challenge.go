@@ -34,6 +34,21 @@ const (
 	ChallengeChallengerTimedOut
 )
 
+func (s ChallengeState) String() string {
+	switch s {
+	case ChallengeContinuing:
+		return "Continuing"
+	case ChallengeAsserterWon:
+		return "AsserterWon"
+	case ChallengeAsserterTimedOut:
+		return "AsserterTimedOut"
+	case ChallengeChallengerTimedOut:
+		return "ChallengerTimedOut"
+	default:
+		return "Unknown"
+	}
+}
+
 var replayTimeout = time.Second
 
 var challengeNoEvents = errors.New("challenge event channel terminated unexpectedly")
@@ -78,8 +93,9 @@ func getNextEventWithTimeout(
 	deadline common.TimeTicks,
 	contract arbbridge.Challenge,
 	client arbbridge.ArbClient,
+	pollInterval time.Duration,
 ) (arbbridge.Event, ChallengeState, error) {
-	ticker := time.NewTicker(common.NewTimeBlocksInt(2).Duration())
+	ticker := time.NewTicker(pollInterval)
 	for {
 		select {
 		case <-ctx.Done():
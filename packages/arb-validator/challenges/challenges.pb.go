@@ -0,0 +1,157 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: challenges.proto
+
+package challenges
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	valprotocol "github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ChallengeProgressBuf struct {
+	BlockId              *common.BlockIdBuf           `protobuf:"bytes,1,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	LogIndex             uint64                       `protobuf:"varint,2,opt,name=logIndex,proto3" json:"logIndex,omitempty"`
+	Deadline             *common.TimeTicksBuf         `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	StartHash            *common.HashBuf              `protobuf:"bytes,4,opt,name=startHash,proto3" json:"startHash,omitempty"`
+	StartMessages        *common.HashBuf              `protobuf:"bytes,5,opt,name=startMessages,proto3" json:"startMessages,omitempty"`
+	StartCount           uint64                       `protobuf:"varint,6,opt,name=startCount,proto3" json:"startCount,omitempty"`
+	Count                uint64                       `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	Precondition         *valprotocol.PreconditionBuf `protobuf:"bytes,8,opt,name=precondition,proto3" json:"precondition,omitempty"`
+	Machine              *common.HashBuf              `protobuf:"bytes,9,opt,name=machine,proto3" json:"machine,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *ChallengeProgressBuf) Reset()         { *m = ChallengeProgressBuf{} }
+func (m *ChallengeProgressBuf) String() string { return proto.CompactTextString(m) }
+func (*ChallengeProgressBuf) ProtoMessage()    {}
+func (*ChallengeProgressBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_85e74e1361bb819c, []int{0}
+}
+
+func (m *ChallengeProgressBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengeProgressBuf.Unmarshal(m, b)
+}
+func (m *ChallengeProgressBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengeProgressBuf.Marshal(b, m, deterministic)
+}
+func (m *ChallengeProgressBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengeProgressBuf.Merge(m, src)
+}
+func (m *ChallengeProgressBuf) XXX_Size() int {
+	return xxx_messageInfo_ChallengeProgressBuf.Size(m)
+}
+func (m *ChallengeProgressBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengeProgressBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengeProgressBuf proto.InternalMessageInfo
+
+func (m *ChallengeProgressBuf) GetBlockId() *common.BlockIdBuf {
+	if m != nil {
+		return m.BlockId
+	}
+	return nil
+}
+
+func (m *ChallengeProgressBuf) GetLogIndex() uint64 {
+	if m != nil {
+		return m.LogIndex
+	}
+	return 0
+}
+
+func (m *ChallengeProgressBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+func (m *ChallengeProgressBuf) GetStartHash() *common.HashBuf {
+	if m != nil {
+		return m.StartHash
+	}
+	return nil
+}
+
+func (m *ChallengeProgressBuf) GetStartMessages() *common.HashBuf {
+	if m != nil {
+		return m.StartMessages
+	}
+	return nil
+}
+
+func (m *ChallengeProgressBuf) GetStartCount() uint64 {
+	if m != nil {
+		return m.StartCount
+	}
+	return 0
+}
+
+func (m *ChallengeProgressBuf) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ChallengeProgressBuf) GetPrecondition() *valprotocol.PreconditionBuf {
+	if m != nil {
+		return m.Precondition
+	}
+	return nil
+}
+
+func (m *ChallengeProgressBuf) GetMachine() *common.HashBuf {
+	if m != nil {
+		return m.Machine
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChallengeProgressBuf)(nil), "challenges.ChallengeProgressBuf")
+}
+
+func init() { proto.RegisterFile("challenges.proto", fileDescriptor_85e74e1361bb819c) }
+
+var fileDescriptor_85e74e1361bb819c = []byte{
+	// 333 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xcf, 0x4b, 0xc3, 0x30,
+	0x14, 0xc7, 0x99, 0xfb, 0x1d, 0x15, 0x25, 0xee, 0x10, 0x86, 0xca, 0xf0, 0x34, 0xc1, 0xad, 0xa2,
+	0x78, 0x97, 0xce, 0x83, 0x3b, 0x08, 0x63, 0xec, 0xe4, 0x2d, 0x4d, 0xd3, 0x36, 0x2c, 0xcd, 0x1b,
+	0x49, 0x3a, 0xfc, 0x5f, 0xfc, 0x67, 0x25, 0xa9, 0x5d, 0x3b, 0xd8, 0xa9, 0x79, 0xdf, 0xf7, 0xf9,
+	0x7e, 0xdf, 0x6b, 0x08, 0xba, 0x66, 0x19, 0x95, 0x92, 0xab, 0x94, 0x9b, 0xf9, 0x4e, 0x83, 0x05,
+	0x8c, 0x6a, 0x65, 0x7c, 0xc3, 0x20, 0xcf, 0x41, 0x05, 0xe5, 0xa7, 0x04, 0xc6, 0x77, 0x7b, 0x2a,
+	0xfd, 0x89, 0x81, 0x0c, 0x1a, 0xe7, 0xb2, 0xfd, 0xf0, 0xdb, 0x46, 0xa3, 0x45, 0x15, 0xb1, 0xd2,
+	0x90, 0x6a, 0x6e, 0x4c, 0x58, 0x24, 0xf8, 0x09, 0xf5, 0x23, 0x09, 0x6c, 0xbb, 0x8c, 0x49, 0x6b,
+	0xd2, 0x9a, 0x9e, 0xbf, 0xe0, 0xf9, 0x7f, 0x6e, 0x58, 0xca, 0x61, 0x91, 0xac, 0x2b, 0x04, 0x8f,
+	0xd1, 0x40, 0x42, 0xba, 0x54, 0x31, 0xff, 0x21, 0x67, 0x93, 0xd6, 0xb4, 0xb3, 0x3e, 0xd4, 0xf8,
+	0x19, 0x0d, 0x62, 0x4e, 0x63, 0x29, 0x14, 0x27, 0x6d, 0x1f, 0x35, 0xaa, 0xa2, 0x36, 0x22, 0xe7,
+	0x1b, 0xc1, 0xb6, 0x6e, 0xe2, 0xfa, 0x40, 0xe1, 0x19, 0x1a, 0x1a, 0x4b, 0xb5, 0xfd, 0xa4, 0x26,
+	0x23, 0x1d, 0x6f, 0xb9, 0xaa, 0x2c, 0x4e, 0x73, 0x74, 0x4d, 0xe0, 0x37, 0x74, 0xe9, 0x8b, 0x2f,
+	0x6e, 0x0c, 0x4d, 0xb9, 0x21, 0xdd, 0xd3, 0x96, 0x63, 0x0a, 0xdf, 0x23, 0xe4, 0x85, 0x05, 0x14,
+	0xca, 0x92, 0x9e, 0xdf, 0xba, 0xa1, 0xe0, 0x11, 0xea, 0x32, 0xdf, 0xea, 0xfb, 0x56, 0x59, 0xe0,
+	0x77, 0x74, 0xb1, 0xd3, 0x9c, 0x81, 0x8a, 0x85, 0x15, 0xa0, 0xc8, 0xc0, 0xcf, 0xba, 0x9d, 0x37,
+	0xaf, 0x76, 0xd5, 0x00, 0xdc, 0xe0, 0x23, 0x07, 0x7e, 0x44, 0xfd, 0x9c, 0xb2, 0xcc, 0x5d, 0xc7,
+	0xf0, 0xf4, 0xa2, 0x55, 0x3f, 0xfc, 0xf8, 0x0e, 0x53, 0x61, 0xb3, 0x22, 0x72, 0x44, 0x00, 0x49,
+	0xc2, 0x32, 0x2a, 0x94, 0xa4, 0x91, 0x09, 0xa8, 0x8e, 0x84, 0xd5, 0x45, 0x1e, 0xec, 0x28, 0xdb,
+	0xba, 0xff, 0x71, 0xca, 0x6c, 0x4f, 0xa5, 0x88, 0xa9, 0x05, 0x1d, 0xd4, 0xef, 0x22, 0xea, 0xf9,
+	0xc5, 0x5e, 0xff, 0x06, 0x00, 0x66, 0x10, 0xba, 0x3b, 0x3e, 0x02, 0x00, 0x00,
+}
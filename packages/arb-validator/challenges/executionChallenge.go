@@ -21,12 +21,14 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 )
 
 func DefendExecutionClaim(
@@ -38,35 +40,43 @@ func DefendExecutionClaim(
 	precondition *valprotocol.Precondition,
 	startMachine machine.Machine,
 	numSteps uint64,
-	bisectionCount uint32,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewExecutionChallengeWatcher(address)
-	if err != nil {
-		return 0, err
+	if startMachine == nil {
+		log.Fatal("nil startMachine in DefendExecutionClaim")
 	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "defending execution claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewExecutionChallengeWatcher(address)
+		if err != nil {
+			return 0, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewExecutionChallenge(address)
-	if err != nil {
-		return ChallengeContinuing, err
-	}
+		contract, err := client.NewExecutionChallenge(address)
+		if err != nil {
+			return ChallengeContinuing, err
+		}
 
-	if startMachine == nil {
-		log.Fatal("nil startMachine in DefendExecutionClaim")
-	}
-	return defendExecution(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		NewAssertionDefender(
-			precondition,
-			numSteps,
-			startMachine,
-		),
-		bisectionCount,
-	)
+		return defendExecution(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			NewAssertionDefender(
+				precondition,
+				numSteps,
+				startMachine,
+			),
+			strategy.ExecutionBisectionCount,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func ChallengeExecutionClaim(
@@ -78,28 +88,37 @@ func ChallengeExecutionClaim(
 	startPrecondition *valprotocol.Precondition,
 	startMachine machine.Machine,
 	challengeEverything bool,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewExecutionChallengeWatcher(address)
-	if err != nil {
-		return 0, err
-	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "challenging execution claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewExecutionChallengeWatcher(address)
+		if err != nil {
+			return 0, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewExecutionChallenge(address)
-	if err != nil {
-		return 0, err
-	}
+		contract, err := client.NewExecutionChallenge(address)
+		if err != nil {
+			return 0, err
+		}
 
-	return challengeExecution(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		startMachine,
-		startPrecondition,
-		challengeEverything,
-	)
+		return challengeExecution(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			startMachine,
+			startPrecondition,
+			challengeEverything,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func defendExecution(
@@ -109,17 +128,27 @@ func defendExecution(
 	client arbbridge.ArbClient,
 	startDefender AssertionDefender,
 	bisectionCount uint32,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("ExecutionChallenge expected InitiateChallengeEvent but got %T", event)
-	}
-
 	defender := startDefender
+	var deadline common.TimeTicks
+	if resume != nil {
+		deadline = resume.deadline
+		defender = NewAssertionDefender(resume.precondition, resume.count, resume.machine)
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		initEv, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("ExecutionChallenge expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = initEv.Deadline
+		recorder.setDeadline(deadline)
+	}
 
 	for {
 		if defender.NumSteps() == 1 {
@@ -137,7 +166,7 @@ func defendExecution(
 					0,
 				)
 				err = contract.OneStepProof(
-					ctx,
+					arbbridge.WithDeadline(ctx, deadline),
 					defender.GetPrecondition(),
 					valprotocol.NewExecutionAssertionStubFromAssertion(assertion),
 					proof,
@@ -151,7 +180,7 @@ func defendExecution(
 			if err != nil || state != ChallengeContinuing {
 				return state, err
 			}
-			_, ok = event.(arbbridge.OneStepProofEvent)
+			_, ok := event.(arbbridge.OneStepProofEvent)
 			if !ok {
 				return 0, fmt.Errorf("ExecutionChallenge defender expected OneStepProof but got %T", event)
 			}
@@ -162,7 +191,7 @@ func defendExecution(
 		if timedOut {
 			var assertions []*valprotocol.ExecutionAssertionStub
 			defenders, assertions = defender.NBisect(uint64(bisectionCount))
-			err := contract.BisectAssertion(ctx, defender.GetPrecondition(), assertions, defender.NumSteps())
+			err := contract.BisectAssertion(arbbridge.WithDeadline(ctx, deadline), defender.GetPrecondition(), assertions, defender.NumSteps())
 			if err != nil {
 				return 0, err
 			}
@@ -183,6 +212,7 @@ func defendExecution(
 			ev.Deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -216,6 +246,13 @@ func defendExecution(
 			steps := valprotocol.CalculateBisectionStepCount(contEv.SegmentIndex.Uint64(), uint64(len(ev.Assertions)), ev.TotalSteps)
 			defender = NewAssertionDefender(pre, steps, mach)
 		}
+		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline:     deadline,
+			count:        defender.NumSteps(),
+			precondition: defender.GetPrecondition(),
+			machine:      defender.GetMachineState(),
+		})
 	}
 }
 
@@ -227,19 +264,29 @@ func challengeExecution(
 	startMachine machine.Machine,
 	startPrecondition *valprotocol.Precondition,
 	challengeEverything bool,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	ev, ok := event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("ExecutionChallenge challenger expected InitiateChallengeEvent but got %T", event)
-	}
-
 	mach := startMachine
 	precondition := startPrecondition
-	deadline := ev.Deadline
+	var deadline common.TimeTicks
+	if resume != nil {
+		mach = resume.machine
+		precondition = resume.precondition
+		deadline = resume.deadline
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		ev, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("ExecutionChallenge challenger expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = ev.Deadline
+		recorder.setDeadline(deadline)
+	}
 	for {
 		event, state, err := getNextEventWithTimeout(
 			ctx,
@@ -247,6 +294,7 @@ func challengeExecution(
 			deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -288,7 +336,7 @@ func challengeExecution(
 			}
 			preconditions = valprotocol.GeneratePreconditions(precondition, ev.Assertions)
 			err = contract.ChooseSegment(
-				ctx,
+				arbbridge.WithDeadline(ctx, ev.Deadline),
 				challengedAssertionNum,
 				preconditions,
 				ev.Assertions,
@@ -325,5 +373,10 @@ func challengeExecution(
 			precondition = precondition.GeneratePostcondition(valprotocol.NewExecutionAssertionStubFromAssertion(assertion))
 		}
 		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline:     deadline,
+			precondition: precondition,
+			machine:      mach,
+		})
 	}
 }
@@ -67,7 +67,8 @@ func testExecutionChallenge(t *testing.T) {
 				precondition,
 				mach.Clone(),
 				numSteps,
-				4,
+				DefaultStrategy().WithExecutionBisectionCount(4),
+				nil,
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -80,6 +81,8 @@ func testExecutionChallenge(t *testing.T) {
 				precondition,
 				mach.Clone(),
 				true,
+				DefaultStrategy(),
+				nil,
 			)
 		},
 	); err != nil {
@@ -23,9 +23,11 @@ import (
 	"log"
 	"math/big"
 	"math/rand"
+	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
 	errors2 "github.com/pkg/errors"
 )
@@ -39,31 +41,39 @@ func DefendInboxTopClaim(
 	inbox *structures.MessageStack,
 	afterInboxTop common.Hash,
 	messageCount *big.Int,
-	bisectionCount uint64,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
-	if err != nil {
-		return 0, err
-	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "defending inbox top claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
+		if err != nil {
+			return 0, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewInboxTopChallenge(address)
-	if err != nil {
-		return 0, err
-	}
-	log.Println("=======> defending inbox top claim")
+		contract, err := client.NewInboxTopChallenge(address)
+		if err != nil {
+			return 0, err
+		}
+		log.Println("=======> defending inbox top claim")
 
-	return defendInboxTop(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		inbox,
-		afterInboxTop,
-		messageCount.Uint64(),
-		bisectionCount,
-	)
+		return defendInboxTop(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			inbox,
+			afterInboxTop,
+			messageCount.Uint64(),
+			strategy.InboxTopBisectionCount,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func ChallengeInboxTopClaim(
@@ -74,27 +84,36 @@ func ChallengeInboxTopClaim(
 	startLogIndex uint,
 	inbox *structures.MessageStack,
 	challengeEverything bool,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
-	if err != nil {
-		return 0, err
-	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "challenging inbox top claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewInboxTopChallengeWatcher(address)
+		if err != nil {
+			return 0, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewInboxTopChallenge(address)
-	if err != nil {
-		return 0, err
-	}
-	log.Println("=======> challenging inbox top claim")
-	return challengeInboxTop(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		inbox,
-		challengeEverything,
-	)
+		contract, err := client.NewInboxTopChallenge(address)
+		if err != nil {
+			return 0, err
+		}
+		log.Println("=======> challenging inbox top claim")
+		return challengeInboxTop(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			inbox,
+			challengeEverything,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func defendInboxTop(
@@ -106,17 +125,28 @@ func defendInboxTop(
 	afterInboxTop common.Hash,
 	messageCount uint64,
 	bisectionCount uint64,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("InboxTopChallenge defender expected InitiateChallengeEvent but got %T", event)
-	}
-
 	startState := afterInboxTop
+	var deadline common.TimeTicks
+	if resume != nil {
+		deadline = resume.deadline
+		startState = resume.startHash
+		messageCount = resume.count
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		initEv, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("InboxTopChallenge defender expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = initEv.Deadline
+		recorder.setDeadline(deadline)
+	}
 
 	for {
 		if messageCount == 1 {
@@ -126,7 +156,7 @@ func defendInboxTop(
 				if err != nil {
 					return 0, err
 				}
-				err = contract.OneStepProof(ctx, startState, msg.CommitmentHash())
+				err = contract.OneStepProof(arbbridge.WithDeadline(ctx, deadline), startState, msg.CommitmentHash())
 				if err != nil {
 					return 0, errors2.Wrap(err, "Error making one step proof")
 				}
@@ -136,7 +166,7 @@ func defendInboxTop(
 			if err != nil || state != ChallengeContinuing {
 				return state, err
 			}
-			_, ok = event.(arbbridge.OneStepProofEvent)
+			_, ok := event.(arbbridge.OneStepProofEvent)
 			if !ok {
 				return 0, fmt.Errorf("InboxTopChallenge defender expected OneStepProof but got %T", event)
 			}
@@ -149,7 +179,7 @@ func defendInboxTop(
 			if err != nil {
 				return 0, err
 			}
-			err = contract.Bisect(ctx, chainHashes, new(big.Int).SetUint64(messageCount))
+			err = contract.Bisect(arbbridge.WithDeadline(ctx, deadline), chainHashes, new(big.Int).SetUint64(messageCount))
 			if err != nil {
 				return 0, errors2.Wrap(err, "Error bisecting")
 			}
@@ -170,6 +200,7 @@ func defendInboxTop(
 			ev.Deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -180,6 +211,12 @@ func defendInboxTop(
 		}
 		startState = ev.ChainHashes[contEv.SegmentIndex.Uint64()]
 		messageCount = getSegmentCount(messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
+		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline:  deadline,
+			startHash: startState,
+			count:     messageCount,
+		})
 	}
 }
 
@@ -190,17 +227,25 @@ func challengeInboxTop(
 	client arbbridge.ArbClient,
 	inbox *structures.MessageStack,
 	challengeEverything bool,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	ev, ok := event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("InboxTopChallenge challenger expected InitiateChallengeEvent but got %T", event)
+	var deadline common.TimeTicks
+	if resume != nil {
+		deadline = resume.deadline
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		ev, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("InboxTopChallenge challenger expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = ev.Deadline
+		recorder.setDeadline(deadline)
 	}
-
-	deadline := ev.Deadline
 	for {
 		event, state, err := getNextEventWithTimeout(
 			ctx,
@@ -208,6 +253,7 @@ func challengeInboxTop(
 			deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -242,7 +288,7 @@ func challengeInboxTop(
 					return 0, errors.New("can't find inbox segment to challenge")
 				}
 			}
-			err = contract.ChooseSegment(ctx, uint16(segmentToChallenge), ev.ChainHashes, ev.TotalLength.Uint64())
+			err = contract.ChooseSegment(arbbridge.WithDeadline(ctx, ev.Deadline), uint16(segmentToChallenge), ev.ChainHashes, ev.TotalLength.Uint64())
 			if err != nil {
 				return 0, err
 			}
@@ -257,5 +303,8 @@ func challengeInboxTop(
 			return 0, fmt.Errorf("InboxTopChallenge challenger expected ContinueChallengeEvent but got %T", event)
 		}
 		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline: deadline,
+		})
 	}
 }
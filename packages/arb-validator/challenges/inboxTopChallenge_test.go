@@ -100,7 +100,8 @@ func testInboxTopChallenge(t *testing.T) {
 				messageStack,
 				bottomHash,
 				messageCount,
-				2,
+				DefaultStrategy().WithInboxTopBisectionCount(2),
+				nil,
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -112,6 +113,8 @@ func testInboxTopChallenge(t *testing.T) {
 				0,
 				messageStack,
 				true,
+				DefaultStrategy(),
+				nil,
 			)
 		},
 	); err != nil {
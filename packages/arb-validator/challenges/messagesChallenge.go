@@ -23,6 +23,7 @@ import (
 	"log"
 	"math/big"
 	"math/rand"
+	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
 )
 
@@ -44,30 +46,38 @@ func DefendMessagesClaim(
 	inbox *structures.MessageStack,
 	beforeInbox common.Hash,
 	messageCount *big.Int,
-	bisectionCount uint64,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewMessagesChallengeWatcher(address)
-	if err != nil {
-		return 0, err
-	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "defending messages claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewMessagesChallengeWatcher(address)
+		if err != nil {
+			return 0, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewMessagesChallenge(address)
-	if err != nil {
-		return 0, err
-	}
+		contract, err := client.NewMessagesChallenge(address)
+		if err != nil {
+			return 0, err
+		}
 
-	return defendMessages(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		inbox,
-		beforeInbox,
-		messageCount.Uint64(),
-		bisectionCount,
-	)
+		return defendMessages(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			inbox,
+			beforeInbox,
+			messageCount.Uint64(),
+			strategy.MessagesBisectionCount,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func ChallengeMessagesClaim(
@@ -80,29 +90,38 @@ func ChallengeMessagesClaim(
 	beforeInbox common.Hash,
 	messageCount *big.Int,
 	challengeEverything bool,
+	strategy Strategy,
+	checkpointer checkpointing.ChallengeCheckpointer,
 ) (ChallengeState, error) {
-	contractWatcher, err := client.NewMessagesChallengeWatcher(address)
-	if err != nil {
-		return ChallengeContinuing, err
-	}
+	recorder := newProgressRecorder(checkpointer, address, client.Address())
+	return runResumable(ctx, client, strategy, "challenging messages claim", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		contractWatcher, err := client.NewMessagesChallengeWatcher(address)
+		if err != nil {
+			return ChallengeContinuing, err
+		}
 
-	reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, startBlockId, startLogIndex, contractWatcher)
+		blockId, logIndex := resume.resumeFrom(startBlockId, startLogIndex)
+		reorgCtx, eventChan := arbbridge.HandleBlockchainEvents(ctx, client, blockId, logIndex, contractWatcher)
 
-	contract, err := client.NewMessagesChallenge(address)
-	if err != nil {
-		return 0, err
-	}
+		contract, err := client.NewMessagesChallenge(address)
+		if err != nil {
+			return 0, err
+		}
 
-	return challengeMessages(
-		reorgCtx,
-		eventChan,
-		contract,
-		client,
-		inbox,
-		beforeInbox,
-		messageCount.Uint64(),
-		challengeEverything,
-	)
+		return challengeMessages(
+			reorgCtx,
+			eventChan,
+			contract,
+			client,
+			inbox,
+			beforeInbox,
+			messageCount.Uint64(),
+			challengeEverything,
+			strategy.pollDuration(),
+			resume,
+			recorder,
+		)
+	})
 }
 
 func defendMessages(
@@ -114,16 +133,10 @@ func defendMessages(
 	beforeInbox common.Hash,
 	messageCount uint64,
 	bisectionCount uint64,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	_, ok = event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("MessagesChallenge defender expected InitiateChallengeEvent but got %T", event)
-	}
-
 	vmInbox, err := inbox.GenerateVMInbox(beforeInbox, messageCount)
 	if err != nil {
 		return 0, err
@@ -135,6 +148,25 @@ func defendMessages(
 	startInbox := beforeInbox
 	startMessages := value.NewEmptyTuple().Hash()
 	inboxStartCount := uint64(0)
+	var deadline common.TimeTicks
+	if resume != nil {
+		deadline = resume.deadline
+		startInbox = resume.startHash
+		startMessages = resume.startMessages
+		inboxStartCount = resume.startCount
+		messageCount = resume.count
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		initEv, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("MessagesChallenge defender expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = initEv.Deadline
+		recorder.setDeadline(deadline)
+	}
 
 	for {
 		log.Println(inboxStartCount, messageCount)
@@ -153,15 +185,15 @@ func defendMessages(
 
 				switch msg := msg.(type) {
 				case message.DeliveredTransaction:
-					err = contract.OneStepProofTransactionMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofTransactionMessage(arbbridge.WithDeadline(ctx, deadline), startInbox, startMessages, msg)
 				case message.DeliveredEth:
-					err = contract.OneStepProofEthMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofEthMessage(arbbridge.WithDeadline(ctx, deadline), startInbox, startMessages, msg)
 				case message.DeliveredERC20:
-					err = contract.OneStepProofERC20Message(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofERC20Message(arbbridge.WithDeadline(ctx, deadline), startInbox, startMessages, msg)
 				case message.DeliveredERC721:
-					err = contract.OneStepProofERC721Message(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofERC721Message(arbbridge.WithDeadline(ctx, deadline), startInbox, startMessages, msg)
 				case message.DeliveredContractTransaction:
-					err = contract.OneStepProofContractTransactionMessage(ctx, startInbox, startMessages, msg)
+					err = contract.OneStepProofContractTransactionMessage(arbbridge.WithDeadline(ctx, deadline), startInbox, startMessages, msg)
 				}
 				if err != nil {
 					return 0, errors2.Wrap(err, "failing making one step proof")
@@ -172,7 +204,7 @@ func defendMessages(
 			if err != nil || state != ChallengeContinuing {
 				return state, err
 			}
-			_, ok = event.(arbbridge.OneStepProofEvent)
+			_, ok := event.(arbbridge.OneStepProofEvent)
 			if !ok {
 				return 0, fmt.Errorf("MessagesChallenge defender expected OneStepProof but got %T", event)
 			}
@@ -190,7 +222,7 @@ func defendMessages(
 			log.Println("chainHashes", chainHashes)
 			log.Println("inboxHashes", inboxHashes)
 
-			err = contract.Bisect(ctx, chainHashes, inboxHashes, new(big.Int).SetUint64(messageCount))
+			err = contract.Bisect(arbbridge.WithDeadline(ctx, deadline), chainHashes, inboxHashes, new(big.Int).SetUint64(messageCount))
 			if err != nil {
 				return 0, errors2.Wrap(err, "failing making bisection")
 			}
@@ -212,6 +244,7 @@ func defendMessages(
 			ev.Deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -225,6 +258,14 @@ func defendMessages(
 		inboxStartCount += getSegmentStart(messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
 		log.Println("messageCount", messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
 		messageCount = getSegmentCount(messageCount, uint64(len(ev.ChainHashes))-1, contEv.SegmentIndex.Uint64())
+		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline:      deadline,
+			startHash:     startInbox,
+			startMessages: startMessages,
+			startCount:    inboxStartCount,
+			count:         messageCount,
+		})
 	}
 }
 
@@ -237,16 +278,10 @@ func challengeMessages(
 	beforeInbox common.Hash,
 	messageCount uint64,
 	challengeEverything bool,
+	pollInterval time.Duration,
+	resume *challengeProgress,
+	recorder *progressRecorder,
 ) (ChallengeState, error) {
-	event, ok := <-eventChan
-	if !ok {
-		return 0, challengeNoEvents
-	}
-	ev, ok := event.(arbbridge.InitiateChallengeEvent)
-	if !ok {
-		return 0, fmt.Errorf("MessagesChallenge challenger expected InitiateChallengeEvent but got %T", event)
-	}
-
 	vmInbox, err := inbox.GenerateVMInbox(beforeInbox, messageCount)
 	if err != nil {
 		return 0, err
@@ -254,7 +289,21 @@ func challengeMessages(
 
 	startInbox := uint64(0)
 
-	deadline := ev.Deadline
+	var deadline common.TimeTicks
+	if resume != nil {
+		deadline = resume.deadline
+	} else {
+		event, ok := <-eventChan
+		if !ok {
+			return 0, challengeNoEvents
+		}
+		ev, ok := event.(arbbridge.InitiateChallengeEvent)
+		if !ok {
+			return 0, fmt.Errorf("MessagesChallenge challenger expected InitiateChallengeEvent but got %T", event)
+		}
+		deadline = ev.Deadline
+		recorder.setDeadline(deadline)
+	}
 	for {
 		event, state, err := getNextEventWithTimeout(
 			ctx,
@@ -262,6 +311,7 @@ func challengeMessages(
 			deadline,
 			contract,
 			client,
+			pollInterval,
 		)
 		if err != nil || state != ChallengeContinuing {
 			return state, err
@@ -312,7 +362,7 @@ func challengeMessages(
 				}
 			}
 			log.Println("ChooseSegment", uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
-			err = contract.ChooseSegment(ctx, uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
+			err = contract.ChooseSegment(arbbridge.WithDeadline(ctx, ev.Deadline), uint16(segmentToChallenge), ev.ChainHashes, ev.SegmentHashes, ev.TotalLength)
 			if err != nil {
 				return 0, err
 			}
@@ -326,5 +376,8 @@ func challengeMessages(
 			return 0, fmt.Errorf("MessagesChallenge challenger expected ContinueChallengeEvent but got %T", event)
 		}
 		deadline = contEv.Deadline
+		recorder.save(contEv.ChainInfo, &challengeProgress{
+			deadline: deadline,
+		})
 	}
 }
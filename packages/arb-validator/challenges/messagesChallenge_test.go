@@ -86,7 +86,8 @@ func testMessagesChallenge(t *testing.T) {
 				messageStack,
 				beforeInbox,
 				new(big.Int).SetUint64(messageCount),
-				2,
+				DefaultStrategy().WithMessagesBisectionCount(2),
+				nil,
 			)
 		},
 		func(challengeAddress common.Address, client *ethbridge.EthArbAuthClient, blockId *common.BlockId) (ChallengeState, error) {
@@ -100,6 +101,8 @@ func testMessagesChallenge(t *testing.T) {
 				beforeInbox,
 				new(big.Int).SetUint64(messageCount),
 				true,
+				DefaultStrategy(),
+				nil,
 			)
 		},
 	); err != nil {
@@ -0,0 +1,227 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
+)
+
+//go:generate bash -c "protoc -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-util) -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-validator-core) -I. --go_out=paths=source_relative:. *.proto"
+
+// challengeProgress is the local state of a challenge participant at the
+// start of a bisection round. Which fields are used depends on the challenge
+// type and on whether we are the asserter or the challenger.
+type challengeProgress struct {
+	blockId       *common.BlockId
+	logIndex      uint
+	deadline      common.TimeTicks
+	startHash     common.Hash
+	startMessages common.Hash
+	startCount    uint64
+	count         uint64
+	precondition  *valprotocol.Precondition
+	machine       machine.Machine
+}
+
+func (p *challengeProgress) marshalForCheckpoint(ctx checkpointing.CheckpointContext) *ChallengeProgressBuf {
+	buf := &ChallengeProgressBuf{
+		BlockId:       p.blockId.MarshalToBuf(),
+		LogIndex:      uint64(p.logIndex),
+		Deadline:      p.deadline.MarshalToBuf(),
+		StartHash:     p.startHash.MarshalToBuf(),
+		StartMessages: p.startMessages.MarshalToBuf(),
+		StartCount:    p.startCount,
+		Count:         p.count,
+	}
+	if p.precondition != nil {
+		ctx.AddValue(p.precondition.BeforeInbox)
+		buf.Precondition = &valprotocol.PreconditionBuf{
+			BeforeHash:  p.precondition.BeforeHash.MarshalToBuf(),
+			TimeBounds:  p.precondition.TimeBounds.MarshalToBuf(),
+			BeforeInbox: p.precondition.BeforeInbox.Hash().MarshalToBuf(),
+		}
+	}
+	if p.machine != nil {
+		ctx.AddMachine(p.machine)
+		buf.Machine = p.machine.Hash().MarshalToBuf()
+	}
+	return buf
+}
+
+func (m *ChallengeProgressBuf) unmarshalFromCheckpoint(restoreCtx checkpointing.RestoreContext) (*challengeProgress, error) {
+	p := &challengeProgress{
+		blockId:       m.BlockId.Unmarshal(),
+		logIndex:      uint(m.LogIndex),
+		deadline:      m.Deadline.Unmarshal(),
+		startHash:     m.StartHash.Unmarshal(),
+		startMessages: m.StartMessages.Unmarshal(),
+		startCount:    m.StartCount,
+		count:         m.Count,
+	}
+	if m.Precondition != nil {
+		beforeInbox := restoreCtx.GetValue(m.Precondition.BeforeInbox.Unmarshal())
+		if beforeInbox == nil {
+			return nil, errors.New("challenge progress is missing precondition inbox")
+		}
+		p.precondition = valprotocol.NewPrecondition(
+			m.Precondition.BeforeHash.Unmarshal(),
+			m.Precondition.TimeBounds.Unmarshal(),
+			beforeInbox,
+		)
+	}
+	if m.Machine != nil {
+		p.machine = restoreCtx.GetMachine(m.Machine.Unmarshal())
+		if p.machine == nil {
+			return nil, errors.New("challenge progress is missing machine")
+		}
+	}
+	return p, nil
+}
+
+// progressRecorder persists the progress of one participant in one
+// challenge. A nil checkpointer disables persistence entirely, in which case
+// a resumed challenge is replayed from its first event.
+type progressRecorder struct {
+	checkpointer checkpointing.ChallengeCheckpointer
+	contract     common.Address
+	participant  common.Address
+
+	// deadline is the latest known deadline of the participant's next
+	// move, which is tracked even when progress isn't persisted
+	deadline common.TimeTicks
+}
+
+func newProgressRecorder(checkpointer checkpointing.ChallengeCheckpointer, contract common.Address, participant common.Address) *progressRecorder {
+	return &progressRecorder{
+		checkpointer: checkpointer,
+		contract:     contract,
+		participant:  participant,
+	}
+}
+
+// save records progress reached after the given event. Failure to save is
+// logged but not fatal since the challenge can always be replayed.
+func (r *progressRecorder) save(afterEvent arbbridge.ChainInfo, p *challengeProgress) {
+	r.setDeadline(p.deadline)
+	if r.checkpointer == nil {
+		return
+	}
+	p.blockId = afterEvent.BlockId
+	p.logIndex = afterEvent.LogIndex + 1
+	ctx := checkpointing.NewCheckpointContextImpl()
+	buf, err := proto.Marshal(p.marshalForCheckpoint(ctx))
+	if err != nil {
+		log.Println("Failed to marshal challenge progress", err)
+		return
+	}
+	if err := r.checkpointer.SaveChallengeProgress(r.contract, r.participant, buf, ctx); err != nil {
+		log.Println("Failed to save challenge progress", err)
+	}
+}
+
+// setDeadline records the deadline of the participant's next move
+func (r *progressRecorder) setDeadline(deadline common.TimeTicks) {
+	r.deadline = deadline
+}
+
+func (r *progressRecorder) currentDeadline() (common.TimeTicks, bool) {
+	return r.deadline, r.deadline.Val != nil
+}
+
+func (r *progressRecorder) load() (*challengeProgress, error) {
+	if r.checkpointer == nil {
+		return nil, nil
+	}
+	contents, restoreCtx, err := r.checkpointer.RestoreChallengeProgress(r.contract, r.participant)
+	if err != nil || contents == nil {
+		return nil, err
+	}
+	buf := &ChallengeProgressBuf{}
+	if err := proto.Unmarshal(contents, buf); err != nil {
+		return nil, err
+	}
+	return buf.unmarshalFromCheckpoint(restoreCtx)
+}
+
+func (r *progressRecorder) clear() {
+	if r.checkpointer == nil {
+		return
+	}
+	if err := r.checkpointer.DeleteChallengeProgress(r.contract, r.participant); err != nil {
+		log.Println("Failed to delete challenge progress", err)
+	}
+}
+
+// resumeFrom returns where event replay should start. If progress was
+// recovered, replay starts right after the last event it covers.
+func (p *challengeProgress) resumeFrom(startBlockId *common.BlockId, startLogIndex uint) (*common.BlockId, uint) {
+	if p == nil {
+		return startBlockId, startLogIndex
+	}
+	return p.blockId, p.logIndex
+}
+
+// runResumable runs a challenge participant with retries, starting each
+// attempt from the latest saved progress if there is any
+func runResumable(
+	ctx context.Context,
+	client arbbridge.ArbClient,
+	strategy Strategy,
+	name string,
+	recorder *progressRecorder,
+	run func(ctx context.Context, resume *challengeProgress) (ChallengeState, error),
+) (ChallengeState, error) {
+	clock := func(ctx context.Context) (common.TimeTicks, error) {
+		blockId, err := client.CurrentBlockId(ctx)
+		if err != nil {
+			return common.TimeTicks{}, err
+		}
+		return common.TicksFromBlockNum(blockId.Height), nil
+	}
+	return runWithRetry(ctx, strategy, name, clock, recorder.currentDeadline, func(ctx context.Context) (ChallengeState, error) {
+		resume, err := recorder.load()
+		if err != nil {
+			log.Println("Failed to load challenge progress, replaying challenge", err)
+			recorder.clear()
+			resume = nil
+		}
+		if resume != nil {
+			recorder.setDeadline(resume.deadline)
+		}
+		state, err := run(ctx, resume)
+		if ctx.Err() != nil {
+			// Shutting down, so keep the progress for the next run
+			return state, err
+		}
+		if err == nil || resume != nil {
+			// Either the challenge is over, or resuming from saved progress
+			// failed and the next attempt should replay from the start
+			recorder.clear()
+		}
+		return state, err
+	})
+}
@@ -0,0 +1,149 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/loader"
+)
+
+// fixedClient is a chain that never advances
+type fixedClient struct {
+	arbbridge.ArbClient
+}
+
+func (fixedClient) CurrentBlockId(ctx context.Context) (*common.BlockId, error) {
+	return &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(100))}, nil
+}
+
+func TestResumeFromSavedProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "challenge-progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	contract := "../contract.ao"
+	rollupAddress := common.Address{1}
+	challengeAddress := common.Address{2}
+	participant := common.Address{3}
+	factory := checkpointing.NewRollupCheckpointerImplFactory(rollupAddress, contract, filepath.Join(dir, "db"), big.NewInt(100), true)
+	checkpointer := factory.New(ctx)
+
+	mach, err := loader.LoadMachineFromFile(contract, true, "cpp")
+	if err != nil {
+		t.Fatal("Loader Error: ", err)
+	}
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(100)),
+		End:   common.NewTimeBlocks(big.NewInt(120)),
+	}
+	inbox := value.NewTuple2(value.NewEmptyTuple(), value.NewInt64Value(5))
+	precondition := valprotocol.NewPrecondition(mach.Hash(), timeBounds, inbox)
+	mach.ExecuteAssertion(10, timeBounds, value.NewEmptyTuple(), 0)
+
+	saved := &challengeProgress{
+		deadline:     common.TimeTicks{Val: big.NewInt(4000)},
+		startHash:    common.Hash{4},
+		count:        7,
+		precondition: precondition,
+		machine:      mach,
+	}
+	event := arbbridge.ChainInfo{
+		BlockId:  &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(90)), HeaderHash: common.Hash{5}},
+		LogIndex: 3,
+	}
+	newProgressRecorder(checkpointer, challengeAddress, participant).save(event, saved)
+
+	// Restart with a fresh checkpointer on the same database
+	checkpointer.(*checkpointing.RollupCheckpointerImpl).Close()
+	checkpointer = factory.New(ctx)
+	defer checkpointer.(*checkpointing.RollupCheckpointerImpl).Close()
+	recorder := newProgressRecorder(checkpointer, challengeAddress, participant)
+	if _, known := recorder.currentDeadline(); known {
+		t.Fatal("restarted recorder already knows the deadline")
+	}
+
+	runs := 0
+	state, err := runResumable(ctx, fixedClient{}, retryTestStrategy(), "test", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		runs++
+		if resume == nil {
+			t.Fatal("didn't resume from saved progress")
+		}
+		blockId, logIndex := resume.resumeFrom(&common.BlockId{}, 0)
+		if !blockId.Equals(event.BlockId) || logIndex != 4 {
+			t.Error("resumed from", blockId, logIndex, "instead of after the saved event")
+		}
+		if resume.deadline.Cmp(saved.deadline) != 0 {
+			t.Error("resumed with deadline", resume.deadline.Val)
+		}
+		if deadline, known := recorder.currentDeadline(); !known || deadline.Cmp(saved.deadline) != 0 {
+			t.Error("recorder didn't pick up the saved deadline")
+		}
+		if resume.startHash != saved.startHash || resume.count != saved.count {
+			t.Error("resumed from the wrong segment")
+		}
+		if resume.precondition.Hash() != precondition.Hash() {
+			t.Error("resumed with the wrong precondition")
+		}
+		if resume.machine == nil || resume.machine.Hash() != mach.Hash() {
+			t.Fatal("resumed with the wrong machine")
+		}
+		return ChallengeAsserterWon, nil
+	})
+	if err != nil || state != ChallengeAsserterWon {
+		t.Fatal("resumed challenge failed", state, err)
+	}
+	if runs != 1 {
+		t.Error("ran", runs, "times")
+	}
+	if progress, err := recorder.load(); err != nil || progress != nil {
+		t.Error("progress was kept after the challenge ended", err)
+	}
+
+	// A failed attempt from saved progress is replayed from the start
+	recorder.save(event, saved)
+	var resumed []bool
+	_, err = runResumable(ctx, fixedClient{}, retryTestStrategy(), "test", recorder, func(ctx context.Context, resume *challengeProgress) (ChallengeState, error) {
+		resumed = append(resumed, resume != nil)
+		if resume != nil {
+			return 0, errors.New("failed")
+		}
+		return ChallengeAsserterWon, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed) != 2 || !resumed[0] || resumed[1] {
+		t.Error("expected a resumed attempt then a replay but got", resumed)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+// Strategy controls how a validator plays its side of a challenge: how
+// finely it bisects each challenge type, how often it polls L1 for the
+// challenge deadline, and how it recovers from transient failures.
+type Strategy struct {
+	InboxTopBisectionCount  uint64
+	MessagesBisectionCount  uint64
+	ExecutionBisectionCount uint32
+	PollInterval            *common.TimeBlocks // in blocks
+	// MaxRetries bounds the retries of a participant which fails before
+	// the challenge's first deadline is known. After that, retries continue
+	// until the deadline of the next move passes.
+	MaxRetries uint
+	// RetryBackoff is the wait before the first retry, which doubles with
+	// each further retry up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+func DefaultStrategy() Strategy {
+	return Strategy{
+		InboxTopBisectionCount:  100,
+		MessagesBisectionCount:  100,
+		ExecutionBisectionCount: 50,
+		PollInterval:            common.NewTimeBlocksInt(2),
+		MaxRetries:              5,
+		RetryBackoff:            5 * time.Second,
+		MaxRetryBackoff:         time.Minute,
+	}
+}
+
+func (s Strategy) WithInboxTopBisectionCount(count uint64) Strategy {
+	ret := s
+	ret.InboxTopBisectionCount = count
+	return ret
+}
+
+func (s Strategy) WithMessagesBisectionCount(count uint64) Strategy {
+	ret := s
+	ret.MessagesBisectionCount = count
+	return ret
+}
+
+func (s Strategy) WithExecutionBisectionCount(count uint32) Strategy {
+	ret := s
+	ret.ExecutionBisectionCount = count
+	return ret
+}
+
+func (s Strategy) WithPollInterval(interval *common.TimeBlocks) Strategy {
+	ret := s
+	ret.PollInterval = interval
+	return ret
+}
+
+func (s Strategy) WithRetries(maxRetries uint, backoff time.Duration) Strategy {
+	ret := s
+	ret.MaxRetries = maxRetries
+	ret.RetryBackoff = backoff
+	if ret.MaxRetryBackoff < backoff {
+		ret.MaxRetryBackoff = backoff
+	}
+	return ret
+}
+
+func (s Strategy) Validate() error {
+	if s.InboxTopBisectionCount < 2 {
+		return errors.New("inbox top bisection count must be at least 2")
+	}
+	if s.MessagesBisectionCount < 2 {
+		return errors.New("messages bisection count must be at least 2")
+	}
+	if s.ExecutionBisectionCount < 2 {
+		return errors.New("execution bisection count must be at least 2")
+	}
+	if s.PollInterval == nil || s.PollInterval.AsInt().Sign() <= 0 {
+		return errors.New("poll interval must be at least one block")
+	}
+	return nil
+}
+
+func (s Strategy) pollDuration() time.Duration {
+	return s.PollInterval.Duration()
+}
+
+// runWithRetry runs a challenge participant, resuming it from the start of
+// the challenge if it fails with an error. Since the participant replays all
+// past challenge events before acting, a resumed run picks up at whatever
+// point the challenge reached on chain.
+//
+// Once the deadline of the participant's next move is known, failed runs are
+// retried with exponential backoff for as long as that deadline hasn't
+// passed. Before then, at most MaxRetries retries are made.
+func runWithRetry(
+	ctx context.Context,
+	strategy Strategy,
+	name string,
+	clock func(ctx context.Context) (common.TimeTicks, error),
+	deadline func() (common.TimeTicks, bool),
+	run func(ctx context.Context) (ChallengeState, error),
+) (ChallengeState, error) {
+	backoff := strategy.RetryBackoff
+	for attempt := uint(0); ; attempt++ {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		state, err := run(attemptCtx)
+		cancel()
+		if err == nil {
+			return state, err
+		}
+		wait, retry := retryDelay(ctx, strategy, attempt, backoff, clock, deadline)
+		if !retry {
+			return state, err
+		}
+		log.Printf("Error %v (attempt %v), resuming in %v: %v\n", name, attempt+1, wait, err)
+		select {
+		case <-ctx.Done():
+			return state, err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+		if backoff > strategy.MaxRetryBackoff {
+			backoff = strategy.MaxRetryBackoff
+		}
+	}
+}
+
+// retryDelay decides whether a participant which has failed attempt+1 times
+// should be retried, and how long to wait before doing so
+func retryDelay(
+	ctx context.Context,
+	strategy Strategy,
+	attempt uint,
+	backoff time.Duration,
+	clock func(ctx context.Context) (common.TimeTicks, error),
+	deadline func() (common.TimeTicks, bool),
+) (time.Duration, bool) {
+	end, ok := deadline()
+	if !ok || end.Val == nil {
+		return backoff, attempt < strategy.MaxRetries
+	}
+	now, err := clock(ctx)
+	if err != nil {
+		log.Println("Failed to check challenge deadline", err)
+		return backoff, attempt < strategy.MaxRetries
+	}
+	if now.Cmp(end) >= 0 {
+		log.Println("Challenge deadline", end, "passed at", now, "so not resuming")
+		return 0, false
+	}
+	// Leave the resumed run at least half the remaining time to act
+	remaining := common.TimeTicks{Val: new(big.Int).Sub(end.Val, now.Val)}.Duration()
+	if backoff > remaining/2 {
+		backoff = remaining / 2
+	}
+	return backoff, true
+}
@@ -0,0 +1,130 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package challenges
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func retryTestStrategy() Strategy {
+	return DefaultStrategy().WithRetries(1, time.Millisecond)
+}
+
+func fixedClock(ticks int64) func(context.Context) (common.TimeTicks, error) {
+	return func(context.Context) (common.TimeTicks, error) {
+		return common.TimeTicks{Val: big.NewInt(ticks)}, nil
+	}
+}
+
+func fixedDeadline(ticks int64) func() (common.TimeTicks, bool) {
+	return func() (common.TimeTicks, bool) {
+		return common.TimeTicks{Val: big.NewInt(ticks)}, true
+	}
+}
+
+func noDeadline() (common.TimeTicks, bool) {
+	return common.TimeTicks{}, false
+}
+
+// failingRun fails the given number of times before succeeding
+func failingRun(failures int, attempts *int) func(context.Context) (ChallengeState, error) {
+	return func(context.Context) (ChallengeState, error) {
+		*attempts++
+		if *attempts <= failures {
+			return 0, errors.New("transient failure")
+		}
+		return ChallengeAsserterWon, nil
+	}
+}
+
+func TestRetryUntilDeadline(t *testing.T) {
+	// With plenty of time left before the deadline, retries continue past
+	// MaxRetries until the run succeeds
+	attempts := 0
+	state, err := runWithRetry(
+		context.Background(),
+		retryTestStrategy(),
+		"testing",
+		fixedClock(0),
+		fixedDeadline(1000000),
+		failingRun(4, &attempts),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != ChallengeAsserterWon || attempts != 5 {
+		t.Errorf("expected success on attempt 5 but got %v on attempt %v", state, attempts)
+	}
+}
+
+func TestNoRetryAfterDeadline(t *testing.T) {
+	attempts := 0
+	_, err := runWithRetry(
+		context.Background(),
+		retryTestStrategy(),
+		"testing",
+		fixedClock(2000),
+		fixedDeadline(1000),
+		failingRun(4, &attempts),
+	)
+	if err == nil {
+		t.Fatal("expected failure once the deadline passed")
+	}
+	if attempts != 1 {
+		t.Errorf("expected no retries after the deadline but made %v attempts", attempts)
+	}
+}
+
+func TestRetryWithoutDeadline(t *testing.T) {
+	attempts := 0
+	_, err := runWithRetry(
+		context.Background(),
+		retryTestStrategy(),
+		"testing",
+		fixedClock(0),
+		noDeadline,
+		failingRun(4, &attempts),
+	)
+	if err == nil {
+		t.Fatal("expected failure after running out of retries")
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts with 1 retry but made %v", attempts)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	strategy := DefaultStrategy().WithRetries(0, time.Second)
+
+	wait, retry := retryDelay(context.Background(), strategy, 3, time.Second, fixedClock(0), fixedDeadline(common.TicksPerBlock*1000))
+	if !retry || wait != time.Second {
+		t.Errorf("expected retry after %v but got %v, %v", time.Second, wait, retry)
+	}
+
+	// Close to the deadline the wait shrinks to leave time to act
+	remaining := common.TimeTicks{Val: big.NewInt(common.TicksPerBlock)}
+	wait, retry = retryDelay(context.Background(), strategy, 3, time.Hour, fixedClock(0), fixedDeadline(common.TicksPerBlock))
+	if !retry || wait != remaining.Duration()/2 {
+		t.Errorf("expected retry after %v but got %v, %v", remaining.Duration()/2, wait, retry)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package checkpointing
+
+import (
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+)
+
+// ChallengeCheckpointer stores the local progress of a validator in an
+// in-flight challenge. Unlike rollup checkpoints, challenge progress is not
+// tied to a block; there is a single record per challenge contract and
+// participant, which is overwritten as the challenge advances.
+type ChallengeCheckpointer interface {
+	SaveChallengeProgress(contract common.Address, participant common.Address, contents []byte, cpCtx CheckpointContext) error
+	RestoreChallengeProgress(contract common.Address, participant common.Address) ([]byte, RestoreContext, error)
+	DeleteChallengeProgress(contract common.Address, participant common.Address) error
+}
+
+func challengeProgressKey(contract common.Address, participant common.Address) []byte {
+	key := append([]byte{3}, contract[:]...)
+	return append(key, participant[:]...)
+}
+
+func saveChallengeProgress(db machine.CheckpointStorage, key []byte, contents []byte, cpCtx CheckpointContext) error {
+	for _, val := range cpCtx.Values() {
+		if ok := db.SaveValue(val); !ok {
+			return errors.New("failed to write challenge value to checkpoint db")
+		}
+	}
+	for _, mach := range cpCtx.Machines() {
+		if ok := mach.Checkpoint(db); !ok {
+			return errors.New("failed to write challenge machine to checkpoint db")
+		}
+	}
+
+	// Release the previous record only after the new one is fully written so
+	// that shared values and machines are never dropped in between
+	oldManifest, err := loadChallengeManifest(db, key)
+	if err != nil {
+		return err
+	}
+
+	ckpWithMan := &CheckpointWithManifest{
+		Contents: contents,
+		Manifest: cpCtx.Manifest(),
+	}
+	bytesBuf, err := proto.Marshal(ckpWithMan)
+	if err != nil {
+		return err
+	}
+	if ok := db.SaveData(key, bytesBuf); !ok {
+		return errors.New("failed to write challenge progress to checkpoint db")
+	}
+	releaseManifest(db, oldManifest)
+	return nil
+}
+
+func restoreChallengeProgress(db machine.CheckpointStorage, key []byte) ([]byte, error) {
+	val := db.GetData(key)
+	if len(val) == 0 {
+		return nil, nil
+	}
+	ckpWithMan := &CheckpointWithManifest{}
+	if err := proto.Unmarshal(val, ckpWithMan); err != nil {
+		return nil, err
+	}
+	return ckpWithMan.Contents, nil
+}
+
+func deleteChallengeProgress(db machine.CheckpointStorage, key []byte) error {
+	manifest, err := loadChallengeManifest(db, key)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		return nil
+	}
+	_ = db.DeleteData(key) // ignore error
+	releaseManifest(db, manifest)
+	return nil
+}
+
+func loadChallengeManifest(db machine.CheckpointStorage, key []byte) (*CheckpointManifest, error) {
+	val := db.GetData(key)
+	if len(val) == 0 {
+		return nil, nil
+	}
+	ckpWithMan := &CheckpointWithManifest{}
+	if err := proto.Unmarshal(val, ckpWithMan); err != nil {
+		return nil, err
+	}
+	if ckpWithMan.Manifest == nil {
+		return &CheckpointManifest{}, nil
+	}
+	return ckpWithMan.Manifest, nil
+}
+
+func releaseManifest(db machine.CheckpointStorage, manifest *CheckpointManifest) {
+	if manifest == nil {
+		return
+	}
+	for _, hbuf := range manifest.Values {
+		_ = db.DeleteValue(hbuf.Unmarshal()) // ignore error
+	}
+	for _, hbuf := range manifest.Machines {
+		_ = db.DeleteCheckpoint(hbuf.Unmarshal()) // ignore error
+	}
+}
@@ -0,0 +1,221 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package checkpointing
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// memoryStorage reference counts values and machines like the checkpoint
+// database does
+type memoryStorage struct {
+	values      map[common.Hash]value.Value
+	valueRefs   map[common.Hash]int
+	machines    map[common.Hash]machine.Machine
+	machineRefs map[common.Hash]int
+	data        map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		values:      make(map[common.Hash]value.Value),
+		valueRefs:   make(map[common.Hash]int),
+		machines:    make(map[common.Hash]machine.Machine),
+		machineRefs: make(map[common.Hash]int),
+		data:        make(map[string][]byte),
+	}
+}
+
+func (s *memoryStorage) DeleteCheckpoint(machineHash common.Hash) bool {
+	s.machineRefs[machineHash]--
+	if s.machineRefs[machineHash] <= 0 {
+		delete(s.machines, machineHash)
+		delete(s.machineRefs, machineHash)
+	}
+	return true
+}
+
+func (s *memoryStorage) CloseCheckpointStorage() bool { return true }
+
+func (s *memoryStorage) GetInitialMachine() (machine.Machine, error) { return nil, nil }
+
+func (s *memoryStorage) GetMachine(machineHash common.Hash) (machine.Machine, error) {
+	return s.machines[machineHash], nil
+}
+
+func (s *memoryStorage) SaveValue(val value.Value) bool {
+	s.values[val.Hash()] = val
+	s.valueRefs[val.Hash()]++
+	return true
+}
+
+func (s *memoryStorage) GetValue(hashValue common.Hash) value.Value {
+	return s.values[hashValue]
+}
+
+func (s *memoryStorage) DeleteValue(hashValue common.Hash) bool {
+	s.valueRefs[hashValue]--
+	if s.valueRefs[hashValue] <= 0 {
+		delete(s.values, hashValue)
+		delete(s.valueRefs, hashValue)
+	}
+	return true
+}
+
+func (s *memoryStorage) SaveData(key []byte, serializedValue []byte) bool {
+	s.data[string(key)] = serializedValue
+	return true
+}
+
+func (s *memoryStorage) GetData(key []byte) []byte {
+	return s.data[string(key)]
+}
+
+func (s *memoryStorage) DeleteData(key []byte) bool {
+	delete(s.data, string(key))
+	return true
+}
+
+// storedMachine saves itself to a memoryStorage
+type storedMachine struct {
+	hash common.Hash
+}
+
+func (m *storedMachine) Hash() common.Hash      { return m.hash }
+func (m *storedMachine) Clone() machine.Machine { return &storedMachine{m.hash} }
+func (m *storedMachine) PrintState()            {}
+
+func (m *storedMachine) CurrentStatus() machine.Status { return machine.Extensive }
+
+func (m *storedMachine) IsBlocked(currentTime *common.TimeBlocks, newMessages bool) machine.BlockReason {
+	return nil
+}
+
+func (m *storedMachine) ExecuteAssertion(
+	maxSteps uint64,
+	timeBounds *protocol.TimeBoundsBlocks,
+	inbox value.TupleValue,
+	maxWallTime time.Duration,
+) (*protocol.ExecutionAssertion, uint64) {
+	return protocol.NewExecutionAssertion(m.hash, false, 0, nil, nil), 0
+}
+
+func (m *storedMachine) MarshalForProof() ([]byte, error) { return nil, nil }
+
+func (m *storedMachine) Checkpoint(storage machine.CheckpointStorage) bool {
+	s := storage.(*memoryStorage)
+	s.machines[m.hash] = m.Clone()
+	s.machineRefs[m.hash]++
+	return true
+}
+
+func progressContext(vals []value.Value, machs ...machine.Machine) CheckpointContext {
+	ctx := NewCheckpointContextImpl()
+	for _, val := range vals {
+		ctx.AddValue(val)
+	}
+	for _, mach := range machs {
+		ctx.AddMachine(mach)
+	}
+	return ctx
+}
+
+func TestChallengeProgressRoundTrip(t *testing.T) {
+	db := newMemoryStorage()
+	key := challengeProgressKey(common.Address{1}, common.Address{2})
+	otherKey := challengeProgressKey(common.Address{1}, common.Address{3})
+
+	if contents, err := restoreChallengeProgress(db, key); err != nil || contents != nil {
+		t.Fatal("restored progress that was never saved", contents, err)
+	}
+
+	shared := value.NewInt64Value(1)
+	first := value.NewInt64Value(2)
+	second := value.NewInt64Value(3)
+	firstMachine := &storedMachine{common.Hash{1}}
+	secondMachine := &storedMachine{common.Hash{2}}
+
+	if err := saveChallengeProgress(db, key, []byte("first"), progressContext([]value.Value{shared, first}, firstMachine)); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveChallengeProgress(db, otherKey, []byte("other"), progressContext([]value.Value{shared})); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := restoreChallengeProgress(db, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(contents, []byte("first")) {
+		t.Error("restored", string(contents), "instead of first")
+	}
+	if db.GetValue(first.Hash()) == nil || db.machines[firstMachine.hash] == nil {
+		t.Error("progress values and machines weren't saved")
+	}
+
+	// Overwriting the progress releases everything only the old record used
+	if err := saveChallengeProgress(db, key, []byte("second"), progressContext([]value.Value{shared, second}, secondMachine)); err != nil {
+		t.Fatal(err)
+	}
+	contents, err = restoreChallengeProgress(db, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(contents, []byte("second")) {
+		t.Error("restored", string(contents), "instead of second")
+	}
+	if db.GetValue(first.Hash()) != nil || db.machines[firstMachine.hash] != nil {
+		t.Error("overwritten progress wasn't released")
+	}
+	if db.GetValue(second.Hash()) == nil || db.machines[secondMachine.hash] == nil {
+		t.Error("new progress values and machines weren't saved")
+	}
+	if db.valueRefs[shared.Hash()] != 2 {
+		t.Error("expected the shared value to be held by both records but had", db.valueRefs[shared.Hash()], "references")
+	}
+
+	if err := deleteChallengeProgress(db, key); err != nil {
+		t.Fatal(err)
+	}
+	if contents, err := restoreChallengeProgress(db, key); err != nil || contents != nil {
+		t.Error("restored deleted progress", contents, err)
+	}
+	if db.GetValue(second.Hash()) != nil || db.machines[secondMachine.hash] != nil {
+		t.Error("deleted progress wasn't released")
+	}
+	if db.GetValue(shared.Hash()) == nil {
+		t.Error("deleting one participant's progress released the other's")
+	}
+	if err := deleteChallengeProgress(db, key); err != nil {
+		t.Error("deleting missing progress failed", err)
+	}
+	contents, err = restoreChallengeProgress(db, otherKey)
+	if err != nil || !bytes.Equal(contents, []byte("other")) {
+		t.Error("other participant's progress was lost", string(contents), err)
+	}
+
+	db.SaveData(key, []byte{0xff})
+	if _, err := restoreChallengeProgress(db, key); err == nil {
+		t.Error("restored corrupt progress")
+	}
+}
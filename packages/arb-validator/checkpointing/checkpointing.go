@@ -19,6 +19,7 @@ package checkpointing
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"os"
@@ -37,8 +38,12 @@ type RollupCheckpointerFactory interface {
 }
 
 type RollupCheckpointer interface {
+	ChallengeCheckpointer
 	HasCheckpointedState() bool
 	RestoreLatestState(context.Context, arbbridge.ArbClient, func([]byte, RestoreContext) error) error
+	// RestoreStateAtHeight restores the newest checkpoint on the current L1
+	// chain which is no higher than the given height
+	RestoreStateAtHeight(context.Context, arbbridge.ArbClient, *common.TimeBlocks, func([]byte, RestoreContext) error) error
 	GetInitialMachine() (machine.Machine, error)
 	AsyncSaveCheckpoint(blockId *common.BlockId, contents []byte, cpCtx CheckpointContext, closeWhenDone chan struct{})
 }
@@ -177,6 +182,55 @@ func (rcp *RollupCheckpointerImpl) RestoreLatestState(ctx context.Context, clien
 	return unmarshalFunc(cobBytes, resCtx)
 }
 
+func (rcp *RollupCheckpointerImpl) RestoreStateAtHeight(ctx context.Context, client arbbridge.ArbClient, height *common.TimeBlocks, unmarshalFunc func([]byte, RestoreContext) error) error {
+	metadataBytes := rcp.RestoreMetadata()
+	if !rcp.HasCheckpointedState() {
+		return errors.New("no checkpoints in database")
+	}
+	metadata := &CheckpointMetadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return err
+	}
+	// Walk back through the checkpoints themselves rather than every block
+	// height so L1 is only asked about heights which have a checkpoint
+	oldest := metadata.Oldest.Unmarshal()
+	id := metadata.Newest.Unmarshal()
+	for id.Height.Cmp(oldest.Height) >= 0 {
+		if id.Height.Cmp(height) <= 0 {
+			onchainId, err := client.BlockIdForHeight(ctx, id.Height)
+			if err != nil {
+				return err
+			}
+			if onchainId.Equals(id) {
+				cobBytes, resCtx, err := rcp.RestoreCheckpoint(id)
+				if err != nil {
+					return err
+				}
+				if cobBytes != nil {
+					return unmarshalFunc(cobBytes, resCtx)
+				}
+			}
+		}
+		if id.Equals(oldest) {
+			break
+		}
+		links := &CheckpointLinks{}
+		if err := proto.Unmarshal(rcp.st.GetData(getLinksKey(id)), links); err != nil {
+			return err
+		}
+		if links.Prev == nil {
+			break
+		}
+		prev := links.Prev.Unmarshal()
+		if prev.Equals(id) {
+			// The first checkpoint links back to itself
+			break
+		}
+		id = prev
+	}
+	return fmt.Errorf("no checkpoint is retained at or below height %v", height)
+}
+
 func (rcp *RollupCheckpointerImpl) RestoreCheckpoint(blockId *common.BlockId) ([]byte, RestoreContext, error) {
 	var metadataBuf *CheckpointMetadata
 	var oldestHeightInCp *common.TimeBlocks
@@ -218,6 +272,19 @@ func (rcp *RollupCheckpointerImpl) AsyncSaveCheckpoint(blockId *common.BlockId,
 	)
 }
 
+func (rcp *RollupCheckpointerImpl) SaveChallengeProgress(contract common.Address, participant common.Address, contents []byte, cpCtx CheckpointContext) error {
+	return saveChallengeProgress(rcp.st, challengeProgressKey(contract, participant), contents, cpCtx)
+}
+
+func (rcp *RollupCheckpointerImpl) RestoreChallengeProgress(contract common.Address, participant common.Address) ([]byte, RestoreContext, error) {
+	contents, err := restoreChallengeProgress(rcp.st, challengeProgressKey(contract, participant))
+	return contents, rcp, err
+}
+
+func (rcp *RollupCheckpointerImpl) DeleteChallengeProgress(contract common.Address, participant common.Address) error {
+	return deleteChallengeProgress(rcp.st, challengeProgressKey(contract, participant))
+}
+
 func (rcp *RollupCheckpointerImpl) Close() {
 	rcp.st.CloseCheckpointStorage()
 }
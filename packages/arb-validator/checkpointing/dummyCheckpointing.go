@@ -57,6 +57,10 @@ func (dcp *DummyCheckpointer) RestoreLatestState(ctx context.Context, client arb
 	return errors.New("no checkpoints in database")
 }
 
+func (dcp *DummyCheckpointer) RestoreStateAtHeight(ctx context.Context, client arbbridge.ArbClient, height *common.TimeBlocks, unmarshalFunc func([]byte, RestoreContext) error) error {
+	return errors.New("no checkpoints in database")
+}
+
 func (dcp *DummyCheckpointer) GetInitialMachine() (machine.Machine, error) {
 	return dcp.fac.initialMachine.Clone(), nil
 }
@@ -67,6 +71,18 @@ func (dcp *DummyCheckpointer) AsyncSaveCheckpoint(blockId *common.BlockId, conte
 	}
 }
 
+func (dcp *DummyCheckpointer) SaveChallengeProgress(contract common.Address, participant common.Address, contents []byte, cpCtx CheckpointContext) error {
+	return nil
+}
+
+func (dcp *DummyCheckpointer) RestoreChallengeProgress(contract common.Address, participant common.Address) ([]byte, RestoreContext, error) {
+	return nil, nil, nil
+}
+
+func (dcp *DummyCheckpointer) DeleteChallengeProgress(contract common.Address, participant common.Address) error {
+	return nil
+}
+
 type dummyCheckpointer struct {
 	metadata       []byte
 	cp             map[*common.BlockId]*dummyCheckpoint
@@ -19,6 +19,7 @@ package checkpointing
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"os"
@@ -265,14 +266,61 @@ func (cp *IndexedCheckpointer) RestoreLatestState(ctx context.Context, clnt arbb
 	if heightBounds == nil {
 		return errors.New("Cannot restore because no checkpoint exists")
 	}
-	for height := heightBounds.hi; height.Cmp(heightBounds.lo) >= 0; height = common.NewTimeBlocks(new(big.Int).Sub(height.AsInt(), big.NewInt(1))) {
+	found, err := cp.restoreAtOrBelowLocked(ctx, clnt, heightBounds.hi, heightBounds.lo, unmarshalFunc)
+	if err != nil {
+		return err
+	}
+	if !found {
+		log.Fatal("Called RestoreLatestState on checkpointer that has no stored checkpoints")
+	}
+	return nil
+}
+
+func (cp *IndexedCheckpointer) RestoreStateAtHeight(ctx context.Context, clnt arbbridge.ArbClient, height *common.TimeBlocks, unmarshalFunc func([]byte, RestoreContext) error) error {
+	cp.Lock()
+	defer cp.Unlock()
+
+	heightBounds, err := cp.getHeightBounds()
+	if err != nil {
+		return err
+	}
+	if heightBounds == nil {
+		return errors.New("Cannot restore because no checkpoint exists")
+	}
+	if height.Cmp(heightBounds.hi) > 0 {
+		height = heightBounds.hi
+	}
+	found, err := cp.restoreAtOrBelowLocked(ctx, clnt, height, heightBounds.lo, unmarshalFunc)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no checkpoint is retained at or below height %v", height)
+	}
+	return nil
+}
+
+// restoreAtOrBelowLocked restores the newest checkpoint between lo and hi
+// which is on the current L1 chain. Must be called with cp locked.
+func (cp *IndexedCheckpointer) restoreAtOrBelowLocked(
+	ctx context.Context,
+	clnt arbbridge.ArbClient,
+	hi *common.TimeBlocks,
+	lo *common.TimeBlocks,
+	unmarshalFunc func([]byte, RestoreContext) error,
+) (bool, error) {
+	for height := hi; height.Cmp(lo) >= 0; height = common.NewTimeBlocks(new(big.Int).Sub(height.AsInt(), big.NewInt(1))) {
 		ids, err := cp.getIdsAtHeight(height)
 		if err != nil {
-			return err
+			return false, err
+		}
+		if len(ids) == 0 {
+			// Only ask L1 about heights we have a checkpoint for
+			continue
 		}
 		onchainId, err := clnt.BlockIdForHeight(ctx, height)
 		if err != nil {
-			return err
+			return false, err
 		}
 		for _, id := range ids {
 			if id.Equals(onchainId) {
@@ -280,14 +328,13 @@ func (cp *IndexedCheckpointer) RestoreLatestState(ctx context.Context, clnt arbb
 				val := cp.db.GetData(key)
 				ckpWithMan := &CheckpointWithManifest{}
 				if err := proto.Unmarshal(val, ckpWithMan); err != nil {
-					return err
+					return false, err
 				}
-				return unmarshalFunc(ckpWithMan.Contents, cp.newRestoreContextLocked())
+				return true, unmarshalFunc(ckpWithMan.Contents, cp.newRestoreContextLocked())
 			}
 		}
 	}
-	log.Fatal("Called RestoreLatestState on checkpointer that has no stored checkpoints")
-	return nil // can't reach this but need to make the compiler happy
+	return false, nil
 }
 
 func (cp *IndexedCheckpointer) writeDaemon() {
@@ -347,6 +394,28 @@ func (cp *IndexedCheckpointer) writeCheckpoint(wc *writableCheckpoint) error {
 	return cp.updateHeightUpperBound(wc.blockId.Height)
 }
 
+func (cp *IndexedCheckpointer) SaveChallengeProgress(contract common.Address, participant common.Address, contents []byte, cpCtx CheckpointContext) error {
+	cp.Lock()
+	defer cp.Unlock()
+
+	return saveChallengeProgress(cp.db, challengeProgressKey(contract, participant), contents, cpCtx)
+}
+
+func (cp *IndexedCheckpointer) RestoreChallengeProgress(contract common.Address, participant common.Address) ([]byte, RestoreContext, error) {
+	cp.Lock()
+	defer cp.Unlock()
+
+	contents, err := restoreChallengeProgress(cp.db, challengeProgressKey(contract, participant))
+	return contents, cp, err
+}
+
+func (cp *IndexedCheckpointer) DeleteChallengeProgress(contract common.Address, participant common.Address) error {
+	cp.Lock()
+	defer cp.Unlock()
+
+	return deleteChallengeProgress(cp.db, challengeProgressKey(contract, participant))
+}
+
 func (cp *IndexedCheckpointer) cleanupDaemon() {
 	ticker := time.NewTicker(common.NewTimeBlocksInt(25).Duration())
 	defer ticker.Stop()
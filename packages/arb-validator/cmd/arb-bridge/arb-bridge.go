@@ -0,0 +1,304 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/cmdhelper"
+)
+
+const usage = "usage: arb-bridge <deposit-eth|deposit-erc20|deposit-erc721|balance|withdraw-eth|withdraw-erc20|withdraw-erc721|track> [flags] <args>"
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	var err error
+	switch os.Args[1] {
+	case "deposit-eth", "deposit-erc20", "deposit-erc721":
+		err = deposit(os.Args[1])
+	case "balance":
+		err = balance()
+	case "withdraw-eth", "withdraw-erc20", "withdraw-erc721":
+		err = withdraw(os.Args[1])
+	case "track":
+		err = track()
+	default:
+		err = errors.New(usage)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// walletFlags are the flags of every command which sends transactions
+type walletFlags struct {
+	passphrase *string
+	gasPrice   cmdhelper.GasPriceFlags
+}
+
+func addWalletFlags(cmd *flag.FlagSet) walletFlags {
+	return walletFlags{
+		passphrase: cmd.String("password", "", "password=pass"),
+		gasPrice:   cmdhelper.AddGasPriceFlags(cmd),
+	}
+}
+
+// trackFlags are the flags used to follow a deposit through the rollup
+type trackFlags struct {
+	certFile *string
+	token    *string
+	poll     *int64
+}
+
+func addTrackFlags(cmd *flag.FlagSet) trackFlags {
+	return trackFlags{
+		certFile: cmd.String("validatorcert", "", "validatorcert=CertFile"),
+		token:    cmd.String("validatortoken", "", "validatortoken=BearerToken (requires validatorcert)"),
+		poll:     cmd.Int64("poll", 5, "poll=NumSeconds"),
+	}
+}
+
+func connect(cmd *flag.FlagSet, flags walletFlags, walletFolder string, ethURL string, rollupAddress string) (*bridge, error) {
+	auth, err := cmdhelper.GetKeystore(walletFolder, flags.passphrase, cmd)
+	if err != nil {
+		return nil, err
+	}
+	ethclint, err := ethclient.Dial(ethURL)
+	if err != nil {
+		return nil, err
+	}
+	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	gasPriceOracle, err := flags.gasPrice.Oracle(ethclint)
+	if err != nil {
+		return nil, err
+	}
+	client.SetGasPriceOracle(gasPriceOracle)
+	watcher, err := client.NewRollupWatcher(common.HexToAddress(rollupAddress))
+	if err != nil {
+		return nil, err
+	}
+	inboxAddress, err := watcher.InboxAddress(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	inbox, err := client.NewGlobalInbox(inboxAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &bridge{user: client.Address(), inbox: inbox}, nil
+}
+
+func parseAmount(name string, amount string) (*big.Int, error) {
+	val, ok := new(big.Int).SetString(amount, 10)
+	if !ok || val.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %v %v", name, amount)
+	}
+	return val, nil
+}
+
+func deposit(kind string) error {
+	depositCmd := flag.NewFlagSet(kind, flag.ExitOnError)
+	wallet := addWalletFlags(depositCmd)
+	tracking := addTrackFlags(depositCmd)
+	destination := depositCmd.String("dest", "", "dest=Address")
+	validator := depositCmd.String("validator", "", "validator=Host:Port")
+	err := depositCmd.Parse(os.Args[2:])
+	if err != nil {
+		return err
+	}
+
+	valueName := "amount"
+	tokenArgs := 1
+	switch kind {
+	case "deposit-eth":
+		tokenArgs = 0
+	case "deposit-erc721":
+		valueName = "token_id"
+	}
+	if depositCmd.NArg() != 4+tokenArgs {
+		tokenUsage := ""
+		if tokenArgs == 1 {
+			tokenUsage = "<token_address> "
+		}
+		return fmt.Errorf(
+			"usage: arb-bridge %v [--password=pass] %v [--dest=Address] [--validator=Host:Port] [--validatorcert=CertFile] [--validatortoken=BearerToken] [--poll=NumSeconds] <wallet_folder> <ethURL> <rollup_address> %v<%v>",
+			kind,
+			cmdhelper.GasPriceUsage,
+			tokenUsage,
+			valueName,
+		)
+	}
+	// Catch bad tracking flags before the deposit is sent
+	if *validator != "" {
+		if _, err := validatorDialOptions(*tracking.certFile, *tracking.token); err != nil {
+			return err
+		}
+	}
+	rollupAddress := common.HexToAddress(depositCmd.Arg(2))
+	value, err := parseAmount(valueName, depositCmd.Arg(3+tokenArgs))
+	if err != nil {
+		return err
+	}
+
+	b, err := connect(depositCmd, wallet, depositCmd.Arg(0), depositCmd.Arg(1), depositCmd.Arg(2))
+	if err != nil {
+		return err
+	}
+	dest := b.user
+	if *destination != "" {
+		dest = common.HexToAddress(*destination)
+	}
+
+	var token common.Address
+	if tokenArgs == 1 {
+		token = common.HexToAddress(depositCmd.Arg(3))
+	}
+
+	ctx := context.Background()
+	delivered, err := b.deposit(ctx, kind, rollupAddress, token, dest, value)
+	if err != nil {
+		return err
+	}
+	messageNum, err := deliveredMessageNum(delivered.Message)
+	if err != nil {
+		return err
+	}
+	fmt.Printf(
+		"Delivered message %v in block %v (tx %v)\n",
+		messageNum,
+		delivered.BlockId.Height,
+		hexutil.Encode(delivered.TxHash[:]),
+	)
+
+	if *validator == "" {
+		fmt.Println("Run arb-bridge track with a validator to follow the deposit into the rollup")
+		return nil
+	}
+	return trackMessage(ctx, *validator, tracking, messageNum)
+}
+
+func track() error {
+	trackCmd := flag.NewFlagSet("track", flag.ExitOnError)
+	tracking := addTrackFlags(trackCmd)
+	err := trackCmd.Parse(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	if trackCmd.NArg() != 2 {
+		return errors.New("usage: arb-bridge track [--validatorcert=CertFile] [--validatortoken=BearerToken] [--poll=NumSeconds] <validator_address> <message_num>")
+	}
+	messageNum, err := parseAmount("message number", trackCmd.Arg(1))
+	if err != nil {
+		return err
+	}
+	return trackMessage(context.Background(), trackCmd.Arg(0), tracking, messageNum)
+}
+
+// trackMessage polls the validator until the rollup has confirmed an
+// assertion which read the given message
+func trackMessage(ctx context.Context, validatorAddress string, flags trackFlags, messageNum *big.Int) error {
+	opts, err := validatorDialOptions(*flags.certFile, *flags.token)
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.Dial(validatorAddress, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := validatorserver.NewRollupValidatorClient(conn)
+	return followMessage(ctx, client, time.Duration(*flags.poll)*time.Second, messageNum, os.Stdout)
+}
+
+func balance() error {
+	balanceCmd := flag.NewFlagSet("balance", flag.ExitOnError)
+	wallet := addWalletFlags(balanceCmd)
+	err := balanceCmd.Parse(os.Args[2:])
+	if err != nil {
+		return err
+	}
+	if balanceCmd.NArg() != 3 {
+		return errors.New("usage: arb-bridge balance [--password=pass] <wallet_folder> <ethURL> <rollup_address>")
+	}
+	b, err := connect(balanceCmd, wallet, balanceCmd.Arg(0), balanceCmd.Arg(1), balanceCmd.Arg(2))
+	if err != nil {
+		return err
+	}
+
+	return b.printBalances(context.Background(), os.Stdout)
+}
+
+func withdraw(kind string) error {
+	withdrawCmd := flag.NewFlagSet(kind, flag.ExitOnError)
+	wallet := addWalletFlags(withdrawCmd)
+	err := withdrawCmd.Parse(os.Args[2:])
+	if err != nil {
+		return err
+	}
+
+	extraUsage := map[string]string{
+		"withdraw-eth":    "",
+		"withdraw-erc20":  " <token_address>",
+		"withdraw-erc721": " <token_address> <token_id>",
+	}[kind]
+	extraArgs := map[string]int{
+		"withdraw-eth":    0,
+		"withdraw-erc20":  1,
+		"withdraw-erc721": 2,
+	}[kind]
+	if withdrawCmd.NArg() != 3+extraArgs {
+		return fmt.Errorf("usage: arb-bridge %v [--password=pass] %v <wallet_folder> <ethURL> <rollup_address>%v", kind, cmdhelper.GasPriceUsage, extraUsage)
+	}
+	b, err := connect(withdrawCmd, wallet, withdrawCmd.Arg(0), withdrawCmd.Arg(1), withdrawCmd.Arg(2))
+	if err != nil {
+		return err
+	}
+
+	var token common.Address
+	if extraArgs > 0 {
+		token = common.HexToAddress(withdrawCmd.Arg(3))
+	}
+	var id *big.Int
+	if extraArgs > 1 {
+		id, err = parseAmount("token_id", withdrawCmd.Arg(4))
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.withdraw(context.Background(), kind, token, id); err != nil {
+		return err
+	}
+	fmt.Println("Withdrawal complete")
+	return nil
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+)
+
+// bridge is the global inbox of a rollup as seen by the keystore's account
+type bridge struct {
+	user  common.Address
+	inbox arbbridge.GlobalInbox
+}
+
+// deposit sends a deposit of the given kind to the rollup. token is ignored
+// for eth deposits and value is the token id for ERC721 deposits.
+func (b *bridge) deposit(
+	ctx context.Context,
+	kind string,
+	rollupAddress common.Address,
+	token common.Address,
+	dest common.Address,
+	value *big.Int,
+) (arbbridge.MessageDeliveredEvent, error) {
+	switch kind {
+	case "deposit-eth":
+		return b.inbox.DepositEthMessage(ctx, rollupAddress, dest, value)
+	case "deposit-erc20":
+		return b.inbox.DepositERC20Message(ctx, rollupAddress, token, dest, value)
+	case "deposit-erc721":
+		return b.inbox.DepositERC721Message(ctx, rollupAddress, token, dest, value)
+	default:
+		return arbbridge.MessageDeliveredEvent{}, fmt.Errorf("unknown deposit %v", kind)
+	}
+}
+
+// withdraw takes funds of the given kind out of the inbox. token is ignored
+// for eth and id is only used for ERC721 tokens.
+func (b *bridge) withdraw(ctx context.Context, kind string, token common.Address, id *big.Int) error {
+	switch kind {
+	case "withdraw-eth":
+		return b.inbox.WithdrawEth(ctx)
+	case "withdraw-erc20":
+		return b.inbox.WithdrawERC20(ctx, token)
+	case "withdraw-erc721":
+		return b.inbox.WithdrawERC721(ctx, token, id)
+	default:
+		return fmt.Errorf("unknown withdrawal %v", kind)
+	}
+}
+
+// printBalances writes every balance the user can withdraw from the inbox
+func (b *bridge) printBalances(ctx context.Context, w io.Writer) error {
+	fmt.Fprintln(w, "Withdrawable balances of", b.user.Hex())
+	ethBalance, err := b.inbox.GetEthBalance(ctx, b.user)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "ETH:", ethBalance, "wei")
+
+	erc20s, err := b.inbox.GetOwnedERC20s(ctx, b.user)
+	if err != nil {
+		return err
+	}
+	for _, token := range erc20s {
+		amount, err := b.inbox.GetTokenBalance(ctx, b.user, token)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "ERC20 %v: %v\n", token.Hex(), amount)
+	}
+
+	erc721s, err := b.inbox.GetOwnedERC721s(ctx, b.user)
+	if err != nil {
+		return err
+	}
+	for _, token := range erc721s {
+		ids, err := b.inbox.GetERC721Tokens(ctx, b.user, token)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "ERC721 %v: %v\n", token.Hex(), ids)
+	}
+	return nil
+}
+
+func deliveredMessageNum(msg message.InboxMessage) (*big.Int, error) {
+	switch msg := msg.(type) {
+	case message.DeliveredEth:
+		return msg.MessageNum, nil
+	case message.DeliveredERC20:
+		return msg.MessageNum, nil
+	case message.DeliveredERC721:
+		return msg.MessageNum, nil
+	default:
+		return nil, fmt.Errorf("unexpected delivered message %T", msg)
+	}
+}
+
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// validatorDialOptions connects with TLS if certFile is set. A token is only
+// ever sent over TLS.
+func validatorDialOptions(certFile string, token string) ([]grpc.DialOption, error) {
+	if certFile == "" {
+		if token != "" {
+			return nil, errors.New("a validator token can only be sent with a validator certificate")
+		}
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+	creds, err := credentials.NewClientTLSFromFile(certFile, "")
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(token)))
+	}
+	return opts, nil
+}
+
+// followMessage reports each stage the message reaches until it's confirmed
+func followMessage(
+	ctx context.Context,
+	client validatorserver.RollupValidatorClient,
+	poll time.Duration,
+	messageNum *big.Int,
+	w io.Writer,
+) error {
+	stages := []string{"seen by the validator", "read by an assertion", "confirmed"}
+	reached := 0
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		reply, err := client.GetInboxCount(ctx, &validatorserver.GetInboxCountArgs{})
+		if err != nil {
+			return err
+		}
+		for i, count := range []string{reply.Delivered, reply.Asserted, reply.Confirmed} {
+			val, err := hexutil.DecodeBig(count)
+			if err != nil {
+				return err
+			}
+			if i == reached && val.Cmp(messageNum) >= 0 {
+				fmt.Fprintf(w, "Message %v %v\n", messageNum, stages[i])
+				reached++
+			}
+		}
+		if reached == len(stages) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/mockbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+)
+
+// walletClient gives the mock inbox the address transactions come from
+type walletClient struct {
+	arbbridge.ArbAuthClient
+	address common.Address
+}
+
+func (c walletClient) Address() common.Address {
+	return c.address
+}
+
+func newMockBridge(t *testing.T, user common.Address) (*bridge, *mockbridge.GlobalInbox) {
+	t.Helper()
+	inbox, err := mockbridge.NewGlobalInbox(common.Address{}, walletClient{address: user})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &bridge{user: user, inbox: inbox}, inbox
+}
+
+func TestDepositAndWithdraw(t *testing.T) {
+	ctx := context.Background()
+	user := common.Address{1}
+	rollupAddress := common.Address{2}
+	token := common.Address{3}
+	b, inbox := newMockBridge(t, user)
+
+	deposits := []struct {
+		kind  string
+		value int64
+	}{
+		{"deposit-eth", 100},
+		{"deposit-erc20", 50},
+		{"deposit-erc721", 7},
+	}
+	for i, d := range deposits {
+		delivered, err := b.deposit(ctx, d.kind, rollupAddress, token, user, big.NewInt(d.value))
+		if err != nil {
+			t.Fatal(err)
+		}
+		messageNum, err := deliveredMessageNum(delivered.Message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if messageNum.Int64() != int64(i+1) {
+			t.Error(d.kind, "delivered message", messageNum, "instead of", i+1)
+		}
+	}
+	if _, err := b.deposit(ctx, "deposit-other", rollupAddress, token, user, big.NewInt(1)); err == nil {
+		t.Error("made an unknown kind of deposit")
+	}
+
+	// Deposits are held by the chain until it pays them out
+	var out bytes.Buffer
+	if err := b.printBalances(ctx, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "ETH: 0 wei") {
+		t.Error("user has funds before the chain paid out:", out.String())
+	}
+	err := inbox.DeliverOutgoing(rollupAddress, []message.UnsentMessage{
+		message.Eth{To: user, From: rollupAddress, Value: big.NewInt(60)},
+		message.ERC20{To: user, From: rollupAddress, TokenAddress: token, Value: big.NewInt(50)},
+		message.ERC721{To: user, From: rollupAddress, TokenAddress: token, Id: big.NewInt(7)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := b.printBalances(ctx, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"ETH: 60 wei", "ERC20 " + token.Hex() + ": 50", "ERC721 " + token.Hex() + ": [7]"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("balances are missing %q: %v", expected, out.String())
+		}
+	}
+
+	for _, kind := range []string{"withdraw-eth", "withdraw-erc20", "withdraw-erc721"} {
+		if err := b.withdraw(ctx, kind, token, big.NewInt(7)); err != nil {
+			t.Error(kind, "failed:", err)
+		}
+		if err := b.withdraw(ctx, kind, token, big.NewInt(7)); err == nil {
+			t.Error(kind, "withdrew the same funds twice")
+		}
+	}
+	out.Reset()
+	if err := b.printBalances(ctx, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "Withdrawable balances of "+user.Hex()+"\nETH: 0 wei\n" {
+		t.Error("funds left after withdrawing everything:", out.String())
+	}
+}
+
+// inboxCounts replays a series of validator inbox counts, repeating the last
+type inboxCounts struct {
+	validatorserver.RollupValidatorClient
+	replies []*validatorserver.GetInboxCountReply
+	polls   int
+}
+
+func (c *inboxCounts) GetInboxCount(ctx context.Context, in *validatorserver.GetInboxCountArgs, opts ...grpc.CallOption) (*validatorserver.GetInboxCountReply, error) {
+	if len(c.replies) == 0 {
+		return nil, errors.New("validator is unavailable")
+	}
+	reply := c.replies[0]
+	if len(c.replies) > 1 {
+		c.replies = c.replies[1:]
+	}
+	c.polls++
+	return reply, nil
+}
+
+func countsReply(delivered, asserted, confirmed int64) *validatorserver.GetInboxCountReply {
+	return &validatorserver.GetInboxCountReply{
+		Delivered: hexutil.EncodeBig(big.NewInt(delivered)),
+		Asserted:  hexutil.EncodeBig(big.NewInt(asserted)),
+		Confirmed: hexutil.EncodeBig(big.NewInt(confirmed)),
+	}
+}
+
+func TestFollowMessage(t *testing.T) {
+	client := &inboxCounts{replies: []*validatorserver.GetInboxCountReply{
+		countsReply(4, 2, 2),
+		countsReply(5, 4, 2),
+		countsReply(5, 5, 2),
+		countsReply(6, 6, 5),
+	}}
+	var out bytes.Buffer
+	if err := followMessage(context.Background(), client, time.Millisecond, big.NewInt(5), &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Message 5 seen by the validator\nMessage 5 read by an assertion\nMessage 5 confirmed\n"
+	if out.String() != expected {
+		t.Errorf("expected stages\n%vbut got\n%v", expected, out.String())
+	}
+	if client.polls != 4 {
+		t.Error("polled", client.polls, "times")
+	}
+
+	// A single poll can pass several stages at once
+	out.Reset()
+	client = &inboxCounts{replies: []*validatorserver.GetInboxCountReply{countsReply(9, 9, 9)}}
+	if err := followMessage(context.Background(), client, time.Millisecond, big.NewInt(5), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != expected {
+		t.Errorf("expected stages\n%vbut got\n%v", expected, out.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client = &inboxCounts{replies: []*validatorserver.GetInboxCountReply{countsReply(1, 0, 0)}}
+	if err := followMessage(ctx, client, time.Millisecond, big.NewInt(5), &out); err != context.Canceled {
+		t.Error("expected cancelled tracking to stop but got", err)
+	}
+}
+
+func TestValidatorTokenRequiresTLS(t *testing.T) {
+	if !bearerToken("secret").RequireTransportSecurity() {
+		t.Error("bearer token may be sent without TLS")
+	}
+	if _, err := validatorDialOptions("", "secret"); err == nil {
+		t.Error("allowed a validator token without a certificate")
+	}
+	opts, err := validatorDialOptions("", "")
+	if err != nil || len(opts) != 1 {
+		t.Error("expected an insecure connection without a token", err)
+	}
+}
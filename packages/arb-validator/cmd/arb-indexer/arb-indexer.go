@@ -0,0 +1,132 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/rpc"
+	"github.com/gorilla/rpc/json"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/eventdb"
+)
+
+// Launches an indexer which records the events of one or more rollup chains
+// and their challenges and serves queries over them with JSON-RPC
+func main() {
+	indexCmd := flag.NewFlagSet("arb-indexer", flag.ExitOnError)
+	dbPath := indexCmd.String("dbpath", "events.db", "dbpath=Path")
+	rpcPort := indexCmd.String("rpcport", "1238", "rpcport=Port")
+	corsOrigins := indexCmd.String("corsorigins", "*", "corsorigins=Origin1,Origin2")
+	fallbackURLs := indexCmd.String("fallbackethurls", "", "fallbackethurls=URL1,URL2")
+	logRange := indexCmd.Uint64("logrange", ethbridge.DefaultFetchConfig().MaxBlockRange, "logrange=NumBlocks")
+	if err := indexCmd.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	if indexCmd.NArg() < 1 {
+		log.Fatal("usage: arb-indexer [--dbpath=Path] [--rpcport=Port] [--corsorigins=Origin1,Origin2] [--fallbackethurls=URL1,URL2] [--logrange=NumBlocks] <ethURL> [rollup_address...]")
+	}
+
+	fetchConfig := ethbridge.DefaultFetchConfig()
+	fetchConfig.MaxBlockRange = *logRange
+	if err := fetchConfig.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	ethURLs := []string{indexCmd.Arg(0)}
+	for _, url := range strings.Split(*fallbackURLs, ",") {
+		if url != "" {
+			ethURLs = append(ethURLs, url)
+		}
+	}
+	if err := run(ethURLs, fetchConfig, indexCmd.Args()[1:], *dbPath, *rpcPort, strings.Split(*corsOrigins, ",")); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run starts indexing any new rollups along with the ones already in the
+// index at dbPath. Any L1 endpoints after the first are used as fallbacks.
+func run(ethURLs []string, fetchConfig ethbridge.FetchConfig, rollups []string, dbPath string, rpcPort string, corsOrigins []string) error {
+	ethclint, err := ethclient.Dial(ethURLs[0])
+	if err != nil {
+		return err
+	}
+	client := ethbridge.NewEthClient(ethclint)
+	client.SetFetchConfig(fetchConfig)
+	for _, url := range ethURLs[1:] {
+		fallback, err := ethclient.Dial(url)
+		if err != nil {
+			return err
+		}
+		client.AddFallbackClients(fallback)
+	}
+	db, err := eventdb.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	indexer := eventdb.NewIndexer(client, db)
+	for _, rollup := range rollups {
+		if !ethcommon.IsHexAddress(rollup) {
+			return fmt.Errorf("invalid rollup address %v", rollup)
+		}
+		if err := indexer.AddRollup(ctx, common.HexToAddress(rollup)); err != nil {
+			return err
+		}
+	}
+
+	errChan := make(chan error, 2)
+	go func() {
+		errChan <- indexer.Run(ctx)
+	}()
+	go func() {
+		errChan <- serveJSON(eventdb.NewRPCServer(db), rpcPort, corsOrigins)
+	}()
+	return <-errChan
+}
+
+func serveJSON(server *eventdb.RPCServer, port string, corsOrigins []string) error {
+	s := rpc.NewServer()
+	s.RegisterCodec(json.NewCodec(), "application/json")
+	s.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
+
+	if err := s.RegisterService(server, "Indexer"); err != nil {
+		return err
+	}
+	r := mux.NewRouter()
+	r.Handle("/", s).Methods("GET", "POST", "OPTIONS")
+
+	headersOk := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"})
+	originsOk := handlers.AllowedOrigins(corsOrigins)
+	methodsOk := handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "OPTIONS"})
+	return http.ListenAndServe(":"+port, handlers.CORS(headersOk, originsOk, methodsOk)(r))
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2019, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/vm"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/loader"
+)
+
+// Prints every difference between two machine states:
+// arb-machine-diff checkpoint [-db2 other_db] contract.ao db machine_hash1 machine_hash2
+// arb-machine-diff proof proof1 proof2
+// Proof files hold the output of MarshalForProof, either as raw bytes or hex.
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalln("usage: arb-machine-diff [checkpoint|proof] ...")
+	}
+	var first, second *machine.Snapshot
+	var err error
+	switch os.Args[1] {
+	case "checkpoint":
+		first, second, err = loadCheckpoints(os.Args[2:])
+	case "proof":
+		first, second, err = loadProofs(os.Args[2:])
+	default:
+		err = fmt.Errorf("unknown command %v", os.Args[1])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("first machine ", first.Hash())
+	fmt.Println("second machine", second.Hash())
+	diffs := machine.DiffSnapshots(first, second)
+	if len(diffs) == 0 {
+		fmt.Println("machines are identical")
+		return
+	}
+	for _, diff := range diffs {
+		fmt.Println(diff)
+	}
+	os.Exit(1)
+}
+
+func loadCheckpoints(args []string) (*machine.Snapshot, *machine.Snapshot, error) {
+	checkpointCmd := flag.NewFlagSet("checkpoint", flag.ExitOnError)
+	db2 := checkpointCmd.String("db2", "", "database holding the second machine if it's different from the first")
+	if err := checkpointCmd.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if checkpointCmd.NArg() != 4 {
+		return nil, nil, errors.New("usage: arb-machine-diff checkpoint [-db2 other_db] contract.ao db machine_hash1 machine_hash2")
+	}
+	contractFile := checkpointCmd.Arg(0)
+	db1 := checkpointCmd.Arg(1)
+	if *db2 == "" {
+		*db2 = db1
+	}
+
+	first, err := loadCheckpoint(contractFile, db1, checkpointCmd.Arg(2))
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := loadCheckpoint(contractFile, *db2, checkpointCmd.Arg(3))
+	if err != nil {
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+func loadCheckpoint(contractFile string, dbPath string, hashStr string) (*machine.Snapshot, error) {
+	hashBytes, err := hexutil.Decode(hashStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid machine hash %v: %v", hashStr, err)
+	}
+	if len(hashBytes) != 32 {
+		return nil, fmt.Errorf("machine hash %v must be 32 bytes", hashStr)
+	}
+	var machineHash common.Hash
+	copy(machineHash[:], hashBytes)
+
+	storage, err := loader.CreateCheckpointStorage(dbPath, contractFile)
+	if err != nil {
+		return nil, err
+	}
+	defer storage.CloseCheckpointStorage()
+	mach, err := storage.GetMachine(machineHash)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load machine %v from %v: %v", machineHash, dbPath, err)
+	}
+	snapshotter, ok := mach.(machine.Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("machine %v doesn't support snapshots", machineHash)
+	}
+	return snapshotter.Snapshot()
+}
+
+func loadProofs(args []string) (*machine.Snapshot, *machine.Snapshot, error) {
+	if len(args) != 2 {
+		return nil, nil, errors.New("usage: arb-machine-diff proof proof1 proof2")
+	}
+	first, err := loadProof(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	second, err := loadProof(args[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return first, second, nil
+}
+
+func loadProof(fileName string) (*machine.Snapshot, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if text := strings.TrimSpace(string(data)); strings.HasPrefix(text, "0x") {
+		data, err = hexutil.Decode(text)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", fileName, err)
+		}
+	}
+	snapshot, err := vm.ParseProof(data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", fileName, err)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,141 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupmanager"
+)
+
+// Re-executes the assertion that created a rollup node using the state in a
+// validator's checkpoint database and reports every field of the on-chain
+// claim that disagrees with the execution. The validator using the database
+// must be stopped first.
+// arb-replay [-db checkpoint_db] <validator_folder> <ethURL> <rollup_address> <node_hash>
+func main() {
+	replayCmd := flag.NewFlagSet("arb-replay", flag.ExitOnError)
+	dbPath := replayCmd.String("db", "", "checkpoint database to restore from if not validator_folder/checkpoint_db")
+	if err := replayCmd.Parse(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+	if replayCmd.NArg() != 4 {
+		log.Fatalln("usage: arb-replay [-db checkpoint_db] <validator_folder> <ethURL> <rollup_address> <node_hash>")
+	}
+	report, err := replay(
+		replayCmd.Arg(0),
+		*dbPath,
+		replayCmd.Arg(1),
+		replayCmd.Arg(2),
+		replayCmd.Arg(3),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("node          ", report.NodeHash)
+	fmt.Println("link type     ", report.LinkType)
+	fmt.Println("start machine ", report.StartHash)
+	fmt.Println("params        ", report.Params)
+	fmt.Println("claim         ", report.Claim)
+	fmt.Println("steps run     ", report.StepsRun)
+	fmt.Println("execution     ", report.Assertion)
+	if len(report.Mismatches) == 0 {
+		fmt.Println("execution matches the claim")
+		return
+	}
+	for _, mismatch := range report.Mismatches {
+		fmt.Println(mismatch)
+	}
+	os.Exit(1)
+}
+
+func replay(
+	validatorFolder string,
+	dbPath string,
+	ethURL string,
+	addressStr string,
+	nodeHashStr string,
+) (*rollup.ReplayReport, error) {
+	if !ethcommon.IsHexAddress(addressStr) {
+		return nil, fmt.Errorf("invalid rollup address %v", addressStr)
+	}
+	rollupAddr := common.HexToAddress(addressStr)
+	hashBytes, err := hexutil.Decode(nodeHashStr)
+	if err != nil || len(hashBytes) != 32 {
+		return nil, fmt.Errorf("invalid node hash %v", nodeHashStr)
+	}
+	var nodeHash common.Hash
+	copy(nodeHash[:], hashBytes)
+
+	if dbPath == "" {
+		dbPath = filepath.Join(validatorFolder, "checkpoint_db")
+	}
+	contractFile := filepath.Join(validatorFolder, "contract.ao")
+
+	ethclint, err := ethclient.Dial(ethURL)
+	if err != nil {
+		return nil, err
+	}
+	client := ethbridge.NewEthClient(ethclint)
+
+	ctx := context.Background()
+	checkpointer := checkpointing.NewIndexedCheckpointerFactory(
+		rollupAddr,
+		contractFile,
+		dbPath,
+		big.NewInt(rollupmanager.DefaultMaxReorgDepth),
+		false,
+	).New(ctx)
+	if !checkpointer.HasCheckpointedState() {
+		return nil, errors.New("checkpoint database has no saved state")
+	}
+
+	var chain *rollup.ChainObserver
+	err = checkpointer.RestoreLatestState(ctx, client, func(chainObserverBytes []byte, restoreCtx checkpointing.RestoreContext) error {
+		chainObserverBuf := &rollup.ChainObserverBuf{}
+		if err := proto.Unmarshal(chainObserverBytes, chainObserverBuf); err != nil {
+			return err
+		}
+		var err error
+		chain, err = chainObserverBuf.UnmarshalFromCheckpoint(ctx, restoreCtx, checkpointer)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if chain.ContractAddress() != rollupAddr {
+		return nil, fmt.Errorf("checkpoint is for rollup %v, not %v", chain.ContractAddress(), rollupAddr)
+	}
+	return chain.ReplayNode(nodeHash)
+}
@@ -17,18 +17,24 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
-	"math"
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/verifier"
+
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/cmdhelper"
 
 	errors2 "github.com/pkg/errors"
@@ -62,14 +68,17 @@ func main() {
 func createRollupChain() error {
 	createCmd := flag.NewFlagSet("validate", flag.ExitOnError)
 	passphrase := createCmd.String("password", "", "password=pass")
-	gasPrice := createCmd.Float64("gasprice", 4.5, "gasprice=FloatInGwei")
+	gasPriceFlags := cmdhelper.AddGasPriceFlags(createCmd)
+	chainFlags := cmdhelper.AddChainConfigFlags(createCmd, rollup.DefaultChainParams())
+	machineHash := createCmd.String("machinehash", "", "machinehash=Hash")
+	yes := createCmd.Bool("yes", false, "yes")
 	err := createCmd.Parse(os.Args[2:])
 	if err != nil {
 		return err
 	}
 
 	if createCmd.NArg() != 3 {
-		return errors.New("usage: arb-validator create [--password=pass] [--gasprice==FloatInGwei] <validator_folder> <ethURL> <factoryAddress>")
+		return errors.New("usage: arb-validator create [--password=pass] " + cmdhelper.GasPriceUsage + " " + cmdhelper.ChainConfigUsage + " [--machinehash=Hash] [--yes] <validator_folder> <ethURL> <factoryAddress>")
 	}
 
 	validatorFolder := createCmd.Arg(0)
@@ -78,19 +87,49 @@ func createRollupChain() error {
 	factoryAddress := common.HexToAddress(addressString)
 	contractFile := filepath.Join(validatorFolder, "contract.ao")
 
-	// 1) Compiled Arbitrum bytecode
-	mach, err := loader.LoadMachineFromFile(contractFile, true, "cpp")
+	record, err := cmdhelper.ReadDeploymentRecord(validatorFolder)
 	if err != nil {
-		return errors2.Wrap(err, "loader error")
+		return err
+	}
+	if record != nil {
+		return fmt.Errorf("%v already holds the deployment record of rollup %v", validatorFolder, record.RollupAddress)
 	}
 
+	// The chain is owned by the deployer unless --owner is given
 	auth, err := cmdhelper.GetKeystore(validatorFolder, passphrase, createCmd)
 	if err != nil {
 		return err
 	}
-	gasPriceAsFloat := 1e9 * (*gasPrice)
-	if gasPriceAsFloat < math.MaxInt64 {
-		auth.GasPrice = big.NewInt(int64(gasPriceAsFloat))
+	config, err := chainFlags.Config(common.NewAddressFromEth(auth.From))
+	if err != nil {
+		return err
+	}
+	params, ownerAddress, err := config.Params()
+	if err != nil {
+		return errors2.Wrap(err, "invalid chain parameters")
+	}
+
+	// 1) Compiled Arbitrum bytecode
+	if err := verifyContract(contractFile); err != nil {
+		return err
+	}
+	mach, err := loader.LoadMachineFromFile(contractFile, true, "cpp")
+	if err != nil {
+		return errors2.Wrap(err, "loader error")
+	}
+	if *machineHash != "" && *machineHash != mach.Hash().String() {
+		return fmt.Errorf("%v has machine hash %v, not %v", contractFile, mach.Hash(), *machineHash)
+	}
+
+	log.Println("Machine hash:", mach.Hash())
+	log.Println("Stake requirement:", params.StakeRequirement, "wei")
+	log.Println("Grace period:", config.GracePeriodBlocks, "blocks")
+	log.Println("Max execution steps:", params.MaxExecutionSteps)
+	log.Println("Max time bounds width:", params.MaxTimeBoundsWidth, "blocks")
+	log.Println("ArbGas speed limit:", params.ArbGasSpeedLimitPerTick, "per tick")
+	log.Println("Owner:", ownerAddress.Hex())
+	if !*yes && !confirm("Create rollup chain with these parameters?") {
+		return errors.New("rollup creation cancelled")
 	}
 
 	ethclint, err := ethclient.Dial(ethURL)
@@ -100,6 +139,11 @@ func createRollupChain() error {
 
 	// Rollup creation
 	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	gasPriceOracle, err := gasPriceFlags.Oracle(ethclint)
+	if err != nil {
+		return err
+	}
+	client.SetGasPriceOracle(gasPriceOracle)
 
 	if err := arbbridge.WaitForNonZeroBalance(context.Background(), client, common.NewAddressFromEth(auth.From)); err != nil {
 		return err
@@ -110,19 +154,63 @@ func createRollupChain() error {
 		return err
 	}
 
-	address, err := factory.CreateRollup(
+	address, creation, err := factory.CreateRollup(
 		context.Background(),
 		mach.Hash(),
-		rollup.DefaultChainParams(),
-		common.Address{},
+		params,
+		ownerAddress,
 	)
 	if err != nil {
 		return err
 	}
 	fmt.Println(address.Hex())
-	return nil
+	return cmdhelper.WriteDeploymentRecord(
+		validatorFolder,
+		cmdhelper.NewDeploymentRecord(address, factoryAddress, mach.Hash(), creation, params, ownerAddress),
+	)
+}
+
+func confirm(question string) bool {
+	fmt.Fprint(os.Stderr, question, " [y/N] ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// verifyContract statically checks the program since a chain created with a
+// malformed machine can never be fixed
+func verifyContract(contractFile string) error {
+	data, err := ioutil.ReadFile(contractFile)
+	if err != nil {
+		return err
+	}
+	prog, err := aoasm.ReadProgram(bytes.NewReader(data))
+	if err != nil {
+		return errors2.Wrap(err, "loader error")
+	}
+	debugInfo, err := prog.DebugInfo()
+	if err != nil {
+		return errors2.Wrap(err, "loader error")
+	}
+
+	report := verifier.Verify(prog.Code, prog.Static)
+	for _, issue := range report.Errors() {
+		log.Printf("%v (%v)\n", issue, debugInfo.Describe(issue.InsnNum))
+	}
+	maxBlock := report.MaxBlockGas()
+	log.Printf(
+		"Verified %v: %v issues, most expensive basic block uses %v gas at %v\n",
+		contractFile,
+		len(report.Issues),
+		maxBlock.Gas,
+		debugInfo.Describe(maxBlock.Start),
+	)
+	return report.Err()
 }
 
-func createManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string) (*rollupmanager.Manager, error) {
-	return rollupmanager.CreateManager(rollupAddress, client, contractFile, dbPath)
+func createManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string, maxReorgDepth *big.Int) (*rollupmanager.Manager, error) {
+	return rollupmanager.CreateManagerWithReorgDepth(rollupAddress, client, contractFile, dbPath, maxReorgDepth)
 }
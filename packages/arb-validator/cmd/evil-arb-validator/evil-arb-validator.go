@@ -51,8 +51,8 @@ func main() {
 	}
 }
 
-func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string) (*rollupmanager.Manager, error) {
-	return rollupmanager.CreateManagerAdvanced(
+func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string, maxReorgDepth *big.Int) (*rollupmanager.Manager, error) {
+	man, err := rollupmanager.CreateManagerAdvanced(
 		context.Background(),
 		rollupAddress,
 		true,
@@ -61,8 +61,13 @@ func createEvilManager(rollupAddress common.Address, client arbbridge.ArbAuthCli
 			rollupAddress,
 			contractFile,
 			dbPath,
-			big.NewInt(100),
+			maxReorgDepth,
 			false,
 		),
 	)
+	if err != nil {
+		return nil, err
+	}
+	man.SetMaxReorgDepth(maxReorgDepth)
+	return man, nil
 }
@@ -21,7 +21,6 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -34,18 +33,33 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/challenges"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/coordinator"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupmanager"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupvalidator"
 
 	"golang.org/x/crypto/ssh/terminal"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 )
 
 func GetKeystore(validatorFolder string, pass *string, flags *flag.FlagSet) (*bind.TransactOpts, error) {
+	ks, account, err := unlockKeystore(validatorFolder, pass, flags)
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewKeyStoreTransactor(ks, account)
+}
+
+// unlockKeystore unlocks the validator's account, creating it if the
+// keystore is empty
+func unlockKeystore(validatorFolder string, pass *string, flags *flag.FlagSet) (*keystore.KeyStore, accounts.Account, error) {
 	ks := keystore.NewKeyStore(filepath.Join(validatorFolder, "wallets"), keystore.StandardScryptN, keystore.StandardScryptP)
 
 	found := false
@@ -65,7 +79,7 @@ func GetKeystore(validatorFolder string, pass *string, flags *flag.FlagSet) (*bi
 
 		bytePassword, err := terminal.ReadPassword(int(syscall.Stdin))
 		if err != nil {
-			return nil, err
+			return nil, accounts.Account{}, err
 		}
 		passphrase = string(bytePassword)
 
@@ -79,72 +93,207 @@ func GetKeystore(validatorFolder string, pass *string, flags *flag.FlagSet) (*bi
 		var err error
 		account, err = ks.NewAccount(passphrase)
 		if err != nil {
-			return nil, err
+			return nil, accounts.Account{}, err
 		}
 	} else {
 		account = ks.Accounts()[0]
 	}
-	err := ks.Unlock(account, passphrase)
-	if err != nil {
-		return nil, err
-	}
-	auth, err := bind.NewKeyStoreTransactor(ks, account)
-	if err != nil {
-		return nil, err
+	if err := ks.Unlock(account, passphrase); err != nil {
+		return nil, accounts.Account{}, err
 	}
-	return auth, nil
+	return ks, account, nil
 }
 
-func ValidateRollupChain(execName string, managerCreationFunc func(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string) (*rollupmanager.Manager, error)) error {
+func ValidateRollupChain(execName string, managerCreationFunc func(rollupAddress common.Address, client arbbridge.ArbAuthClient, contractFile string, dbPath string, maxReorgDepth *big.Int) (*rollupmanager.Manager, error)) error {
 	// Check number of args
 
 	validateCmd := flag.NewFlagSet("validate", flag.ExitOnError)
 	passphrase := validateCmd.String("password", "", "password=pass")
 	rpcEnable := validateCmd.Bool("rpc", false, "rpc")
+	defaultRPCConfig := rollupvalidator.DefaultRPCConfig()
+	rpcPort := validateCmd.String("rpcport", defaultRPCConfig.JSONPort, "rpcport=Port")
+	grpcPort := validateCmd.String("grpcport", defaultRPCConfig.GRPCPort, "grpcport=Port (disabled if empty, unauthenticated unless rpctoken is set)")
+	tlsCert := validateCmd.String("tlscert", "", "tlscert=CertFile")
+	tlsKey := validateCmd.String("tlskey", "", "tlskey=KeyFile")
+	rpcToken := validateCmd.String("rpctoken", "", "rpctoken=BearerToken")
+	corsOrigins := validateCmd.String("corsorigins", strings.Join(defaultRPCConfig.CORSOrigins, ","), "corsorigins=Origin1,Origin2")
+	callWorkers := validateCmd.Int("callworkers", defaultRPCConfig.Calls.Workers, "callworkers=NumCalls")
+	callQueue := validateCmd.Int("callqueue", defaultRPCConfig.Calls.QueueDepth, "callqueue=NumCalls")
+	callSteps := validateCmd.Uint64("callsteps", defaultRPCConfig.Calls.MaxSteps, "callsteps=NumSteps")
+	callTime := validateCmd.Int64("calltime", int64(defaultRPCConfig.Calls.MaxTime/time.Second), "calltime=NumSeconds")
 	blocktime := validateCmd.Int64("blocktime", 2, "blocktime=NumSeconds")
-	gasPrice := validateCmd.Float64("gasprice", 4.5, "gasprice=FloatInGwei")
+	gasPriceFlags := AddGasPriceFlags(validateCmd)
+	fallbackURLs := validateCmd.String("fallbackethurls", "", "fallbackethurls=URL1,URL2")
+	logRange := validateCmd.Uint64("logrange", ethbridge.DefaultFetchConfig().MaxBlockRange, "logrange=NumBlocks")
+	defaultStrategy := challenges.DefaultStrategy()
+	inboxTopBisection := validateCmd.Uint64("inboxbisection", defaultStrategy.InboxTopBisectionCount, "inboxbisection=NumSegments")
+	messagesBisection := validateCmd.Uint64("messagesbisection", defaultStrategy.MessagesBisectionCount, "messagesbisection=NumSegments")
+	executionBisection := validateCmd.Uint("executionbisection", uint(defaultStrategy.ExecutionBisectionCount), "executionbisection=NumSegments")
+	challengePoll := validateCmd.Int64("challengepoll", defaultStrategy.PollInterval.AsInt().Int64(), "challengepoll=NumBlocks")
+	challengeRetries := validateCmd.Uint("challengeretries", defaultStrategy.MaxRetries, "challengeretries=NumRetries")
+	maxStakes := validateCmd.Int("maxstakes", 0, "maxstakes=NumStakes")
+	maxCapital := validateCmd.String("maxcapital", "", "maxcapital=AmountInWei")
+	maxChallenges := validateCmd.Int("maxchallenges", 0, "maxchallenges=NumChallenges")
+	challengeWindow := validateCmd.Int64("challengewindow", 0, "challengewindow=NumBlocks")
+	requireConfidence := validateCmd.Bool("requireconfidence", false, "requireconfidence")
+	reorgDepth := validateCmd.Int64("reorgdepth", rollupmanager.DefaultMaxReorgDepth, "reorgdepth=NumBlocks")
+	unanimousMode := validateCmd.String("unanimous", "", "unanimous=coordinator|follower")
+	assertKeys := validateCmd.String("assertkeys", "", "assertkeys=Address1,Address2")
+	coordinatorKey := validateCmd.String("coordinatorkey", "", "coordinatorkey=Address")
+	unanimousPeers := validateCmd.String("unanimouspeers", "", "unanimouspeers=Host1:Port,Host2:Port")
+	unanimousPort := validateCmd.String("unanimousport", "1237", "unanimousport=Port")
 	err := validateCmd.Parse(os.Args[2:])
 	if err != nil {
 		return err
 	}
 
-	if validateCmd.NArg() != 3 {
-		return fmt.Errorf("usage: %v validate [--password=pass] [--rpc] [--blocktime=NumSeconds] [--gasprice==FloatInGwei] <validator_folder> <ethURL> <rollup_address>", execName)
+	if validateCmd.NArg() != 2 && validateCmd.NArg() != 3 {
+		return fmt.Errorf("usage: %v validate [--password=pass] [--rpc] [--rpcport=Port] [--grpcport=Port] [--tlscert=CertFile] [--tlskey=KeyFile] [--rpctoken=BearerToken] [--corsorigins=Origin1,Origin2] [--callworkers=NumCalls] [--callqueue=NumCalls] [--callsteps=NumSteps] [--calltime=NumSeconds] [--blocktime=NumSeconds] %v [--fallbackethurls=URL1,URL2] [--logrange=NumBlocks] [--inboxbisection=NumSegments] [--messagesbisection=NumSegments] [--executionbisection=NumSegments] [--challengepoll=NumBlocks] [--challengeretries=NumRetries] [--maxstakes=NumStakes] [--maxcapital=AmountInWei] [--maxchallenges=NumChallenges] [--challengewindow=NumBlocks] [--requireconfidence] [--reorgdepth=NumBlocks] [--unanimous=coordinator|follower] [--assertkeys=Address1,Address2] [--coordinatorkey=Address] [--unanimouspeers=Host1:Port,Host2:Port] [--unanimousport=Port] <validator_folder> <ethURL> [rollup_address]", execName, GasPriceUsage)
 	}
 
 	common.SetDurationPerBlock(time.Duration(*blocktime) * time.Second)
 
+	stakingPolicy := rollup.DefaultStakingPolicy().
+		WithMaxStakes(*maxStakes).
+		WithMaxOpenChallenges(*maxChallenges).
+		WithMinChallengeWindow(common.NewTimeBlocksInt(*challengeWindow)).
+		WithRequireConfidence(*requireConfidence)
+	if *maxCapital != "" {
+		capital, ok := new(big.Int).SetString(*maxCapital, 10)
+		if !ok {
+			return fmt.Errorf("invalid maxcapital %v", *maxCapital)
+		}
+		stakingPolicy = stakingPolicy.WithMaxCapital(capital)
+	}
+	if err := stakingPolicy.Validate(); err != nil {
+		return err
+	}
+	if *reorgDepth <= 0 {
+		return fmt.Errorf("reorgdepth must be positive, got %v", *reorgDepth)
+	}
+	fetchConfig := ethbridge.DefaultFetchConfig()
+	fetchConfig.MaxBlockRange = *logRange
+	if err := fetchConfig.Validate(); err != nil {
+		return err
+	}
+
 	validatorFolder := validateCmd.Arg(0)
 	ethURL := validateCmd.Arg(1)
-	addressString := validateCmd.Arg(2)
-	address := common.HexToAddress(addressString)
+	deployment, err := ReadDeploymentRecord(validatorFolder)
+	if err != nil {
+		return err
+	}
+	var address common.Address
+	switch {
+	case validateCmd.NArg() == 3:
+		address = common.HexToAddress(validateCmd.Arg(2))
+		if deployment != nil && common.HexToAddress(deployment.RollupAddress) != address {
+			return fmt.Errorf("%v was deployed for rollup %v, not %v", validatorFolder, deployment.RollupAddress, address)
+		}
+	case deployment != nil:
+		address = common.HexToAddress(deployment.RollupAddress)
+	default:
+		return fmt.Errorf("%v has no deployment record so the rollup address is required", validatorFolder)
+	}
+
+	rpcConfig := rollupvalidator.RPCConfig{
+		JSONPort:    *rpcPort,
+		GRPCPort:    *grpcPort,
+		TLSCertFile: *tlsCert,
+		TLSKeyFile:  *tlsKey,
+		AuthToken:   *rpcToken,
+		CORSOrigins: strings.Split(*corsOrigins, ","),
+		IndexPath:   filepath.Join(validatorFolder, "tx_index_db"),
+		Calls: rollupmanager.CallPoolConfig{
+			Workers:    *callWorkers,
+			QueueDepth: *callQueue,
+			MaxSteps:   *callSteps,
+			MaxTime:    time.Duration(*callTime) * time.Second,
+		},
+	}
+	if err := rpcConfig.Validate(); err != nil {
+		return err
+	}
+
+	var unanimousConfig coordinator.Config
+	if *unanimousMode != "" {
+		if *unanimousMode != "coordinator" && *unanimousMode != "follower" {
+			return fmt.Errorf("unanimous mode must be coordinator or follower, not %v", *unanimousMode)
+		}
+		var keys []common.Address
+		for _, key := range strings.Split(*assertKeys, ",") {
+			if key != "" {
+				keys = append(keys, common.HexToAddress(key))
+			}
+		}
+		unanimousConfig = coordinator.DefaultConfig(keys, common.HexToAddress(*coordinatorKey))
+		if err := unanimousConfig.Validate(); err != nil {
+			return err
+		}
+	}
 
-	auth, err := GetKeystore(validatorFolder, passphrase, validateCmd)
+	ks, account, err := unlockKeystore(validatorFolder, passphrase, validateCmd)
+	if err != nil {
+		return err
+	}
+	auth, err := bind.NewKeyStoreTransactor(ks, account)
 	if err != nil {
 		return err
 	}
 
 	// Rollup creation
-	gasPriceAsFloat := 1e9 * (*gasPrice)
-	if gasPriceAsFloat < math.MaxInt64 {
-		auth.GasPrice = big.NewInt(int64(gasPriceAsFloat))
-	}
 	ethclint, err := ethclient.Dial(ethURL)
 	if err != nil {
 		return err
 	}
 	client := ethbridge.NewEthAuthClient(ethclint, auth)
+	gasPriceOracle, err := gasPriceFlags.Oracle(ethclint)
+	if err != nil {
+		return err
+	}
+	client.SetGasPriceOracle(gasPriceOracle)
+	client.SetFetchConfig(fetchConfig)
+	for _, url := range strings.Split(*fallbackURLs, ",") {
+		if url == "" {
+			continue
+		}
+		fallback, err := ethclient.Dial(url)
+		if err != nil {
+			return err
+		}
+		client.AddFallbackClients(fallback)
+	}
 
 	if err := arbbridge.WaitForNonZeroBalance(context.Background(), client, common.NewAddressFromEth(auth.From)); err != nil {
 		return err
 	}
 
+	if deployment != nil {
+		watcher, err := client.NewRollupWatcher(address)
+		if err != nil {
+			return err
+		}
+		if err := deployment.Check(context.Background(), watcher); err != nil {
+			return err
+		}
+	}
+
 	rollupActor, err := client.NewRollup(address)
 	if err != nil {
 		return err
 	}
 
 	validatorListener := rollup.NewValidatorChainListener(context.Background(), address, rollupActor)
+	strategy := defaultStrategy.
+		WithInboxTopBisectionCount(*inboxTopBisection).
+		WithMessagesBisectionCount(*messagesBisection).
+		WithExecutionBisectionCount(uint32(*executionBisection)).
+		WithPollInterval(common.NewTimeBlocksInt(*challengePoll)).
+		WithRetries(*challengeRetries, defaultStrategy.RetryBackoff)
+	if err := validatorListener.SetChallengeStrategy(strategy); err != nil {
+		return err
+	}
+	validatorListener.SetStakingPolicy(stakingPolicy)
 	err = validatorListener.AddStaker(client)
 	if err != nil {
 		return err
@@ -153,7 +302,7 @@ func ValidateRollupChain(execName string, managerCreationFunc func(rollupAddress
 	contractFile := filepath.Join(validatorFolder, "contract.ao")
 	dbPath := filepath.Join(validatorFolder, "checkpoint_db")
 
-	manager, err := managerCreationFunc(address, client, contractFile, dbPath)
+	manager, err := managerCreationFunc(address, client, contractFile, dbPath, big.NewInt(*reorgDepth))
 
 	if err != nil {
 		return err
@@ -161,8 +310,30 @@ func ValidateRollupChain(execName string, managerCreationFunc func(rollupAddress
 	manager.AddListener(&rollup.AnnouncerListener{})
 	manager.AddListener(validatorListener)
 
+	if *unanimousMode != "" {
+		signer := coordinator.NewKeystoreSigner(ks, account)
+		var peers []string
+		if *unanimousPeers != "" {
+			peers = strings.Split(*unanimousPeers, ",")
+		}
+		journalPath := filepath.Join(validatorFolder, "unanimous_db")
+		follower, err := startUnanimous(*unanimousMode, manager, signer, unanimousConfig, journalPath, peers, *unanimousPort, *tlsCert, *tlsKey)
+		if err != nil {
+			return err
+		}
+		if *rpcEnable {
+			rpcConfig.UnanimousResults = follower.Results()
+		} else {
+			go func() {
+				for result := range follower.Results() {
+					log.Println("Unanimous assertion", result.ProposalResults.SequenceNum, "is final with", result.ProposalResults.NewLogCount, "logs")
+				}
+			}()
+		}
+	}
+
 	if *rpcEnable {
-		if err := rollupvalidator.LaunchRPC(manager, "1235"); err != nil {
+		if err := rollupvalidator.LaunchRPC(manager, rpcConfig); err != nil {
 			log.Fatal(err)
 		}
 	} else {
@@ -171,3 +342,61 @@ func ValidateRollupChain(execName string, managerCreationFunc func(rollupAddress
 	}
 	return nil
 }
+
+// startUnanimous runs this validator as the coordinator or a follower of a
+// unanimous validator set alongside the rollup validator. The caller must
+// read the returned follower's results.
+func startUnanimous(
+	mode string,
+	manager *rollupmanager.Manager,
+	signer coordinator.Signer,
+	config coordinator.Config,
+	journalPath string,
+	peers []string,
+	port string,
+	tlsCert string,
+	tlsKey string,
+) (*coordinator.Follower, error) {
+	journal, err := coordinator.OpenJournal(journalPath)
+	if err != nil {
+		return nil, err
+	}
+	var follower *coordinator.Follower
+	if mode == "coordinator" {
+		dialOpt := grpc.WithInsecure()
+		if tlsCert != "" {
+			creds, err := credentials.NewClientTLSFromFile(tlsCert, "")
+			if err != nil {
+				return nil, err
+			}
+			dialOpt = grpc.WithTransportCredentials(creds)
+		}
+		clients, err := coordinator.DialFollowers(peers, dialOpt)
+		if err != nil {
+			return nil, err
+		}
+		coord, err := coordinator.NewCoordinator(manager, manager.RollupAddress, signer, config, journal, clients)
+		if err != nil {
+			return nil, err
+		}
+		go coord.Run(context.Background())
+		follower = coord.Follower
+	} else {
+		follower, err = coordinator.NewFollower(manager, manager.RollupAddress, signer, config, journal)
+		if err != nil {
+			return nil, err
+		}
+		var opts []grpc.ServerOption
+		if tlsCert != "" && tlsKey != "" {
+			creds, err := credentials.NewServerTLSFromFile(tlsCert, tlsKey)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, grpc.Creds(creds))
+		}
+		go func() {
+			log.Fatal(coordinator.ServeFollower(follower, port, opts...))
+		}()
+	}
+	return follower, nil
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdhelper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+const deploymentFile = "deployment.json"
+
+// ChainConfig holds every parameter of a new rollup chain. Big values are
+// decimal strings so that they survive being edited by hand.
+type ChainConfig struct {
+	StakeRequirement        string `json:"stakeRequirement"` // in Wei
+	GracePeriodBlocks       uint64 `json:"gracePeriodBlocks"`
+	MaxExecutionSteps       uint64 `json:"maxExecutionSteps"`
+	MaxTimeBoundsWidth      uint64 `json:"maxTimeBoundsWidth"`      // in blocks
+	ArbGasSpeedLimitPerTick uint64 `json:"arbGasSpeedLimitPerTick"` // in ArbGas per tick
+	Owner                   string `json:"owner"`
+}
+
+func NewChainConfig(params valprotocol.ChainParams, owner common.Address) ChainConfig {
+	return ChainConfig{
+		StakeRequirement:        params.StakeRequirement.String(),
+		GracePeriodBlocks:       new(big.Int).Div(params.GracePeriod.Val, big.NewInt(common.TicksPerBlock)).Uint64(),
+		MaxExecutionSteps:       params.MaxExecutionSteps,
+		MaxTimeBoundsWidth:      params.MaxTimeBoundsWidth,
+		ArbGasSpeedLimitPerTick: params.ArbGasSpeedLimitPerTick,
+		Owner:                   owner.Hex(),
+	}
+}
+
+// ChainConfigUsage documents the flags added by AddChainConfigFlags
+const ChainConfigUsage = "[--config=ChainConfigFile] [--stake=AmountInWei] [--graceperiod=NumBlocks] [--maxsteps=NumSteps] [--timeboundswidth=NumBlocks] [--speedlimit=ArbGasPerTick] [--owner=Address]"
+
+// ChainConfigFlags are the flags which pick the parameters of a new chain
+type ChainConfigFlags struct {
+	cmd             *flag.FlagSet
+	defaults        valprotocol.ChainParams
+	file            *string
+	stake           *string
+	gracePeriod     *uint64
+	maxSteps        *uint64
+	timeBoundsWidth *uint64
+	speedLimit      *uint64
+	owner           *string
+}
+
+func AddChainConfigFlags(cmd *flag.FlagSet, defaults valprotocol.ChainParams) ChainConfigFlags {
+	defaultConfig := NewChainConfig(defaults, common.Address{})
+	return ChainConfigFlags{
+		cmd:             cmd,
+		defaults:        defaults,
+		file:            cmd.String("config", "", "config=ChainConfigFile"),
+		stake:           cmd.String("stake", defaultConfig.StakeRequirement, "stake=AmountInWei"),
+		gracePeriod:     cmd.Uint64("graceperiod", defaultConfig.GracePeriodBlocks, "graceperiod=NumBlocks"),
+		maxSteps:        cmd.Uint64("maxsteps", defaultConfig.MaxExecutionSteps, "maxsteps=NumSteps"),
+		timeBoundsWidth: cmd.Uint64("timeboundswidth", defaultConfig.MaxTimeBoundsWidth, "timeboundswidth=NumBlocks"),
+		speedLimit:      cmd.Uint64("speedlimit", defaultConfig.ArbGasSpeedLimitPerTick, "speedlimit=ArbGasPerTick"),
+		owner:           cmd.String("owner", "", "owner=Address (defaults to the deployer)"),
+	}
+}
+
+// Config merges the parsed flags over the config file over the defaults.
+// The chain is owned by the deployer unless another owner is given.
+func (f ChainConfigFlags) Config(deployer common.Address) (ChainConfig, error) {
+	config := NewChainConfig(f.defaults, deployer)
+	if *f.file != "" {
+		var err error
+		config, err = ReadChainConfig(*f.file, config)
+		if err != nil {
+			return ChainConfig{}, err
+		}
+	}
+	f.cmd.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "stake":
+			config.StakeRequirement = *f.stake
+		case "graceperiod":
+			config.GracePeriodBlocks = *f.gracePeriod
+		case "maxsteps":
+			config.MaxExecutionSteps = *f.maxSteps
+		case "timeboundswidth":
+			config.MaxTimeBoundsWidth = *f.timeBoundsWidth
+		case "speedlimit":
+			config.ArbGasSpeedLimitPerTick = *f.speedLimit
+		case "owner":
+			config.Owner = *f.owner
+		}
+	})
+	return config, nil
+}
+
+// ReadChainConfig loads a chain config file. Fields missing from the file
+// keep their value in config.
+func ReadChainConfig(path string, config ChainConfig) (ChainConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ChainConfig{}, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ChainConfig{}, fmt.Errorf("invalid chain config %v: %v", path, err)
+	}
+	return config, nil
+}
+
+// Params converts the config to chain parameters and validates them
+func (c ChainConfig) Params() (valprotocol.ChainParams, common.Address, error) {
+	stake, ok := new(big.Int).SetString(c.StakeRequirement, 10)
+	if !ok {
+		return valprotocol.ChainParams{}, common.Address{}, fmt.Errorf("invalid stake requirement %v", c.StakeRequirement)
+	}
+	if !ethcommon.IsHexAddress(c.Owner) {
+		return valprotocol.ChainParams{}, common.Address{}, fmt.Errorf("invalid owner address %q", c.Owner)
+	}
+	owner := common.HexToAddress(c.Owner)
+	if owner == (common.Address{}) {
+		return valprotocol.ChainParams{}, common.Address{}, errors.New("chain can't be owned by the zero address")
+	}
+	gracePeriod := new(big.Int).SetUint64(c.GracePeriodBlocks)
+	params := valprotocol.ChainParams{
+		StakeRequirement:        stake,
+		GracePeriod:             common.TicksFromBlockNum(common.NewTimeBlocks(gracePeriod)),
+		MaxExecutionSteps:       c.MaxExecutionSteps,
+		MaxTimeBoundsWidth:      c.MaxTimeBoundsWidth,
+		ArbGasSpeedLimitPerTick: c.ArbGasSpeedLimitPerTick,
+	}
+	if err := params.Validate(); err != nil {
+		return valprotocol.ChainParams{}, common.Address{}, err
+	}
+	return params, owner, nil
+}
+
+// DeploymentRecord describes a rollup chain created by the create command.
+// It's written to the validator folder so that later commands can check
+// they're talking to the chain that was deployed.
+type DeploymentRecord struct {
+	RollupAddress     string      `json:"rollupAddress"`
+	FactoryAddress    string      `json:"factoryAddress"`
+	MachineHash       string      `json:"machineHash"`
+	CreationBlock     string      `json:"creationBlock"`
+	CreationBlockHash string      `json:"creationBlockHash"`
+	Chain             ChainConfig `json:"chain"`
+}
+
+func NewDeploymentRecord(
+	rollupAddress common.Address,
+	factoryAddress common.Address,
+	machineHash common.Hash,
+	creation *common.BlockId,
+	params valprotocol.ChainParams,
+	owner common.Address,
+) DeploymentRecord {
+	return DeploymentRecord{
+		RollupAddress:     rollupAddress.Hex(),
+		FactoryAddress:    factoryAddress.Hex(),
+		MachineHash:       machineHash.String(),
+		CreationBlock:     creation.Height.String(),
+		CreationBlockHash: creation.HeaderHash.String(),
+		Chain:             NewChainConfig(params, owner),
+	}
+}
+
+func WriteDeploymentRecord(validatorFolder string, record DeploymentRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(validatorFolder, deploymentFile), data, 0644)
+}
+
+// ReadDeploymentRecord returns the validator folder's deployment record, or
+// nil if the chain wasn't created from this folder
+func ReadDeploymentRecord(validatorFolder string) (*DeploymentRecord, error) {
+	path := filepath.Join(validatorFolder, deploymentFile)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record := &DeploymentRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("invalid deployment record %v: %v", path, err)
+	}
+	return record, nil
+}
+
+// Check verifies that the rollup on chain matches the record
+func (r *DeploymentRecord) Check(ctx context.Context, watcher arbbridge.ArbRollupWatcher) error {
+	expectedParams, _, err := r.Chain.Params()
+	if err != nil {
+		return err
+	}
+	creation, machineHash, err := watcher.GetCreationInfo(ctx)
+	if err != nil {
+		return err
+	}
+	if machineHash.String() != r.MachineHash {
+		return fmt.Errorf("rollup was created with machine %v but the deployment record has %v", machineHash, r.MachineHash)
+	}
+	if creation.HeaderHash.String() != r.CreationBlockHash {
+		return fmt.Errorf("rollup was created in block %v but the deployment record has %v", creation, r.CreationBlockHash)
+	}
+	params, err := watcher.GetParams(ctx)
+	if err != nil {
+		return err
+	}
+	if params.StakeRequirement.Cmp(expectedParams.StakeRequirement) != 0 ||
+		params.GracePeriod.Cmp(expectedParams.GracePeriod) != 0 ||
+		params.MaxExecutionSteps != expectedParams.MaxExecutionSteps ||
+		params.MaxTimeBoundsWidth != expectedParams.MaxTimeBoundsWidth ||
+		params.ArbGasSpeedLimitPerTick != expectedParams.ArbGasSpeedLimitPerTick {
+		return fmt.Errorf("rollup parameters %+v don't match the deployment record %+v", params, expectedParams)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdhelper
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+func testChainParams() valprotocol.ChainParams {
+	return valprotocol.ChainParams{
+		StakeRequirement:        big.NewInt(1000),
+		GracePeriod:             common.TicksFromBlockNum(common.NewTimeBlocks(big.NewInt(30))),
+		MaxExecutionSteps:       10000,
+		MaxTimeBoundsWidth:      20,
+		ArbGasSpeedLimitPerTick: 80000,
+	}
+}
+
+func writeChainConfig(t *testing.T, dir string, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parseChainConfig(t *testing.T, deployer common.Address, args ...string) (ChainConfig, error) {
+	t.Helper()
+	cmd := flag.NewFlagSet("create", flag.ContinueOnError)
+	flags := AddChainConfigFlags(cmd, testChainParams())
+	if err := cmd.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return flags.Config(deployer)
+}
+
+func TestChainConfigMerge(t *testing.T) {
+	deployer := common.Address{1}
+	owner := common.Address{2}
+	dir, err := ioutil.TempDir("", "chainconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config, err := parseChainConfig(t, deployer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != NewChainConfig(testChainParams(), deployer) {
+		t.Errorf("expected the defaults owned by the deployer but got %+v", config)
+	}
+
+	// The file overrides the fields it sets and keeps the other defaults
+	path := writeChainConfig(t, dir, "config.json", `{"stakeRequirement": "2000", "maxExecutionSteps": 500, "owner": "`+owner.Hex()+`"}`)
+	config, err = parseChainConfig(t, deployer, "--config="+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewChainConfig(testChainParams(), owner)
+	expected.StakeRequirement = "2000"
+	expected.MaxExecutionSteps = 500
+	if config != expected {
+		t.Errorf("expected %+v but got %+v", expected, config)
+	}
+
+	// Flags override both the file and the defaults
+	config, err = parseChainConfig(t, deployer, "--config="+path, "--stake=3000", "--graceperiod=10", "--owner="+deployer.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected.StakeRequirement = "3000"
+	expected.GracePeriodBlocks = 10
+	expected.Owner = deployer.Hex()
+	if config != expected {
+		t.Errorf("expected %+v but got %+v", expected, config)
+	}
+
+	if _, err := parseChainConfig(t, deployer, "--config="+writeChainConfig(t, dir, "invalid.json", "{")); err == nil {
+		t.Error("read an invalid config file")
+	}
+	if _, err := parseChainConfig(t, deployer, "--config="+path+".missing"); err == nil {
+		t.Error("read a missing config file")
+	}
+}
+
+func TestChainConfigParams(t *testing.T) {
+	owner := common.Address{2}
+	config := NewChainConfig(testChainParams(), owner)
+	params, parsedOwner, err := config.Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if NewChainConfig(params, parsedOwner) != config {
+		t.Errorf("config didn't round trip: %+v owned by %v", params, parsedOwner)
+	}
+
+	for _, badOwner := range []string{"", "0x1234", common.Address{}.Hex()} {
+		config := NewChainConfig(testChainParams(), owner)
+		config.Owner = badOwner
+		if _, _, err := config.Params(); err == nil {
+			t.Errorf("accepted owner %q", badOwner)
+		}
+	}
+
+	config.StakeRequirement = "lots"
+	if _, _, err := config.Params(); err == nil {
+		t.Error("accepted an invalid stake requirement")
+	}
+}
+
+// fakeRollup reports the creation info and parameters of a deployed chain
+type fakeRollup struct {
+	arbbridge.ArbRollupWatcher
+	creation    *common.BlockId
+	machineHash common.Hash
+	params      valprotocol.ChainParams
+}
+
+func (r fakeRollup) GetCreationInfo(ctx context.Context) (*common.BlockId, common.Hash, error) {
+	return r.creation, r.machineHash, nil
+}
+
+func (r fakeRollup) GetParams(ctx context.Context) (valprotocol.ChainParams, error) {
+	return r.params, nil
+}
+
+func TestDeploymentRecordCheck(t *testing.T) {
+	ctx := context.Background()
+	creation := &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(7)), HeaderHash: common.Hash{7}}
+	deployed := fakeRollup{creation: creation, machineHash: common.Hash{1}, params: testChainParams()}
+	record := NewDeploymentRecord(common.Address{3}, common.Address{4}, common.Hash{1}, creation, testChainParams(), common.Address{2})
+
+	if err := record.Check(ctx, deployed); err != nil {
+		t.Fatal("record doesn't match its own deployment:", err)
+	}
+
+	otherMachine := deployed
+	otherMachine.machineHash = common.Hash{2}
+	otherBlock := deployed
+	otherBlock.creation = &common.BlockId{Height: creation.Height, HeaderHash: common.Hash{8}}
+	otherStake := deployed
+	otherStake.params = testChainParams()
+	otherStake.params.StakeRequirement = big.NewInt(1)
+	otherSteps := deployed
+	otherSteps.params = testChainParams()
+	otherSteps.params.MaxExecutionSteps++
+	otherSpeed := deployed
+	otherSpeed.params = testChainParams()
+	otherSpeed.params.ArbGasSpeedLimitPerTick++
+
+	mismatches := []struct {
+		name    string
+		rollup  fakeRollup
+		message string
+	}{
+		{"machine", otherMachine, "machine"},
+		{"creation block", otherBlock, "block"},
+		{"stake", otherStake, "parameters"},
+		{"max steps", otherSteps, "parameters"},
+		{"speed limit", otherSpeed, "parameters"},
+	}
+	for _, mismatch := range mismatches {
+		err := record.Check(ctx, mismatch.rollup)
+		if err == nil {
+			t.Errorf("didn't notice a different %v", mismatch.name)
+		} else if !strings.Contains(err.Error(), mismatch.message) {
+			t.Errorf("unexpected error for a different %v: %v", mismatch.name, err)
+		}
+	}
+
+	corrupt := record
+	corrupt.Chain.StakeRequirement = ""
+	if err := corrupt.Check(ctx, deployed); err == nil {
+		t.Error("checked against a corrupt record")
+	}
+}
+
+func TestDeploymentRecordFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deployment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	record, err := ReadDeploymentRecord(dir)
+	if err != nil || record != nil {
+		t.Fatal("expected no record in an empty folder", record, err)
+	}
+	creation := &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(7)), HeaderHash: common.Hash{7}}
+	written := NewDeploymentRecord(common.Address{3}, common.Address{4}, common.Hash{1}, creation, testChainParams(), common.Address{2})
+	if err := WriteDeploymentRecord(dir, written); err != nil {
+		t.Fatal(err)
+	}
+	record, err = ReadDeploymentRecord(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || *record != written {
+		t.Errorf("expected %+v but read %+v", written, record)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmdhelper
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/ethbridge"
+)
+
+// GasPriceUsage documents the flags added by AddGasPriceFlags
+const GasPriceUsage = "[--gasprice=FloatInGwei] [--gasstrategy=fixed|suggested|urgent] [--maxgasprice=FloatInGwei]"
+
+// GasPriceFlags are the flags which pick the gas price of L1 transactions
+type GasPriceFlags struct {
+	price    *float64
+	strategy *string
+	maxPrice *float64
+}
+
+func AddGasPriceFlags(cmd *flag.FlagSet) GasPriceFlags {
+	return GasPriceFlags{
+		price:    cmd.Float64("gasprice", 4.5, "gasprice=FloatInGwei"),
+		strategy: cmd.String("gasstrategy", "fixed", "gasstrategy=fixed|suggested|urgent"),
+		maxPrice: cmd.Float64("maxgasprice", 0, "maxgasprice=FloatInGwei (0 for no limit)"),
+	}
+}
+
+// Oracle builds the gas price oracle selected by the flags. The fixed
+// strategy always pays --gasprice, suggested pays the L1 node's suggestion
+// and urgent scales the node's suggestion by each transaction's priority
+// and deadline.
+func (f GasPriceFlags) Oracle(client *ethclient.Client) (ethbridge.GasPriceOracle, error) {
+	var oracle ethbridge.GasPriceOracle
+	switch *f.strategy {
+	case "fixed":
+		price, err := gweiToWei("gasprice", *f.price)
+		if err != nil {
+			return nil, err
+		}
+		oracle = ethbridge.NewFixedGasPrice(price)
+	case "suggested":
+		oracle = ethbridge.NewSuggestedGasPrice(client)
+	case "urgent":
+		oracle = ethbridge.NewUrgentGasPrice(
+			ethbridge.NewSuggestedGasPrice(client),
+			ethbridge.DefaultUrgencyConfig(),
+		)
+	default:
+		return nil, fmt.Errorf("gasstrategy must be fixed, suggested or urgent, not %v", *f.strategy)
+	}
+	if *f.maxPrice != 0 {
+		maxPrice, err := gweiToWei("maxgasprice", *f.maxPrice)
+		if err != nil {
+			return nil, err
+		}
+		oracle = ethbridge.NewCappedGasPrice(oracle, maxPrice)
+	}
+	return oracle, nil
+}
+
+func gweiToWei(name string, gwei float64) (*big.Int, error) {
+	wei := 1e9 * gwei
+	if wei < 0 || wei >= math.MaxInt64 {
+		return nil, fmt.Errorf("invalid %v %v", name, gwei)
+	}
+	return big.NewInt(int64(wei)), nil
+}
@@ -0,0 +1,229 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+)
+
+// Coordinator proposes assertions to every follower and commits them once
+// all assert keys have signed. The rollup has no way to accept a co-signed
+// assertion on chain, so agreement gives the signers instant finality among
+// themselves while the rollup validator keeps asserting the same execution
+// through the disputable path. Any disagreement or unreachable follower
+// abandons the proposal and every party restarts from the state after the
+// last final assertion, or from the rollup's latest valid node once it has
+// read past that.
+//
+// Final assertions and their signatures are kept in each party's journal
+// and delivered on Results. Passing Results to the validator's RPC server
+// (rollupvalidator.RPCConfig.UnanimousResults) reports their transactions
+// with UNANIMOUS finality until they're asserted on chain. The AVM's
+// EventChainCheckpointer isn't used for the journal since it stores Go
+// machines while validators run the C++ machine.
+type Coordinator struct {
+	*Follower
+	followers []UnanimousFollowerClient
+}
+
+func NewCoordinator(
+	chain ChainSource,
+	vmID common.Address,
+	signer Signer,
+	config Config,
+	journal *Journal,
+	followers []UnanimousFollowerClient,
+) (*Coordinator, error) {
+	local, err := NewFollower(chain, vmID, signer, config, journal)
+	if err != nil {
+		return nil, err
+	}
+	if signer.Address() != config.CoordinatorKey {
+		return nil, fmt.Errorf("coordinator must sign with %v, not %v", config.CoordinatorKey, signer.Address())
+	}
+	if len(followers)+1 != len(config.AssertKeys) {
+		return nil, fmt.Errorf("unanimous mode with %v assert keys needs %v followers but got %v", len(config.AssertKeys), len(config.AssertKeys)-1, len(followers))
+	}
+	return &Coordinator{
+		Follower:  local,
+		followers: followers,
+	}, nil
+}
+
+// DialFollowers connects to the followers at the given addresses
+func DialFollowers(addrs []string, opts ...grpc.DialOption) ([]UnanimousFollowerClient, error) {
+	clients := make([]UnanimousFollowerClient, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, err := grpc.Dial(addr, opts...)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, NewUnanimousFollowerClient(conn))
+	}
+	return clients, nil
+}
+
+// Run proposes a new assertion every round interval until ctx is cancelled
+func (c *Coordinator) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.RoundInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.runRound(ctx); err != nil {
+				log.Println("Unanimous assertion failed, falling back to the rollup:", err)
+			}
+		}
+	}
+}
+
+// nextProposal builds a proposal which reads every message delivered since
+// the agreed state. It returns nil if there are no new messages.
+func (c *Coordinator) nextProposal() (*UnanimousProposalBuf, error) {
+	c.Lock()
+	defer c.Unlock()
+	if c.state == nil {
+		c.rebase()
+		if c.state == nil {
+			return nil, errNoFinalState
+		}
+	}
+	segment, err := c.chain.InboxSegment(c.state.inboxCount, c.config.MaxMessages)
+	if err != nil {
+		return nil, err
+	}
+	if segment.Count == 0 {
+		return nil, nil
+	}
+	sequenceNum := c.state.sequenceNum
+	if c.lastSigned > sequenceNum {
+		sequenceNum = c.lastSigned
+	}
+	height := c.chain.CurrentBlockId().Height
+	prop := &UnanimousProposalBuf{
+		BeforeHash:       c.state.machine.Hash().MarshalToBuf(),
+		BeforeInbox:      c.state.inboxTop.MarshalToBuf(),
+		BeforeInboxCount: common.MarshalBigInt(c.state.inboxCount),
+		SequenceNum:      sequenceNum + 1,
+		TimeBounds:       (&protocol.TimeBoundsBlocks{Start: height, End: height}).MarshalToBuf(),
+		MessageCount:     segment.Count,
+		MaxSteps:         c.config.MaxSteps,
+	}
+	prop.CoordinatorSignature, err = c.signer.Sign(proposalHash(c.vmID, prop))
+	if err != nil {
+		return nil, err
+	}
+	return prop, nil
+}
+
+func (c *Coordinator) runRound(ctx context.Context) error {
+	prop, err := c.nextProposal()
+	if err != nil || prop == nil {
+		return err
+	}
+	own, err := c.Propose(ctx, prop)
+	if err != nil {
+		return err
+	}
+	if !own.Accepted {
+		return fmt.Errorf("coordinator rejected its own proposal: %v", own.Reason)
+	}
+	assertionHash := own.AssertionHash.Unmarshal()
+
+	type response struct {
+		*UnanimousSignatureBuf
+		error
+	}
+	responses := make(chan response, len(c.followers))
+	for _, follower := range c.followers {
+		go func(follower UnanimousFollowerClient) {
+			reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+			defer cancel()
+			sig, err := follower.Propose(reqCtx, prop)
+			responses <- response{sig, err}
+		}(follower)
+	}
+	sigs := [][]byte{own.Signature}
+	var failure error
+	for range c.followers {
+		resp := <-responses
+		switch {
+		case failure != nil:
+		case resp.error != nil:
+			failure = resp.error
+		case !resp.Accepted:
+			failure = fmt.Errorf("follower rejected proposal: %v", resp.Reason)
+		case resp.AssertionHash == nil:
+			failure = errors.New("follower accepted without an assertion hash")
+		case resp.AssertionHash.Unmarshal() != assertionHash:
+			failure = fmt.Errorf("follower computed assertion %v instead of %v", resp.AssertionHash.Unmarshal(), assertionHash)
+		default:
+			sigs = append(sigs, resp.Signature)
+		}
+	}
+	if failure == nil {
+		sigs, failure = orderSignatures(assertionHash, sigs, c.config.AssertKeys)
+	}
+
+	commit := &UnanimousCommitBuf{SequenceNum: prop.SequenceNum}
+	if failure == nil {
+		commit.Accepted = true
+		commit.AssertionHash = assertionHash.MarshalToBuf()
+		commit.Signatures = sigs
+	}
+	commit.CoordinatorSignature, err = c.signer.Sign(commitHash(c.vmID, commit))
+	if err != nil {
+		return err
+	}
+	if _, err := c.Commit(ctx, commit); err != nil {
+		return err
+	}
+	for _, follower := range c.followers {
+		reqCtx, cancel := context.WithTimeout(ctx, c.config.RequestTimeout)
+		if _, err := follower.Commit(reqCtx, commit); err != nil {
+			// The follower will notice it's out of sync when it sees the
+			// next proposal and fall back to the rollup itself
+			log.Println("Failed to send unanimous commit to follower:", err)
+		}
+		cancel()
+	}
+	return failure
+}
+
+// ServeFollower serves the follower's gRPC interface on the given port. The
+// follower ignores any proposal or commit not signed by the coordinator key.
+func ServeFollower(follower *Follower, port string, opts ...grpc.ServerOption) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer(opts...)
+	RegisterUnanimousFollowerServer(server, follower)
+	return server.Serve(lis)
+}
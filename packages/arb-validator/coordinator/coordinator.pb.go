@@ -0,0 +1,454 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: coordinator.proto
+
+package coordinator
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	protocol "github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type UnanimousProposalBuf struct {
+	BeforeHash           *common.HashBuf               `protobuf:"bytes,1,opt,name=beforeHash,proto3" json:"beforeHash,omitempty"`
+	BeforeInbox          *common.HashBuf               `protobuf:"bytes,2,opt,name=beforeInbox,proto3" json:"beforeInbox,omitempty"`
+	BeforeInboxCount     *common.BigIntegerBuf         `protobuf:"bytes,3,opt,name=beforeInboxCount,proto3" json:"beforeInboxCount,omitempty"`
+	SequenceNum          uint64                        `protobuf:"varint,4,opt,name=sequenceNum,proto3" json:"sequenceNum,omitempty"`
+	TimeBounds           *protocol.TimeBoundsBlocksBuf `protobuf:"bytes,5,opt,name=timeBounds,proto3" json:"timeBounds,omitempty"`
+	MessageCount         uint64                        `protobuf:"varint,6,opt,name=messageCount,proto3" json:"messageCount,omitempty"`
+	MaxSteps             uint64                        `protobuf:"varint,7,opt,name=maxSteps,proto3" json:"maxSteps,omitempty"`
+	CoordinatorSignature []byte                        `protobuf:"bytes,8,opt,name=coordinatorSignature,proto3" json:"coordinatorSignature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_unrecognized     []byte                        `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
+}
+
+func (m *UnanimousProposalBuf) Reset()         { *m = UnanimousProposalBuf{} }
+func (m *UnanimousProposalBuf) String() string { return proto.CompactTextString(m) }
+func (*UnanimousProposalBuf) ProtoMessage()    {}
+func (*UnanimousProposalBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99e779eb11ceee19, []int{0}
+}
+
+func (m *UnanimousProposalBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnanimousProposalBuf.Unmarshal(m, b)
+}
+func (m *UnanimousProposalBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnanimousProposalBuf.Marshal(b, m, deterministic)
+}
+func (m *UnanimousProposalBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnanimousProposalBuf.Merge(m, src)
+}
+func (m *UnanimousProposalBuf) XXX_Size() int {
+	return xxx_messageInfo_UnanimousProposalBuf.Size(m)
+}
+func (m *UnanimousProposalBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnanimousProposalBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnanimousProposalBuf proto.InternalMessageInfo
+
+func (m *UnanimousProposalBuf) GetBeforeHash() *common.HashBuf {
+	if m != nil {
+		return m.BeforeHash
+	}
+	return nil
+}
+
+func (m *UnanimousProposalBuf) GetBeforeInbox() *common.HashBuf {
+	if m != nil {
+		return m.BeforeInbox
+	}
+	return nil
+}
+
+func (m *UnanimousProposalBuf) GetBeforeInboxCount() *common.BigIntegerBuf {
+	if m != nil {
+		return m.BeforeInboxCount
+	}
+	return nil
+}
+
+func (m *UnanimousProposalBuf) GetSequenceNum() uint64 {
+	if m != nil {
+		return m.SequenceNum
+	}
+	return 0
+}
+
+func (m *UnanimousProposalBuf) GetTimeBounds() *protocol.TimeBoundsBlocksBuf {
+	if m != nil {
+		return m.TimeBounds
+	}
+	return nil
+}
+
+func (m *UnanimousProposalBuf) GetMessageCount() uint64 {
+	if m != nil {
+		return m.MessageCount
+	}
+	return 0
+}
+
+func (m *UnanimousProposalBuf) GetMaxSteps() uint64 {
+	if m != nil {
+		return m.MaxSteps
+	}
+	return 0
+}
+
+func (m *UnanimousProposalBuf) GetCoordinatorSignature() []byte {
+	if m != nil {
+		return m.CoordinatorSignature
+	}
+	return nil
+}
+
+type UnanimousSignatureBuf struct {
+	Accepted             bool            `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Reason               string          `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	AssertionHash        *common.HashBuf `protobuf:"bytes,3,opt,name=assertionHash,proto3" json:"assertionHash,omitempty"`
+	Signature            []byte          `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *UnanimousSignatureBuf) Reset()         { *m = UnanimousSignatureBuf{} }
+func (m *UnanimousSignatureBuf) String() string { return proto.CompactTextString(m) }
+func (*UnanimousSignatureBuf) ProtoMessage()    {}
+func (*UnanimousSignatureBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99e779eb11ceee19, []int{1}
+}
+
+func (m *UnanimousSignatureBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnanimousSignatureBuf.Unmarshal(m, b)
+}
+func (m *UnanimousSignatureBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnanimousSignatureBuf.Marshal(b, m, deterministic)
+}
+func (m *UnanimousSignatureBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnanimousSignatureBuf.Merge(m, src)
+}
+func (m *UnanimousSignatureBuf) XXX_Size() int {
+	return xxx_messageInfo_UnanimousSignatureBuf.Size(m)
+}
+func (m *UnanimousSignatureBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnanimousSignatureBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnanimousSignatureBuf proto.InternalMessageInfo
+
+func (m *UnanimousSignatureBuf) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *UnanimousSignatureBuf) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *UnanimousSignatureBuf) GetAssertionHash() *common.HashBuf {
+	if m != nil {
+		return m.AssertionHash
+	}
+	return nil
+}
+
+func (m *UnanimousSignatureBuf) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type UnanimousCommitBuf struct {
+	SequenceNum uint64 `protobuf:"varint,1,opt,name=sequenceNum,proto3" json:"sequenceNum,omitempty"`
+	// If false, the proposal is abandoned and every party falls back to the
+	// latest valid node of the rollup
+	Accepted             bool            `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	AssertionHash        *common.HashBuf `protobuf:"bytes,3,opt,name=assertionHash,proto3" json:"assertionHash,omitempty"`
+	Signatures           [][]byte        `protobuf:"bytes,4,rep,name=signatures,proto3" json:"signatures,omitempty"`
+	CoordinatorSignature []byte          `protobuf:"bytes,5,opt,name=coordinatorSignature,proto3" json:"coordinatorSignature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *UnanimousCommitBuf) Reset()         { *m = UnanimousCommitBuf{} }
+func (m *UnanimousCommitBuf) String() string { return proto.CompactTextString(m) }
+func (*UnanimousCommitBuf) ProtoMessage()    {}
+func (*UnanimousCommitBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99e779eb11ceee19, []int{2}
+}
+
+func (m *UnanimousCommitBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnanimousCommitBuf.Unmarshal(m, b)
+}
+func (m *UnanimousCommitBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnanimousCommitBuf.Marshal(b, m, deterministic)
+}
+func (m *UnanimousCommitBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnanimousCommitBuf.Merge(m, src)
+}
+func (m *UnanimousCommitBuf) XXX_Size() int {
+	return xxx_messageInfo_UnanimousCommitBuf.Size(m)
+}
+func (m *UnanimousCommitBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnanimousCommitBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnanimousCommitBuf proto.InternalMessageInfo
+
+func (m *UnanimousCommitBuf) GetSequenceNum() uint64 {
+	if m != nil {
+		return m.SequenceNum
+	}
+	return 0
+}
+
+func (m *UnanimousCommitBuf) GetAccepted() bool {
+	if m != nil {
+		return m.Accepted
+	}
+	return false
+}
+
+func (m *UnanimousCommitBuf) GetAssertionHash() *common.HashBuf {
+	if m != nil {
+		return m.AssertionHash
+	}
+	return nil
+}
+
+func (m *UnanimousCommitBuf) GetSignatures() [][]byte {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+func (m *UnanimousCommitBuf) GetCoordinatorSignature() []byte {
+	if m != nil {
+		return m.CoordinatorSignature
+	}
+	return nil
+}
+
+type UnanimousCommitReplyBuf struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnanimousCommitReplyBuf) Reset()         { *m = UnanimousCommitReplyBuf{} }
+func (m *UnanimousCommitReplyBuf) String() string { return proto.CompactTextString(m) }
+func (*UnanimousCommitReplyBuf) ProtoMessage()    {}
+func (*UnanimousCommitReplyBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_99e779eb11ceee19, []int{3}
+}
+
+func (m *UnanimousCommitReplyBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnanimousCommitReplyBuf.Unmarshal(m, b)
+}
+func (m *UnanimousCommitReplyBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnanimousCommitReplyBuf.Marshal(b, m, deterministic)
+}
+func (m *UnanimousCommitReplyBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnanimousCommitReplyBuf.Merge(m, src)
+}
+func (m *UnanimousCommitReplyBuf) XXX_Size() int {
+	return xxx_messageInfo_UnanimousCommitReplyBuf.Size(m)
+}
+func (m *UnanimousCommitReplyBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnanimousCommitReplyBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnanimousCommitReplyBuf proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*UnanimousProposalBuf)(nil), "coordinator.UnanimousProposalBuf")
+	proto.RegisterType((*UnanimousSignatureBuf)(nil), "coordinator.UnanimousSignatureBuf")
+	proto.RegisterType((*UnanimousCommitBuf)(nil), "coordinator.UnanimousCommitBuf")
+	proto.RegisterType((*UnanimousCommitReplyBuf)(nil), "coordinator.UnanimousCommitReplyBuf")
+}
+
+func init() { proto.RegisterFile("coordinator.proto", fileDescriptor_99e779eb11ceee19) }
+
+var fileDescriptor_99e779eb11ceee19 = []byte{
+	// 510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0x4d, 0x72, 0xd3, 0x4c,
+	0x10, 0x2d, 0xc5, 0x8e, 0xe3, 0xb4, 0xf3, 0xd5, 0x47, 0x86, 0x84, 0x08, 0x15, 0x3f, 0xc6, 0xc5,
+	0xc2, 0x1b, 0xac, 0xc2, 0x14, 0x4b, 0x16, 0xc8, 0x05, 0x45, 0x36, 0x90, 0x52, 0x60, 0xc3, 0x6e,
+	0x24, 0x8f, 0xe4, 0xa9, 0x48, 0xd3, 0x62, 0x7e, 0x20, 0x5c, 0x83, 0x13, 0x70, 0x0a, 0x6e, 0xc3,
+	0x5d, 0x28, 0x8d, 0xe2, 0x89, 0xec, 0x88, 0x2c, 0x58, 0x49, 0xfd, 0xfa, 0x75, 0xcf, 0xeb, 0x7e,
+	0xd5, 0x70, 0x98, 0x22, 0xca, 0x25, 0x17, 0x54, 0xa3, 0x9c, 0x55, 0x12, 0x35, 0x92, 0x51, 0x0b,
+	0x0a, 0xee, 0xa6, 0x58, 0x96, 0x28, 0xc2, 0xe6, 0xd3, 0x30, 0x82, 0x13, 0xfb, 0x49, 0xb1, 0x08,
+	0xd7, 0x3f, 0x4d, 0x62, 0xf2, 0xa3, 0x07, 0x47, 0x9f, 0x04, 0x15, 0xbc, 0x44, 0xa3, 0xce, 0x24,
+	0x56, 0xa8, 0x68, 0x11, 0x99, 0x8c, 0x84, 0x00, 0x09, 0xcb, 0x50, 0xb2, 0x77, 0x54, 0xad, 0x7c,
+	0x6f, 0xec, 0x4d, 0x47, 0xf3, 0xff, 0x67, 0x57, 0x4d, 0x6b, 0x2c, 0x32, 0x59, 0xdc, 0xa2, 0x90,
+	0xe7, 0x30, 0x6a, 0xa2, 0x53, 0x91, 0xe0, 0xa5, 0xbf, 0xd3, 0x5d, 0xd1, 0xe6, 0x90, 0xd7, 0x70,
+	0xa7, 0x15, 0x2e, 0xd0, 0x08, 0xed, 0xf7, 0x6c, 0xdd, 0xf1, 0xba, 0x2e, 0xe2, 0xf9, 0xa9, 0xd0,
+	0x2c, 0x67, 0xb2, 0xae, 0xbe, 0x41, 0x27, 0x63, 0x18, 0x29, 0xf6, 0xc5, 0x30, 0x91, 0xb2, 0xf7,
+	0xa6, 0xf4, 0xfb, 0x63, 0x6f, 0xda, 0x8f, 0xdb, 0x10, 0x79, 0x05, 0xa0, 0x79, 0xc9, 0x22, 0x34,
+	0x62, 0xa9, 0xfc, 0x5d, 0xdb, 0xfe, 0xe1, 0xcc, 0xad, 0xe1, 0xa3, 0xcb, 0x45, 0x05, 0xa6, 0x17,
+	0xca, 0x8e, 0x75, 0x5d, 0x40, 0x26, 0x70, 0x50, 0x32, 0xa5, 0x68, 0xce, 0x1a, 0x7d, 0x03, 0xfb,
+	0xc2, 0x06, 0x46, 0x02, 0x18, 0x96, 0xf4, 0xf2, 0x5c, 0xb3, 0x4a, 0xf9, 0x7b, 0x36, 0xef, 0x62,
+	0x32, 0x87, 0xa3, 0x96, 0x3b, 0xe7, 0x3c, 0x17, 0x54, 0x1b, 0xc9, 0xfc, 0xe1, 0xd8, 0x9b, 0x1e,
+	0xc4, 0x9d, 0xb9, 0xc9, 0x4f, 0x0f, 0x8e, 0x9d, 0x29, 0x0e, 0xae, 0x5d, 0x09, 0x60, 0x48, 0xd3,
+	0x94, 0x55, 0x9a, 0x2d, 0xad, 0x27, 0xc3, 0xd8, 0xc5, 0xe4, 0x1e, 0x0c, 0x24, 0xa3, 0x0a, 0x85,
+	0xdd, 0xfd, 0x7e, 0x7c, 0x15, 0x91, 0x97, 0xf0, 0x1f, 0x55, 0x8a, 0x49, 0xcd, 0x51, 0x58, 0x33,
+	0x7b, 0xdd, 0xd6, 0x6c, 0xb2, 0xc8, 0x03, 0xd8, 0x57, 0x4e, 0x6d, 0xdf, 0xaa, 0xbd, 0x06, 0x26,
+	0xbf, 0x3d, 0x20, 0x4e, 0xe2, 0x02, 0xcb, 0x92, 0xeb, 0x5a, 0xdf, 0x96, 0x1d, 0xde, 0x4d, 0x3b,
+	0xda, 0x13, 0xec, 0x6c, 0x4d, 0xf0, 0x8f, 0x4a, 0x1f, 0x01, 0x38, 0x61, 0xca, 0xef, 0x8f, 0x7b,
+	0xd3, 0x83, 0xb8, 0x85, 0xfc, 0xd5, 0x82, 0xdd, 0x5b, 0x2c, 0xb8, 0x0f, 0x27, 0x5b, 0xe3, 0xc5,
+	0xac, 0x2a, 0xbe, 0x47, 0x26, 0x9b, 0xff, 0xf2, 0xe0, 0xd0, 0xe5, 0xde, 0x62, 0x51, 0xe0, 0x37,
+	0x26, 0xc9, 0x19, 0xec, 0x35, 0xe7, 0xc3, 0xc8, 0x93, 0x59, 0xfb, 0x44, 0xbb, 0xae, 0x2b, 0x98,
+	0x74, 0x53, 0x36, 0xbc, 0xfe, 0x00, 0x83, 0xe6, 0x65, 0xf2, 0xb8, 0x9b, 0xed, 0xd6, 0x1e, 0x3c,
+	0xbd, 0x8d, 0xb0, 0x16, 0x1e, 0xbd, 0xf9, 0xbc, 0xc8, 0xb9, 0x5e, 0x99, 0xa4, 0xde, 0x67, 0x88,
+	0x59, 0x96, 0xae, 0x28, 0x17, 0x05, 0x4d, 0x54, 0x48, 0x65, 0xc2, 0xb5, 0x34, 0x65, 0x58, 0xd1,
+	0xf4, 0x82, 0xe6, 0xcc, 0x22, 0xcf, 0xbe, 0xd2, 0x82, 0x2f, 0xeb, 0x96, 0x61, 0xab, 0x7d, 0x32,
+	0xb0, 0xb7, 0xf3, 0xe2, 0xcf, 0x00, 0xff, 0xef, 0x35, 0x5b, 0x89, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// UnanimousFollowerClient is the client API for UnanimousFollower service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type UnanimousFollowerClient interface {
+	// Propose asks the follower to execute and sign an assertion
+	Propose(ctx context.Context, in *UnanimousProposalBuf, opts ...grpc.CallOption) (*UnanimousSignatureBuf, error)
+	// Commit tells the follower whether every party signed the proposal
+	Commit(ctx context.Context, in *UnanimousCommitBuf, opts ...grpc.CallOption) (*UnanimousCommitReplyBuf, error)
+}
+
+type unanimousFollowerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewUnanimousFollowerClient(cc *grpc.ClientConn) UnanimousFollowerClient {
+	return &unanimousFollowerClient{cc}
+}
+
+func (c *unanimousFollowerClient) Propose(ctx context.Context, in *UnanimousProposalBuf, opts ...grpc.CallOption) (*UnanimousSignatureBuf, error) {
+	out := new(UnanimousSignatureBuf)
+	err := c.cc.Invoke(ctx, "/coordinator.UnanimousFollower/Propose", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *unanimousFollowerClient) Commit(ctx context.Context, in *UnanimousCommitBuf, opts ...grpc.CallOption) (*UnanimousCommitReplyBuf, error) {
+	out := new(UnanimousCommitReplyBuf)
+	err := c.cc.Invoke(ctx, "/coordinator.UnanimousFollower/Commit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UnanimousFollowerServer is the server API for UnanimousFollower service.
+type UnanimousFollowerServer interface {
+	// Propose asks the follower to execute and sign an assertion
+	Propose(context.Context, *UnanimousProposalBuf) (*UnanimousSignatureBuf, error)
+	// Commit tells the follower whether every party signed the proposal
+	Commit(context.Context, *UnanimousCommitBuf) (*UnanimousCommitReplyBuf, error)
+}
+
+// UnimplementedUnanimousFollowerServer can be embedded to have forward compatible implementations.
+type UnimplementedUnanimousFollowerServer struct {
+}
+
+func (*UnimplementedUnanimousFollowerServer) Propose(ctx context.Context, req *UnanimousProposalBuf) (*UnanimousSignatureBuf, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Propose not implemented")
+}
+func (*UnimplementedUnanimousFollowerServer) Commit(ctx context.Context, req *UnanimousCommitBuf) (*UnanimousCommitReplyBuf, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Commit not implemented")
+}
+
+func RegisterUnanimousFollowerServer(s *grpc.Server, srv UnanimousFollowerServer) {
+	s.RegisterService(&_UnanimousFollower_serviceDesc, srv)
+}
+
+func _UnanimousFollower_Propose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnanimousProposalBuf)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnanimousFollowerServer).Propose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coordinator.UnanimousFollower/Propose",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnanimousFollowerServer).Propose(ctx, req.(*UnanimousProposalBuf))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UnanimousFollower_Commit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnanimousCommitBuf)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UnanimousFollowerServer).Commit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/coordinator.UnanimousFollower/Commit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UnanimousFollowerServer).Commit(ctx, req.(*UnanimousCommitBuf))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _UnanimousFollower_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "coordinator.UnanimousFollower",
+	HandlerType: (*UnanimousFollowerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Propose",
+			Handler:    _UnanimousFollower_Propose_Handler,
+		},
+		{
+			MethodName: "Commit",
+			Handler:    _UnanimousFollower_Commit_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "coordinator.proto",
+}
@@ -0,0 +1,266 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valmessage"
+)
+
+// localClient calls a follower in the same process
+type localClient struct {
+	follower *Follower
+	down     bool
+}
+
+func (c *localClient) Propose(ctx context.Context, in *UnanimousProposalBuf, opts ...grpc.CallOption) (*UnanimousSignatureBuf, error) {
+	if c.down {
+		return nil, errors.New("follower is unreachable")
+	}
+	return c.follower.Propose(ctx, in)
+}
+
+func (c *localClient) Commit(ctx context.Context, in *UnanimousCommitBuf, opts ...grpc.CallOption) (*UnanimousCommitReplyBuf, error) {
+	if c.down {
+		return nil, errors.New("follower is unreachable")
+	}
+	return c.follower.Commit(ctx, in)
+}
+
+// newCoordinator sets up a coordinator with count-1 followers which all see
+// the same chain
+func newCoordinator(t *testing.T, count int) (*Coordinator, []*localClient) {
+	t.Helper()
+	signers, followers := newFollowers(t, count)
+	clients := make([]*localClient, 0, count-1)
+	grpcClients := make([]UnanimousFollowerClient, 0, count-1)
+	for _, follower := range followers[1:] {
+		client := &localClient{follower: follower}
+		clients = append(clients, client)
+		grpcClients = append(grpcClients, client)
+	}
+	local := followers[0]
+	coord, err := NewCoordinator(local.chain, local.vmID, signers[0], local.config, NewMemoryJournal(), grpcClients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return coord, clients
+}
+
+// nextResult waits for the next assertion the party sees become final
+func nextResult(t *testing.T, party *Follower) valmessage.FinalizedAssertion {
+	t.Helper()
+	select {
+	case result := <-party.Results():
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("no assertion became final")
+	}
+	return valmessage.FinalizedAssertion{}
+}
+
+func TestUnanimousAgreement(t *testing.T) {
+	coord, clients := newCoordinator(t, 3)
+	if err := coord.runRound(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	parties := []*Follower{coord.Follower, clients[0].follower, clients[1].follower}
+	for i, party := range parties {
+		result := nextResult(t, party)
+		if result.ProposalResults.SequenceNum != 1 {
+			t.Error("party", i, "finalized sequence number", result.ProposalResults.SequenceNum)
+		}
+		if len(result.Signatures) != 3 {
+			t.Error("party", i, "finalized with", len(result.Signatures), "signatures")
+		}
+		if party.state.inboxCount.Uint64() != 5 {
+			t.Error("party", i, "didn't advance past the inbox")
+		}
+		if _, sigs, found, err := party.journal.Signatures(1); err != nil || !found || len(sigs) != 3 {
+			t.Error("party", i, "didn't journal the final assertion", err)
+		}
+	}
+	if coord.state.machine.Hash() != clients[0].follower.state.machine.Hash() {
+		t.Error("parties disagree on the machine after a unanimous assertion")
+	}
+
+	// Nothing new has arrived so there's nothing to propose
+	if err := coord.runRound(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if coord.lastSigned != 1 {
+		t.Error("proposed an assertion without new messages")
+	}
+}
+
+func TestUnanimousFallback(t *testing.T) {
+	cases := []struct {
+		name string
+		fail func(client *localClient)
+	}{
+		{"disagreement", func(client *localClient) {
+			client.follower.chain.(*fakeChain).machine = &hashMachine{salt: 1}
+		}},
+		{"unreachable", func(client *localClient) {
+			client.down = true
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			coord, clients := newCoordinator(t, 3)
+			c.fail(clients[1])
+			if err := coord.runRound(context.Background()); err == nil {
+				t.Fatal("round succeeded without every signature")
+			}
+			for i, party := range []*Follower{coord.Follower, clients[0].follower} {
+				select {
+				case <-party.Results():
+					t.Error("party", i, "finalized an abandoned assertion")
+				default:
+				}
+				if party.proposed != nil || party.state.inboxCount.Sign() != 0 {
+					t.Error("party", i, "didn't fall back to the rollup")
+				}
+				if party.journal.LastFinalized() != 0 {
+					t.Error("party", i, "journaled an abandoned assertion as final")
+				}
+			}
+
+			// Once the follower is fixed and restarted, the next round picks
+			// up from the rollup with a new sequence number
+			broken := clients[1].follower
+			restarted, err := NewFollower(&fakeChain{machine: &hashMachine{}, messages: 5}, broken.vmID, broken.signer, broken.config, broken.journal)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clients[1] = &localClient{follower: restarted}
+			coord.followers[1] = clients[1]
+			if err := coord.runRound(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			result := nextResult(t, coord.Follower)
+			if result.ProposalResults.SequenceNum != 2 {
+				t.Error("recovered with sequence number", result.ProposalResults.SequenceNum)
+			}
+		})
+	}
+}
+
+func TestFallbackKeepsFinalState(t *testing.T) {
+	coord, clients := newCoordinator(t, 3)
+	ctx := context.Background()
+	if err := coord.runRound(ctx); err != nil {
+		t.Fatal(err)
+	}
+	final := coord.state.machine.Hash()
+
+	// An abandoned round goes back to the final assertion rather than the
+	// rollup, which hasn't read any messages yet
+	parties := []*Follower{coord.Follower, clients[0].follower, clients[1].follower}
+	for _, party := range parties {
+		nextResult(t, party)
+		party.chain.(*fakeChain).messages = 8
+	}
+	clients[1].down = true
+	if err := coord.runRound(ctx); err == nil {
+		t.Fatal("round succeeded without every signature")
+	}
+	for i, party := range parties[:2] {
+		if party.state.inboxCount.Uint64() != 5 || party.state.machine.Hash() != final {
+			t.Error("party", i, "fell back past its final assertion")
+		}
+	}
+
+	clients[1].down = false
+	if err := coord.runRound(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i, party := range parties {
+		result := nextResult(t, party)
+		if result.ProposalResults.SequenceNum != 3 || result.ProposalResults.BeforeHash != final {
+			t.Error("party", i, "didn't continue from the final assertion")
+		}
+		if party.state.inboxCount.Uint64() != 8 {
+			t.Error("party", i, "didn't read the new messages")
+		}
+	}
+}
+
+func TestRestartWaitsForFinalState(t *testing.T) {
+	coord, clients := newCoordinator(t, 2)
+	ctx := context.Background()
+	if err := coord.runRound(ctx); err != nil {
+		t.Fatal(err)
+	}
+	follower := clients[0].follower
+
+	// A restarted follower lost the final machine and the rollup hasn't
+	// reached it, so it can't agree to anything
+	chain := &fakeChain{machine: &hashMachine{}, messages: 8}
+	restarted, err := NewFollower(chain, follower.vmID, follower.signer, follower.config, follower.journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clients[0].follower = restarted
+	coord.chain.(*fakeChain).messages = 8
+	if err := coord.runRound(ctx); err == nil {
+		t.Fatal("restarted follower agreed without the final state")
+	}
+	if restarted.state != nil {
+		t.Error("restarted follower fell back past its final assertion")
+	}
+
+	// Once the rollup has read past the final assertion both continue from it
+	valid := &hashMachine{hash: common.Hash{7}}
+	for _, c := range []*fakeChain{chain, coord.chain.(*fakeChain)} {
+		c.valid = valid
+		c.validCount = 6
+	}
+	// The coordinator only notices once a round from its old state fails
+	if err := coord.runRound(ctx); err == nil {
+		t.Fatal("restarted follower agreed to a proposal behind the rollup")
+	}
+	if err := coord.runRound(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if restarted.state.inboxCount.Uint64() != 8 {
+		t.Error("restarted follower didn't continue from the rollup")
+	}
+	if _, _, found, err := restarted.journal.Signatures(1); err != nil || !found {
+		t.Error("lost the earlier final assertion", err)
+	}
+}
+
+func TestCoordinatorNeedsCoordinatorKey(t *testing.T) {
+	signers, followers := newFollowers(t, 2)
+	local := followers[1]
+	_, err := NewCoordinator(local.chain, local.vmID, signers[1], local.config, NewMemoryJournal(), []UnanimousFollowerClient{&localClient{follower: followers[0]}})
+	if err == nil {
+		t.Error("started a coordinator without the coordinator key")
+	}
+	if _, err := NewCoordinator(local.chain, local.vmID, signers[0], local.config, NewMemoryJournal(), nil); err == nil {
+		t.Error("started a coordinator without enough followers")
+	}
+}
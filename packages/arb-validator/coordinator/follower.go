@@ -0,0 +1,428 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/unanimous"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valmessage"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+)
+
+//go:generate bash -c "protoc -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-util) -I. --go_out=paths=source_relative,plugins=grpc:. *.proto"
+
+// ChainSource gives unanimous mode access to the rollup chain that it runs
+// alongside. It's implemented by rollupmanager.Manager.
+type ChainSource interface {
+	LatestValidState() *rollup.ValidState
+	InboxSegment(beforeCount *big.Int, maxCount uint64) (*rollup.InboxSegment, error)
+	CurrentBlockId() *common.BlockId
+}
+
+// Config describes the set of parties which must co-sign every unanimous
+// assertion and limits the work done for each one
+type Config struct {
+	// AssertKeys are the addresses of every party. An assertion is only
+	// final once all of them have signed it.
+	AssertKeys []common.Address
+	// CoordinatorKey is the assert key which signs every proposal and
+	// commit. Followers ignore messages that it hasn't signed.
+	CoordinatorKey common.Address
+	// MaxSequenceGap limits how far ahead of the last signed assertion a
+	// proposal's sequence number may be
+	MaxSequenceGap uint64
+	// MaxSteps limits the execution of a single assertion
+	MaxSteps uint64
+	// MaxMessages limits the number of inbox messages read by an assertion
+	MaxMessages uint64
+	// RoundInterval is how often the coordinator proposes an assertion
+	RoundInterval time.Duration
+	// RequestTimeout is how long the coordinator waits for each follower
+	// before falling back to the disputable path
+	RequestTimeout time.Duration
+}
+
+func DefaultConfig(assertKeys []common.Address, coordinatorKey common.Address) Config {
+	return Config{
+		AssertKeys:     assertKeys,
+		CoordinatorKey: coordinatorKey,
+		MaxSequenceGap: 1000,
+		MaxSteps:       10000000,
+		MaxMessages:    1000,
+		RoundInterval:  time.Second * 2,
+		RequestTimeout: time.Second * 10,
+	}
+}
+
+func (c Config) Validate() error {
+	if len(c.AssertKeys) == 0 {
+		return errors.New("unanimous mode needs at least one assert key")
+	}
+	seen := make(map[common.Address]bool)
+	for _, key := range c.AssertKeys {
+		if seen[key] {
+			return fmt.Errorf("assert key %v is listed twice", key)
+		}
+		seen[key] = true
+	}
+	if !c.hasKey(c.CoordinatorKey) {
+		return fmt.Errorf("coordinator key %v isn't one of the assert keys", c.CoordinatorKey)
+	}
+	if c.MaxSequenceGap == 0 {
+		return errors.New("unanimous sequence gap must be positive")
+	}
+	if c.MaxSteps == 0 || c.MaxMessages == 0 {
+		return errors.New("unanimous step and message limits must be positive")
+	}
+	if c.RoundInterval <= 0 || c.RequestTimeout <= 0 {
+		return errors.New("unanimous round interval and request timeout must be positive")
+	}
+	return nil
+}
+
+func (c Config) hasKey(address common.Address) bool {
+	for _, key := range c.AssertKeys {
+		if key == address {
+			return true
+		}
+	}
+	return false
+}
+
+// unanimousState is the machine all parties have agreed on
+type unanimousState struct {
+	machine     machine.Machine
+	inboxTop    common.Hash
+	inboxCount  *big.Int
+	sequenceNum uint64
+}
+
+// signedProposal is an assertion this party has signed but which hasn't yet
+// been committed
+type signedProposal struct {
+	request       valmessage.UnanimousRequestData
+	assertionHash common.Hash
+	next          *unanimousState
+	results       *valmessage.UnanimousUpdateResults
+}
+
+// Follower executes and signs assertions proposed by the coordinator. The
+// coordinator runs one too for its own signature. Every signature and final
+// assertion goes through the journal first so that a restart can't lead to
+// a second signature for the same sequence number.
+type Follower struct {
+	sync.Mutex
+	chain      ChainSource
+	vmID       common.Address
+	signer     Signer
+	config     Config
+	journal    *Journal
+	state      *unanimousState
+	lastSigned uint64
+	proposed   *signedProposal
+
+	// committed is the state after the latest final assertion, if it was
+	// made since this follower started
+	committed *unanimousState
+
+	// queued holds final assertions which haven't been read from results
+	queued       []valmessage.FinalizedAssertion
+	resultsReady chan struct{}
+	results      chan valmessage.FinalizedAssertion
+}
+
+func NewFollower(chain ChainSource, vmID common.Address, signer Signer, config Config, journal *Journal) (*Follower, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if !config.hasKey(signer.Address()) {
+		return nil, fmt.Errorf("%v isn't one of the assert keys", signer.Address())
+	}
+	f := &Follower{
+		chain:        chain,
+		vmID:         vmID,
+		signer:       signer,
+		config:       config,
+		journal:      journal,
+		lastSigned:   journal.LastSigned(),
+		resultsReady: make(chan struct{}, 1),
+		results:      make(chan valmessage.FinalizedAssertion),
+	}
+	go f.deliverResults()
+	return f, nil
+}
+
+// Results delivers every assertion that all parties have signed, in the
+// order they became final. Commit doesn't wait for them to be read, so
+// results are queued in memory until they are.
+func (f *Follower) Results() <-chan valmessage.FinalizedAssertion {
+	return f.results
+}
+
+func (f *Follower) deliverResults() {
+	for range f.resultsReady {
+		for {
+			f.Lock()
+			if len(f.queued) == 0 {
+				f.Unlock()
+				break
+			}
+			result := f.queued[0]
+			f.queued = f.queued[1:]
+			f.Unlock()
+			f.results <- result
+		}
+	}
+}
+
+// latestSequenceNum is the highest sequence number this party has agreed to
+// or signed. Must be called with f locked.
+func (f *Follower) latestSequenceNum() uint64 {
+	if f.state != nil && f.state.sequenceNum > f.lastSigned {
+		return f.state.sequenceNum
+	}
+	return f.lastSigned
+}
+
+// checkSequenceNum rejects sequence numbers so far ahead that they'd use up
+// the sequence number space. Must be called with f locked.
+func (f *Follower) checkSequenceNum(sequenceNum uint64) error {
+	latest := f.latestSequenceNum()
+	if sequenceNum > latest && sequenceNum-latest > f.config.MaxSequenceGap {
+		return fmt.Errorf("sequence number %v is more than %v ahead of %v", sequenceNum, f.config.MaxSequenceGap, latest)
+	}
+	return nil
+}
+
+// rebase drops any agreed state that isn't final. It starts again from the
+// state after the latest final assertion, unless the rollup's latest valid
+// node has read messages past it, in which case it starts from that node. A
+// restarted party doesn't have the final machine, so until the rollup
+// reaches the final state there is no agreed state. Must be called with f
+// locked.
+func (f *Follower) rebase() {
+	sequenceNum := f.journal.LastFinalized()
+	if f.state != nil && f.state.sequenceNum > sequenceNum {
+		sequenceNum = f.state.sequenceNum
+	}
+	f.proposed = nil
+
+	valid := f.chain.LatestValidState()
+	final := f.journal.LastFinalState()
+	switch {
+	case final == nil || valid.InboxCount.Cmp(final.InboxCount) > 0:
+		// Nothing is final or the rollup has moved past it
+	case f.committed != nil:
+		f.state = &unanimousState{
+			machine:     f.committed.machine,
+			inboxTop:    f.committed.inboxTop,
+			inboxCount:  f.committed.inboxCount,
+			sequenceNum: sequenceNum,
+		}
+		return
+	case valid.InboxCount.Cmp(final.InboxCount) != 0 ||
+		valid.InboxTop != final.InboxTop ||
+		valid.Machine.Hash() != final.MachineHash:
+		f.state = nil
+		return
+	}
+	f.state = &unanimousState{
+		machine:     valid.Machine,
+		inboxTop:    valid.InboxTop,
+		inboxCount:  valid.InboxCount,
+		sequenceNum: sequenceNum,
+	}
+}
+
+// errNoFinalState is returned while a restarted party waits for the rollup
+// to reach the state after the latest final assertion
+var errNoFinalState = errors.New("waiting for the rollup to reach the last final state")
+
+func (f *Follower) matchesState(prop *UnanimousProposalBuf) bool {
+	return f.state.machine.Hash() == prop.BeforeHash.Unmarshal() &&
+		f.state.inboxTop == prop.BeforeInbox.Unmarshal() &&
+		f.state.inboxCount.Cmp(prop.BeforeInboxCount.Unmarshal()) == 0
+}
+
+func reject(reason string) *UnanimousSignatureBuf {
+	return &UnanimousSignatureBuf{Accepted: false, Reason: reason}
+}
+
+// Propose executes the proposed assertion from the agreed state and signs it
+// if every input checks out
+func (f *Follower) Propose(ctx context.Context, prop *UnanimousProposalBuf) (*UnanimousSignatureBuf, error) {
+	f.Lock()
+	defer f.Unlock()
+	if prop.BeforeHash == nil || prop.BeforeInbox == nil || prop.BeforeInboxCount == nil || prop.TimeBounds == nil {
+		return reject("proposal is incomplete"), nil
+	}
+	if err := checkCoordinator(proposalHash(f.vmID, prop), prop.CoordinatorSignature, f.config.CoordinatorKey); err != nil {
+		return reject(err.Error()), nil
+	}
+	if f.state == nil {
+		f.rebase()
+		if f.state == nil {
+			return reject(errNoFinalState.Error()), nil
+		}
+	}
+	if prop.SequenceNum <= f.lastSigned {
+		return reject(fmt.Sprintf("already signed sequence number %v", f.lastSigned)), nil
+	}
+	if err := f.checkSequenceNum(prop.SequenceNum); err != nil {
+		return reject(err.Error()), nil
+	}
+	if !f.matchesState(prop) {
+		// The coordinator may have fallen back to the rollup already
+		f.rebase()
+		if f.state == nil {
+			return reject(errNoFinalState.Error()), nil
+		}
+		if !f.matchesState(prop) {
+			return reject("proposal doesn't start from the agreed state"), nil
+		}
+	}
+	if prop.MaxSteps == 0 || prop.MaxSteps > f.config.MaxSteps {
+		return reject(fmt.Sprintf("step limit must be between 1 and %v", f.config.MaxSteps)), nil
+	}
+	if prop.MessageCount > f.config.MaxMessages {
+		return reject(fmt.Sprintf("can't read more than %v messages", f.config.MaxMessages)), nil
+	}
+	timeBounds := prop.TimeBounds.Unmarshal()
+	if timeBounds.Start.Cmp(timeBounds.End) > 0 || timeBounds.Start.Cmp(f.chain.CurrentBlockId().Height) > 0 {
+		return reject("time bounds aren't valid at the current block"), nil
+	}
+	segment, err := f.chain.InboxSegment(f.state.inboxCount, prop.MessageCount)
+	if err != nil {
+		return reject(err.Error()), nil
+	}
+	if segment.Count != prop.MessageCount {
+		return reject("proposal reads messages which haven't been seen on L1"), nil
+	}
+
+	request := valmessage.UnanimousRequestData{
+		BeforeHash:  f.state.machine.Hash(),
+		BeforeInbox: f.state.inboxTop,
+		SequenceNum: prop.SequenceNum,
+		TimeBounds:  timeBounds,
+	}
+	mach := f.state.machine.Clone()
+	assertion, _ := mach.ExecuteAssertion(prop.MaxSteps, timeBounds, segment.Inbox.AsValue(), 0)
+	assertionHash, err := unanimous.UnanimousAssertHash(
+		f.vmID,
+		prop.SequenceNum,
+		request.BeforeHash,
+		segment.AfterTop,
+		segment.BeforeTop,
+		assertion,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.journal.RecordIntentToSign(prop.SequenceNum, assertionHash); err != nil {
+		return nil, err
+	}
+	sig, err := f.signer.Sign(assertionHash)
+	if err != nil {
+		return nil, err
+	}
+	f.lastSigned = prop.SequenceNum
+	f.proposed = &signedProposal{
+		request:       request,
+		assertionHash: assertionHash,
+		next: &unanimousState{
+			machine:     mach,
+			inboxTop:    segment.AfterTop,
+			inboxCount:  new(big.Int).Add(f.state.inboxCount, new(big.Int).SetUint64(segment.Count)),
+			sequenceNum: prop.SequenceNum,
+		},
+		results: &valmessage.UnanimousUpdateResults{
+			UnanimousRequestData: request,
+			NewInboxHash:         segment.AfterTop,
+			Assertion:            assertion,
+			NewLogCount:          len(assertion.Logs),
+		},
+	}
+	return &UnanimousSignatureBuf{
+		Accepted:      true,
+		AssertionHash: assertionHash.MarshalToBuf(),
+		Signature:     sig,
+	}, nil
+}
+
+// Commit moves to the proposed state once every party has signed it, or
+// falls back to the rollup if the proposal was abandoned
+func (f *Follower) Commit(ctx context.Context, commit *UnanimousCommitBuf) (*UnanimousCommitReplyBuf, error) {
+	f.Lock()
+	defer f.Unlock()
+	if err := checkCoordinator(commitHash(f.vmID, commit), commit.CoordinatorSignature, f.config.CoordinatorKey); err != nil {
+		return nil, err
+	}
+	if !commit.Accepted {
+		if commit.SequenceNum < f.lastSigned {
+			// A newer proposal has been signed since this one was abandoned
+			return &UnanimousCommitReplyBuf{}, nil
+		}
+		if err := f.checkSequenceNum(commit.SequenceNum); err != nil {
+			return nil, err
+		}
+		f.rebase()
+		if f.state != nil && commit.SequenceNum > f.state.sequenceNum {
+			f.state.sequenceNum = commit.SequenceNum
+		}
+		return &UnanimousCommitReplyBuf{}, nil
+	}
+	proposed := f.proposed
+	if proposed == nil || commit.AssertionHash == nil ||
+		proposed.next.sequenceNum != commit.SequenceNum ||
+		proposed.assertionHash != commit.AssertionHash.Unmarshal() {
+		return nil, errors.New("commit doesn't match the signed proposal")
+	}
+	sigs, err := orderSignatures(proposed.assertionHash, commit.Signatures, f.config.AssertKeys)
+	if err != nil {
+		return nil, err
+	}
+	next := proposed.next
+	after := FinalState{
+		MachineHash: next.machine.Hash(),
+		InboxTop:    next.inboxTop,
+		InboxCount:  next.inboxCount,
+	}
+	if err := f.journal.RecordSignatures(commit.SequenceNum, proposed.assertionHash, sigs, after); err != nil {
+		return nil, err
+	}
+	f.state = next
+	f.committed = next
+	f.proposed = nil
+	f.queued = append(f.queued, valmessage.FinalizedAssertion{
+		Assertion:       proposed.results.Assertion,
+		Signatures:      sigs,
+		ProposalResults: proposed.results,
+	})
+	select {
+	case f.resultsReady <- struct{}{}:
+	default:
+	}
+	return &UnanimousCommitReplyBuf{}, nil
+}
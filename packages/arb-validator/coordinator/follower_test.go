@@ -0,0 +1,239 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
+)
+
+// hashMachine stands in for a real VM. Each assertion folds the inbox into
+// its hash, salted so that tests can make a party compute a different result.
+type hashMachine struct {
+	hash common.Hash
+	salt byte
+}
+
+func (m *hashMachine) Hash() common.Hash      { return m.hash }
+func (m *hashMachine) Clone() machine.Machine { return &hashMachine{m.hash, m.salt} }
+func (m *hashMachine) PrintState()            {}
+
+func (m *hashMachine) CurrentStatus() machine.Status { return machine.Extensive }
+
+func (m *hashMachine) IsBlocked(currentTime *common.TimeBlocks, newMessages bool) machine.BlockReason {
+	return nil
+}
+
+func (m *hashMachine) ExecuteAssertion(
+	maxSteps uint64,
+	timeBounds *protocol.TimeBoundsBlocks,
+	inbox value.TupleValue,
+	maxWallTime time.Duration,
+) (*protocol.ExecutionAssertion, uint64) {
+	m.hash = hashing.SoliditySHA3(hashing.Bytes32(m.hash), hashing.Bytes32(inbox.Hash()), hashing.Uint8(m.salt))
+	return protocol.NewExecutionAssertion(m.hash, true, 1, nil, nil), 1
+}
+
+func (m *hashMachine) MarshalForProof() ([]byte, error) { return nil, nil }
+
+func (m *hashMachine) Checkpoint(storage machine.CheckpointStorage) bool { return true }
+
+// fakeChain has a fixed number of delivered messages and a valid node at
+// the start of the inbox, or at validCount messages with the machine valid
+type fakeChain struct {
+	machine    machine.Machine
+	messages   uint64
+	valid      machine.Machine
+	validCount uint64
+}
+
+func inboxTopAt(count uint64) common.Hash {
+	return hashing.SoliditySHA3(hashing.Uint64(count))
+}
+
+func (c *fakeChain) LatestValidState() *rollup.ValidState {
+	if c.valid != nil {
+		return &rollup.ValidState{
+			Machine:    c.valid.Clone(),
+			InboxTop:   inboxTopAt(c.validCount),
+			InboxCount: new(big.Int).SetUint64(c.validCount),
+		}
+	}
+	return &rollup.ValidState{
+		Machine:    c.machine.Clone(),
+		InboxTop:   inboxTopAt(0),
+		InboxCount: big.NewInt(0),
+	}
+}
+
+func (c *fakeChain) InboxSegment(beforeCount *big.Int, maxCount uint64) (*rollup.InboxSegment, error) {
+	before := beforeCount.Uint64()
+	count := c.messages - before
+	if count > maxCount {
+		count = maxCount
+	}
+	return &rollup.InboxSegment{
+		BeforeTop: inboxTopAt(before),
+		AfterTop:  inboxTopAt(before + count),
+		Count:     count,
+		Inbox:     structures.NewVMInbox(),
+	}, nil
+}
+
+func (c *fakeChain) CurrentBlockId() *common.BlockId {
+	return &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(10)), HeaderHash: common.Hash{}}
+}
+
+// newFollowers creates a follower for every signer. The first signer is the
+// coordinator.
+func newFollowers(t *testing.T, count int) ([]Signer, []*Follower) {
+	t.Helper()
+	signers, keys := newSigners(t, count)
+	config := DefaultConfig(keys, keys[0])
+	followers := make([]*Follower, 0, count)
+	for _, signer := range signers {
+		chain := &fakeChain{machine: &hashMachine{}, messages: 5}
+		follower, err := NewFollower(chain, common.Address{1}, signer, config, NewMemoryJournal())
+		if err != nil {
+			t.Fatal(err)
+		}
+		followers = append(followers, follower)
+	}
+	return signers, followers
+}
+
+func coordinatorProposal(t *testing.T, signer Signer, sequenceNum uint64) *UnanimousProposalBuf {
+	t.Helper()
+	height := common.NewTimeBlocks(big.NewInt(10))
+	prop := &UnanimousProposalBuf{
+		BeforeHash:       common.Hash{}.MarshalToBuf(),
+		BeforeInbox:      inboxTopAt(0).MarshalToBuf(),
+		BeforeInboxCount: common.MarshalBigInt(big.NewInt(0)),
+		SequenceNum:      sequenceNum,
+		TimeBounds:       (&protocol.TimeBoundsBlocks{Start: height, End: height}).MarshalToBuf(),
+		MessageCount:     5,
+		MaxSteps:         100,
+	}
+	sig, err := signer.Sign(proposalHash(common.Address{1}, prop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prop.CoordinatorSignature = sig
+	return prop
+}
+
+func coordinatorCommit(t *testing.T, signer Signer, commit *UnanimousCommitBuf) *UnanimousCommitBuf {
+	t.Helper()
+	sig, err := signer.Sign(commitHash(common.Address{1}, commit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit.CoordinatorSignature = sig
+	return commit
+}
+
+func TestProposeRequiresCoordinator(t *testing.T) {
+	signers, followers := newFollowers(t, 2)
+	follower := followers[1]
+	ctx := context.Background()
+
+	reply, err := follower.Propose(ctx, coordinatorProposal(t, signers[1], 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Accepted {
+		t.Error("accepted a proposal signed by a follower")
+	}
+
+	prop := coordinatorProposal(t, signers[0], 1)
+	prop.MaxSteps++
+	reply, err = follower.Propose(ctx, prop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Accepted {
+		t.Error("accepted a proposal modified after it was signed")
+	}
+
+	reply, err = follower.Propose(ctx, coordinatorProposal(t, signers[0], 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Accepted {
+		t.Fatal("rejected the coordinator's proposal:", reply.Reason)
+	}
+}
+
+func TestProposeBoundsSequenceGap(t *testing.T) {
+	signers, followers := newFollowers(t, 2)
+	follower := followers[1]
+	ctx := context.Background()
+
+	reply, err := follower.Propose(ctx, coordinatorProposal(t, signers[0], math.MaxUint64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Accepted {
+		t.Error("accepted a proposal far past the last sequence number")
+	}
+
+	reply, err = follower.Propose(ctx, coordinatorProposal(t, signers[0], follower.config.MaxSequenceGap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Accepted {
+		t.Error("rejected a proposal within the sequence gap:", reply.Reason)
+	}
+}
+
+func TestCommitRequiresCoordinator(t *testing.T) {
+	signers, followers := newFollowers(t, 2)
+	follower := followers[1]
+	ctx := context.Background()
+
+	reply, err := follower.Propose(ctx, coordinatorProposal(t, signers[0], 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Accepted {
+		t.Fatal("rejected the coordinator's proposal:", reply.Reason)
+	}
+
+	abandon := coordinatorCommit(t, signers[1], &UnanimousCommitBuf{SequenceNum: 1})
+	if _, err := follower.Commit(ctx, abandon); err == nil {
+		t.Error("accepted an abandon commit signed by a follower")
+	}
+	if follower.proposed == nil {
+		t.Error("unauthenticated commit dropped the signed proposal")
+	}
+
+	far := coordinatorCommit(t, signers[0], &UnanimousCommitBuf{SequenceNum: math.MaxUint64})
+	if _, err := follower.Commit(ctx, far); err == nil {
+		t.Error("accepted an abandon commit far past the last sequence number")
+	}
+}
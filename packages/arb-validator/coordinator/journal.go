@@ -0,0 +1,243 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+var (
+	journalMetaKey  = []byte("metadata")
+	finalStateKey   = []byte("final")
+	intentPrefix    = []byte("i")
+	signaturePrefix = []byte("s")
+)
+
+// signatureLength is the length of a recoverable secp256k1 signature
+const signatureLength = 65
+
+// FinalState is the agreed position after the latest final assertion
+type FinalState struct {
+	MachineHash common.Hash
+	InboxTop    common.Hash
+	InboxCount  *big.Int
+}
+
+// Journal durably records every assertion a party intends to sign before it
+// signs, and the full set of signatures once an assertion is final. It stops
+// a restarted party from signing a second assertion with a sequence number
+// it already used, and keeps the proof that an assertion was final. It
+// records the position after the latest final assertion but not the
+// machine, so a restarted party waits for the rollup to reach that position.
+type Journal struct {
+	db            ethdb.KeyValueStore
+	lastSigned    uint64
+	lastFinalized uint64
+	finalState    *FinalState
+}
+
+// OpenJournal returns a journal stored in a leveldb database at the given
+// path, creating it if it doesn't exist
+func OpenJournal(path string) (*Journal, error) {
+	db, err := leveldb.New(path, 16, 16, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewJournal(db)
+}
+
+// NewMemoryJournal returns a journal which is lost when the process exits
+func NewMemoryJournal() *Journal {
+	journal, _ := NewJournal(memorydb.New()) // an empty store has no metadata to fail on
+	return journal
+}
+
+// NewJournal returns a journal backed by the given store, picking up where
+// any previous journal in it left off
+func NewJournal(db ethdb.KeyValueStore) (*Journal, error) {
+	journal := &Journal{db: db}
+	has, err := db.Has(journalMetaKey)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		data, err := db.Get(journalMetaKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != 16 {
+			return nil, errors.New("corrupt unanimous journal metadata")
+		}
+		journal.lastSigned = binary.BigEndian.Uint64(data[0:])
+		journal.lastFinalized = binary.BigEndian.Uint64(data[8:])
+	}
+	has, err = db.Has(finalStateKey)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		data, err := db.Get(finalStateKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 64 {
+			return nil, errors.New("corrupt unanimous journal final state")
+		}
+		journal.finalState = &FinalState{InboxCount: new(big.Int).SetBytes(data[64:])}
+		copy(journal.finalState.MachineHash[:], data[0:])
+		copy(journal.finalState.InboxTop[:], data[32:])
+	}
+	return journal, nil
+}
+
+// Close closes the underlying store
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+// LastSigned is the highest sequence number this party may have signed
+func (j *Journal) LastSigned() uint64 {
+	return j.lastSigned
+}
+
+// LastFinalized is the highest sequence number with a full set of signatures
+func (j *Journal) LastFinalized() uint64 {
+	return j.lastFinalized
+}
+
+// LastFinalState is the position after the assertion with sequence number
+// LastFinalized, or nil if none is final
+func (j *Journal) LastFinalState() *FinalState {
+	return j.finalState
+}
+
+// RecordIntentToSign must be called before signing the assertion with the
+// given sequence number so that it's never signed twice
+func (j *Journal) RecordIntentToSign(sequenceNum uint64, assertionHash common.Hash) error {
+	if sequenceNum <= j.lastSigned {
+		return fmt.Errorf("already intended to sign sequence number %v", j.lastSigned)
+	}
+	batch := j.db.NewBatch()
+	if err := batch.Put(sequenceKey(intentPrefix, sequenceNum), assertionHash[:]); err != nil {
+		return err
+	}
+	if err := batch.Put(journalMetaKey, encodeJournalMeta(sequenceNum, j.lastFinalized)); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	j.lastSigned = sequenceNum
+	return nil
+}
+
+// RecordSignatures stores the signatures of every assert key on an
+// assertion this party intended to sign, which makes it final, along with
+// the position after it
+func (j *Journal) RecordSignatures(sequenceNum uint64, assertionHash common.Hash, sigs [][]byte, after FinalState) error {
+	intended, err := j.db.Get(sequenceKey(intentPrefix, sequenceNum))
+	if err != nil {
+		return fmt.Errorf("no intent to sign sequence number %v", sequenceNum)
+	}
+	var intendedHash common.Hash
+	copy(intendedHash[:], intended)
+	if intendedHash != assertionHash {
+		return fmt.Errorf("sequence number %v was intended for a different assertion", sequenceNum)
+	}
+	record := make([]byte, 0, 32+signatureLength*len(sigs))
+	record = append(record, assertionHash[:]...)
+	for _, sig := range sigs {
+		if len(sig) != signatureLength {
+			return fmt.Errorf("signature has length %v instead of %v", len(sig), signatureLength)
+		}
+		record = append(record, sig...)
+	}
+	lastFinalized := j.lastFinalized
+	finalState := j.finalState
+	batch := j.db.NewBatch()
+	if sequenceNum >= lastFinalized {
+		lastFinalized = sequenceNum
+		finalState = &after
+		if err := batch.Put(finalStateKey, encodeFinalState(after)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(sequenceKey(signaturePrefix, sequenceNum), record); err != nil {
+		return err
+	}
+	if err := batch.Put(journalMetaKey, encodeJournalMeta(j.lastSigned, lastFinalized)); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	j.lastFinalized = lastFinalized
+	j.finalState = finalState
+	return nil
+}
+
+// Signatures returns the assertion and signatures recorded as final for the
+// given sequence number. found is false if it isn't final.
+func (j *Journal) Signatures(sequenceNum uint64) (assertionHash common.Hash, sigs [][]byte, found bool, err error) {
+	key := sequenceKey(signaturePrefix, sequenceNum)
+	has, err := j.db.Has(key)
+	if err != nil || !has {
+		return common.Hash{}, nil, false, err
+	}
+	record, err := j.db.Get(key)
+	if err != nil {
+		return common.Hash{}, nil, false, err
+	}
+	if len(record) < 32 || (len(record)-32)%signatureLength != 0 {
+		return common.Hash{}, nil, false, fmt.Errorf("corrupt signatures for sequence number %v", sequenceNum)
+	}
+	copy(assertionHash[:], record[:32])
+	for i := 32; i < len(record); i += signatureLength {
+		sigs = append(sigs, record[i:i+signatureLength])
+	}
+	return assertionHash, sigs, true, nil
+}
+
+func encodeJournalMeta(lastSigned, lastFinalized uint64) []byte {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[0:], lastSigned)
+	binary.BigEndian.PutUint64(data[8:], lastFinalized)
+	return data
+}
+
+func encodeFinalState(state FinalState) []byte {
+	data := make([]byte, 0, 64+32)
+	data = append(data, state.MachineHash[:]...)
+	data = append(data, state.InboxTop[:]...)
+	return append(data, state.InboxCount.Bytes()...)
+}
+
+func sequenceKey(prefix []byte, sequenceNum uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], sequenceNum)
+	return key
+}
@@ -0,0 +1,132 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestJournalRestore(t *testing.T) {
+	db := memorydb.New()
+	journal, err := NewJournal(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers, keys := newSigners(t, 2)
+	hash := common.Hash{1}
+	sigs := signAll(t, hash, signers)
+	after := FinalState{MachineHash: common.Hash{4}, InboxTop: common.Hash{5}, InboxCount: big.NewInt(6)}
+
+	if err := journal.RecordSignatures(1, hash, sigs, after); err == nil {
+		t.Error("recorded signatures without an intent to sign")
+	}
+	if err := journal.RecordIntentToSign(1, hash); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.RecordIntentToSign(1, common.Hash{2}); err == nil {
+		t.Error("intended to sign the same sequence number twice")
+	}
+	if err := journal.RecordSignatures(1, common.Hash{2}, sigs, after); err == nil {
+		t.Error("recorded signatures for a different assertion")
+	}
+	if err := journal.RecordSignatures(1, hash, sigs, after); err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.RecordIntentToSign(2, common.Hash{3}); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := NewJournal(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.LastSigned() != 2 || restored.LastFinalized() != 1 {
+		t.Error("restored journal at", restored.LastSigned(), restored.LastFinalized())
+	}
+	final := restored.LastFinalState()
+	if final == nil || final.MachineHash != after.MachineHash || final.InboxTop != after.InboxTop || final.InboxCount.Cmp(after.InboxCount) != 0 {
+		t.Error("restored final state", final, "instead of", after)
+	}
+	finalHash, finalSigs, found, err := restored.Signatures(1)
+	if err != nil || !found {
+		t.Fatal("final assertion wasn't restored", err)
+	}
+	if finalHash != hash {
+		t.Error("restored assertion", finalHash, "instead of", hash)
+	}
+	ordered, err := orderSignatures(hash, finalSigs, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range ordered {
+		if !bytes.Equal(ordered[i], sigs[i]) {
+			t.Error("signature", i, "wasn't restored")
+		}
+	}
+	if _, _, found, err := restored.Signatures(2); err != nil || found {
+		t.Error("assertion that was only signed is reported as final", err)
+	}
+}
+
+func TestFollowerRestartKeepsLastSigned(t *testing.T) {
+	signers, followers := newFollowers(t, 2)
+	follower := followers[1]
+	ctx := context.Background()
+
+	reply, err := follower.Propose(ctx, coordinatorProposal(t, signers[0], 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Accepted {
+		t.Fatal("rejected the coordinator's proposal:", reply.Reason)
+	}
+
+	// A crash before the commit arrives mustn't let the follower sign a
+	// different assertion with the same sequence number
+	restarted, err := NewFollower(follower.chain, follower.vmID, follower.signer, follower.config, follower.journal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prop := coordinatorProposal(t, signers[0], 1)
+	prop.MessageCount = 4
+	prop.CoordinatorSignature, err = signers[0].Sign(proposalHash(follower.vmID, prop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err = restarted.Propose(ctx, prop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Accepted {
+		t.Error("signed a sequence number again after restarting")
+	}
+
+	reply, err = restarted.Propose(ctx, coordinatorProposal(t, signers[0], 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Accepted {
+		t.Error("rejected the next sequence number after restarting:", reply.Reason)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
+)
+
+// Signer signs unanimous assertions with one of the rollup's assert keys
+type Signer interface {
+	Address() common.Address
+	Sign(hash common.Hash) ([]byte, error)
+}
+
+type keystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+}
+
+// NewKeystoreSigner signs with an unlocked account from a keystore
+func NewKeystoreSigner(ks *keystore.KeyStore, account accounts.Account) Signer {
+	return keystoreSigner{ks, account}
+}
+
+func (s keystoreSigner) Address() common.Address {
+	return common.NewAddressFromEth(s.account.Address)
+}
+
+func (s keystoreSigner) Sign(hash common.Hash) ([]byte, error) {
+	return s.ks.SignHash(s.account, signedHash(hash).Bytes())
+}
+
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner signs with the given private key
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return keySigner{key}
+}
+
+func (s keySigner) Address() common.Address {
+	return common.NewAddressFromEth(crypto.PubkeyToAddress(s.key.PublicKey))
+}
+
+func (s keySigner) Sign(hash common.Hash) ([]byte, error) {
+	return crypto.Sign(signedHash(hash).Bytes(), s.key)
+}
+
+// signedHash applies the standard Ethereum signed message prefix so that
+// signatures can be checked with ecrecover
+func signedHash(hash common.Hash) common.Hash {
+	return hashing.SoliditySHA3WithPrefix(hash[:])
+}
+
+func recoverSigner(hash common.Hash, sig []byte) (common.Address, error) {
+	pubKey, err := crypto.SigToPub(signedHash(hash).Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.NewAddressFromEth(crypto.PubkeyToAddress(*pubKey)), nil
+}
+
+// orderSignatures checks that every key signed hash and returns the
+// signatures in the same order as keys
+func orderSignatures(hash common.Hash, sigs [][]byte, keys []common.Address) ([][]byte, error) {
+	byKey := make(map[common.Address][]byte)
+	for _, sig := range sigs {
+		signer, err := recoverSigner(hash, sig)
+		if err != nil {
+			return nil, err
+		}
+		byKey[signer] = sig
+	}
+	ordered := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		sig, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("assertion %v is missing a signature from %v", hash, key)
+		}
+		ordered = append(ordered, sig)
+	}
+	return ordered, nil
+}
+
+// proposalHash covers every field of a proposal except the coordinator's
+// signature. Proposals must be complete before they're hashed.
+func proposalHash(vmID common.Address, prop *UnanimousProposalBuf) common.Hash {
+	timeBounds := prop.TimeBounds.Unmarshal()
+	return hashing.SoliditySHA3(
+		[]byte("unanimous proposal"),
+		hashing.Address(vmID),
+		hashing.Bytes32(prop.BeforeHash.Unmarshal()),
+		hashing.Bytes32(prop.BeforeInbox.Unmarshal()),
+		hashing.Uint256(prop.BeforeInboxCount.Unmarshal()),
+		hashing.Uint64(prop.SequenceNum),
+		hashing.TimeBlocks(timeBounds.Start),
+		hashing.TimeBlocks(timeBounds.End),
+		hashing.Uint64(prop.MessageCount),
+		hashing.Uint64(prop.MaxSteps),
+	)
+}
+
+// commitHash covers every field of a commit except the coordinator's
+// signature
+func commitHash(vmID common.Address, commit *UnanimousCommitBuf) common.Hash {
+	var assertionHash common.Hash
+	if commit.AssertionHash != nil {
+		assertionHash = commit.AssertionHash.Unmarshal()
+	}
+	data := []interface{}{
+		[]byte("unanimous commit"),
+		hashing.Address(vmID),
+		hashing.Uint64(commit.SequenceNum),
+		hashing.Bool(commit.Accepted),
+		hashing.Bytes32(assertionHash),
+	}
+	for _, sig := range commit.Signatures {
+		data = append(data, hashing.Bytes32(hashing.SoliditySHA3(sig)))
+	}
+	return hashing.SoliditySHA3(data...)
+}
+
+// checkCoordinator makes sure that hash was signed by the coordinator key
+func checkCoordinator(hash common.Hash, sig []byte, coordinatorKey common.Address) error {
+	if len(sig) == 0 {
+		return errors.New("message isn't signed by the coordinator")
+	}
+	signer, err := recoverSigner(hash, sig)
+	if err != nil {
+		return err
+	}
+	if signer != coordinatorKey {
+		return fmt.Errorf("message is signed by %v instead of the coordinator", signer)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package coordinator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func newSigners(t *testing.T, count int) ([]Signer, []common.Address) {
+	t.Helper()
+	signers := make([]Signer, 0, count)
+	keys := make([]common.Address, 0, count)
+	for i := 0; i < count; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer := NewKeySigner(key)
+		signers = append(signers, signer)
+		keys = append(keys, signer.Address())
+	}
+	return signers, keys
+}
+
+func signAll(t *testing.T, hash common.Hash, signers []Signer) [][]byte {
+	t.Helper()
+	sigs := make([][]byte, 0, len(signers))
+	for _, signer := range signers {
+		sig, err := signer.Sign(hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func TestOrderSignatures(t *testing.T) {
+	signers, keys := newSigners(t, 3)
+	hash := common.Hash{1, 2, 3}
+	sigs := signAll(t, hash, signers)
+
+	shuffled := [][]byte{sigs[2], sigs[0], sigs[1]}
+	ordered, err := orderSignatures(hash, shuffled, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sigs {
+		if !bytes.Equal(ordered[i], sigs[i]) {
+			t.Error("signature", i, "is out of order")
+		}
+	}
+
+	if _, err := orderSignatures(hash, sigs[:2], keys); err == nil {
+		t.Error("accepted assertion missing a signature")
+	}
+	if _, err := orderSignatures(common.Hash{4}, sigs, keys); err == nil {
+		t.Error("accepted signatures of a different assertion")
+	}
+	outsiders, _ := newSigners(t, 1)
+	withOutsider := append(signAll(t, hash, outsiders), sigs[:2]...)
+	if _, err := orderSignatures(hash, withOutsider, keys); err == nil {
+		t.Error("accepted a signature from outside the assert keys")
+	}
+}
@@ -0,0 +1,634 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eventdb is a persistent, reorg aware history of the events emitted
+// by rollup chains and their challenges. Events are stored by the L1 block
+// they were emitted in along with the hash of every indexed block so that a
+// reorg can be rolled back to the point where the chains diverged.
+package eventdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+var (
+	metadataKey      = []byte("metadata")
+	blockPrefix      = []byte("b")
+	eventPrefix      = []byte("e")
+	contractPrefix   = []byte("c")
+	stakerPrefix     = []byte("s")
+	challengePrefix  = []byte("C")
+	completionPrefix = []byte("o")
+	watchPrefix      = []byte("w")
+)
+
+// WatchKind is the type of contract a watch follows
+type WatchKind uint32
+
+const (
+	RollupWatch WatchKind = iota
+	InboxTopChallengeWatch
+	MessagesChallengeWatch
+	ExecutionChallengeWatch
+)
+
+func (k WatchKind) String() string {
+	switch k {
+	case RollupWatch:
+		return "Rollup"
+	case InboxTopChallengeWatch:
+		return "InboxTopChallenge"
+	case MessagesChallengeWatch:
+		return "MessagesChallenge"
+	case ExecutionChallengeWatch:
+		return "ExecutionChallenge"
+	default:
+		return "Unknown"
+	}
+}
+
+func challengeWatchKind(challengeType valprotocol.ChildType) (WatchKind, error) {
+	switch challengeType {
+	case valprotocol.InvalidInboxTopChildType:
+		return InboxTopChallengeWatch, nil
+	case valprotocol.InvalidMessagesChildType:
+		return MessagesChallengeWatch, nil
+	case valprotocol.InvalidExecutionChildType:
+		return ExecutionChallengeWatch, nil
+	default:
+		return 0, fmt.Errorf("unknown challenge type %v", challengeType)
+	}
+}
+
+// Watch is a contract whose events are indexed. A watch on a challenge ends
+// at the block where the rollup reported the challenge's completion.
+type Watch struct {
+	Contract common.Address
+	Kind     WatchKind
+	Since    *common.TimeBlocks
+	Until    *common.TimeBlocks
+}
+
+// Active returns whether the contract can still emit events
+func (w Watch) Active() bool {
+	return w.Until == nil
+}
+
+// Challenge is a challenge started on a rollup along with its outcome, if
+// it's been resolved
+type Challenge struct {
+	Rollup    common.Address
+	Started   arbbridge.ChallengeStartedEvent
+	Completed *arbbridge.ChallengeCompletedEvent
+}
+
+// EventDB stores the events of the watched contracts. It's safe for
+// concurrent use, but blocks must be added from a single goroutine.
+type EventDB struct {
+	db ethdb.KeyValueStore
+
+	sync.RWMutex
+	head *common.BlockId
+}
+
+// Open returns an index stored in a leveldb database at the given path,
+// creating it if it doesn't exist
+func Open(path string) (*EventDB, error) {
+	db, err := leveldb.New(path, 16, 16, "")
+	if err != nil {
+		return nil, err
+	}
+	return New(db)
+}
+
+// NewMemory returns an index which is lost when the process exits
+func NewMemory() *EventDB {
+	edb, _ := New(memorydb.New()) // an empty store has no metadata to fail on
+	return edb
+}
+
+// New returns an index backed by the given store, picking up where any
+// previous index in it left off
+func New(db ethdb.KeyValueStore) (*EventDB, error) {
+	edb := &EventDB{db: db}
+	data, err := edb.getOptional(metadataKey)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		buf := &common.BlockIdBuf{}
+		if err := proto.Unmarshal(data, buf); err != nil {
+			return nil, err
+		}
+		edb.head = buf.Unmarshal()
+	}
+	return edb, nil
+}
+
+// Close closes the underlying store
+func (edb *EventDB) Close() error {
+	return edb.db.Close()
+}
+
+// Head returns the most recent block in the index, or nil if no contracts
+// are watched yet
+func (edb *EventDB) Head() *common.BlockId {
+	edb.RLock()
+	defer edb.RUnlock()
+	return edb.head
+}
+
+// BlockHash returns the hash of the indexed block at the given height
+func (edb *EventDB) BlockHash(height *common.TimeBlocks) (common.Hash, bool, error) {
+	data, err := edb.getOptional(blockKey(blockHeight(height)))
+	if err != nil || data == nil {
+		return common.Hash{}, false, err
+	}
+	var hash common.Hash
+	copy(hash[:], data)
+	return hash, true, nil
+}
+
+// AddWatch starts indexing the given contract from the block after the
+// current head. The head must already have been rewound to before the
+// contract's creation.
+func (edb *EventDB) AddWatch(contract common.Address, kind WatchKind, head *common.BlockId) error {
+	edb.Lock()
+	defer edb.Unlock()
+	if edb.head != nil && edb.head.Height.Cmp(head.Height) != 0 {
+		return errors.New("watch must start at the head of the index")
+	}
+	batch := edb.db.NewBatch()
+	since := common.NewTimeBlocks(new(big.Int).Add(head.Height.AsInt(), big.NewInt(1)))
+	if err := putWatch(batch, Watch{Contract: contract, Kind: kind, Since: since}); err != nil {
+		return err
+	}
+	return edb.commit(batch, head)
+}
+
+// Watches returns every contract that is or was indexed
+func (edb *EventDB) Watches() ([]Watch, error) {
+	it := edb.db.NewIteratorWithPrefix(watchPrefix)
+	defer it.Release()
+	var watches []Watch
+	for it.Next() {
+		var contract common.Address
+		copy(contract[:], it.Key()[len(watchPrefix):])
+		watch, err := unmarshalWatch(contract, it.Value())
+		if err != nil {
+			return nil, err
+		}
+		watches = append(watches, watch)
+	}
+	return watches, it.Error()
+}
+
+// Watch returns the watch on the given contract, if there is one
+func (edb *EventDB) Watch(contract common.Address) (Watch, bool, error) {
+	data, err := edb.getOptional(watchKey(contract))
+	if err != nil || data == nil {
+		return Watch{}, false, err
+	}
+	watch, err := unmarshalWatch(contract, data)
+	return watch, err == nil, err
+}
+
+// AddBlock records the events emitted in the block following the head and
+// makes it the new head. Challenges started in the block are watched from
+// then on and challenges completed in it stop being watched.
+func (edb *EventDB) AddBlock(blockId *common.BlockId, records []Record) error {
+	edb.Lock()
+	defer edb.Unlock()
+	if edb.head == nil {
+		return errors.New("can't add a block before watching a contract")
+	}
+	expected := new(big.Int).Add(edb.head.Height.AsInt(), big.NewInt(1))
+	if blockId.Height.AsInt().Cmp(expected) != 0 {
+		return fmt.Errorf("expected block %v but got %v", expected, blockId.Height)
+	}
+
+	records = append([]Record{}, records...)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Event.GetChainInfo().LogIndex < records[j].Event.GetChainInfo().LogIndex
+	})
+
+	height := blockHeight(blockId.Height)
+	batch := edb.db.NewBatch()
+	if err := batch.Put(blockKey(height), blockId.HeaderHash.Bytes()); err != nil {
+		return err
+	}
+	for _, record := range records {
+		buf, err := record.MarshalToBuf()
+		if err != nil {
+			return err
+		}
+		data, err := proto.Marshal(buf)
+		if err != nil {
+			return err
+		}
+		logIndex := uint64(record.Event.GetChainInfo().LogIndex)
+		if err := batch.Put(eventKey(height, logIndex), data); err != nil {
+			return err
+		}
+	}
+	for _, key := range indexKeys(height, records) {
+		if err := batch.Put(key, nil); err != nil {
+			return err
+		}
+	}
+
+	watches := make(map[common.Address]Watch)
+	for _, record := range records {
+		switch ev := record.Event.(type) {
+		case arbbridge.ChallengeStartedEvent:
+			kind, err := challengeWatchKind(ev.ChallengeType)
+			if err != nil {
+				return err
+			}
+			watches[ev.ChallengeContract] = Watch{Contract: ev.ChallengeContract, Kind: kind, Since: blockId.Height}
+		case arbbridge.ChallengeCompletedEvent:
+			logIndex := uint64(ev.LogIndex)
+			if err := batch.Put(completionKey(ev.ChallengeContract), eventKey(height, logIndex)); err != nil {
+				return err
+			}
+			watch, found := watches[ev.ChallengeContract]
+			if !found {
+				var err error
+				watch, found, err = edb.Watch(ev.ChallengeContract)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return fmt.Errorf("challenge %v completed without starting", ev.ChallengeContract)
+				}
+			}
+			watch.Until = blockId.Height
+			watches[ev.ChallengeContract] = watch
+		}
+	}
+	for _, watch := range watches {
+		if err := putWatch(batch, watch); err != nil {
+			return err
+		}
+	}
+	return edb.commit(batch, blockId)
+}
+
+// Rewind removes every block after the given one, which becomes the new
+// head. Watches on challenges started after it are removed and watches on
+// challenges completed after it become active again. Rollups watched from
+// after it are kept and watched from the new head instead, since they were
+// added by the caller rather than discovered in the removed blocks.
+func (edb *EventDB) Rewind(head *common.BlockId) error {
+	edb.Lock()
+	defer edb.Unlock()
+	if edb.head == nil || edb.head.Height.Cmp(head.Height) <= 0 {
+		return edb.commit(edb.db.NewBatch(), head)
+	}
+
+	start := blockHeight(head.Height) + 1
+	batch := edb.db.NewBatch()
+	for height := start; height <= blockHeight(edb.head.Height); height++ {
+		records, err := edb.blockEvents(height)
+		if err != nil {
+			return err
+		}
+		for _, key := range indexKeys(height, records) {
+			if err := batch.Delete(key); err != nil {
+				return err
+			}
+		}
+		for _, record := range records {
+			if ev, ok := record.Event.(arbbridge.ChallengeCompletedEvent); ok {
+				if err := batch.Delete(completionKey(ev.ChallengeContract)); err != nil {
+					return err
+				}
+			}
+			logIndex := uint64(record.Event.GetChainInfo().LogIndex)
+			if err := batch.Delete(eventKey(height, logIndex)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(blockKey(height)); err != nil {
+			return err
+		}
+	}
+
+	watches, err := edb.Watches()
+	if err != nil {
+		return err
+	}
+	for _, watch := range watches {
+		switch {
+		case watch.Kind == RollupWatch && blockHeight(watch.Since) > start:
+			watch.Since = common.NewTimeBlocks(new(big.Int).SetUint64(start))
+			if err := putWatch(batch, watch); err != nil {
+				return err
+			}
+		case watch.Kind != RollupWatch && blockHeight(watch.Since) >= start:
+			if err := batch.Delete(watchKey(watch.Contract)); err != nil {
+				return err
+			}
+		case watch.Until != nil && blockHeight(watch.Until) >= start:
+			watch.Until = nil
+			if err := putWatch(batch, watch); err != nil {
+				return err
+			}
+		}
+	}
+	return edb.commit(batch, head)
+}
+
+// EventsInRange returns the events emitted between the given heights
+// inclusive. A nil bound isn't used to filter.
+func (edb *EventDB) EventsInRange(from, to *common.TimeBlocks) ([]Record, error) {
+	return edb.scan(eventPrefix, from, to, false)
+}
+
+// ContractEvents returns the events of the given rollup or challenge
+// between the given heights inclusive. The messages delivered to a rollup's
+// inbox are included with its events.
+func (edb *EventDB) ContractEvents(contract common.Address, from, to *common.TimeBlocks) ([]Record, error) {
+	return edb.scan(makeKey(contractPrefix, contract[:]), from, to, true)
+}
+
+// StakerEvents returns the events involving the given staker between the
+// given heights inclusive, including the challenges they took part in and
+// the assertions they made
+func (edb *EventDB) StakerEvents(staker common.Address, from, to *common.TimeBlocks) ([]Record, error) {
+	return edb.scan(makeKey(stakerPrefix, staker[:]), from, to, true)
+}
+
+// Assertions returns the assertions made by the given staker between the
+// given heights inclusive
+func (edb *EventDB) Assertions(staker common.Address, from, to *common.TimeBlocks) ([]Record, error) {
+	records, err := edb.StakerEvents(staker, from, to)
+	if err != nil {
+		return nil, err
+	}
+	assertions := make([]Record, 0)
+	for _, record := range records {
+		if _, ok := record.Event.(arbbridge.AssertedEvent); ok {
+			assertions = append(assertions, record)
+		}
+	}
+	return assertions, nil
+}
+
+// Challenges returns the challenges started between the given heights
+// inclusive along with their outcomes
+func (edb *EventDB) Challenges(from, to *common.TimeBlocks) ([]Challenge, error) {
+	records, err := edb.scan(challengePrefix, from, to, true)
+	if err != nil {
+		return nil, err
+	}
+	challenges := make([]Challenge, 0, len(records))
+	for _, record := range records {
+		started, ok := record.Event.(arbbridge.ChallengeStartedEvent)
+		if !ok {
+			return nil, errors.New("corrupt event index challenge entry")
+		}
+		challenge := Challenge{Rollup: record.Contract, Started: started}
+		key, err := edb.getOptional(completionKey(started.ChallengeContract))
+		if err != nil {
+			return nil, err
+		}
+		if key != nil {
+			completion, err := edb.getRecord(key)
+			if err != nil {
+				return nil, err
+			}
+			completed, ok := completion.Event.(arbbridge.ChallengeCompletedEvent)
+			if !ok {
+				return nil, errors.New("corrupt event index completion entry")
+			}
+			challenge.Completed = &completed
+		}
+		challenges = append(challenges, challenge)
+	}
+	return challenges, nil
+}
+
+// scan returns the events under the given prefix between the given
+// heights. Keys are either events themselves or, for an index, the prefix
+// followed by the height and log index of an event.
+func (edb *EventDB) scan(prefix []byte, from, to *common.TimeBlocks, index bool) ([]Record, error) {
+	start := uint64(0)
+	if from != nil {
+		start = blockHeight(from)
+	}
+	records := make([]Record, 0)
+	it := edb.db.NewIteratorWithStart(makeKey(prefix, encodeUint64(start)))
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+16 || string(key[:len(prefix)]) != string(prefix) {
+			break
+		}
+		height := binary.BigEndian.Uint64(key[len(prefix):])
+		if to != nil && height > blockHeight(to) {
+			break
+		}
+		var record Record
+		var err error
+		if index {
+			record, err = edb.getRecord(makeKey(eventPrefix, key[len(prefix):]))
+		} else {
+			record, err = unmarshalRecord(it.Value())
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, it.Error()
+}
+
+func (edb *EventDB) blockEvents(height uint64) ([]Record, error) {
+	it := edb.db.NewIteratorWithPrefix(makeKey(eventPrefix, encodeUint64(height)))
+	defer it.Release()
+	var records []Record
+	for it.Next() {
+		record, err := unmarshalRecord(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, it.Error()
+}
+
+func (edb *EventDB) getRecord(key []byte) (Record, error) {
+	data, err := edb.db.Get(key)
+	if err != nil {
+		return Record{}, err
+	}
+	return unmarshalRecord(data)
+}
+
+func (edb *EventDB) getOptional(key []byte) ([]byte, error) {
+	has, err := edb.db.Has(key)
+	if err != nil || !has {
+		return nil, err
+	}
+	return edb.db.Get(key)
+}
+
+// commit writes the batch along with the new head and only then makes the
+// new head visible
+func (edb *EventDB) commit(batch ethdb.Batch, head *common.BlockId) error {
+	data, err := proto.Marshal(head.MarshalToBuf())
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(metadataKey, data); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	edb.head = head.Clone()
+	return nil
+}
+
+// indexKeys returns the index entries for the events of a block. Asserted
+// events don't name the asserter, but makeAssertion moves the asserter's
+// stake to the new node in the same transaction.
+func indexKeys(height uint64, records []Record) [][]byte {
+	asserters := make(map[common.Hash]common.Address)
+	for _, record := range records {
+		if ev, ok := record.Event.(arbbridge.StakeMovedEvent); ok {
+			asserters[ev.TxHash] = ev.Staker
+		}
+	}
+
+	var keys [][]byte
+	for _, record := range records {
+		info := record.Event.GetChainInfo()
+		position := makeKey(encodeUint64(height), encodeUint64(uint64(info.LogIndex)))
+		keys = append(keys, makeKey(contractPrefix, record.Contract[:], position))
+		var stakers []common.Address
+		switch ev := record.Event.(type) {
+		case arbbridge.StakeCreatedEvent:
+			stakers = append(stakers, ev.Staker)
+		case arbbridge.StakeMovedEvent:
+			stakers = append(stakers, ev.Staker)
+		case arbbridge.StakeRefundedEvent:
+			stakers = append(stakers, ev.Staker)
+		case arbbridge.AssertedEvent:
+			if asserter, ok := asserters[info.TxHash]; ok {
+				stakers = append(stakers, asserter)
+			}
+		case arbbridge.ChallengeStartedEvent:
+			stakers = append(stakers, ev.Asserter, ev.Challenger)
+			keys = append(keys, makeKey(challengePrefix, position))
+		case arbbridge.ChallengeCompletedEvent:
+			stakers = append(stakers, ev.Winner, ev.Loser)
+		}
+		for _, staker := range stakers {
+			keys = append(keys, makeKey(stakerPrefix, staker[:], position))
+		}
+	}
+	return keys
+}
+
+func unmarshalRecord(data []byte) (Record, error) {
+	buf := &EventBuf{}
+	if err := proto.Unmarshal(data, buf); err != nil {
+		return Record{}, err
+	}
+	return buf.Unmarshal()
+}
+
+func putWatch(batch ethdb.Batch, watch Watch) error {
+	buf := &WatchBuf{
+		Kind:  uint32(watch.Kind),
+		Since: watch.Since.Marshal(),
+	}
+	if watch.Until != nil {
+		buf.Until = watch.Until.Marshal()
+	}
+	data, err := proto.Marshal(buf)
+	if err != nil {
+		return err
+	}
+	return batch.Put(watchKey(watch.Contract), data)
+}
+
+func unmarshalWatch(contract common.Address, data []byte) (Watch, error) {
+	buf := &WatchBuf{}
+	if err := proto.Unmarshal(data, buf); err != nil {
+		return Watch{}, err
+	}
+	watch := Watch{
+		Contract: contract,
+		Kind:     WatchKind(buf.Kind),
+		Since:    buf.Since.Unmarshal(),
+	}
+	if buf.Until != nil {
+		watch.Until = buf.Until.Unmarshal()
+	}
+	return watch, nil
+}
+
+func blockHeight(height *common.TimeBlocks) uint64 {
+	return height.AsInt().Uint64()
+}
+
+func encodeUint64(i uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, i)
+	return buf
+}
+
+func makeKey(parts ...[]byte) []byte {
+	var key []byte
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+func blockKey(height uint64) []byte {
+	return makeKey(blockPrefix, encodeUint64(height))
+}
+
+func eventKey(height uint64, logIndex uint64) []byte {
+	return makeKey(eventPrefix, encodeUint64(height), encodeUint64(logIndex))
+}
+
+func completionKey(challenge common.Address) []byte {
+	return makeKey(completionPrefix, challenge[:])
+}
+
+func watchKey(contract common.Address) []byte {
+	return makeKey(watchPrefix, contract[:])
+}
@@ -0,0 +1,1421 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: eventdb.proto
+
+package eventdb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	valprotocol "github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ChainInfoBuf struct {
+	BlockId              *common.BlockIdBuf `protobuf:"bytes,1,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	LogIndex             uint64             `protobuf:"varint,2,opt,name=logIndex,proto3" json:"logIndex,omitempty"`
+	TxHash               *common.HashBuf    `protobuf:"bytes,3,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ChainInfoBuf) Reset()         { *m = ChainInfoBuf{} }
+func (m *ChainInfoBuf) String() string { return proto.CompactTextString(m) }
+func (*ChainInfoBuf) ProtoMessage()    {}
+func (*ChainInfoBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{0}
+}
+
+func (m *ChainInfoBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChainInfoBuf.Unmarshal(m, b)
+}
+func (m *ChainInfoBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChainInfoBuf.Marshal(b, m, deterministic)
+}
+func (m *ChainInfoBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChainInfoBuf.Merge(m, src)
+}
+func (m *ChainInfoBuf) XXX_Size() int {
+	return xxx_messageInfo_ChainInfoBuf.Size(m)
+}
+func (m *ChainInfoBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChainInfoBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChainInfoBuf proto.InternalMessageInfo
+
+func (m *ChainInfoBuf) GetBlockId() *common.BlockIdBuf {
+	if m != nil {
+		return m.BlockId
+	}
+	return nil
+}
+
+func (m *ChainInfoBuf) GetLogIndex() uint64 {
+	if m != nil {
+		return m.LogIndex
+	}
+	return 0
+}
+
+func (m *ChainInfoBuf) GetTxHash() *common.HashBuf {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+type StakeCreatedBuf struct {
+	Staker               *common.AddressBuf `protobuf:"bytes,1,opt,name=staker,proto3" json:"staker,omitempty"`
+	NodeHash             *common.HashBuf    `protobuf:"bytes,2,opt,name=nodeHash,proto3" json:"nodeHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StakeCreatedBuf) Reset()         { *m = StakeCreatedBuf{} }
+func (m *StakeCreatedBuf) String() string { return proto.CompactTextString(m) }
+func (*StakeCreatedBuf) ProtoMessage()    {}
+func (*StakeCreatedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{1}
+}
+
+func (m *StakeCreatedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeCreatedBuf.Unmarshal(m, b)
+}
+func (m *StakeCreatedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeCreatedBuf.Marshal(b, m, deterministic)
+}
+func (m *StakeCreatedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeCreatedBuf.Merge(m, src)
+}
+func (m *StakeCreatedBuf) XXX_Size() int {
+	return xxx_messageInfo_StakeCreatedBuf.Size(m)
+}
+func (m *StakeCreatedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeCreatedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeCreatedBuf proto.InternalMessageInfo
+
+func (m *StakeCreatedBuf) GetStaker() *common.AddressBuf {
+	if m != nil {
+		return m.Staker
+	}
+	return nil
+}
+
+func (m *StakeCreatedBuf) GetNodeHash() *common.HashBuf {
+	if m != nil {
+		return m.NodeHash
+	}
+	return nil
+}
+
+type ChallengeStartedBuf struct {
+	Asserter             *common.AddressBuf `protobuf:"bytes,1,opt,name=asserter,proto3" json:"asserter,omitempty"`
+	Challenger           *common.AddressBuf `protobuf:"bytes,2,opt,name=challenger,proto3" json:"challenger,omitempty"`
+	ChallengeType        uint32             `protobuf:"varint,3,opt,name=challengeType,proto3" json:"challengeType,omitempty"`
+	ChallengeContract    *common.AddressBuf `protobuf:"bytes,4,opt,name=challengeContract,proto3" json:"challengeContract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ChallengeStartedBuf) Reset()         { *m = ChallengeStartedBuf{} }
+func (m *ChallengeStartedBuf) String() string { return proto.CompactTextString(m) }
+func (*ChallengeStartedBuf) ProtoMessage()    {}
+func (*ChallengeStartedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{2}
+}
+
+func (m *ChallengeStartedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengeStartedBuf.Unmarshal(m, b)
+}
+func (m *ChallengeStartedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengeStartedBuf.Marshal(b, m, deterministic)
+}
+func (m *ChallengeStartedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengeStartedBuf.Merge(m, src)
+}
+func (m *ChallengeStartedBuf) XXX_Size() int {
+	return xxx_messageInfo_ChallengeStartedBuf.Size(m)
+}
+func (m *ChallengeStartedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengeStartedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengeStartedBuf proto.InternalMessageInfo
+
+func (m *ChallengeStartedBuf) GetAsserter() *common.AddressBuf {
+	if m != nil {
+		return m.Asserter
+	}
+	return nil
+}
+
+func (m *ChallengeStartedBuf) GetChallenger() *common.AddressBuf {
+	if m != nil {
+		return m.Challenger
+	}
+	return nil
+}
+
+func (m *ChallengeStartedBuf) GetChallengeType() uint32 {
+	if m != nil {
+		return m.ChallengeType
+	}
+	return 0
+}
+
+func (m *ChallengeStartedBuf) GetChallengeContract() *common.AddressBuf {
+	if m != nil {
+		return m.ChallengeContract
+	}
+	return nil
+}
+
+type ChallengeCompletedBuf struct {
+	Winner               *common.AddressBuf `protobuf:"bytes,1,opt,name=winner,proto3" json:"winner,omitempty"`
+	Loser                *common.AddressBuf `protobuf:"bytes,2,opt,name=loser,proto3" json:"loser,omitempty"`
+	ChallengeContract    *common.AddressBuf `protobuf:"bytes,3,opt,name=challengeContract,proto3" json:"challengeContract,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ChallengeCompletedBuf) Reset()         { *m = ChallengeCompletedBuf{} }
+func (m *ChallengeCompletedBuf) String() string { return proto.CompactTextString(m) }
+func (*ChallengeCompletedBuf) ProtoMessage()    {}
+func (*ChallengeCompletedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{3}
+}
+
+func (m *ChallengeCompletedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengeCompletedBuf.Unmarshal(m, b)
+}
+func (m *ChallengeCompletedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengeCompletedBuf.Marshal(b, m, deterministic)
+}
+func (m *ChallengeCompletedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengeCompletedBuf.Merge(m, src)
+}
+func (m *ChallengeCompletedBuf) XXX_Size() int {
+	return xxx_messageInfo_ChallengeCompletedBuf.Size(m)
+}
+func (m *ChallengeCompletedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengeCompletedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengeCompletedBuf proto.InternalMessageInfo
+
+func (m *ChallengeCompletedBuf) GetWinner() *common.AddressBuf {
+	if m != nil {
+		return m.Winner
+	}
+	return nil
+}
+
+func (m *ChallengeCompletedBuf) GetLoser() *common.AddressBuf {
+	if m != nil {
+		return m.Loser
+	}
+	return nil
+}
+
+func (m *ChallengeCompletedBuf) GetChallengeContract() *common.AddressBuf {
+	if m != nil {
+		return m.ChallengeContract
+	}
+	return nil
+}
+
+type StakeRefundedBuf struct {
+	Staker               *common.AddressBuf `protobuf:"bytes,1,opt,name=staker,proto3" json:"staker,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StakeRefundedBuf) Reset()         { *m = StakeRefundedBuf{} }
+func (m *StakeRefundedBuf) String() string { return proto.CompactTextString(m) }
+func (*StakeRefundedBuf) ProtoMessage()    {}
+func (*StakeRefundedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{4}
+}
+
+func (m *StakeRefundedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeRefundedBuf.Unmarshal(m, b)
+}
+func (m *StakeRefundedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeRefundedBuf.Marshal(b, m, deterministic)
+}
+func (m *StakeRefundedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeRefundedBuf.Merge(m, src)
+}
+func (m *StakeRefundedBuf) XXX_Size() int {
+	return xxx_messageInfo_StakeRefundedBuf.Size(m)
+}
+func (m *StakeRefundedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeRefundedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeRefundedBuf proto.InternalMessageInfo
+
+func (m *StakeRefundedBuf) GetStaker() *common.AddressBuf {
+	if m != nil {
+		return m.Staker
+	}
+	return nil
+}
+
+type PrunedBuf struct {
+	Leaf                 *common.HashBuf `protobuf:"bytes,1,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *PrunedBuf) Reset()         { *m = PrunedBuf{} }
+func (m *PrunedBuf) String() string { return proto.CompactTextString(m) }
+func (*PrunedBuf) ProtoMessage()    {}
+func (*PrunedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{5}
+}
+
+func (m *PrunedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrunedBuf.Unmarshal(m, b)
+}
+func (m *PrunedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrunedBuf.Marshal(b, m, deterministic)
+}
+func (m *PrunedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrunedBuf.Merge(m, src)
+}
+func (m *PrunedBuf) XXX_Size() int {
+	return xxx_messageInfo_PrunedBuf.Size(m)
+}
+func (m *PrunedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrunedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrunedBuf proto.InternalMessageInfo
+
+func (m *PrunedBuf) GetLeaf() *common.HashBuf {
+	if m != nil {
+		return m.Leaf
+	}
+	return nil
+}
+
+type StakeMovedBuf struct {
+	Staker               *common.AddressBuf `protobuf:"bytes,1,opt,name=staker,proto3" json:"staker,omitempty"`
+	Location             *common.HashBuf    `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *StakeMovedBuf) Reset()         { *m = StakeMovedBuf{} }
+func (m *StakeMovedBuf) String() string { return proto.CompactTextString(m) }
+func (*StakeMovedBuf) ProtoMessage()    {}
+func (*StakeMovedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{6}
+}
+
+func (m *StakeMovedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeMovedBuf.Unmarshal(m, b)
+}
+func (m *StakeMovedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeMovedBuf.Marshal(b, m, deterministic)
+}
+func (m *StakeMovedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeMovedBuf.Merge(m, src)
+}
+func (m *StakeMovedBuf) XXX_Size() int {
+	return xxx_messageInfo_StakeMovedBuf.Size(m)
+}
+func (m *StakeMovedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeMovedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeMovedBuf proto.InternalMessageInfo
+
+func (m *StakeMovedBuf) GetStaker() *common.AddressBuf {
+	if m != nil {
+		return m.Staker
+	}
+	return nil
+}
+
+func (m *StakeMovedBuf) GetLocation() *common.HashBuf {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+type AssertedBuf struct {
+	PrevLeafHash         *common.HashBuf                 `protobuf:"bytes,1,opt,name=prevLeafHash,proto3" json:"prevLeafHash,omitempty"`
+	Params               *valprotocol.AssertionParamsBuf `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	Claim                *valprotocol.AssertionClaimBuf  `protobuf:"bytes,3,opt,name=claim,proto3" json:"claim,omitempty"`
+	MaxInboxTop          *common.HashBuf                 `protobuf:"bytes,4,opt,name=maxInboxTop,proto3" json:"maxInboxTop,omitempty"`
+	MaxInboxCount        *common.BigIntegerBuf           `protobuf:"bytes,5,opt,name=maxInboxCount,proto3" json:"maxInboxCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *AssertedBuf) Reset()         { *m = AssertedBuf{} }
+func (m *AssertedBuf) String() string { return proto.CompactTextString(m) }
+func (*AssertedBuf) ProtoMessage()    {}
+func (*AssertedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{7}
+}
+
+func (m *AssertedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AssertedBuf.Unmarshal(m, b)
+}
+func (m *AssertedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AssertedBuf.Marshal(b, m, deterministic)
+}
+func (m *AssertedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AssertedBuf.Merge(m, src)
+}
+func (m *AssertedBuf) XXX_Size() int {
+	return xxx_messageInfo_AssertedBuf.Size(m)
+}
+func (m *AssertedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_AssertedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AssertedBuf proto.InternalMessageInfo
+
+func (m *AssertedBuf) GetPrevLeafHash() *common.HashBuf {
+	if m != nil {
+		return m.PrevLeafHash
+	}
+	return nil
+}
+
+func (m *AssertedBuf) GetParams() *valprotocol.AssertionParamsBuf {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (m *AssertedBuf) GetClaim() *valprotocol.AssertionClaimBuf {
+	if m != nil {
+		return m.Claim
+	}
+	return nil
+}
+
+func (m *AssertedBuf) GetMaxInboxTop() *common.HashBuf {
+	if m != nil {
+		return m.MaxInboxTop
+	}
+	return nil
+}
+
+func (m *AssertedBuf) GetMaxInboxCount() *common.BigIntegerBuf {
+	if m != nil {
+		return m.MaxInboxCount
+	}
+	return nil
+}
+
+type ConfirmedBuf struct {
+	NodeHash             *common.HashBuf `protobuf:"bytes,1,opt,name=nodeHash,proto3" json:"nodeHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ConfirmedBuf) Reset()         { *m = ConfirmedBuf{} }
+func (m *ConfirmedBuf) String() string { return proto.CompactTextString(m) }
+func (*ConfirmedBuf) ProtoMessage()    {}
+func (*ConfirmedBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{8}
+}
+
+func (m *ConfirmedBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfirmedBuf.Unmarshal(m, b)
+}
+func (m *ConfirmedBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfirmedBuf.Marshal(b, m, deterministic)
+}
+func (m *ConfirmedBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfirmedBuf.Merge(m, src)
+}
+func (m *ConfirmedBuf) XXX_Size() int {
+	return xxx_messageInfo_ConfirmedBuf.Size(m)
+}
+func (m *ConfirmedBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfirmedBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfirmedBuf proto.InternalMessageInfo
+
+func (m *ConfirmedBuf) GetNodeHash() *common.HashBuf {
+	if m != nil {
+		return m.NodeHash
+	}
+	return nil
+}
+
+type ConfirmedAssertionBuf struct {
+	LogsAccHash          []*common.HashBuf `protobuf:"bytes,1,rep,name=logsAccHash,proto3" json:"logsAccHash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ConfirmedAssertionBuf) Reset()         { *m = ConfirmedAssertionBuf{} }
+func (m *ConfirmedAssertionBuf) String() string { return proto.CompactTextString(m) }
+func (*ConfirmedAssertionBuf) ProtoMessage()    {}
+func (*ConfirmedAssertionBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{9}
+}
+
+func (m *ConfirmedAssertionBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConfirmedAssertionBuf.Unmarshal(m, b)
+}
+func (m *ConfirmedAssertionBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConfirmedAssertionBuf.Marshal(b, m, deterministic)
+}
+func (m *ConfirmedAssertionBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConfirmedAssertionBuf.Merge(m, src)
+}
+func (m *ConfirmedAssertionBuf) XXX_Size() int {
+	return xxx_messageInfo_ConfirmedAssertionBuf.Size(m)
+}
+func (m *ConfirmedAssertionBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConfirmedAssertionBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConfirmedAssertionBuf proto.InternalMessageInfo
+
+func (m *ConfirmedAssertionBuf) GetLogsAccHash() []*common.HashBuf {
+	if m != nil {
+		return m.LogsAccHash
+	}
+	return nil
+}
+
+type MessageDeliveredBuf struct {
+	MessageType uint32 `protobuf:"varint,1,opt,name=messageType,proto3" json:"messageType,omitempty"`
+	// The message's checkpoint value, marshalled
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MessageDeliveredBuf) Reset()         { *m = MessageDeliveredBuf{} }
+func (m *MessageDeliveredBuf) String() string { return proto.CompactTextString(m) }
+func (*MessageDeliveredBuf) ProtoMessage()    {}
+func (*MessageDeliveredBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{10}
+}
+
+func (m *MessageDeliveredBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessageDeliveredBuf.Unmarshal(m, b)
+}
+func (m *MessageDeliveredBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessageDeliveredBuf.Marshal(b, m, deterministic)
+}
+func (m *MessageDeliveredBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessageDeliveredBuf.Merge(m, src)
+}
+func (m *MessageDeliveredBuf) XXX_Size() int {
+	return xxx_messageInfo_MessageDeliveredBuf.Size(m)
+}
+func (m *MessageDeliveredBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessageDeliveredBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessageDeliveredBuf proto.InternalMessageInfo
+
+func (m *MessageDeliveredBuf) GetMessageType() uint32 {
+	if m != nil {
+		return m.MessageType
+	}
+	return 0
+}
+
+func (m *MessageDeliveredBuf) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type InitiateChallengeBuf struct {
+	Deadline             *common.TimeTicksBuf `protobuf:"bytes,1,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *InitiateChallengeBuf) Reset()         { *m = InitiateChallengeBuf{} }
+func (m *InitiateChallengeBuf) String() string { return proto.CompactTextString(m) }
+func (*InitiateChallengeBuf) ProtoMessage()    {}
+func (*InitiateChallengeBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{11}
+}
+
+func (m *InitiateChallengeBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InitiateChallengeBuf.Unmarshal(m, b)
+}
+func (m *InitiateChallengeBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InitiateChallengeBuf.Marshal(b, m, deterministic)
+}
+func (m *InitiateChallengeBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InitiateChallengeBuf.Merge(m, src)
+}
+func (m *InitiateChallengeBuf) XXX_Size() int {
+	return xxx_messageInfo_InitiateChallengeBuf.Size(m)
+}
+func (m *InitiateChallengeBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_InitiateChallengeBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InitiateChallengeBuf proto.InternalMessageInfo
+
+func (m *InitiateChallengeBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type AsserterTimeoutBuf struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AsserterTimeoutBuf) Reset()         { *m = AsserterTimeoutBuf{} }
+func (m *AsserterTimeoutBuf) String() string { return proto.CompactTextString(m) }
+func (*AsserterTimeoutBuf) ProtoMessage()    {}
+func (*AsserterTimeoutBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{12}
+}
+
+func (m *AsserterTimeoutBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AsserterTimeoutBuf.Unmarshal(m, b)
+}
+func (m *AsserterTimeoutBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AsserterTimeoutBuf.Marshal(b, m, deterministic)
+}
+func (m *AsserterTimeoutBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AsserterTimeoutBuf.Merge(m, src)
+}
+func (m *AsserterTimeoutBuf) XXX_Size() int {
+	return xxx_messageInfo_AsserterTimeoutBuf.Size(m)
+}
+func (m *AsserterTimeoutBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_AsserterTimeoutBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AsserterTimeoutBuf proto.InternalMessageInfo
+
+type ChallengerTimeoutBuf struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChallengerTimeoutBuf) Reset()         { *m = ChallengerTimeoutBuf{} }
+func (m *ChallengerTimeoutBuf) String() string { return proto.CompactTextString(m) }
+func (*ChallengerTimeoutBuf) ProtoMessage()    {}
+func (*ChallengerTimeoutBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{13}
+}
+
+func (m *ChallengerTimeoutBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengerTimeoutBuf.Unmarshal(m, b)
+}
+func (m *ChallengerTimeoutBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengerTimeoutBuf.Marshal(b, m, deterministic)
+}
+func (m *ChallengerTimeoutBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengerTimeoutBuf.Merge(m, src)
+}
+func (m *ChallengerTimeoutBuf) XXX_Size() int {
+	return xxx_messageInfo_ChallengerTimeoutBuf.Size(m)
+}
+func (m *ChallengerTimeoutBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengerTimeoutBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengerTimeoutBuf proto.InternalMessageInfo
+
+type ContinueChallengeBuf struct {
+	SegmentIndex         *common.BigIntegerBuf `protobuf:"bytes,1,opt,name=segmentIndex,proto3" json:"segmentIndex,omitempty"`
+	Deadline             *common.TimeTicksBuf  `protobuf:"bytes,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ContinueChallengeBuf) Reset()         { *m = ContinueChallengeBuf{} }
+func (m *ContinueChallengeBuf) String() string { return proto.CompactTextString(m) }
+func (*ContinueChallengeBuf) ProtoMessage()    {}
+func (*ContinueChallengeBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{14}
+}
+
+func (m *ContinueChallengeBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContinueChallengeBuf.Unmarshal(m, b)
+}
+func (m *ContinueChallengeBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContinueChallengeBuf.Marshal(b, m, deterministic)
+}
+func (m *ContinueChallengeBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContinueChallengeBuf.Merge(m, src)
+}
+func (m *ContinueChallengeBuf) XXX_Size() int {
+	return xxx_messageInfo_ContinueChallengeBuf.Size(m)
+}
+func (m *ContinueChallengeBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContinueChallengeBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContinueChallengeBuf proto.InternalMessageInfo
+
+func (m *ContinueChallengeBuf) GetSegmentIndex() *common.BigIntegerBuf {
+	if m != nil {
+		return m.SegmentIndex
+	}
+	return nil
+}
+
+func (m *ContinueChallengeBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type OneStepProofBuf struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OneStepProofBuf) Reset()         { *m = OneStepProofBuf{} }
+func (m *OneStepProofBuf) String() string { return proto.CompactTextString(m) }
+func (*OneStepProofBuf) ProtoMessage()    {}
+func (*OneStepProofBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{15}
+}
+
+func (m *OneStepProofBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OneStepProofBuf.Unmarshal(m, b)
+}
+func (m *OneStepProofBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OneStepProofBuf.Marshal(b, m, deterministic)
+}
+func (m *OneStepProofBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OneStepProofBuf.Merge(m, src)
+}
+func (m *OneStepProofBuf) XXX_Size() int {
+	return xxx_messageInfo_OneStepProofBuf.Size(m)
+}
+func (m *OneStepProofBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_OneStepProofBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OneStepProofBuf proto.InternalMessageInfo
+
+type InboxTopBisectionBuf struct {
+	ChainHashes          []*common.HashBuf     `protobuf:"bytes,1,rep,name=chainHashes,proto3" json:"chainHashes,omitempty"`
+	TotalLength          *common.BigIntegerBuf `protobuf:"bytes,2,opt,name=totalLength,proto3" json:"totalLength,omitempty"`
+	Deadline             *common.TimeTicksBuf  `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *InboxTopBisectionBuf) Reset()         { *m = InboxTopBisectionBuf{} }
+func (m *InboxTopBisectionBuf) String() string { return proto.CompactTextString(m) }
+func (*InboxTopBisectionBuf) ProtoMessage()    {}
+func (*InboxTopBisectionBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{16}
+}
+
+func (m *InboxTopBisectionBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InboxTopBisectionBuf.Unmarshal(m, b)
+}
+func (m *InboxTopBisectionBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InboxTopBisectionBuf.Marshal(b, m, deterministic)
+}
+func (m *InboxTopBisectionBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InboxTopBisectionBuf.Merge(m, src)
+}
+func (m *InboxTopBisectionBuf) XXX_Size() int {
+	return xxx_messageInfo_InboxTopBisectionBuf.Size(m)
+}
+func (m *InboxTopBisectionBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_InboxTopBisectionBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InboxTopBisectionBuf proto.InternalMessageInfo
+
+func (m *InboxTopBisectionBuf) GetChainHashes() []*common.HashBuf {
+	if m != nil {
+		return m.ChainHashes
+	}
+	return nil
+}
+
+func (m *InboxTopBisectionBuf) GetTotalLength() *common.BigIntegerBuf {
+	if m != nil {
+		return m.TotalLength
+	}
+	return nil
+}
+
+func (m *InboxTopBisectionBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type MessagesBisectionBuf struct {
+	ChainHashes          []*common.HashBuf     `protobuf:"bytes,1,rep,name=chainHashes,proto3" json:"chainHashes,omitempty"`
+	SegmentHashes        []*common.HashBuf     `protobuf:"bytes,2,rep,name=segmentHashes,proto3" json:"segmentHashes,omitempty"`
+	TotalLength          *common.BigIntegerBuf `protobuf:"bytes,3,opt,name=totalLength,proto3" json:"totalLength,omitempty"`
+	Deadline             *common.TimeTicksBuf  `protobuf:"bytes,4,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *MessagesBisectionBuf) Reset()         { *m = MessagesBisectionBuf{} }
+func (m *MessagesBisectionBuf) String() string { return proto.CompactTextString(m) }
+func (*MessagesBisectionBuf) ProtoMessage()    {}
+func (*MessagesBisectionBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{17}
+}
+
+func (m *MessagesBisectionBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MessagesBisectionBuf.Unmarshal(m, b)
+}
+func (m *MessagesBisectionBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MessagesBisectionBuf.Marshal(b, m, deterministic)
+}
+func (m *MessagesBisectionBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MessagesBisectionBuf.Merge(m, src)
+}
+func (m *MessagesBisectionBuf) XXX_Size() int {
+	return xxx_messageInfo_MessagesBisectionBuf.Size(m)
+}
+func (m *MessagesBisectionBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_MessagesBisectionBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MessagesBisectionBuf proto.InternalMessageInfo
+
+func (m *MessagesBisectionBuf) GetChainHashes() []*common.HashBuf {
+	if m != nil {
+		return m.ChainHashes
+	}
+	return nil
+}
+
+func (m *MessagesBisectionBuf) GetSegmentHashes() []*common.HashBuf {
+	if m != nil {
+		return m.SegmentHashes
+	}
+	return nil
+}
+
+func (m *MessagesBisectionBuf) GetTotalLength() *common.BigIntegerBuf {
+	if m != nil {
+		return m.TotalLength
+	}
+	return nil
+}
+
+func (m *MessagesBisectionBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type ExecutionBisectionBuf struct {
+	Assertions           []*valprotocol.ExecutionAssertionStubBuf `protobuf:"bytes,1,rep,name=assertions,proto3" json:"assertions,omitempty"`
+	TotalSteps           uint64                                   `protobuf:"varint,2,opt,name=totalSteps,proto3" json:"totalSteps,omitempty"`
+	Deadline             *common.TimeTicksBuf                     `protobuf:"bytes,3,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                 `json:"-"`
+	XXX_unrecognized     []byte                                   `json:"-"`
+	XXX_sizecache        int32                                    `json:"-"`
+}
+
+func (m *ExecutionBisectionBuf) Reset()         { *m = ExecutionBisectionBuf{} }
+func (m *ExecutionBisectionBuf) String() string { return proto.CompactTextString(m) }
+func (*ExecutionBisectionBuf) ProtoMessage()    {}
+func (*ExecutionBisectionBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{18}
+}
+
+func (m *ExecutionBisectionBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExecutionBisectionBuf.Unmarshal(m, b)
+}
+func (m *ExecutionBisectionBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExecutionBisectionBuf.Marshal(b, m, deterministic)
+}
+func (m *ExecutionBisectionBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExecutionBisectionBuf.Merge(m, src)
+}
+func (m *ExecutionBisectionBuf) XXX_Size() int {
+	return xxx_messageInfo_ExecutionBisectionBuf.Size(m)
+}
+func (m *ExecutionBisectionBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExecutionBisectionBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExecutionBisectionBuf proto.InternalMessageInfo
+
+func (m *ExecutionBisectionBuf) GetAssertions() []*valprotocol.ExecutionAssertionStubBuf {
+	if m != nil {
+		return m.Assertions
+	}
+	return nil
+}
+
+func (m *ExecutionBisectionBuf) GetTotalSteps() uint64 {
+	if m != nil {
+		return m.TotalSteps
+	}
+	return 0
+}
+
+func (m *ExecutionBisectionBuf) GetDeadline() *common.TimeTicksBuf {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type EventBuf struct {
+	ChainInfo *ChainInfoBuf `protobuf:"bytes,1,opt,name=chainInfo,proto3" json:"chainInfo,omitempty"`
+	// The contract which emitted the event
+	Contract *common.AddressBuf `protobuf:"bytes,2,opt,name=contract,proto3" json:"contract,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//	*EventBuf_StakeCreated
+	//	*EventBuf_ChallengeStarted
+	//	*EventBuf_ChallengeCompleted
+	//	*EventBuf_StakeRefunded
+	//	*EventBuf_Pruned
+	//	*EventBuf_StakeMoved
+	//	*EventBuf_Asserted
+	//	*EventBuf_Confirmed
+	//	*EventBuf_ConfirmedAssertion
+	//	*EventBuf_MessageDelivered
+	//	*EventBuf_InitiateChallenge
+	//	*EventBuf_AsserterTimeout
+	//	*EventBuf_ChallengerTimeout
+	//	*EventBuf_ContinueChallenge
+	//	*EventBuf_OneStepProof
+	//	*EventBuf_InboxTopBisection
+	//	*EventBuf_MessagesBisection
+	//	*EventBuf_ExecutionBisection
+	Event                isEventBuf_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *EventBuf) Reset()         { *m = EventBuf{} }
+func (m *EventBuf) String() string { return proto.CompactTextString(m) }
+func (*EventBuf) ProtoMessage()    {}
+func (*EventBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{19}
+}
+
+func (m *EventBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EventBuf.Unmarshal(m, b)
+}
+func (m *EventBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EventBuf.Marshal(b, m, deterministic)
+}
+func (m *EventBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EventBuf.Merge(m, src)
+}
+func (m *EventBuf) XXX_Size() int {
+	return xxx_messageInfo_EventBuf.Size(m)
+}
+func (m *EventBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_EventBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EventBuf proto.InternalMessageInfo
+
+func (m *EventBuf) GetChainInfo() *ChainInfoBuf {
+	if m != nil {
+		return m.ChainInfo
+	}
+	return nil
+}
+
+func (m *EventBuf) GetContract() *common.AddressBuf {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+type isEventBuf_Event interface {
+	isEventBuf_Event()
+}
+
+type EventBuf_StakeCreated struct {
+	StakeCreated *StakeCreatedBuf `protobuf:"bytes,3,opt,name=stakeCreated,proto3,oneof"`
+}
+
+type EventBuf_ChallengeStarted struct {
+	ChallengeStarted *ChallengeStartedBuf `protobuf:"bytes,4,opt,name=challengeStarted,proto3,oneof"`
+}
+
+type EventBuf_ChallengeCompleted struct {
+	ChallengeCompleted *ChallengeCompletedBuf `protobuf:"bytes,5,opt,name=challengeCompleted,proto3,oneof"`
+}
+
+type EventBuf_StakeRefunded struct {
+	StakeRefunded *StakeRefundedBuf `protobuf:"bytes,6,opt,name=stakeRefunded,proto3,oneof"`
+}
+
+type EventBuf_Pruned struct {
+	Pruned *PrunedBuf `protobuf:"bytes,7,opt,name=pruned,proto3,oneof"`
+}
+
+type EventBuf_StakeMoved struct {
+	StakeMoved *StakeMovedBuf `protobuf:"bytes,8,opt,name=stakeMoved,proto3,oneof"`
+}
+
+type EventBuf_Asserted struct {
+	Asserted *AssertedBuf `protobuf:"bytes,9,opt,name=asserted,proto3,oneof"`
+}
+
+type EventBuf_Confirmed struct {
+	Confirmed *ConfirmedBuf `protobuf:"bytes,10,opt,name=confirmed,proto3,oneof"`
+}
+
+type EventBuf_ConfirmedAssertion struct {
+	ConfirmedAssertion *ConfirmedAssertionBuf `protobuf:"bytes,11,opt,name=confirmedAssertion,proto3,oneof"`
+}
+
+type EventBuf_MessageDelivered struct {
+	MessageDelivered *MessageDeliveredBuf `protobuf:"bytes,12,opt,name=messageDelivered,proto3,oneof"`
+}
+
+type EventBuf_InitiateChallenge struct {
+	InitiateChallenge *InitiateChallengeBuf `protobuf:"bytes,13,opt,name=initiateChallenge,proto3,oneof"`
+}
+
+type EventBuf_AsserterTimeout struct {
+	AsserterTimeout *AsserterTimeoutBuf `protobuf:"bytes,14,opt,name=asserterTimeout,proto3,oneof"`
+}
+
+type EventBuf_ChallengerTimeout struct {
+	ChallengerTimeout *ChallengerTimeoutBuf `protobuf:"bytes,15,opt,name=challengerTimeout,proto3,oneof"`
+}
+
+type EventBuf_ContinueChallenge struct {
+	ContinueChallenge *ContinueChallengeBuf `protobuf:"bytes,16,opt,name=continueChallenge,proto3,oneof"`
+}
+
+type EventBuf_OneStepProof struct {
+	OneStepProof *OneStepProofBuf `protobuf:"bytes,17,opt,name=oneStepProof,proto3,oneof"`
+}
+
+type EventBuf_InboxTopBisection struct {
+	InboxTopBisection *InboxTopBisectionBuf `protobuf:"bytes,18,opt,name=inboxTopBisection,proto3,oneof"`
+}
+
+type EventBuf_MessagesBisection struct {
+	MessagesBisection *MessagesBisectionBuf `protobuf:"bytes,19,opt,name=messagesBisection,proto3,oneof"`
+}
+
+type EventBuf_ExecutionBisection struct {
+	ExecutionBisection *ExecutionBisectionBuf `protobuf:"bytes,20,opt,name=executionBisection,proto3,oneof"`
+}
+
+func (*EventBuf_StakeCreated) isEventBuf_Event() {}
+
+func (*EventBuf_ChallengeStarted) isEventBuf_Event() {}
+
+func (*EventBuf_ChallengeCompleted) isEventBuf_Event() {}
+
+func (*EventBuf_StakeRefunded) isEventBuf_Event() {}
+
+func (*EventBuf_Pruned) isEventBuf_Event() {}
+
+func (*EventBuf_StakeMoved) isEventBuf_Event() {}
+
+func (*EventBuf_Asserted) isEventBuf_Event() {}
+
+func (*EventBuf_Confirmed) isEventBuf_Event() {}
+
+func (*EventBuf_ConfirmedAssertion) isEventBuf_Event() {}
+
+func (*EventBuf_MessageDelivered) isEventBuf_Event() {}
+
+func (*EventBuf_InitiateChallenge) isEventBuf_Event() {}
+
+func (*EventBuf_AsserterTimeout) isEventBuf_Event() {}
+
+func (*EventBuf_ChallengerTimeout) isEventBuf_Event() {}
+
+func (*EventBuf_ContinueChallenge) isEventBuf_Event() {}
+
+func (*EventBuf_OneStepProof) isEventBuf_Event() {}
+
+func (*EventBuf_InboxTopBisection) isEventBuf_Event() {}
+
+func (*EventBuf_MessagesBisection) isEventBuf_Event() {}
+
+func (*EventBuf_ExecutionBisection) isEventBuf_Event() {}
+
+func (m *EventBuf) GetEvent() isEventBuf_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *EventBuf) GetStakeCreated() *StakeCreatedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_StakeCreated); ok {
+		return x.StakeCreated
+	}
+	return nil
+}
+
+func (m *EventBuf) GetChallengeStarted() *ChallengeStartedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ChallengeStarted); ok {
+		return x.ChallengeStarted
+	}
+	return nil
+}
+
+func (m *EventBuf) GetChallengeCompleted() *ChallengeCompletedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ChallengeCompleted); ok {
+		return x.ChallengeCompleted
+	}
+	return nil
+}
+
+func (m *EventBuf) GetStakeRefunded() *StakeRefundedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_StakeRefunded); ok {
+		return x.StakeRefunded
+	}
+	return nil
+}
+
+func (m *EventBuf) GetPruned() *PrunedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_Pruned); ok {
+		return x.Pruned
+	}
+	return nil
+}
+
+func (m *EventBuf) GetStakeMoved() *StakeMovedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_StakeMoved); ok {
+		return x.StakeMoved
+	}
+	return nil
+}
+
+func (m *EventBuf) GetAsserted() *AssertedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_Asserted); ok {
+		return x.Asserted
+	}
+	return nil
+}
+
+func (m *EventBuf) GetConfirmed() *ConfirmedBuf {
+	if x, ok := m.GetEvent().(*EventBuf_Confirmed); ok {
+		return x.Confirmed
+	}
+	return nil
+}
+
+func (m *EventBuf) GetConfirmedAssertion() *ConfirmedAssertionBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ConfirmedAssertion); ok {
+		return x.ConfirmedAssertion
+	}
+	return nil
+}
+
+func (m *EventBuf) GetMessageDelivered() *MessageDeliveredBuf {
+	if x, ok := m.GetEvent().(*EventBuf_MessageDelivered); ok {
+		return x.MessageDelivered
+	}
+	return nil
+}
+
+func (m *EventBuf) GetInitiateChallenge() *InitiateChallengeBuf {
+	if x, ok := m.GetEvent().(*EventBuf_InitiateChallenge); ok {
+		return x.InitiateChallenge
+	}
+	return nil
+}
+
+func (m *EventBuf) GetAsserterTimeout() *AsserterTimeoutBuf {
+	if x, ok := m.GetEvent().(*EventBuf_AsserterTimeout); ok {
+		return x.AsserterTimeout
+	}
+	return nil
+}
+
+func (m *EventBuf) GetChallengerTimeout() *ChallengerTimeoutBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ChallengerTimeout); ok {
+		return x.ChallengerTimeout
+	}
+	return nil
+}
+
+func (m *EventBuf) GetContinueChallenge() *ContinueChallengeBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ContinueChallenge); ok {
+		return x.ContinueChallenge
+	}
+	return nil
+}
+
+func (m *EventBuf) GetOneStepProof() *OneStepProofBuf {
+	if x, ok := m.GetEvent().(*EventBuf_OneStepProof); ok {
+		return x.OneStepProof
+	}
+	return nil
+}
+
+func (m *EventBuf) GetInboxTopBisection() *InboxTopBisectionBuf {
+	if x, ok := m.GetEvent().(*EventBuf_InboxTopBisection); ok {
+		return x.InboxTopBisection
+	}
+	return nil
+}
+
+func (m *EventBuf) GetMessagesBisection() *MessagesBisectionBuf {
+	if x, ok := m.GetEvent().(*EventBuf_MessagesBisection); ok {
+		return x.MessagesBisection
+	}
+	return nil
+}
+
+func (m *EventBuf) GetExecutionBisection() *ExecutionBisectionBuf {
+	if x, ok := m.GetEvent().(*EventBuf_ExecutionBisection); ok {
+		return x.ExecutionBisection
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*EventBuf) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*EventBuf_StakeCreated)(nil),
+		(*EventBuf_ChallengeStarted)(nil),
+		(*EventBuf_ChallengeCompleted)(nil),
+		(*EventBuf_StakeRefunded)(nil),
+		(*EventBuf_Pruned)(nil),
+		(*EventBuf_StakeMoved)(nil),
+		(*EventBuf_Asserted)(nil),
+		(*EventBuf_Confirmed)(nil),
+		(*EventBuf_ConfirmedAssertion)(nil),
+		(*EventBuf_MessageDelivered)(nil),
+		(*EventBuf_InitiateChallenge)(nil),
+		(*EventBuf_AsserterTimeout)(nil),
+		(*EventBuf_ChallengerTimeout)(nil),
+		(*EventBuf_ContinueChallenge)(nil),
+		(*EventBuf_OneStepProof)(nil),
+		(*EventBuf_InboxTopBisection)(nil),
+		(*EventBuf_MessagesBisection)(nil),
+		(*EventBuf_ExecutionBisection)(nil),
+	}
+}
+
+// WatchBuf is a contract followed by the indexer
+type WatchBuf struct {
+	Kind uint32 `protobuf:"varint,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	// The height of the block the contract was created in
+	Since *common.TimeBlocksBuf `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	// The height of the block that ended the challenge, if it's over
+	Until                *common.TimeBlocksBuf `protobuf:"bytes,3,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *WatchBuf) Reset()         { *m = WatchBuf{} }
+func (m *WatchBuf) String() string { return proto.CompactTextString(m) }
+func (*WatchBuf) ProtoMessage()    {}
+func (*WatchBuf) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a21c3feb787cbcf4, []int{20}
+}
+
+func (m *WatchBuf) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchBuf.Unmarshal(m, b)
+}
+func (m *WatchBuf) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchBuf.Marshal(b, m, deterministic)
+}
+func (m *WatchBuf) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchBuf.Merge(m, src)
+}
+func (m *WatchBuf) XXX_Size() int {
+	return xxx_messageInfo_WatchBuf.Size(m)
+}
+func (m *WatchBuf) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchBuf.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchBuf proto.InternalMessageInfo
+
+func (m *WatchBuf) GetKind() uint32 {
+	if m != nil {
+		return m.Kind
+	}
+	return 0
+}
+
+func (m *WatchBuf) GetSince() *common.TimeBlocksBuf {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *WatchBuf) GetUntil() *common.TimeBlocksBuf {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChainInfoBuf)(nil), "eventdb.ChainInfoBuf")
+	proto.RegisterType((*StakeCreatedBuf)(nil), "eventdb.StakeCreatedBuf")
+	proto.RegisterType((*ChallengeStartedBuf)(nil), "eventdb.ChallengeStartedBuf")
+	proto.RegisterType((*ChallengeCompletedBuf)(nil), "eventdb.ChallengeCompletedBuf")
+	proto.RegisterType((*StakeRefundedBuf)(nil), "eventdb.StakeRefundedBuf")
+	proto.RegisterType((*PrunedBuf)(nil), "eventdb.PrunedBuf")
+	proto.RegisterType((*StakeMovedBuf)(nil), "eventdb.StakeMovedBuf")
+	proto.RegisterType((*AssertedBuf)(nil), "eventdb.AssertedBuf")
+	proto.RegisterType((*ConfirmedBuf)(nil), "eventdb.ConfirmedBuf")
+	proto.RegisterType((*ConfirmedAssertionBuf)(nil), "eventdb.ConfirmedAssertionBuf")
+	proto.RegisterType((*MessageDeliveredBuf)(nil), "eventdb.MessageDeliveredBuf")
+	proto.RegisterType((*InitiateChallengeBuf)(nil), "eventdb.InitiateChallengeBuf")
+	proto.RegisterType((*AsserterTimeoutBuf)(nil), "eventdb.AsserterTimeoutBuf")
+	proto.RegisterType((*ChallengerTimeoutBuf)(nil), "eventdb.ChallengerTimeoutBuf")
+	proto.RegisterType((*ContinueChallengeBuf)(nil), "eventdb.ContinueChallengeBuf")
+	proto.RegisterType((*OneStepProofBuf)(nil), "eventdb.OneStepProofBuf")
+	proto.RegisterType((*InboxTopBisectionBuf)(nil), "eventdb.InboxTopBisectionBuf")
+	proto.RegisterType((*MessagesBisectionBuf)(nil), "eventdb.MessagesBisectionBuf")
+	proto.RegisterType((*ExecutionBisectionBuf)(nil), "eventdb.ExecutionBisectionBuf")
+	proto.RegisterType((*EventBuf)(nil), "eventdb.EventBuf")
+	proto.RegisterType((*WatchBuf)(nil), "eventdb.WatchBuf")
+}
+
+func init() { proto.RegisterFile("eventdb.proto", fileDescriptor_a21c3feb787cbcf4) }
+
+var fileDescriptor_a21c3feb787cbcf4 = []byte{
+	// 1262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0x5d, 0x8f, 0xdb, 0x44,
+	0x17, 0x8e, 0xf7, 0x33, 0x7b, 0x92, 0xbc, 0xdb, 0x9d, 0xcd, 0x56, 0xf3, 0x16, 0x5a, 0x2a, 0x83,
+	0xa0, 0xa2, 0x65, 0xd3, 0x0f, 0xaa, 0x82, 0x2a, 0x15, 0x36, 0xa1, 0xe0, 0x54, 0x5d, 0xb1, 0xf2,
+	0xae, 0x84, 0xc4, 0xdd, 0xc4, 0x9e, 0x64, 0xcd, 0xda, 0x33, 0x91, 0x3d, 0x0e, 0xe1, 0x12, 0xf1,
+	0x4b, 0xf8, 0x05, 0x08, 0x89, 0x3f, 0xc4, 0x35, 0x37, 0xfc, 0x04, 0x34, 0xe3, 0x8f, 0x1d, 0x8f,
+	0x9d, 0xa5, 0x5d, 0xae, 0x62, 0x1f, 0x3f, 0xcf, 0x99, 0x73, 0xce, 0x9c, 0x99, 0xe7, 0x04, 0x7a,
+	0x74, 0x41, 0x99, 0xf0, 0x27, 0x87, 0xf3, 0x98, 0x0b, 0x8e, 0xb6, 0xf3, 0xd7, 0x5b, 0xfb, 0x1e,
+	0x8f, 0x22, 0xce, 0x06, 0xd9, 0x4f, 0xf6, 0xf5, 0xd6, 0xed, 0x05, 0x09, 0xd5, 0x93, 0xc7, 0xc3,
+	0x81, 0xf6, 0x9c, 0x7d, 0xb6, 0x7f, 0xb6, 0xa0, 0x3b, 0x3a, 0x27, 0x01, 0x1b, 0xb3, 0x29, 0x1f,
+	0xa6, 0x53, 0xf4, 0x00, 0xb6, 0x27, 0x21, 0xf7, 0x2e, 0xc6, 0x3e, 0xb6, 0xee, 0x5a, 0xf7, 0x3a,
+	0x8f, 0xd1, 0x61, 0xee, 0x6f, 0x98, 0x99, 0x87, 0xe9, 0xd4, 0x2d, 0x20, 0xe8, 0x16, 0xb4, 0x43,
+	0x3e, 0x1b, 0x33, 0x9f, 0x2e, 0xf1, 0xda, 0x5d, 0xeb, 0xde, 0x86, 0x5b, 0xbe, 0xa3, 0x8f, 0x60,
+	0x4b, 0x2c, 0x1d, 0x92, 0x9c, 0xe3, 0x75, 0xe5, 0x68, 0xb7, 0x70, 0x24, 0x6d, 0xd2, 0x4b, 0xfe,
+	0xd9, 0xfe, 0x01, 0x76, 0x4f, 0x05, 0xb9, 0xa0, 0xa3, 0x98, 0x12, 0x41, 0xe5, 0x02, 0xe8, 0x63,
+	0xd8, 0x4a, 0xa4, 0x29, 0x36, 0x83, 0x38, 0xf2, 0xfd, 0x98, 0x26, 0x89, 0xa2, 0x67, 0x08, 0x74,
+	0x1f, 0xda, 0x8c, 0xfb, 0x54, 0xad, 0xb4, 0xd6, 0xbc, 0x52, 0x09, 0xb0, 0xff, 0xb4, 0x60, 0x7f,
+	0x74, 0x4e, 0xc2, 0x90, 0xb2, 0x19, 0x3d, 0x15, 0x24, 0xce, 0x17, 0x3c, 0x84, 0x36, 0x49, 0x12,
+	0x1a, 0x8b, 0x2b, 0x97, 0x2c, 0x31, 0xe8, 0x31, 0x80, 0x57, 0xb8, 0x89, 0xf1, 0xda, 0x4a, 0x86,
+	0x86, 0x42, 0x1f, 0x40, 0xaf, 0x7c, 0x3b, 0xfb, 0x69, 0x4e, 0x55, 0x5d, 0x7a, 0x6e, 0xd5, 0x88,
+	0xbe, 0x84, 0xbd, 0xd2, 0x30, 0xe2, 0x4c, 0xc4, 0xc4, 0x13, 0x78, 0x63, 0xe5, 0x02, 0x75, 0xb0,
+	0xfd, 0x9b, 0x05, 0x07, 0xa3, 0x4b, 0x6b, 0x34, 0x0f, 0xe9, 0x65, 0x59, 0x7f, 0x0c, 0x18, 0xbb,
+	0xba, 0xac, 0x19, 0x02, 0xdd, 0x83, 0xcd, 0x90, 0x27, 0x57, 0x26, 0x97, 0x01, 0x9a, 0x23, 0x5e,
+	0x7f, 0x9b, 0x88, 0x5f, 0xc0, 0x0d, 0xd5, 0x01, 0x2e, 0x9d, 0xa6, 0xcc, 0x7f, 0xeb, 0x16, 0xb0,
+	0x1f, 0xc2, 0xce, 0x49, 0x9c, 0xb2, 0x8c, 0xf8, 0x3e, 0x6c, 0x84, 0x94, 0x4c, 0xb1, 0xd5, 0xdc,
+	0x0b, 0xea, 0xa3, 0x7d, 0x0e, 0x3d, 0xb5, 0xe2, 0x31, 0x5f, 0x5c, 0xab, 0xe3, 0x42, 0xee, 0x11,
+	0x11, 0x70, 0xb6, 0xb2, 0xe3, 0x0a, 0x80, 0xfd, 0xeb, 0x1a, 0x74, 0x8e, 0xb2, 0xb6, 0x51, 0x0b,
+	0x3d, 0x81, 0xee, 0x3c, 0xa6, 0x8b, 0xd7, 0x94, 0x4c, 0x55, 0xcb, 0xae, 0x08, 0xb3, 0x02, 0x42,
+	0xcf, 0x60, 0x6b, 0x4e, 0x62, 0x12, 0x25, 0xf9, 0x7a, 0xef, 0x1d, 0xea, 0x47, 0x39, 0x73, 0x1f,
+	0x70, 0x76, 0xa2, 0x30, 0x2a, 0xd4, 0x0c, 0x8e, 0x3e, 0x85, 0x4d, 0x2f, 0x24, 0x41, 0x94, 0xef,
+	0xc7, 0x9d, 0x66, 0xde, 0x48, 0x42, 0xd4, 0x8e, 0x2a, 0x30, 0x7a, 0x04, 0x9d, 0x88, 0x2c, 0xc7,
+	0x6c, 0xc2, 0x97, 0x67, 0x7c, 0x8e, 0x37, 0x9a, 0x43, 0xd4, 0x31, 0xe8, 0x39, 0xf4, 0x8a, 0xd7,
+	0x11, 0x4f, 0x99, 0xc0, 0x9b, 0x8a, 0x74, 0x50, 0x90, 0x86, 0xc1, 0x6c, 0xcc, 0x04, 0x9d, 0xd1,
+	0x58, 0x52, 0xab, 0x58, 0xfb, 0x39, 0x74, 0x47, 0x9c, 0x4d, 0x83, 0x38, 0xca, 0x6a, 0xa4, 0x1f,
+	0x69, 0xeb, 0xdf, 0x8e, 0xf4, 0x2b, 0x38, 0x28, 0xc9, 0x65, 0x46, 0xd2, 0xcb, 0x23, 0xe8, 0x84,
+	0x7c, 0x96, 0x1c, 0x79, 0x5e, 0xee, 0x68, 0xbd, 0x31, 0x0b, 0x0d, 0x63, 0x1f, 0xc3, 0xfe, 0x31,
+	0x4d, 0x12, 0x32, 0xa3, 0x5f, 0xd1, 0x30, 0x58, 0xd0, 0x38, 0x8b, 0xe7, 0x2e, 0x74, 0xa2, 0xcc,
+	0xac, 0xce, 0xad, 0xa5, 0xce, 0xad, 0x6e, 0x42, 0x7d, 0xd8, 0x5c, 0x90, 0x30, 0xa5, 0x6a, 0x7f,
+	0xba, 0x6e, 0xf6, 0x62, 0x3b, 0xd0, 0x1f, 0xb3, 0x40, 0x04, 0x44, 0xd0, 0xf2, 0x40, 0x4a, 0x7f,
+	0x0f, 0xa1, 0xed, 0x53, 0xe2, 0x87, 0x01, 0xa3, 0x79, 0x7e, 0xfd, 0x22, 0xac, 0xb3, 0x20, 0xa2,
+	0x67, 0x81, 0x77, 0x91, 0xdd, 0x37, 0x05, 0xca, 0xee, 0x03, 0xca, 0x9b, 0x28, 0x96, 0x08, 0x9e,
+	0x8a, 0x61, 0x3a, 0xb5, 0x6f, 0x42, 0xbf, 0xf4, 0xab, 0xdb, 0x7f, 0xb1, 0xa0, 0x2f, 0x0f, 0x57,
+	0xc0, 0xd2, 0xea, 0xc2, 0x9f, 0x43, 0x37, 0xa1, 0xb3, 0x88, 0x32, 0x91, 0xdd, 0xd9, 0xd6, 0x55,
+	0x9b, 0x54, 0x81, 0x56, 0x62, 0x5e, 0x7b, 0xa3, 0x98, 0xf7, 0x60, 0xf7, 0x5b, 0x46, 0x4f, 0x05,
+	0x9d, 0x9f, 0xc4, 0x9c, 0x4f, 0x65, 0x60, 0x7f, 0x58, 0xd0, 0x2f, 0x5a, 0x66, 0x18, 0x24, 0xd4,
+	0xd3, 0xf6, 0xca, 0x93, 0x32, 0x24, 0x77, 0x81, 0x26, 0x2b, 0xf7, 0x4a, 0xc3, 0xa0, 0x67, 0xd0,
+	0x11, 0x5c, 0x90, 0xf0, 0x35, 0x65, 0x33, 0x51, 0x5c, 0xfd, 0x2b, 0x52, 0xd1, 0x91, 0x95, 0x4c,
+	0xd6, 0xdf, 0x28, 0x93, 0xbf, 0x2c, 0xe8, 0xe7, 0x7d, 0x91, 0xfc, 0xd7, 0xb0, 0x9f, 0x42, 0x2f,
+	0xaf, 0x6b, 0x4e, 0x5a, 0x6b, 0x26, 0x55, 0x51, 0x66, 0xb6, 0xeb, 0xd7, 0xca, 0x76, 0xe3, 0x8d,
+	0xb2, 0xfd, 0xdd, 0x82, 0x83, 0x97, 0x4b, 0xea, 0xa5, 0x2a, 0x4b, 0x3d, 0xdd, 0xaf, 0x01, 0x48,
+	0x71, 0xc2, 0x8a, 0x6c, 0x3f, 0xac, 0x5c, 0x29, 0x25, 0xaf, 0x3c, 0x89, 0xa7, 0x22, 0x9d, 0x28,
+	0x25, 0xbc, 0x64, 0xa2, 0x3b, 0x00, 0x2a, 0x44, 0xd9, 0x1b, 0x49, 0x3e, 0x38, 0x68, 0x96, 0x6b,
+	0xec, 0xd0, 0xdf, 0x00, 0xed, 0x97, 0x72, 0x0e, 0xca, 0xae, 0xd8, 0x1d, 0xaf, 0x98, 0x69, 0xca,
+	0x16, 0x2f, 0x86, 0x26, 0x7d, 0xda, 0x71, 0x2f, 0x71, 0x72, 0x02, 0xf0, 0x0a, 0xf1, 0x5a, 0x2d,
+	0x79, 0x25, 0x06, 0xbd, 0x80, 0x6e, 0xa2, 0x4d, 0x2d, 0x79, 0x9c, 0xb8, 0x5c, 0xc7, 0x18, 0x69,
+	0x9c, 0x96, 0x5b, 0xc1, 0xa3, 0x57, 0x70, 0xc3, 0x33, 0x06, 0x91, 0x7c, 0x7f, 0xde, 0xd5, 0x63,
+	0x35, 0x27, 0x15, 0xa7, 0xe5, 0xd6, 0x78, 0xe8, 0x04, 0x90, 0x57, 0x13, 0xfc, 0xfc, 0x06, 0xbe,
+	0x53, 0xf7, 0xa6, 0xcf, 0x04, 0x4e, 0xcb, 0x6d, 0xe0, 0xa2, 0x23, 0xe8, 0x25, 0xba, 0x22, 0xe3,
+	0x2d, 0xe5, 0xec, 0xff, 0xd5, 0xf4, 0x34, 0xbd, 0x76, 0x5a, 0x6e, 0x95, 0x81, 0x1e, 0xc0, 0xd6,
+	0x5c, 0x89, 0x32, 0xde, 0xce, 0xcb, 0x59, 0x70, 0x4b, 0xad, 0x76, 0x5a, 0x6e, 0x8e, 0x41, 0x9f,
+	0x01, 0x24, 0xa5, 0x20, 0xe3, 0xb6, 0x62, 0xdc, 0xac, 0xae, 0x56, 0x68, 0xb5, 0xd3, 0x72, 0x35,
+	0x2c, 0x7a, 0x5c, 0x8e, 0x6e, 0x3e, 0xde, 0xc9, 0x9b, 0xa5, 0xe0, 0x69, 0xc2, 0xeb, 0xb4, 0xca,
+	0xf1, 0xcd, 0x47, 0x4f, 0x61, 0xc7, 0x2b, 0x34, 0x03, 0x83, 0xd9, 0x21, 0x9a, 0x14, 0x39, 0x2d,
+	0xf7, 0x12, 0xa9, 0xea, 0x5c, 0x93, 0x1a, 0xdc, 0x31, 0xeb, 0xdc, 0xa4, 0x46, 0xaa, 0xce, 0xb5,
+	0x0f, 0xb2, 0x0b, 0x22, 0x43, 0x70, 0x70, 0xd7, 0xe8, 0x82, 0x06, 0x45, 0x92, 0x5d, 0x60, 0xf2,
+	0xd0, 0x31, 0xec, 0x05, 0xa6, 0xda, 0xe0, 0x9e, 0x72, 0x76, 0xbb, 0x74, 0xd6, 0xa4, 0x47, 0x4e,
+	0xcb, 0xad, 0x33, 0xd1, 0x37, 0xb0, 0x4b, 0xaa, 0x92, 0x83, 0xff, 0xa7, 0x9c, 0xbd, 0x63, 0x96,
+	0x57, 0x93, 0x1e, 0xa7, 0xe5, 0x9a, 0x2c, 0x19, 0x97, 0x67, 0xaa, 0x14, 0xde, 0x35, 0xe2, 0x6a,
+	0xd2, 0x31, 0x19, 0x57, 0x8d, 0xa9, 0xdc, 0x99, 0xda, 0x86, 0x6f, 0x98, 0xee, 0x1a, 0xd4, 0x4f,
+	0xb9, 0x33, 0xed, 0xf2, 0x1c, 0x73, 0x4d, 0xa5, 0xf0, 0x9e, 0x71, 0x8e, 0x0d, 0x09, 0x93, 0xe7,
+	0x58, 0xc7, 0x67, 0x55, 0x37, 0x14, 0x0d, 0xa3, 0x5a, 0xd5, 0xeb, 0x9a, 0x97, 0x55, 0xdd, 0xb0,
+	0x4b, 0x77, 0x91, 0xa9, 0x34, 0x78, 0xdf, 0x70, 0xd7, 0xa4, 0x45, 0xd2, 0x5d, 0x8d, 0x29, 0x3b,
+	0x96, 0xd6, 0xae, 0x72, 0xdc, 0x37, 0x3a, 0xb6, 0xf1, 0xb6, 0x97, 0x1d, 0x5b, 0xe7, 0x0e, 0xb7,
+	0x61, 0x53, 0xd1, 0xec, 0x25, 0xb4, 0xbf, 0x23, 0xc2, 0x93, 0x62, 0x85, 0x10, 0x6c, 0x5c, 0x04,
+	0xcc, 0xcf, 0x27, 0x23, 0xf5, 0x8c, 0xee, 0xc3, 0x66, 0x12, 0x30, 0x8f, 0x9a, 0xca, 0x2c, 0xf7,
+	0x51, 0xfd, 0x97, 0xcc, 0xfe, 0x43, 0x28, 0x8c, 0x04, 0xa7, 0x4c, 0x04, 0x21, 0x5e, 0xbf, 0x12,
+	0xac, 0x30, 0xc3, 0xa3, 0xef, 0xbf, 0x98, 0x05, 0xe2, 0x3c, 0x9d, 0x48, 0xd4, 0x80, 0x4f, 0xa7,
+	0xea, 0x16, 0x0f, 0xc9, 0x24, 0x19, 0x90, 0x78, 0x12, 0x88, 0x38, 0x8d, 0x06, 0x73, 0xe2, 0x5d,
+	0xc8, 0x52, 0x48, 0xcb, 0x27, 0x0b, 0x12, 0x06, 0x3e, 0x11, 0x3c, 0x1e, 0xe4, 0x19, 0x4f, 0xb6,
+	0x94, 0x62, 0x3d, 0xf9, 0x67, 0x00, 0x4a, 0x4f, 0xf3, 0xf9, 0x4c, 0x0f, 0x00, 0x00,
+}
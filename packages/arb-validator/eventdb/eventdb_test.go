@@ -0,0 +1,308 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+var (
+	rollupA    = common.Address{0xa}
+	challengeC = common.Address{0xc}
+	stakerX    = common.Address{1}
+	stakerY    = common.Address{2}
+)
+
+func blockId(height int64, fork byte) *common.BlockId {
+	return &common.BlockId{
+		Height:     common.NewTimeBlocksInt(height),
+		HeaderHash: common.Hash{byte(height), fork},
+	}
+}
+
+func chainInfo(id *common.BlockId, logIndex uint, tx byte) arbbridge.ChainInfo {
+	return arbbridge.ChainInfo{BlockId: id, LogIndex: logIndex, TxHash: common.Hash{0xff, tx}}
+}
+
+func assertedEvent(info arbbridge.ChainInfo) arbbridge.AssertedEvent {
+	return arbbridge.AssertedEvent{
+		ChainInfo:    info,
+		PrevLeafHash: common.Hash{3},
+		Params: &valprotocol.AssertionParams{
+			NumSteps: 10,
+			TimeBounds: &protocol.TimeBoundsBlocks{
+				Start: common.NewTimeBlocksInt(1),
+				End:   common.NewTimeBlocksInt(20),
+			},
+			ImportedMessageCount: big.NewInt(2),
+		},
+		Claim: &valprotocol.AssertionClaim{
+			AfterInboxTop:         common.Hash{4},
+			ImportedMessagesSlice: common.Hash{5},
+			AssertionStub:         &valprotocol.ExecutionAssertionStub{AfterHash: common.Hash{6}, NumGas: 100},
+		},
+		MaxInboxTop:   common.Hash{7},
+		MaxInboxCount: big.NewInt(3),
+	}
+}
+
+func addBlock(t *testing.T, db *EventDB, id *common.BlockId, events ...Record) {
+	t.Helper()
+	if err := db.AddBlock(id, events); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addHistory indexes a rollup where stakerX asserts in block 11, stakerY
+// challenges in block 12 and stakerX wins in block 13
+func addHistory(t *testing.T, db *EventDB) {
+	t.Helper()
+	if err := db.AddWatch(rollupA, RollupWatch, blockId(10, 0)); err != nil {
+		t.Fatal(err)
+	}
+	b11 := blockId(11, 0)
+	addBlock(t, db, b11,
+		Record{rollupA, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(b11, 0, 1), Staker: stakerX}},
+		Record{rollupA, arbbridge.StakeMovedEvent{ChainInfo: chainInfo(b11, 2, 2), Staker: stakerX}},
+		Record{rollupA, assertedEvent(chainInfo(b11, 1, 2))},
+	)
+	b12 := blockId(12, 0)
+	addBlock(t, db, b12,
+		Record{rollupA, arbbridge.ChallengeStartedEvent{
+			ChainInfo:         chainInfo(b12, 0, 3),
+			Asserter:          stakerX,
+			Challenger:        stakerY,
+			ChallengeType:     valprotocol.InvalidExecutionChildType,
+			ChallengeContract: challengeC,
+		}},
+		Record{challengeC, arbbridge.InitiateChallengeEvent{
+			ChainInfo: chainInfo(b12, 1, 3),
+			Deadline:  common.TimeTicks{Val: big.NewInt(500)},
+		}},
+	)
+	b13 := blockId(13, 0)
+	addBlock(t, db, b13,
+		Record{challengeC, arbbridge.ChallengerTimeoutEvent{ChainInfo: chainInfo(b13, 0, 4)}},
+		Record{rollupA, arbbridge.ChallengeCompletedEvent{
+			ChainInfo:         chainInfo(b13, 1, 4),
+			Winner:            stakerX,
+			Loser:             stakerY,
+			ChallengeContract: challengeC,
+		}},
+	)
+}
+
+func kinds(records []Record) []string {
+	ret := make([]string, 0, len(records))
+	for _, record := range records {
+		ret = append(ret, record.Kind())
+	}
+	return ret
+}
+
+func checkKinds(t *testing.T, records []Record, err error, expected ...string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := kinds(records)
+	if len(got) != len(expected) {
+		t.Fatalf("expected events %v but got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected events %v but got %v", expected, got)
+		}
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	info := chainInfo(blockId(5, 0), 3, 9)
+	msg := message.DeliveredEth{
+		Eth:        message.Eth{To: stakerX, From: stakerY, Value: big.NewInt(50)},
+		BlockNum:   common.NewTimeBlocksInt(5),
+		MessageNum: big.NewInt(1),
+	}
+	records := []Record{
+		{rollupA, assertedEvent(info)},
+		{rollupA, arbbridge.MessageDeliveredEvent{ChainInfo: info, Message: msg}},
+		{challengeC, arbbridge.ExecutionBisectionEvent{
+			ChainInfo:  info,
+			Assertions: []*valprotocol.ExecutionAssertionStub{{AfterHash: common.Hash{8}, NumGas: 5}},
+			TotalSteps: 40,
+			Deadline:   common.TimeTicks{Val: big.NewInt(1000)},
+		}},
+	}
+	for _, record := range records {
+		buf, err := record.MarshalToBuf()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := buf.Unmarshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Contract != record.Contract || loaded.Kind() != record.Kind() {
+			t.Fatalf("loaded %v from %v", loaded, record)
+		}
+		loadedInfo := loaded.Event.GetChainInfo()
+		if !loadedInfo.BlockId.Equals(info.BlockId) || loadedInfo.LogIndex != info.LogIndex || loadedInfo.TxHash != info.TxHash {
+			t.Fatal("chain info changed after loading")
+		}
+		switch ev := loaded.Event.(type) {
+		case arbbridge.AssertedEvent:
+			orig := record.Event.(arbbridge.AssertedEvent)
+			if !ev.Params.Equals(orig.Params) || !ev.Claim.Equals(orig.Claim) || ev.MaxInboxCount.Cmp(orig.MaxInboxCount) != 0 {
+				t.Fatal("assertion changed after loading")
+			}
+		case arbbridge.MessageDeliveredEvent:
+			if !ev.Message.Equals(msg) {
+				t.Fatalf("loaded message %v instead of %v", ev.Message, msg)
+			}
+		case arbbridge.ExecutionBisectionEvent:
+			if len(ev.Assertions) != 1 || !ev.Assertions[0].Equals(records[2].Event.(arbbridge.ExecutionBisectionEvent).Assertions[0]) {
+				t.Fatal("bisection changed after loading")
+			}
+		}
+	}
+
+	if _, err := (Record{rollupA, arbbridge.NewTimeEvent{ChainInfo: info}}).MarshalToBuf(); err == nil {
+		t.Fatal("marshalled an event which isn't indexed")
+	}
+}
+
+func TestQueries(t *testing.T) {
+	db := NewMemory()
+	addHistory(t, db)
+
+	all, err := db.EventsInRange(nil, nil)
+	checkKinds(t, all, err,
+		"StakeCreated", "Asserted", "StakeMoved",
+		"ChallengeStarted", "InitiateChallenge",
+		"ChallengerTimeout", "ChallengeCompleted",
+	)
+	inRange, err := db.EventsInRange(common.NewTimeBlocksInt(12), common.NewTimeBlocksInt(12))
+	checkKinds(t, inRange, err, "ChallengeStarted", "InitiateChallenge")
+
+	assertions, err := db.Assertions(stakerX, nil, nil)
+	checkKinds(t, assertions, err, "Asserted")
+	assertions, err = db.Assertions(stakerY, nil, nil)
+	checkKinds(t, assertions, err)
+
+	stakerEvents, err := db.StakerEvents(stakerY, nil, nil)
+	checkKinds(t, stakerEvents, err, "ChallengeStarted", "ChallengeCompleted")
+
+	challengeEvents, err := db.ContractEvents(challengeC, nil, nil)
+	checkKinds(t, challengeEvents, err, "InitiateChallenge", "ChallengerTimeout")
+
+	challenges, err := db.Challenges(nil, common.NewTimeBlocksInt(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(challenges) != 1 || challenges[0].Rollup != rollupA || challenges[0].Completed == nil {
+		t.Fatalf("expected one completed challenge but got %v", challenges)
+	}
+	if challenges[0].Completed.Winner != stakerX {
+		t.Error("wrong challenge winner")
+	}
+	challenges, err = db.Challenges(common.NewTimeBlocksInt(13), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(challenges) != 0 {
+		t.Errorf("expected no challenges started after block 12 but got %v", challenges)
+	}
+
+	watch, found, err := db.Watch(challengeC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || watch.Kind != ExecutionChallengeWatch || watch.Active() {
+		t.Errorf("expected a completed execution challenge watch but got %v", watch)
+	}
+}
+
+func TestAddBlockOutOfOrder(t *testing.T) {
+	db := NewMemory()
+	if err := db.AddBlock(blockId(11, 0), nil); err == nil {
+		t.Error("added a block without watching a contract")
+	}
+	addHistory(t, db)
+	if err := db.AddBlock(blockId(15, 0), nil); err == nil {
+		t.Error("added a block which doesn't follow the head")
+	}
+}
+
+func TestRewind(t *testing.T) {
+	db := NewMemory()
+	addHistory(t, db)
+
+	if err := db.Rewind(blockId(12, 0)); err != nil {
+		t.Fatal(err)
+	}
+	challenges, err := db.Challenges(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(challenges) != 1 || challenges[0].Completed != nil {
+		t.Fatalf("expected one open challenge but got %v", challenges)
+	}
+	watch, _, err := db.Watch(challengeC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !watch.Active() {
+		t.Error("challenge watch wasn't reopened")
+	}
+
+	if err := db.Rewind(blockId(11, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := db.Watch(challengeC); err != nil || found {
+		t.Error("challenge watch wasn't removed", err)
+	}
+	stakerEvents, err := db.StakerEvents(stakerY, nil, nil)
+	checkKinds(t, stakerEvents, err)
+	if _, found, _ := db.BlockHash(common.NewTimeBlocksInt(12)); found {
+		t.Error("rewound block is still indexed")
+	}
+
+	b12 := blockId(12, 1)
+	addBlock(t, db, b12, Record{rollupA, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(b12, 0, 5), Staker: stakerY}})
+	hash, found, err := db.BlockHash(common.NewTimeBlocksInt(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || hash != b12.HeaderHash {
+		t.Error("replacement block not indexed")
+	}
+	stakerEvents, err = db.StakerEvents(stakerY, nil, nil)
+	checkKinds(t, stakerEvents, err, "StakeCreated")
+
+	all, err := db.EventsInRange(nil, nil)
+	checkKinds(t, all, err, "StakeCreated", "Asserted", "StakeMoved", "StakeCreated")
+	if !db.Head().Equals(b12) {
+		t.Error("wrong head after rewinding", db.Head())
+	}
+}
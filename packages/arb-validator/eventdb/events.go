@@ -0,0 +1,334 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+//go:generate bash -c "protoc -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-util) -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-validator-core) -I. --go_out=paths=source_relative:. *.proto"
+
+package eventdb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// Record is an event along with the contract which emitted it
+type Record struct {
+	Contract common.Address
+	Event    arbbridge.Event
+}
+
+// Kind returns the name of the record's event type, such as "Asserted"
+func (r Record) Kind() string {
+	switch r.Event.(type) {
+	case arbbridge.StakeCreatedEvent:
+		return "StakeCreated"
+	case arbbridge.ChallengeStartedEvent:
+		return "ChallengeStarted"
+	case arbbridge.ChallengeCompletedEvent:
+		return "ChallengeCompleted"
+	case arbbridge.StakeRefundedEvent:
+		return "StakeRefunded"
+	case arbbridge.PrunedEvent:
+		return "Pruned"
+	case arbbridge.StakeMovedEvent:
+		return "StakeMoved"
+	case arbbridge.AssertedEvent:
+		return "Asserted"
+	case arbbridge.ConfirmedEvent:
+		return "Confirmed"
+	case arbbridge.ConfirmedAssertionEvent:
+		return "ConfirmedAssertion"
+	case arbbridge.MessageDeliveredEvent:
+		return "MessageDelivered"
+	case arbbridge.InitiateChallengeEvent:
+		return "InitiateChallenge"
+	case arbbridge.AsserterTimeoutEvent:
+		return "AsserterTimeout"
+	case arbbridge.ChallengerTimeoutEvent:
+		return "ChallengerTimeout"
+	case arbbridge.ContinueChallengeEvent:
+		return "ContinueChallenge"
+	case arbbridge.OneStepProofEvent:
+		return "OneStepProof"
+	case arbbridge.InboxTopBisectionEvent:
+		return "InboxTopBisection"
+	case arbbridge.MessagesBisectionEvent:
+		return "MessagesBisection"
+	case arbbridge.ExecutionBisectionEvent:
+		return "ExecutionBisection"
+	default:
+		return "Unknown"
+	}
+}
+
+func marshalChainInfo(info arbbridge.ChainInfo) *ChainInfoBuf {
+	return &ChainInfoBuf{
+		BlockId:  info.BlockId.MarshalToBuf(),
+		LogIndex: uint64(info.LogIndex),
+		TxHash:   common.Hash(info.TxHash).MarshalToBuf(),
+	}
+}
+
+func (buf *ChainInfoBuf) Unmarshal() arbbridge.ChainInfo {
+	return arbbridge.ChainInfo{
+		BlockId:  buf.BlockId.Unmarshal(),
+		LogIndex: uint(buf.LogIndex),
+		TxHash:   buf.TxHash.Unmarshal(),
+	}
+}
+
+func unmarshalHashes(bufs []*common.HashBuf) []common.Hash {
+	hashes := make([]common.Hash, 0, len(bufs))
+	for _, buf := range bufs {
+		hashes = append(hashes, buf.Unmarshal())
+	}
+	return hashes
+}
+
+// MarshalToBuf fails if the record holds an event which the index doesn't
+// store, such as NewTimeEvent
+func (r Record) MarshalToBuf() (*EventBuf, error) {
+	buf := &EventBuf{
+		ChainInfo: marshalChainInfo(r.Event.GetChainInfo()),
+		Contract:  r.Contract.MarshallToBuf(),
+	}
+	switch ev := r.Event.(type) {
+	case arbbridge.StakeCreatedEvent:
+		buf.Event = &EventBuf_StakeCreated{StakeCreated: &StakeCreatedBuf{
+			Staker:   ev.Staker.MarshallToBuf(),
+			NodeHash: ev.NodeHash.MarshalToBuf(),
+		}}
+	case arbbridge.ChallengeStartedEvent:
+		buf.Event = &EventBuf_ChallengeStarted{ChallengeStarted: &ChallengeStartedBuf{
+			Asserter:          ev.Asserter.MarshallToBuf(),
+			Challenger:        ev.Challenger.MarshallToBuf(),
+			ChallengeType:     uint32(ev.ChallengeType),
+			ChallengeContract: ev.ChallengeContract.MarshallToBuf(),
+		}}
+	case arbbridge.ChallengeCompletedEvent:
+		buf.Event = &EventBuf_ChallengeCompleted{ChallengeCompleted: &ChallengeCompletedBuf{
+			Winner:            ev.Winner.MarshallToBuf(),
+			Loser:             ev.Loser.MarshallToBuf(),
+			ChallengeContract: ev.ChallengeContract.MarshallToBuf(),
+		}}
+	case arbbridge.StakeRefundedEvent:
+		buf.Event = &EventBuf_StakeRefunded{StakeRefunded: &StakeRefundedBuf{
+			Staker: ev.Staker.MarshallToBuf(),
+		}}
+	case arbbridge.PrunedEvent:
+		buf.Event = &EventBuf_Pruned{Pruned: &PrunedBuf{
+			Leaf: ev.Leaf.MarshalToBuf(),
+		}}
+	case arbbridge.StakeMovedEvent:
+		buf.Event = &EventBuf_StakeMoved{StakeMoved: &StakeMovedBuf{
+			Staker:   ev.Staker.MarshallToBuf(),
+			Location: ev.Location.MarshalToBuf(),
+		}}
+	case arbbridge.AssertedEvent:
+		buf.Event = &EventBuf_Asserted{Asserted: &AssertedBuf{
+			PrevLeafHash:  ev.PrevLeafHash.MarshalToBuf(),
+			Params:        ev.Params.MarshalToBuf(),
+			Claim:         ev.Claim.MarshalToBuf(),
+			MaxInboxTop:   ev.MaxInboxTop.MarshalToBuf(),
+			MaxInboxCount: common.MarshalBigInt(ev.MaxInboxCount),
+		}}
+	case arbbridge.ConfirmedEvent:
+		buf.Event = &EventBuf_Confirmed{Confirmed: &ConfirmedBuf{
+			NodeHash: ev.NodeHash.MarshalToBuf(),
+		}}
+	case arbbridge.ConfirmedAssertionEvent:
+		buf.Event = &EventBuf_ConfirmedAssertion{ConfirmedAssertion: &ConfirmedAssertionBuf{
+			LogsAccHash: common.MarshalSliceOfHashes(ev.LogsAccHash),
+		}}
+	case arbbridge.MessageDeliveredEvent:
+		var val bytes.Buffer
+		if err := value.MarshalValue(ev.Message.CheckpointValue(), &val); err != nil {
+			return nil, err
+		}
+		buf.Event = &EventBuf_MessageDelivered{MessageDelivered: &MessageDeliveredBuf{
+			MessageType: uint32(ev.Message.Type()),
+			Value:       val.Bytes(),
+		}}
+	case arbbridge.InitiateChallengeEvent:
+		buf.Event = &EventBuf_InitiateChallenge{InitiateChallenge: &InitiateChallengeBuf{
+			Deadline: ev.Deadline.MarshalToBuf(),
+		}}
+	case arbbridge.AsserterTimeoutEvent:
+		buf.Event = &EventBuf_AsserterTimeout{AsserterTimeout: &AsserterTimeoutBuf{}}
+	case arbbridge.ChallengerTimeoutEvent:
+		buf.Event = &EventBuf_ChallengerTimeout{ChallengerTimeout: &ChallengerTimeoutBuf{}}
+	case arbbridge.ContinueChallengeEvent:
+		buf.Event = &EventBuf_ContinueChallenge{ContinueChallenge: &ContinueChallengeBuf{
+			SegmentIndex: common.MarshalBigInt(ev.SegmentIndex),
+			Deadline:     ev.Deadline.MarshalToBuf(),
+		}}
+	case arbbridge.OneStepProofEvent:
+		buf.Event = &EventBuf_OneStepProof{OneStepProof: &OneStepProofBuf{}}
+	case arbbridge.InboxTopBisectionEvent:
+		buf.Event = &EventBuf_InboxTopBisection{InboxTopBisection: &InboxTopBisectionBuf{
+			ChainHashes: common.MarshalSliceOfHashes(ev.ChainHashes),
+			TotalLength: common.MarshalBigInt(ev.TotalLength),
+			Deadline:    ev.Deadline.MarshalToBuf(),
+		}}
+	case arbbridge.MessagesBisectionEvent:
+		buf.Event = &EventBuf_MessagesBisection{MessagesBisection: &MessagesBisectionBuf{
+			ChainHashes:   common.MarshalSliceOfHashes(ev.ChainHashes),
+			SegmentHashes: common.MarshalSliceOfHashes(ev.SegmentHashes),
+			TotalLength:   common.MarshalBigInt(ev.TotalLength),
+			Deadline:      ev.Deadline.MarshalToBuf(),
+		}}
+	case arbbridge.ExecutionBisectionEvent:
+		assertions := make([]*valprotocol.ExecutionAssertionStubBuf, 0, len(ev.Assertions))
+		for _, assertion := range ev.Assertions {
+			assertions = append(assertions, assertion.MarshalToBuf())
+		}
+		buf.Event = &EventBuf_ExecutionBisection{ExecutionBisection: &ExecutionBisectionBuf{
+			Assertions: assertions,
+			TotalSteps: ev.TotalSteps,
+			Deadline:   ev.Deadline.MarshalToBuf(),
+		}}
+	default:
+		return nil, fmt.Errorf("can't index event of type %T", r.Event)
+	}
+	return buf, nil
+}
+
+func (buf *EventBuf) Unmarshal() (Record, error) {
+	info := buf.ChainInfo.Unmarshal()
+	var event arbbridge.Event
+	switch ev := buf.Event.(type) {
+	case *EventBuf_StakeCreated:
+		event = arbbridge.StakeCreatedEvent{
+			ChainInfo: info,
+			Staker:    ev.StakeCreated.Staker.Unmarshal(),
+			NodeHash:  ev.StakeCreated.NodeHash.Unmarshal(),
+		}
+	case *EventBuf_ChallengeStarted:
+		event = arbbridge.ChallengeStartedEvent{
+			ChainInfo:         info,
+			Asserter:          ev.ChallengeStarted.Asserter.Unmarshal(),
+			Challenger:        ev.ChallengeStarted.Challenger.Unmarshal(),
+			ChallengeType:     valprotocol.ChildType(ev.ChallengeStarted.ChallengeType),
+			ChallengeContract: ev.ChallengeStarted.ChallengeContract.Unmarshal(),
+		}
+	case *EventBuf_ChallengeCompleted:
+		event = arbbridge.ChallengeCompletedEvent{
+			ChainInfo:         info,
+			Winner:            ev.ChallengeCompleted.Winner.Unmarshal(),
+			Loser:             ev.ChallengeCompleted.Loser.Unmarshal(),
+			ChallengeContract: ev.ChallengeCompleted.ChallengeContract.Unmarshal(),
+		}
+	case *EventBuf_StakeRefunded:
+		event = arbbridge.StakeRefundedEvent{
+			ChainInfo: info,
+			Staker:    ev.StakeRefunded.Staker.Unmarshal(),
+		}
+	case *EventBuf_Pruned:
+		event = arbbridge.PrunedEvent{
+			ChainInfo: info,
+			Leaf:      ev.Pruned.Leaf.Unmarshal(),
+		}
+	case *EventBuf_StakeMoved:
+		event = arbbridge.StakeMovedEvent{
+			ChainInfo: info,
+			Staker:    ev.StakeMoved.Staker.Unmarshal(),
+			Location:  ev.StakeMoved.Location.Unmarshal(),
+		}
+	case *EventBuf_Asserted:
+		event = arbbridge.AssertedEvent{
+			ChainInfo:     info,
+			PrevLeafHash:  ev.Asserted.PrevLeafHash.Unmarshal(),
+			Params:        ev.Asserted.Params.Unmarshal(),
+			Claim:         ev.Asserted.Claim.Unmarshal(),
+			MaxInboxTop:   ev.Asserted.MaxInboxTop.Unmarshal(),
+			MaxInboxCount: ev.Asserted.MaxInboxCount.Unmarshal(),
+		}
+	case *EventBuf_Confirmed:
+		event = arbbridge.ConfirmedEvent{
+			ChainInfo: info,
+			NodeHash:  ev.Confirmed.NodeHash.Unmarshal(),
+		}
+	case *EventBuf_ConfirmedAssertion:
+		event = arbbridge.ConfirmedAssertionEvent{
+			ChainInfo:   info,
+			LogsAccHash: unmarshalHashes(ev.ConfirmedAssertion.LogsAccHash),
+		}
+	case *EventBuf_MessageDelivered:
+		val, err := value.UnmarshalValue(bytes.NewReader(ev.MessageDelivered.Value))
+		if err != nil {
+			return Record{}, err
+		}
+		msg, err := message.UnmarshalFromCheckpoint(message.MessageType(ev.MessageDelivered.MessageType), val)
+		if err != nil {
+			return Record{}, err
+		}
+		event = arbbridge.MessageDeliveredEvent{
+			ChainInfo: info,
+			Message:   msg,
+		}
+	case *EventBuf_InitiateChallenge:
+		event = arbbridge.InitiateChallengeEvent{
+			ChainInfo: info,
+			Deadline:  ev.InitiateChallenge.Deadline.Unmarshal(),
+		}
+	case *EventBuf_AsserterTimeout:
+		event = arbbridge.AsserterTimeoutEvent{ChainInfo: info}
+	case *EventBuf_ChallengerTimeout:
+		event = arbbridge.ChallengerTimeoutEvent{ChainInfo: info}
+	case *EventBuf_ContinueChallenge:
+		event = arbbridge.ContinueChallengeEvent{
+			ChainInfo:    info,
+			SegmentIndex: ev.ContinueChallenge.SegmentIndex.Unmarshal(),
+			Deadline:     ev.ContinueChallenge.Deadline.Unmarshal(),
+		}
+	case *EventBuf_OneStepProof:
+		event = arbbridge.OneStepProofEvent{ChainInfo: info}
+	case *EventBuf_InboxTopBisection:
+		event = arbbridge.InboxTopBisectionEvent{
+			ChainInfo:   info,
+			ChainHashes: unmarshalHashes(ev.InboxTopBisection.ChainHashes),
+			TotalLength: ev.InboxTopBisection.TotalLength.Unmarshal(),
+			Deadline:    ev.InboxTopBisection.Deadline.Unmarshal(),
+		}
+	case *EventBuf_MessagesBisection:
+		event = arbbridge.MessagesBisectionEvent{
+			ChainInfo:     info,
+			ChainHashes:   unmarshalHashes(ev.MessagesBisection.ChainHashes),
+			SegmentHashes: unmarshalHashes(ev.MessagesBisection.SegmentHashes),
+			TotalLength:   ev.MessagesBisection.TotalLength.Unmarshal(),
+			Deadline:      ev.MessagesBisection.Deadline.Unmarshal(),
+		}
+	case *EventBuf_ExecutionBisection:
+		assertions := make([]*valprotocol.ExecutionAssertionStub, 0, len(ev.ExecutionBisection.Assertions))
+		for _, assertion := range ev.ExecutionBisection.Assertions {
+			assertions = append(assertions, assertion.Unmarshal())
+		}
+		event = arbbridge.ExecutionBisectionEvent{
+			ChainInfo:  info,
+			Assertions: assertions,
+			TotalSteps: ev.ExecutionBisection.TotalSteps,
+			Deadline:   ev.ExecutionBisection.Deadline.Unmarshal(),
+		}
+	default:
+		return Record{}, fmt.Errorf("unknown event type %T in index", buf.Event)
+	}
+	return Record{Contract: buf.Contract.Unmarshal(), Event: event}, nil
+}
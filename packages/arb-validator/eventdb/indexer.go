@@ -0,0 +1,242 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventdb
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+)
+
+var retryDelay = time.Second * 5
+
+// Indexer follows the L1 chain and adds the events of the watched contracts
+// to an EventDB
+type Indexer struct {
+	client   arbbridge.ArbClient
+	db       *EventDB
+	watchers map[common.Address]arbbridge.ContractWatcher
+}
+
+// NewIndexer returns an indexer which resumes following the contracts
+// watched in db
+func NewIndexer(client arbbridge.ArbClient, db *EventDB) *Indexer {
+	return &Indexer{
+		client:   client,
+		db:       db,
+		watchers: make(map[common.Address]arbbridge.ContractWatcher),
+	}
+}
+
+// AddRollup starts indexing the given rollup chain. If the index has
+// already passed the block the rollup was created in, it's rewound so that
+// the rollup's full history is picked up. It must not be called while Run
+// is active.
+func (indexer *Indexer) AddRollup(ctx context.Context, rollup common.Address) error {
+	if _, found, err := indexer.db.Watch(rollup); err != nil || found {
+		return err
+	}
+	watcher, err := indexer.client.NewRollupWatcher(rollup)
+	if err != nil {
+		return err
+	}
+	creation, _, err := watcher.GetCreationInfo(ctx)
+	if err != nil {
+		return err
+	}
+	head := indexer.db.Head()
+	if head == nil || head.Height.Cmp(creation.Height) >= 0 {
+		prevHeight := new(big.Int).Sub(creation.Height.AsInt(), big.NewInt(1))
+		head, err = indexer.client.BlockIdForHeight(ctx, common.NewTimeBlocks(prevHeight))
+		if err != nil {
+			return err
+		}
+		if err := indexer.db.Rewind(head); err != nil {
+			return err
+		}
+		if err := indexer.syncWatchers(); err != nil {
+			return err
+		}
+	}
+	return indexer.db.AddWatch(rollup, RollupWatch, head)
+}
+
+// Run indexes new blocks until the context is cancelled, rolling the index
+// back whenever the L1 chain reorganizes
+func (indexer *Indexer) Run(ctx context.Context) error {
+	for {
+		err := indexer.follow(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Println("Event indexer stopped following chain:", err)
+		if err := indexer.rewindToCanonical(ctx); err != nil {
+			log.Println("Event indexer failed to check for reorg:", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+func (indexer *Indexer) follow(ctx context.Context) error {
+	head := indexer.db.Head()
+	if head == nil {
+		return errors.New("no rollups to index")
+	}
+	headers, err := indexer.client.SubscribeBlockHeaders(ctx, head)
+	if err != nil {
+		return err
+	}
+	for maybeBlockId := range headers {
+		if maybeBlockId.Err != nil {
+			return maybeBlockId.Err
+		}
+		blockId := maybeBlockId.BlockId
+		if blockId.Height.Cmp(indexer.db.Head().Height) <= 0 {
+			// The subscription starts with the head itself
+			continue
+		}
+		if err := indexer.indexBlock(ctx, blockId); err != nil {
+			return err
+		}
+	}
+	return errors.New("header subscription closed")
+}
+
+// indexBlock collects the block's events from every active watch, including
+// challenges started in the block itself
+func (indexer *Indexer) indexBlock(ctx context.Context, blockId *common.BlockId) error {
+	watches, err := indexer.db.Watches()
+	if err != nil {
+		return err
+	}
+	pending := make([]Watch, 0, len(watches))
+	for _, watch := range watches {
+		if watch.Active() {
+			pending = append(pending, watch)
+		}
+	}
+
+	var records []Record
+	for i := 0; i < len(pending); i++ {
+		contract := pending[i].Contract
+		watcher, ok := indexer.watchers[contract]
+		if !ok {
+			watcher, err = indexer.newWatcher(pending[i])
+			if err != nil {
+				return err
+			}
+			indexer.watchers[contract] = watcher
+		}
+		events, err := watcher.GetEvents(ctx, blockId)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if started, ok := event.(arbbridge.ChallengeStartedEvent); ok {
+				kind, err := challengeWatchKind(started.ChallengeType)
+				if err != nil {
+					return err
+				}
+				pending = append(pending, Watch{Contract: started.ChallengeContract, Kind: kind})
+			}
+			records = append(records, Record{Contract: contract, Event: event})
+		}
+	}
+	if err := indexer.db.AddBlock(blockId, records); err != nil {
+		return err
+	}
+	return indexer.syncWatchers()
+}
+
+// rewindToCanonical finds the most recent indexed block which is still part
+// of the L1 chain and rewinds the index to it
+func (indexer *Indexer) rewindToCanonical(ctx context.Context) error {
+	head := indexer.db.Head()
+	if head == nil {
+		return nil
+	}
+	height := new(big.Int).Set(head.Height.AsInt())
+	for {
+		blockHeight := common.NewTimeBlocks(new(big.Int).Set(height))
+		hash, found, err := indexer.db.BlockHash(blockHeight)
+		if err != nil {
+			return err
+		}
+		canonical, err := indexer.client.BlockIdForHeight(ctx, blockHeight)
+		if err != nil {
+			return err
+		}
+		// The block before the first indexed one isn't stored, but it was
+		// looked up by height so we can simply replace it
+		if !found || canonical.HeaderHash == hash {
+			if canonical.Equals(head) {
+				return nil
+			}
+			log.Println("Event indexer rewinding to", canonical)
+			if err := indexer.db.Rewind(canonical); err != nil {
+				return err
+			}
+			return indexer.syncWatchers()
+		}
+		height.Sub(height, big.NewInt(1))
+	}
+}
+
+// syncWatchers drops the watchers of contracts which are no longer active.
+// Watchers are created as they're needed by indexBlock.
+func (indexer *Indexer) syncWatchers() error {
+	watches, err := indexer.db.Watches()
+	if err != nil {
+		return err
+	}
+	active := make(map[common.Address]bool)
+	for _, watch := range watches {
+		if watch.Active() {
+			active[watch.Contract] = true
+		}
+	}
+	for contract := range indexer.watchers {
+		if !active[contract] {
+			delete(indexer.watchers, contract)
+		}
+	}
+	return nil
+}
+
+func (indexer *Indexer) newWatcher(watch Watch) (arbbridge.ContractWatcher, error) {
+	switch watch.Kind {
+	case RollupWatch:
+		return indexer.client.NewRollupWatcher(watch.Contract)
+	case InboxTopChallengeWatch:
+		return indexer.client.NewInboxTopChallengeWatcher(watch.Contract)
+	case MessagesChallengeWatch:
+		return indexer.client.NewMessagesChallengeWatcher(watch.Contract)
+	case ExecutionChallengeWatch:
+		return indexer.client.NewExecutionChallengeWatcher(watch.Contract)
+	default:
+		return nil, errors.New("unknown watch kind")
+	}
+}
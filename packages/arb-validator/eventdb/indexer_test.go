@@ -0,0 +1,226 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// fakeChain serves blocks and events from a chain which reorganizes the
+// first time its headers are followed to the tip
+type fakeChain struct {
+	arbbridge.ArbClient
+
+	sync.Mutex
+	blocks    map[int64]*common.BlockId
+	events    map[common.Address]map[common.Hash][]arbbridge.Event
+	created   map[common.Address]int64
+	reorg     func()
+	subscribe int
+}
+
+type fakeWatcher struct {
+	arbbridge.ArbRollupWatcher
+	chain    *fakeChain
+	contract common.Address
+}
+
+func (w fakeWatcher) GetEvents(_ context.Context, blockId *common.BlockId) ([]arbbridge.Event, error) {
+	w.chain.Lock()
+	defer w.chain.Unlock()
+	return w.chain.events[w.contract][blockId.HeaderHash], nil
+}
+
+// GetCreationInfo reports the rollup as created in block 11 unless the
+// chain says otherwise
+func (w fakeWatcher) GetCreationInfo(context.Context) (*common.BlockId, common.Hash, error) {
+	height, ok := w.chain.created[w.contract]
+	if !ok {
+		height = 11
+	}
+	return w.chain.block(height), common.Hash{}, nil
+}
+
+func (c *fakeChain) block(height int64) *common.BlockId {
+	c.Lock()
+	defer c.Unlock()
+	return c.blocks[height]
+}
+
+func (c *fakeChain) addEvent(contract common.Address, event arbbridge.Event) {
+	if c.events[contract] == nil {
+		c.events[contract] = make(map[common.Hash][]arbbridge.Event)
+	}
+	hash := event.GetChainInfo().BlockId.HeaderHash
+	c.events[contract][hash] = append(c.events[contract][hash], event)
+}
+
+func (c *fakeChain) NewRollupWatcher(address common.Address) (arbbridge.ArbRollupWatcher, error) {
+	return fakeWatcher{chain: c, contract: address}, nil
+}
+
+func (c *fakeChain) NewExecutionChallengeWatcher(address common.Address) (arbbridge.ExecutionChallengeWatcher, error) {
+	return fakeWatcher{chain: c, contract: address}, nil
+}
+
+func (c *fakeChain) BlockIdForHeight(_ context.Context, height *common.TimeBlocks) (*common.BlockId, error) {
+	return c.block(height.AsInt().Int64()), nil
+}
+
+func (c *fakeChain) SubscribeBlockHeaders(ctx context.Context, start *common.BlockId) (<-chan arbbridge.MaybeBlockId, error) {
+	c.Lock()
+	c.subscribe++
+	first := c.subscribe == 1
+	c.Unlock()
+	headers := make(chan arbbridge.MaybeBlockId, 100)
+	go func() {
+		defer close(headers)
+		for height := start.Height.AsInt().Int64(); c.block(height) != nil; height++ {
+			headers <- arbbridge.MaybeBlockId{BlockId: c.block(height)}
+		}
+		if first {
+			c.Lock()
+			c.reorg()
+			c.Unlock()
+			headers <- arbbridge.MaybeBlockId{Err: errors.New("reorg occured")}
+			return
+		}
+		<-ctx.Done()
+	}()
+	return headers, nil
+}
+
+func TestIndexerFollowsReorg(t *testing.T) {
+	retryDelay = time.Millisecond
+	chain := &fakeChain{
+		blocks: make(map[int64]*common.BlockId),
+		events: make(map[common.Address]map[common.Hash][]arbbridge.Event),
+	}
+	for height := int64(10); height <= 13; height++ {
+		chain.blocks[height] = blockId(height, 0)
+	}
+	b11 := chain.blocks[11]
+	b12 := chain.blocks[12]
+	chain.addEvent(rollupA, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(b11, 0, 1), Staker: stakerX})
+	chain.addEvent(rollupA, arbbridge.ChallengeStartedEvent{
+		ChainInfo:         chainInfo(b12, 0, 2),
+		Asserter:          stakerX,
+		Challenger:        stakerY,
+		ChallengeType:     valprotocol.InvalidExecutionChildType,
+		ChallengeContract: challengeC,
+	})
+	chain.addEvent(challengeC, arbbridge.OneStepProofEvent{ChainInfo: chainInfo(b12, 1, 3)})
+	chain.reorg = func() {
+		for height := int64(12); height <= 14; height++ {
+			chain.blocks[height] = blockId(height, 1)
+		}
+		chain.addEvent(rollupA, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(chain.blocks[14], 0, 4), Staker: stakerY})
+	}
+
+	db := NewMemory()
+	indexer := NewIndexer(chain, db)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := indexer.AddRollup(ctx, rollupA); err != nil {
+		t.Fatal(err)
+	}
+	if !db.Head().Equals(blockId(10, 0)) {
+		t.Fatal("index doesn't start before the rollup was created")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- indexer.Run(ctx)
+	}()
+	deadline := time.After(time.Second * 5)
+	for !db.Head().Equals(blockId(14, 1)) {
+		select {
+		case <-deadline:
+			t.Fatal("indexer didn't reach the new tip")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	all, err := db.EventsInRange(nil, nil)
+	checkKinds(t, all, err, "StakeCreated", "StakeCreated")
+	if _, found, err := db.Watch(challengeC); err != nil || found {
+		t.Error("challenge from the abandoned fork is still watched", err)
+	}
+	hash, _, err := db.BlockHash(common.NewTimeBlocksInt(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != blockId(12, 1).HeaderHash {
+		t.Error("block from the abandoned fork is still indexed")
+	}
+}
+
+func TestIndexerAddsOlderRollup(t *testing.T) {
+	rollupB := common.Address{0xb}
+	for _, order := range [][]common.Address{{rollupA, rollupB}, {rollupB, rollupA}} {
+		chain := &fakeChain{
+			blocks:  make(map[int64]*common.BlockId),
+			events:  make(map[common.Address]map[common.Hash][]arbbridge.Event),
+			created: map[common.Address]int64{rollupB: 13},
+		}
+		for height := int64(10); height <= 14; height++ {
+			chain.blocks[height] = blockId(height, 0)
+		}
+		chain.addEvent(rollupA, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(chain.blocks[11], 0, 1), Staker: stakerX})
+		chain.addEvent(rollupB, arbbridge.StakeCreatedEvent{ChainInfo: chainInfo(chain.blocks[13], 0, 2), Staker: stakerY})
+
+		db := NewMemory()
+		indexer := NewIndexer(chain, db)
+		ctx := context.Background()
+		indexTo := func(height int64) {
+			for next := db.Head().Height.AsInt().Int64() + 1; next <= height; next++ {
+				if err := indexer.indexBlock(ctx, chain.block(next)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+
+		// Index past both creations with only the first rollup, then add
+		// the second, which rewinds the index if it's older
+		if err := indexer.AddRollup(ctx, order[0]); err != nil {
+			t.Fatal(err)
+		}
+		indexTo(14)
+		if err := indexer.AddRollup(ctx, order[1]); err != nil {
+			t.Fatal(err)
+		}
+		indexTo(14)
+
+		for _, rollup := range order {
+			if _, found, err := db.Watch(rollup); err != nil || !found {
+				t.Fatal("rollup", rollup, "is no longer watched after adding", order[1], err)
+			}
+			events, err := db.ContractEvents(rollup, nil, nil)
+			checkKinds(t, events, err, "StakeCreated")
+		}
+	}
+}
@@ -0,0 +1,307 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventdb
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+)
+
+// RPCServer exposes the index over gorilla JSON-RPC
+type RPCServer struct {
+	db *EventDB
+}
+
+func NewRPCServer(db *EventDB) *RPCServer {
+	return &RPCServer{db: db}
+}
+
+// RangeArgs limits a query to the events emitted between two L1 heights
+// inclusive. A missing bound isn't used to filter.
+type RangeArgs struct {
+	FromBlock *uint64 `json:"fromBlock"`
+	ToBlock   *uint64 `json:"toBlock"`
+}
+
+func (args RangeArgs) bounds() (*common.TimeBlocks, *common.TimeBlocks) {
+	var from, to *common.TimeBlocks
+	if args.FromBlock != nil {
+		from = common.NewTimeBlocks(new(big.Int).SetUint64(*args.FromBlock))
+	}
+	if args.ToBlock != nil {
+		to = common.NewTimeBlocks(new(big.Int).SetUint64(*args.ToBlock))
+	}
+	return from, to
+}
+
+type GetEventsArgs struct {
+	RangeArgs
+	// Contract limits the results to a rollup or challenge if set
+	Contract string `json:"contract"`
+	// Staker limits the results to the events involving a staker if set
+	Staker string `json:"staker"`
+}
+
+type GetAssertionsArgs struct {
+	RangeArgs
+	Staker string `json:"staker"`
+}
+
+type GetChallengesArgs struct {
+	RangeArgs
+}
+
+type GetStatusArgs struct {
+}
+
+// EventInfo is an indexed event. Data holds the fields specific to the
+// event's type with hashes and addresses in hex and big numbers in decimal.
+type EventInfo struct {
+	Contract    string                 `json:"contract"`
+	Type        string                 `json:"type"`
+	BlockHeight string                 `json:"blockHeight"`
+	BlockHash   string                 `json:"blockHash"`
+	LogIndex    uint64                 `json:"logIndex"`
+	TxHash      string                 `json:"txHash"`
+	Data        map[string]interface{} `json:"data"`
+}
+
+type ChallengeInfo struct {
+	Rollup    string     `json:"rollup"`
+	Started   *EventInfo `json:"started"`
+	Completed *EventInfo `json:"completed,omitempty"`
+}
+
+type WatchInfo struct {
+	Contract string `json:"contract"`
+	Kind     string `json:"kind"`
+	Since    string `json:"since"`
+	Until    string `json:"until,omitempty"`
+}
+
+type GetEventsReply struct {
+	Events []*EventInfo `json:"events"`
+}
+
+type GetChallengesReply struct {
+	Challenges []*ChallengeInfo `json:"challenges"`
+}
+
+type GetStatusReply struct {
+	HeadHeight string       `json:"headHeight"`
+	HeadHash   string       `json:"headHash"`
+	Watches    []*WatchInfo `json:"watches"`
+}
+
+func parseAddress(name string, address string) (common.Address, error) {
+	data, err := hexutil.Decode(address)
+	if err != nil || len(data) != 20 {
+		return common.Address{}, errors.New("invalid " + name + " address")
+	}
+	var ret common.Address
+	copy(ret[:], data)
+	return ret, nil
+}
+
+// GetEvents returns the indexed events, optionally limited to a single
+// contract or staker
+func (m *RPCServer) GetEvents(r *http.Request, args *GetEventsArgs, reply *GetEventsReply) error {
+	from, to := args.bounds()
+	var records []Record
+	var err error
+	switch {
+	case args.Contract != "" && args.Staker != "":
+		return errors.New("can't filter by both contract and staker")
+	case args.Contract != "":
+		var contract common.Address
+		contract, err = parseAddress("contract", args.Contract)
+		if err == nil {
+			records, err = m.db.ContractEvents(contract, from, to)
+		}
+	case args.Staker != "":
+		var staker common.Address
+		staker, err = parseAddress("staker", args.Staker)
+		if err == nil {
+			records, err = m.db.StakerEvents(staker, from, to)
+		}
+	default:
+		records, err = m.db.EventsInRange(from, to)
+	}
+	if err != nil {
+		return err
+	}
+	reply.Events = recordInfos(records)
+	return nil
+}
+
+// GetAssertions returns the assertions made by a staker
+func (m *RPCServer) GetAssertions(r *http.Request, args *GetAssertionsArgs, reply *GetEventsReply) error {
+	staker, err := parseAddress("staker", args.Staker)
+	if err != nil {
+		return err
+	}
+	from, to := args.bounds()
+	records, err := m.db.Assertions(staker, from, to)
+	if err != nil {
+		return err
+	}
+	reply.Events = recordInfos(records)
+	return nil
+}
+
+// GetChallenges returns the challenges started in a range of blocks along
+// with their outcomes
+func (m *RPCServer) GetChallenges(r *http.Request, args *GetChallengesArgs, reply *GetChallengesReply) error {
+	from, to := args.bounds()
+	challenges, err := m.db.Challenges(from, to)
+	if err != nil {
+		return err
+	}
+	reply.Challenges = make([]*ChallengeInfo, 0, len(challenges))
+	for _, challenge := range challenges {
+		info := &ChallengeInfo{
+			Rollup:  challenge.Rollup.Hex(),
+			Started: recordInfo(Record{Contract: challenge.Rollup, Event: challenge.Started}),
+		}
+		if challenge.Completed != nil {
+			info.Completed = recordInfo(Record{Contract: challenge.Rollup, Event: *challenge.Completed})
+		}
+		reply.Challenges = append(reply.Challenges, info)
+	}
+	return nil
+}
+
+// GetStatus returns the most recently indexed block and the watched
+// contracts
+func (m *RPCServer) GetStatus(r *http.Request, args *GetStatusArgs, reply *GetStatusReply) error {
+	if head := m.db.Head(); head != nil {
+		reply.HeadHeight = head.Height.String()
+		reply.HeadHash = head.HeaderHash.String()
+	}
+	watches, err := m.db.Watches()
+	if err != nil {
+		return err
+	}
+	reply.Watches = make([]*WatchInfo, 0, len(watches))
+	for _, watch := range watches {
+		info := &WatchInfo{
+			Contract: watch.Contract.Hex(),
+			Kind:     watch.Kind.String(),
+			Since:    watch.Since.String(),
+		}
+		if watch.Until != nil {
+			info.Until = watch.Until.String()
+		}
+		reply.Watches = append(reply.Watches, info)
+	}
+	return nil
+}
+
+func recordInfos(records []Record) []*EventInfo {
+	infos := make([]*EventInfo, 0, len(records))
+	for _, record := range records {
+		infos = append(infos, recordInfo(record))
+	}
+	return infos
+}
+
+func recordInfo(record Record) *EventInfo {
+	chainInfo := record.Event.GetChainInfo()
+	data, _ := jsonValue(reflect.ValueOf(record.Event)).(map[string]interface{})
+	return &EventInfo{
+		Contract:    record.Contract.Hex(),
+		Type:        record.Kind(),
+		BlockHeight: chainInfo.BlockId.Height.String(),
+		BlockHash:   chainInfo.BlockId.HeaderHash.String(),
+		LogIndex:    uint64(chainInfo.LogIndex),
+		TxHash:      common.Hash(chainInfo.TxHash).String(),
+		Data:        data,
+	}
+}
+
+var (
+	addressType    = reflect.TypeOf(common.Address{})
+	hashType       = reflect.TypeOf(common.Hash{})
+	bigIntType     = reflect.TypeOf(&big.Int{})
+	timeBlocksType = reflect.TypeOf(&common.TimeBlocks{})
+	timeTicksType  = reflect.TypeOf(common.TimeTicks{})
+	chainInfoType  = reflect.TypeOf(arbbridge.ChainInfo{})
+)
+
+// jsonValue converts an event's fields to values which encode readably as
+// JSON. Embedded ChainInfo is left out since EventInfo already holds it.
+func jsonValue(v reflect.Value) interface{} {
+	switch v.Type() {
+	case addressType:
+		return v.Interface().(common.Address).Hex()
+	case hashType:
+		return v.Interface().(common.Hash).String()
+	case bigIntType, timeBlocksType:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Interface().(interface{ String() string }).String()
+	case timeTicksType:
+		return v.Interface().(common.TimeTicks).Val.String()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return jsonValue(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" || field.Type == chainInfoType {
+				continue
+			}
+			if field.Anonymous {
+				// Delivered messages embed the message that was sent
+				if embedded, ok := jsonValue(v.Field(i)).(map[string]interface{}); ok {
+					for name, val := range embedded {
+						fields[name] = val
+					}
+					continue
+				}
+			}
+			fields[field.Name] = jsonValue(v.Field(i))
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(data), v)
+			return hexutil.Encode(data)
+		}
+		items := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, jsonValue(v.Index(i)))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eventdb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRPCServer(t *testing.T) {
+	db := NewMemory()
+	addHistory(t, db)
+	server := NewRPCServer(db)
+
+	to := uint64(11)
+	eventsReply := &GetEventsReply{}
+	err := server.GetEvents(nil, &GetEventsArgs{Staker: stakerX.Hex(), RangeArgs: RangeArgs{ToBlock: &to}}, eventsReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eventsReply.Events) != 3 {
+		t.Fatalf("expected 3 events for staker in block 11 but got %v", len(eventsReply.Events))
+	}
+	if err := server.GetEvents(nil, &GetEventsArgs{Staker: "0x12"}, &GetEventsReply{}); err == nil {
+		t.Error("accepted an invalid staker address")
+	}
+
+	assertionsReply := &GetEventsReply{}
+	if err := server.GetAssertions(nil, &GetAssertionsArgs{Staker: stakerX.Hex()}, assertionsReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(assertionsReply.Events) != 1 {
+		t.Fatalf("expected one assertion but got %v", len(assertionsReply.Events))
+	}
+	assertion := assertionsReply.Events[0]
+	if assertion.Type != "Asserted" || assertion.Data["MaxInboxCount"] != "3" {
+		t.Errorf("unexpected assertion %v", assertion)
+	}
+	if _, err := json.Marshal(assertion); err != nil {
+		t.Error(err)
+	}
+
+	challengesReply := &GetChallengesReply{}
+	if err := server.GetChallenges(nil, &GetChallengesArgs{}, challengesReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(challengesReply.Challenges) != 1 || challengesReply.Challenges[0].Completed == nil {
+		t.Fatal("expected one completed challenge")
+	}
+	if challengesReply.Challenges[0].Completed.Data["Winner"] != stakerX.Hex() {
+		t.Errorf("unexpected outcome %v", challengesReply.Challenges[0].Completed.Data)
+	}
+
+	statusReply := &GetStatusReply{}
+	if err := server.GetStatus(nil, &GetStatusArgs{}, statusReply); err != nil {
+		t.Fatal(err)
+	}
+	if statusReply.HeadHeight != "13" || len(statusReply.Watches) != 2 {
+		t.Errorf("unexpected status %v", statusReply)
+	}
+}
@@ -15,6 +15,7 @@ require (
 	github.com/offchainlabs/arbitrum/packages/arb-validator-core v0.4.3
 	github.com/pkg/errors v0.9.1
 	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
+	google.golang.org/grpc v1.23.1
 )
 
 replace github.com/offchainlabs/arbitrum/packages/arb-avm-go => ../arb-avm-go
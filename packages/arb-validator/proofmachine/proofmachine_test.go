@@ -120,3 +120,22 @@ func TestValidateProof(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProofLocal(t *testing.T) {
+	testMachines := []string{
+		"opcodetestmath.ao",
+		"opcodetestlogic.ao",
+		"opcodetesthash.ao",
+		"opcodetestethhash2.ao",
+		"opcodeteststack.ao",
+		"opcodetestdup.ao",
+		"opcodetesttuple.ao",
+	}
+	conn := NewLocalConnection([2]uint64{0, 10000})
+	for _, machName := range testMachines {
+		machName := machName // capture range variable
+		t.Run(machName, func(t *testing.T) {
+			runTestValidateProof(t, machName, conn)
+		})
+	}
+}
@@ -29,10 +29,28 @@ import (
 type FinalizedAssertion struct {
 	Assertion     *protocol.ExecutionAssertion // Disputable assertion
 	OnChainTxHash common.Hash                  // Disputable assertion on-chain Tx hash
+	NodeHash      common.Hash                  // Valid node created by the assertion
+	PrevNodeHash  common.Hash                  // Valid node created by the previous assertion, if still known
+}
+
+// PendingAssertion is an assertion this validator prepared but which isn't on
+// chain yet
+type PendingAssertion struct {
+	Assertion    *protocol.ExecutionAssertion
+	PrevNodeHash common.Hash // Node the assertion will be made on
+}
+
+// ConfirmedAssertion reports that the valid node with the given hash was
+// confirmed, along with every node before it
+type ConfirmedAssertion struct {
+	NodeHash common.Hash
+	BlockId  *common.BlockId // L1 block containing the confirmation
 }
 
 type AssertionListener struct {
 	CompletedAssertionChan chan FinalizedAssertion
+	PendingAssertionChan   chan PendingAssertion
+	ConfirmedAssertionChan chan ConfirmedAssertion
 }
 
 func (al *AssertionListener) StakeCreated(context.Context, *ChainObserver, arbbridge.StakeCreatedEvent) {
@@ -49,13 +67,36 @@ func (al *AssertionListener) CompletedChallenge(context.Context, *ChainObserver,
 }
 func (al *AssertionListener) SawAssertion(context.Context, *ChainObserver, arbbridge.AssertedEvent) {
 }
-func (al *AssertionListener) ConfirmedNode(context.Context, *ChainObserver, arbbridge.ConfirmedEvent) {
+func (al *AssertionListener) ConfirmedNode(ctx context.Context, chain *ChainObserver, ev arbbridge.ConfirmedEvent) {
+	if al.ConfirmedAssertionChan == nil {
+		return
+	}
+	// Confirming an invalid node confirms the valid assertions before it
+	node := chain.nodeGraph.nodeFromHash[ev.NodeHash]
+	for node != nil && (node.disputable == nil || node.linkType != valprotocol.ValidChildType) {
+		node = node.prev
+	}
+	if node == nil {
+		return
+	}
+	al.ConfirmedAssertionChan <- ConfirmedAssertion{
+		NodeHash: node.hash,
+		BlockId:  ev.BlockId.Clone(),
+	}
 }
 func (al *AssertionListener) PrunedLeaf(context.Context, *ChainObserver, arbbridge.PrunedEvent) {}
 func (al *AssertionListener) MessageDelivered(context.Context, *ChainObserver, arbbridge.MessageDeliveredEvent) {
 }
 
-func (al *AssertionListener) AssertionPrepared(context.Context, *ChainObserver, *preparedAssertion) {}
+func (al *AssertionListener) AssertionPrepared(ctx context.Context, chain *ChainObserver, prepared *preparedAssertion) {
+	if al.PendingAssertionChan == nil {
+		return
+	}
+	al.PendingAssertionChan <- PendingAssertion{
+		Assertion:    prepared.assertion,
+		PrevNodeHash: prepared.leafHash,
+	}
+}
 func (al *AssertionListener) ConfirmableNodes(context.Context, *ChainObserver, *valprotocol.ConfirmOpportunity) {
 }
 func (al *AssertionListener) PrunableLeafs(context.Context, *ChainObserver, []valprotocol.PruneParams) {
@@ -66,9 +107,21 @@ func (al *AssertionListener) OldStakes(context.Context, *ChainObserver, []recove
 
 func (al *AssertionListener) AdvancedCalculatedValidNode(context.Context, *ChainObserver, common.Hash) {
 }
-func (al *AssertionListener) AdvancedKnownAssertion(ctx context.Context, chain *ChainObserver, assertion *protocol.ExecutionAssertion, txHash common.Hash) {
+func (al *AssertionListener) AdvancedKnownAssertion(ctx context.Context, chain *ChainObserver, assertion *protocol.ExecutionAssertion, txHash common.Hash, nodeHash common.Hash) {
+	var prevNodeHash common.Hash
+	if node, ok := chain.nodeGraph.nodeFromHash[nodeHash]; ok {
+		prev := node.prev
+		for prev != nil && (prev.disputable == nil || prev.linkType != valprotocol.ValidChildType) {
+			prev = prev.prev
+		}
+		if prev != nil {
+			prevNodeHash = prev.hash
+		}
+	}
 	al.CompletedAssertionChan <- FinalizedAssertion{
 		Assertion:     assertion,
 		OnChainTxHash: txHash,
+		NodeHash:      nodeHash,
+		PrevNodeHash:  prevNodeHash,
 	}
 }
@@ -0,0 +1,100 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+func confirmedEvent(nodeHash common.Hash, height int64) arbbridge.ConfirmedEvent {
+	return arbbridge.ConfirmedEvent{
+		ChainInfo: arbbridge.ChainInfo{
+			BlockId: &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(height)), HeaderHash: common.Hash{}},
+		},
+		NodeHash: nodeHash,
+	}
+}
+
+func TestAssertionListenerConfirmedNode(t *testing.T) {
+	chain, err := setUpChain(common.Address{9}, "dummy", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, afterFirst := assertFromMachine(chain, chain.nodeGraph.latestConfirmed, chain.nodeGraph.latestConfirmed.machine, nil)
+	second, _ := assertFromMachine(chain, first, afterFirst, nil)
+	invalidSecond := first.GetSuccessor(chain.nodeGraph.NodeGraph, valprotocol.InvalidExecutionChildType)
+
+	al := &AssertionListener{ConfirmedAssertionChan: make(chan ConfirmedAssertion, 1)}
+	ctx := context.Background()
+	expectConfirmed := func(nodeHash common.Hash, height int64) {
+		t.Helper()
+		select {
+		case confirmed := <-al.ConfirmedAssertionChan:
+			if confirmed.NodeHash != nodeHash || confirmed.BlockId.Height.AsInt().Int64() != height {
+				t.Error("expected confirmation of", nodeHash, "at", height, "but got", confirmed.NodeHash, "at", confirmed.BlockId.Height)
+			}
+		default:
+			t.Error("expected confirmation of", nodeHash)
+		}
+	}
+	expectNothing := func() {
+		t.Helper()
+		select {
+		case confirmed := <-al.ConfirmedAssertionChan:
+			t.Error("unexpected confirmation of", confirmed.NodeHash)
+		default:
+		}
+	}
+
+	al.ConfirmedNode(ctx, chain, confirmedEvent(first.hash, 20))
+	expectConfirmed(first.hash, 20)
+
+	// Confirming the invalid sibling of second confirms the last valid
+	// assertion before it
+	al.ConfirmedNode(ctx, chain, confirmedEvent(invalidSecond.hash, 21))
+	expectConfirmed(first.hash, 21)
+
+	al.ConfirmedNode(ctx, chain, confirmedEvent(second.hash, 22))
+	expectConfirmed(second.hash, 22)
+
+	// Neither the initial node nor an unknown node were created by an
+	// assertion
+	al.ConfirmedNode(ctx, chain, confirmedEvent(chain.nodeGraph.latestConfirmed.hash, 23))
+	expectNothing()
+	al.ConfirmedNode(ctx, chain, confirmedEvent(common.Hash{0xde, 0xad}, 24))
+	expectNothing()
+
+	// A listener that doesn't track confirmations ignores them
+	(&AssertionListener{}).ConfirmedNode(ctx, chain, confirmedEvent(first.hash, 25))
+}
+
+func TestAssertionListenerPrepared(t *testing.T) {
+	al := &AssertionListener{PendingAssertionChan: make(chan PendingAssertion, 1)}
+	assertion := protocol.NewExecutionAssertion(common.Hash{1}, false, 10, nil, nil)
+	al.AssertionPrepared(context.Background(), nil, &preparedAssertion{leafHash: common.Hash{2}, assertion: assertion})
+	pending := <-al.PendingAssertionChan
+	if pending.Assertion != assertion || pending.PrevNodeHash != (common.Hash{2}) {
+		t.Error("wrong pending assertion", pending)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
+)
+
+// CallBase describes the state a call ran on so that its result can be
+// reported with the right finality
+type CallBase struct {
+	// NodeHash is the node whose machine the call ran on
+	NodeHash common.Hash
+	// Confirmed is set if the node had been confirmed when the call ran
+	Confirmed bool
+	// Pending is set if the call also ran messages which haven't been
+	// included in an assertion
+	Pending bool
+}
+
+// callBase must be called with the lock held
+func (chain *ChainObserver) callBase(node *Node) CallBase {
+	return CallBase{NodeHash: node.hash, Confirmed: chain.isConfirmed(node)}
+}
+
+// isConfirmed checks whether the node is the latest confirmed node or one of
+// its predecessors. It must be called with the lock held.
+func (chain *ChainObserver) isConfirmed(node *Node) bool {
+	for confirmed := chain.nodeGraph.latestConfirmed; confirmed != nil && confirmed.depth >= node.depth; confirmed = confirmed.prev {
+		if confirmed == node {
+			return true
+		}
+	}
+	return false
+}
+
+// LatestCallState returns a copy of the latest known valid machine and the
+// node it came from
+func (chain *ChainObserver) LatestCallState() (machine.Machine, CallBase) {
+	chain.RLock()
+	defer chain.RUnlock()
+	return chain.calculatedValidNode.machine.Clone(), chain.callBase(chain.calculatedValidNode)
+}
+
+// MachineAfterNode returns a copy of the machine in the state given by the
+// node with the given hash, which must still be retained in the node graph.
+// Any replay needed to rebuild the machine runs after releasing the chain
+// lock.
+func (chain *ChainObserver) MachineAfterNode(nodeHash common.Hash) (machine.Machine, CallBase, error) {
+	chain.RLock()
+	node, ok := chain.nodeGraph.nodeFromHash[nodeHash]
+	if !ok {
+		chain.RUnlock()
+		return nil, CallBase{}, fmt.Errorf("node %v is unknown or has been pruned", nodeHash)
+	}
+	base := chain.callBase(node)
+	plan, err := chain.planReplay(node)
+	chain.RUnlock()
+	if err != nil {
+		return nil, CallBase{}, err
+	}
+	mach, err := plan.run()
+	return mach, base, err
+}
+
+// PendingCallState returns a copy of the latest known valid machine along
+// with the messages which have been delivered to the inbox on L1 but haven't
+// yet been included in an assertion after that machine
+func (chain *ChainObserver) PendingCallState() (machine.Machine, *structures.VMInbox, CallBase, error) {
+	chain.RLock()
+	defer chain.RUnlock()
+	node := chain.calculatedValidNode
+	count := new(big.Int).Sub(chain.inbox.TopCount(), node.vmProtoData.InboxCount)
+	inbox, err := chain.inbox.GenerateVMInbox(node.vmProtoData.InboxTop, count.Uint64())
+	if err != nil {
+		return nil, nil, CallBase{}, err
+	}
+	base := chain.callBase(node)
+	base.Pending = true
+	return node.machine.Clone(), inbox, base, nil
+}
+
+// MachineAtHeight returns a copy of the latest known valid machine as of the
+// given L1 block height. Heights before the current block are served from the
+// newest checkpoint no higher than the height.
+func (chain *ChainObserver) MachineAtHeight(
+	ctx context.Context,
+	client arbbridge.ArbClient,
+	height *common.TimeBlocks,
+) (machine.Machine, CallBase, error) {
+	chain.RLock()
+	if height.Cmp(chain.latestBlockId.Height) >= 0 {
+		mach := chain.calculatedValidNode.machine.Clone()
+		base := chain.callBase(chain.calculatedValidNode)
+		chain.RUnlock()
+		return mach, base, nil
+	}
+	checkpointer := chain.checkpointer
+	chain.RUnlock()
+
+	var mach machine.Machine
+	var restoredNode *Node
+	err := checkpointer.RestoreStateAtHeight(ctx, client, height, func(chainObserverBytes []byte, restoreCtx checkpointing.RestoreContext) error {
+		chainObserverBuf := &ChainObserverBuf{}
+		if err := proto.Unmarshal(chainObserverBytes, chainObserverBuf); err != nil {
+			return err
+		}
+		restored, err := chainObserverBuf.UnmarshalFromCheckpoint(ctx, restoreCtx, nil)
+		if err != nil {
+			return err
+		}
+		restoredNode = restored.calculatedValidNode
+		mach, err = restored.machineAtNode(restoredNode)
+		return err
+	})
+	if err != nil {
+		return nil, CallBase{}, err
+	}
+
+	chain.RLock()
+	defer chain.RUnlock()
+	if node, ok := chain.nodeGraph.nodeFromHash[restoredNode.hash]; ok {
+		return mach, chain.callBase(node), nil
+	}
+	// A node we considered valid is only pruned once it's been confirmed
+	return mach, CallBase{
+		NodeHash:  restoredNode.hash,
+		Confirmed: restoredNode.depth <= chain.nodeGraph.latestConfirmed.depth,
+	}, nil
+}
@@ -0,0 +1,147 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
+)
+
+// heightClient reports a block with an empty header hash at every height
+// and counts how often it's asked
+type heightClient struct {
+	arbbridge.ArbClient
+	lookups int
+}
+
+func (c *heightClient) BlockIdForHeight(ctx context.Context, height *common.TimeBlocks) (*common.BlockId, error) {
+	c.lookups++
+	return &common.BlockId{Height: height, HeaderHash: common.Hash{}}, nil
+}
+
+func TestMachineAfterNode(t *testing.T) {
+	chain, err := setUpChain(common.Address{6}, "dummy", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, afterFirst := assertFromMachine(chain, chain.nodeGraph.latestConfirmed, chain.nodeGraph.latestConfirmed.machine, nil)
+	second, afterSecond := assertFromMachine(chain, first, afterFirst, nil)
+
+	// Neither node has a machine, so both are replayed from the root
+	mach, base, err := chain.MachineAfterNode(second.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mach.Hash() != afterSecond.Hash() {
+		t.Error("replayed machine", mach.Hash(), "doesn't match", afterSecond.Hash())
+	}
+	if base.NodeHash != second.hash || base.Confirmed || base.Pending {
+		t.Error("wrong call base for unconfirmed node", base)
+	}
+	if _, base, err := chain.MachineAfterNode(chain.nodeGraph.latestConfirmed.hash); err != nil || !base.Confirmed {
+		t.Error("latest confirmed node isn't confirmed", base, err)
+	}
+	if second.machine != nil {
+		t.Error("replaying a node shouldn't store its machine")
+	}
+
+	if _, _, err := chain.MachineAfterNode(common.Hash{0xde, 0xad}); err == nil {
+		t.Error("expected error for unknown node")
+	}
+}
+
+func TestPendingCallState(t *testing.T) {
+	chain, err := setUpChain(common.Address{7}, "dummy", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mach, inbox, base, err := chain.PendingCallState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mach.Hash() != chain.calculatedValidNode.machine.Hash() {
+		t.Error("pending call machine doesn't match the latest valid node")
+	}
+	if mach == chain.calculatedValidNode.machine {
+		t.Error("pending call machine must be a copy")
+	}
+	if inbox.Hash() != value.NewEmptyTuple().Hash() {
+		t.Error("expected no pending messages")
+	}
+	if !base.Pending || base.NodeHash != chain.calculatedValidNode.hash {
+		t.Error("wrong call base for pending call", base)
+	}
+}
+
+func TestMachineAtHeight(t *testing.T) {
+	chain, err := setUpChain(common.Address{8}, "fresh_rocksdb", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpointHeight := common.NewTimeBlocks(big.NewInt(7337))
+	ctx := checkpointing.NewCheckpointContextImpl()
+	buf, err := chain.marshalToBytes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doneChan := make(chan struct{})
+	chain.checkpointer.AsyncSaveCheckpoint(&common.BlockId{Height: checkpointHeight, HeaderHash: common.Hash{}}, buf, ctx, doneChan)
+	<-doneChan
+	expected := chain.calculatedValidNode.machine.Hash()
+	expectedNode := chain.calculatedValidNode.hash
+
+	// The chain moves on after the checkpoint
+	assertFromMachine(chain, chain.nodeGraph.latestConfirmed, chain.nodeGraph.latestConfirmed.machine, nil)
+	chain.latestBlockId = &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(9000)), HeaderHash: common.Hash{}}
+
+	client := &heightClient{}
+	mach, base, err := chain.MachineAtHeight(context.Background(), client, common.NewTimeBlocks(big.NewInt(8000)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mach.Hash() != expected {
+		t.Error("machine at height", mach.Hash(), "doesn't match checkpointed machine", expected)
+	}
+	if base.NodeHash != expectedNode || !base.Confirmed {
+		t.Error("wrong call base for checkpointed machine", base)
+	}
+	// Only the height with a checkpoint is looked up on L1, not every block
+	// between it and the requested height
+	if client.lookups != 1 {
+		t.Error("expected 1 L1 lookup but made", client.lookups)
+	}
+
+	if _, _, err := chain.MachineAtHeight(context.Background(), client, common.NewTimeBlocks(big.NewInt(7000))); err == nil {
+		t.Error("expected error for height before the oldest checkpoint")
+	}
+
+	mach, _, err = chain.MachineAtHeight(context.Background(), client, chain.latestBlockId.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mach.Hash() != chain.calculatedValidNode.machine.Hash() {
+		t.Error("machine at the latest height should be the latest valid machine")
+	}
+}
@@ -0,0 +1,211 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+const (
+	executionCacheSize  = 16
+	maxSpeculationDepth = 4
+)
+
+// executionKey identifies a machine execution by all of its inputs. The time
+// bounds are included since the machine is able to observe them. The wall
+// time limit is included since a run it cuts short isn't deterministic.
+type executionKey struct {
+	machineHash common.Hash
+	inboxHash   common.Hash
+	timeBounds  common.Hash
+	maxSteps    uint64
+	maxWallTime time.Duration
+}
+
+type executionResult struct {
+	assertion *protocol.ExecutionAssertion
+	stepsRun  uint64
+	machine   machine.Machine
+}
+
+type executionJob struct {
+	key        executionKey
+	mach       machine.Machine
+	timeBounds *protocol.TimeBoundsBlocks
+	inbox      value.TupleValue
+
+	done   chan struct{}
+	result *executionResult
+}
+
+func (job *executionJob) finished() bool {
+	select {
+	case <-job.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// wait blocks until the job has run and returns a result whose machine the
+// caller is free to modify
+func (job *executionJob) wait(ctx context.Context) (*executionResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-job.done:
+	}
+	return &executionResult{
+		assertion: job.result.assertion,
+		stepsRun:  job.result.stepsRun,
+		machine:   job.result.machine.Clone(),
+	}, nil
+}
+
+// executionPool runs machine executions on a fixed set of workers so that
+// several candidate successors can be evaluated at once. Requests for an
+// execution that is already running or recently finished share its result.
+type executionPool struct {
+	sync.Mutex
+	ctx       context.Context
+	jobs      chan *executionJob
+	running   map[executionKey]*executionJob
+	cache     map[executionKey]*executionJob
+	cacheKeys []executionKey
+}
+
+func newExecutionPool(ctx context.Context, workers int) *executionPool {
+	pool := &executionPool{
+		ctx:     ctx,
+		jobs:    make(chan *executionJob),
+		running: make(map[executionKey]*executionJob),
+		cache:   make(map[executionKey]*executionJob),
+	}
+	for i := 0; i < workers; i++ {
+		go pool.worker()
+	}
+	return pool
+}
+
+func defaultExecutionWorkers() int {
+	workers := runtime.NumCPU()
+	if workers < 2 {
+		workers = 2
+	}
+	return workers
+}
+
+// execute returns a job running mach for up to maxSteps steps. mach is
+// cloned before being run so it must not be modified while this is called.
+func (pool *executionPool) execute(
+	mach machine.Machine,
+	maxSteps uint64,
+	timeBounds *protocol.TimeBoundsBlocks,
+	inbox value.TupleValue,
+	maxWallTime time.Duration,
+) *executionJob {
+	key := executionKey{
+		machineHash: mach.Hash(),
+		inboxHash:   inbox.Hash(),
+		timeBounds:  timeBounds.AsValue().Hash(),
+		maxSteps:    maxSteps,
+		maxWallTime: maxWallTime,
+	}
+	pool.Lock()
+	defer pool.Unlock()
+	if job, ok := pool.cache[key]; ok {
+		return job
+	}
+	if job, ok := pool.running[key]; ok {
+		return job
+	}
+	job := &executionJob{
+		key:        key,
+		mach:       mach.Clone(),
+		timeBounds: timeBounds.Clone(),
+		inbox:      inbox,
+		done:       make(chan struct{}),
+	}
+	pool.running[key] = job
+	go func() {
+		select {
+		case <-pool.ctx.Done():
+		case pool.jobs <- job:
+		}
+	}()
+	return job
+}
+
+func (pool *executionPool) worker() {
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case job := <-pool.jobs:
+			pool.run(job)
+		}
+	}
+}
+
+func (pool *executionPool) run(job *executionJob) {
+	assertion, stepsRun := job.mach.ExecuteAssertion(
+		job.key.maxSteps,
+		job.timeBounds,
+		job.inbox,
+		job.key.maxWallTime,
+	)
+	job.result = &executionResult{
+		assertion: assertion,
+		stepsRun:  stepsRun,
+		machine:   job.mach,
+	}
+	job.mach = nil
+
+	pool.Lock()
+	delete(pool.running, job.key)
+	pool.addToCache(job.key, job)
+	// Running for exactly the number of steps taken with no wall time limit
+	// gives the same result, which is what a claim based on this execution
+	// will ask for. A run which may have been cut short by its wall time
+	// limit can't stand in for any other number of steps.
+	exactKey := job.key
+	exactKey.maxSteps = stepsRun
+	exactKey.maxWallTime = 0
+	if _, ok := pool.cache[exactKey]; !ok && exactKey != job.key {
+		pool.addToCache(exactKey, job)
+	}
+	pool.Unlock()
+	close(job.done)
+}
+
+// addToCache must be called with pool locked
+func (pool *executionPool) addToCache(key executionKey, job *executionJob) {
+	pool.cache[key] = job
+	pool.cacheKeys = append(pool.cacheKeys, key)
+	for len(pool.cacheKeys) > executionCacheSize {
+		delete(pool.cache, pool.cacheKeys[0])
+		pool.cacheKeys = pool.cacheKeys[1:]
+	}
+}
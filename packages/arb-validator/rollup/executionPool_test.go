@@ -0,0 +1,107 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// wallTimeMachine runs for the requested number of steps unless it's given
+// a wall time limit, in which case it runs out of time halfway through
+type wallTimeMachine struct{}
+
+func (m wallTimeMachine) Hash() common.Hash {
+	return common.Hash{1}
+}
+
+func (m wallTimeMachine) Clone() machine.Machine {
+	return m
+}
+
+func (m wallTimeMachine) PrintState() {}
+
+func (m wallTimeMachine) CurrentStatus() machine.Status {
+	return machine.Extensive
+}
+
+func (m wallTimeMachine) IsBlocked(*common.TimeBlocks, bool) machine.BlockReason {
+	return nil
+}
+
+func (m wallTimeMachine) ExecuteAssertion(
+	maxSteps uint64,
+	_ *protocol.TimeBoundsBlocks,
+	_ value.TupleValue,
+	maxWallTime time.Duration,
+) (*protocol.ExecutionAssertion, uint64) {
+	steps := maxSteps
+	if maxWallTime > 0 {
+		steps = maxSteps / 2
+	}
+	return protocol.NewExecutionAssertion(common.Hash{byte(steps)}, false, steps, nil, nil), steps
+}
+
+func (m wallTimeMachine) MarshalForProof() ([]byte, error) {
+	return nil, nil
+}
+
+func (m wallTimeMachine) Checkpoint(machine.CheckpointStorage) bool {
+	return false
+}
+
+func TestExecutionPoolWallTimeLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := newExecutionPool(ctx, 2)
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocksInt(0),
+		End:   common.NewTimeBlocksInt(10),
+	}
+	inbox := value.NewEmptyTuple()
+
+	// Our own assertion is cut short by its wall time limit
+	own, err := pool.execute(wallTimeMachine{}, 100, timeBounds, inbox, time.Second).wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if own.stepsRun != 50 {
+		t.Fatalf("expected truncated run of 50 steps but got %v", own.stepsRun)
+	}
+
+	// Checking a full length claim with the same inputs must run in full
+	// rather than reuse the truncated result
+	claim, err := pool.execute(wallTimeMachine{}, 100, timeBounds, inbox, 0).wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim.stepsRun != 100 {
+		t.Errorf("expected full run of 100 steps but got %v", claim.stepsRun)
+	}
+
+	// A claim for exactly the steps our own run took reuses its result
+	job := pool.execute(wallTimeMachine{}, 50, timeBounds, inbox, 0)
+	if !job.finished() {
+		t.Error("expected claim matching our truncated run to be cached")
+	}
+}
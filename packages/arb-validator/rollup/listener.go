@@ -55,7 +55,7 @@ type ChainListener interface {
 	OldStakes(context.Context, *ChainObserver, []recoverStakeOldParams)
 
 	AdvancedCalculatedValidNode(context.Context, *ChainObserver, common.Hash)
-	AdvancedKnownAssertion(context.Context, *ChainObserver, *protocol.ExecutionAssertion, common.Hash)
+	AdvancedKnownAssertion(context.Context, *ChainObserver, *protocol.ExecutionAssertion, common.Hash, common.Hash)
 }
 
 type StakingKey struct {
@@ -63,15 +63,45 @@ type StakingKey struct {
 	contract arbbridge.ArbRollup
 }
 
+type ChallengeRole uint8
+
+const (
+	ChallengeAsserter ChallengeRole = iota
+	ChallengeChallenger
+)
+
+func (r ChallengeRole) String() string {
+	switch r {
+	case ChallengeAsserter:
+		return "asserter"
+	case ChallengeChallenger:
+		return "challenger"
+	default:
+		return "unknown"
+	}
+}
+
+// ChallengeOutcome records how one of our challenge participants finished
+type ChallengeOutcome struct {
+	Contract common.Address
+	Role     ChallengeRole
+	State    challenges.ChallengeState
+	Err      error
+}
+
 type ValidatorChainListener struct {
 	sync.Mutex
 	actor                  arbbridge.ArbRollup
 	rollupAddress          common.Address
 	stakingKeys            map[common.Address]*StakingKey
+	challengeStrategy      challenges.Strategy
+	challengeOutcomes      map[common.Address][]ChallengeOutcome
+	stakingPolicy          StakingPolicy
 	broadcastAssertions    map[common.Hash]*valprotocol.AssertionParams
 	broadcastConfirmations map[common.Hash]bool
 	broadcastLeafPrunes    map[common.Hash]bool
 	broadcastCreateStakes  map[common.Address]*common.TimeBlocks
+	broadcastRecoveries    map[common.Address]time.Time
 }
 
 func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address, actor arbbridge.ArbRollup) *ValidatorChainListener {
@@ -79,10 +109,14 @@ func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address
 		actor:                  actor,
 		rollupAddress:          rollupAddress,
 		stakingKeys:            make(map[common.Address]*StakingKey),
+		challengeStrategy:      challenges.DefaultStrategy(),
+		challengeOutcomes:      make(map[common.Address][]ChallengeOutcome),
+		stakingPolicy:          DefaultStakingPolicy(),
 		broadcastAssertions:    make(map[common.Hash]*valprotocol.AssertionParams),
 		broadcastConfirmations: make(map[common.Hash]bool),
 		broadcastLeafPrunes:    make(map[common.Hash]bool),
 		broadcastCreateStakes:  make(map[common.Address]*common.TimeBlocks),
+		broadcastRecoveries:    make(map[common.Address]time.Time),
 	}
 	go func() {
 		ticker := time.NewTicker(common.NewTimeBlocksInt(30).Duration())
@@ -97,6 +131,7 @@ func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address
 				ret.broadcastConfirmations = make(map[common.Hash]bool)
 				ret.broadcastLeafPrunes = make(map[common.Hash]bool)
 				ret.broadcastCreateStakes = make(map[common.Address]*common.TimeBlocks)
+				ret.broadcastRecoveries = make(map[common.Address]time.Time)
 				ret.Unlock()
 			}
 		}
@@ -104,11 +139,10 @@ func NewValidatorChainListener(ctx context.Context, rollupAddress common.Address
 	return ret
 }
 
-func stakeLatestValid(ctx context.Context, chain *ChainObserver, stakingKey *StakingKey) error {
+func stakeLatestValid(ctx context.Context, chain *ChainObserver, stakingKey *StakingKey, stakeAmount *big.Int) error {
 	location := chain.knownValidNode
 	proof1 := GeneratePathProof(chain.nodeGraph.latestConfirmed, location)
 	proof2 := GeneratePathProof(location, chain.nodeGraph.getLeaf(location))
-	stakeAmount := chain.nodeGraph.params.StakeRequirement
 
 	log.Println("Placing stake for", stakingKey.client.Address())
 	return stakingKey.contract.PlaceStake(ctx, stakeAmount, proof1, proof2)
@@ -128,6 +162,144 @@ func (lis *ValidatorChainListener) AddStaker(client arbbridge.ArbAuthClient) err
 	return nil
 }
 
+func (lis *ValidatorChainListener) SetChallengeStrategy(strategy challenges.Strategy) error {
+	if err := strategy.Validate(); err != nil {
+		return err
+	}
+	lis.Lock()
+	lis.challengeStrategy = strategy
+	lis.Unlock()
+	return nil
+}
+
+func (lis *ValidatorChainListener) SetStakingPolicy(policy StakingPolicy) {
+	lis.Lock()
+	lis.stakingPolicy = policy
+	lis.Unlock()
+}
+
+// stakeContext summarizes the capital committed by our staking keys. Stakes
+// that have been sent but not yet seen on chain count as committed. Must be
+// called with lis locked.
+func (lis *ValidatorChainListener) stakeContext(chain *ChainObserver, staker common.Address) StakeContext {
+	stakedKeys := 0
+	for address := range lis.stakingKeys {
+		_, pending := lis.broadcastCreateStakes[address]
+		if pending || chain.nodeGraph.stakers.Get(address) != nil {
+			stakedKeys++
+		}
+	}
+	stakeRequirement := chain.nodeGraph.params.StakeRequirement
+	return StakeContext{
+		Staker:           staker,
+		CurrentBlock:     chain.latestBlockId.Height,
+		StakeRequirement: stakeRequirement,
+		StakedKeys:       stakedKeys,
+		CommittedCapital: new(big.Int).Mul(stakeRequirement, big.NewInt(int64(stakedKeys))),
+	}
+}
+
+func onValidPath(chain *ChainObserver, location *Node) bool {
+	return GeneratePathProof(location, chain.calculatedValidNode) != nil
+}
+
+func (lis *ValidatorChainListener) challengeContext(chain *ChainObserver, opp *challengeOpportunity) ChallengeContext {
+	asserter := chain.nodeGraph.stakers.Get(opp.asserter)
+	challenger := chain.nodeGraph.stakers.Get(opp.challenger)
+	ret := ChallengeContext{
+		Asserter:     opp.asserter,
+		Challenger:   opp.challenger,
+		ConflictType: opp.challengerNodeType,
+		CurrentTime:  common.TicksFromBlockNum(chain.latestBlockId.Height),
+		Deadline:     opp.deadlineTicks,
+	}
+	if _, ok := lis.stakingKeys[opp.asserter]; ok {
+		ret.Participant = &opp.asserter
+		ret.Confident = onValidPath(chain, asserter.location)
+	} else if _, ok := lis.stakingKeys[opp.challenger]; ok {
+		ret.Participant = &opp.challenger
+		ret.Confident = onValidPath(chain, challenger.location)
+	} else {
+		ret.Confident = onValidPath(chain, asserter.location) || onValidPath(chain, challenger.location)
+	}
+	for address := range lis.stakingKeys {
+		staker := chain.nodeGraph.stakers.Get(address)
+		if staker != nil && !staker.challenge.IsZero() {
+			ret.OpenChallenges++
+		}
+	}
+	return ret
+}
+
+// shouldRecover reports whether enough time has passed since we last tried
+// to recover the given stake, and if so marks it as attempted now
+func (lis *ValidatorChainListener) shouldRecover(staker common.Address) bool {
+	lis.Lock()
+	defer lis.Unlock()
+	now := time.Now()
+	lastTry, ok := lis.broadcastRecoveries[staker]
+	if ok && now.Sub(lastTry) < lis.stakingPolicy.RecoveryInterval().Duration() {
+		return false
+	}
+	lis.broadcastRecoveries[staker] = now
+	return true
+}
+
+// ChallengeOutcomes returns the results reported by our participants in the
+// given challenge that has not yet been resolved on chain
+func (lis *ValidatorChainListener) ChallengeOutcomes(contract common.Address) []ChallengeOutcome {
+	lis.Lock()
+	defer lis.Unlock()
+	return append([]ChallengeOutcome(nil), lis.challengeOutcomes[contract]...)
+}
+
+// openChallenge starts collecting the outcomes of our participants in a
+// challenge. They're kept until the challenge is resolved on chain.
+func (lis *ValidatorChainListener) openChallenge(contract common.Address) {
+	lis.Lock()
+	defer lis.Unlock()
+	if _, ok := lis.challengeOutcomes[contract]; !ok {
+		lis.challengeOutcomes[contract] = []ChallengeOutcome{}
+	}
+}
+
+func (lis *ValidatorChainListener) reportChallengeOutcome(chal *Challenge, role ChallengeRole, kind string, state challenges.ChallengeState, err error) {
+	if err != nil {
+		log.Printf("Failed as %v in %v challenge %v: %v\n", role, kind, chal.contract, err)
+	} else {
+		log.Printf("Completed as %v in %v challenge %v with state %v\n", role, kind, chal.contract, state)
+	}
+	lis.Lock()
+	defer lis.Unlock()
+	outcomes, ok := lis.challengeOutcomes[chal.contract]
+	if !ok {
+		// The challenge was already resolved, so there's nothing left to
+		// report the outcome to
+		return
+	}
+	lis.challengeOutcomes[chal.contract] = append(outcomes, ChallengeOutcome{
+		Contract: chal.contract,
+		Role:     role,
+		State:    state,
+		Err:      err,
+	})
+}
+
+// resolveChallenge logs how our participants did in a challenge that was
+// resolved on chain and forgets their outcomes
+func (lis *ValidatorChainListener) resolveChallenge(ev arbbridge.ChallengeCompletedEvent) {
+	lis.Lock()
+	defer lis.Unlock()
+	for _, outcome := range lis.challengeOutcomes[ev.ChallengeContract] {
+		if outcome.Err != nil {
+			log.Printf("Challenge %v won by %v after our %v failed: %v\n", ev.ChallengeContract, ev.Winner, outcome.Role, outcome.Err)
+		} else {
+			log.Printf("Challenge %v won by %v after our %v finished with state %v\n", ev.ChallengeContract, ev.Winner, outcome.Role, outcome.State)
+		}
+	}
+	delete(lis.challengeOutcomes, ev.ChallengeContract)
+}
+
 func makeAssertion(ctx context.Context, rollup arbbridge.ArbRollup, prepared *preparedAssertion, proof []common.Hash) error {
 	return rollup.MakeAssertion(
 		ctx,
@@ -196,16 +368,25 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 		}
 		lis.Lock()
 		stakeTime, placedStake := lis.broadcastCreateStakes[stakingAddress]
+		retryTime := new(big.Int)
 		if placedStake {
-			log.Println("Thinking about placing stake", chain.latestBlockId.Height.AsInt(), new(big.Int).Add(stakeTime.AsInt(), big.NewInt(3)))
+			retryTime.Add(stakeTime.AsInt(), lis.stakingPolicy.StakeRetryDelay().AsInt())
+			log.Println("Thinking about placing stake", chain.latestBlockId.Height.AsInt(), retryTime)
 		}
-		if !placedStake || chain.latestBlockId.Height.AsInt().Cmp(new(big.Int).Add(stakeTime.AsInt(), big.NewInt(3))) >= 0 {
+		if !placedStake || chain.latestBlockId.Height.AsInt().Cmp(retryTime) >= 0 {
+			delete(lis.broadcastCreateStakes, stakingAddress)
+			stakeAmount := lis.stakingPolicy.StakeAmount(lis.stakeContext(chain, stakingAddress))
+			if stakeAmount == nil {
+				lis.Unlock()
+				log.Println("Staking policy is holding off on placing a stake for", stakingAddress)
+				continue
+			}
 			lis.broadcastCreateStakes[stakingAddress] = chain.latestBlockId.Height
 			log.Println("No stake is currently down, so setting up a stake")
 			lis.Unlock()
 			// Put down new stake so that we can assert next time
 			go func() {
-				err := stakeLatestValid(ctx, chain, stakingKey)
+				err := stakeLatestValid(ctx, chain, stakingKey, stakeAmount)
 				if err != nil {
 					lis.Lock()
 					delete(lis.broadcastCreateStakes, stakingAddress)
@@ -220,6 +401,28 @@ func (lis *ValidatorChainListener) AssertionPrepared(ctx context.Context, chain
 	}
 }
 
+// considerChallenge starts a challenge for the given opportunity if our
+// staking policy allows it, returning whether it did
+func (lis *ValidatorChainListener) considerChallenge(ctx context.Context, chain *ChainObserver, opp *challengeOpportunity) bool {
+	chalCtx := lis.challengeContext(chain, opp)
+	lis.Lock()
+	shouldChallenge := lis.stakingPolicy.ShouldChallenge(chalCtx)
+	lis.Unlock()
+	if !shouldChallenge {
+		log.Printf("Staking policy declined to challenge %v with %v\n", opp.asserter, opp.challenger)
+		return false
+	}
+	go func() {
+		err := lis.initiateChallenge(ctx, opp)
+		if err != nil {
+			log.Println("Failed to initiate challenge", err)
+		} else {
+			log.Println("Successfully initiated challenge")
+		}
+	}()
+	return true
+}
+
 func (lis *ValidatorChainListener) initiateChallenge(ctx context.Context, opp *challengeOpportunity) error {
 	return lis.actor.StartChallenge(
 		ctx,
@@ -248,14 +451,7 @@ func (lis *ValidatorChainListener) StakeCreated(ctx context.Context, chain *Chai
 		}
 		opp := chain.nodeGraph.checkChallengeOpportunityAny(staker)
 		if opp != nil {
-			go func() {
-				err := lis.initiateChallenge(ctx, opp)
-				if err != nil {
-					log.Println("Failed to initiate challenge", err)
-				} else {
-					log.Println("Successfully initiated challenge")
-				}
-			}()
+			lis.considerChallenge(ctx, chain, opp)
 		}
 	} else {
 		lis.challengeStakerIfPossible(ctx, chain, ev.Staker)
@@ -285,29 +481,13 @@ func (lis *ValidatorChainListener) challengeStakerIfPossible(ctx context.Context
 			continue
 		}
 		opp := chain.nodeGraph.checkChallengeOpportunityPair(newStaker, meAsStaker)
-		if opp != nil {
-			go func() {
-				err := lis.initiateChallenge(ctx, opp)
-				if err != nil {
-					log.Println("Failed to initiate challenge", err)
-				} else {
-					log.Println("Successfully initiated challenge")
-				}
-			}()
+		if opp != nil && lis.considerChallenge(ctx, chain, opp) {
 			return
 		}
 	}
 	opp := chain.nodeGraph.checkChallengeOpportunityAny(newStaker)
 	if opp != nil {
-		go func() {
-			err := lis.initiateChallenge(ctx, opp)
-			if err != nil {
-				log.Println("Failed to initiate challenge", err)
-			} else {
-				log.Println("Successfully initiated challenge")
-			}
-		}()
-		return
+		lis.considerChallenge(ctx, chain, opp)
 	}
 }
 
@@ -325,6 +505,14 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 	// Must already be staked to be challenged
 	startBlockId := chal.blockId
 	startLogIndex := chal.logIndex - 1
+	lis.Lock()
+	strategy := lis.challengeStrategy
+	lis.Unlock()
+	_, isAsserter := lis.stakingKeys[chal.asserter]
+	_, isChallenger := lis.stakingKeys[chal.challenger]
+	if isAsserter || isChallenger {
+		lis.openChallenge(chal.contract)
+	}
 	asserterKey, ok := lis.stakingKeys[chal.asserter]
 	if ok {
 		switch chal.conflictNode.linkType {
@@ -342,13 +530,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 						chal.conflictNode.disputable.MaxInboxCount,
 						new(big.Int).Add(chal.conflictNode.prev.vmProtoData.InboxCount, chal.conflictNode.disputable.AssertionParams.ImportedMessageCount),
 					),
-					100,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed defending inbox top claim", err)
-				} else {
-					log.Println("Completed defending inbox top claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeAsserter, "inbox top", res, err)
 			}()
 		case valprotocol.InvalidMessagesChildType:
 			go func() {
@@ -361,13 +546,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.inbox.MessageStack,
 					chal.conflictNode.vmProtoData.InboxTop,
 					chal.conflictNode.disputable.AssertionParams.ImportedMessageCount,
-					100,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed defending messages claim", err)
-				} else {
-					log.Println("Completed defending messages claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeAsserter, "messages", res, err)
 			}()
 		case valprotocol.InvalidExecutionChildType:
 			go func() {
@@ -380,13 +562,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.executionPrecondition(chal.conflictNode),
 					chal.conflictNode.prev.machine,
 					chal.conflictNode.disputable.AssertionParams.NumSteps,
-					50,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed defending execution claim", err)
-				} else {
-					log.Println("Completed defending execution claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeAsserter, "execution", res, err)
 			}()
 		default:
 			log.Fatal("unexpected challenge type")
@@ -406,12 +585,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					startLogIndex,
 					chain.inbox.MessageStack,
 					false,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed challenging inbox top claim", err)
-				} else {
-					log.Println("Completed challenging inbox top claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeChallenger, "inbox top", res, err)
 			}()
 		case valprotocol.InvalidMessagesChildType:
 			go func() {
@@ -425,12 +602,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chal.conflictNode.vmProtoData.InboxTop,
 					chal.conflictNode.disputable.AssertionParams.ImportedMessageCount,
 					false,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed challenging messages claim", err)
-				} else {
-					log.Println("Completed challenging messages claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeChallenger, "messages", res, err)
 			}()
 		case valprotocol.InvalidExecutionChildType:
 			go func() {
@@ -443,12 +618,10 @@ func (lis *ValidatorChainListener) launchChallenge(ctx context.Context, chain *C
 					chain.executionPrecondition(chal.conflictNode),
 					chal.conflictNode.prev.machine,
 					false,
+					strategy,
+					chain.checkpointer,
 				)
-				if err != nil {
-					log.Println("Failed challenging execution claim", err)
-				} else {
-					log.Println("Completed challenging execution claim", res)
-				}
+				lis.reportChallengeOutcome(chal, ChallengeChallenger, "execution", res, err)
 			}()
 		default:
 			log.Fatal("unexpected challenge type")
@@ -467,6 +640,16 @@ func (lis *ValidatorChainListener) CompletedChallenge(ctx context.Context, chain
 	if ok {
 		lis.lostChallenge(ev)
 	}
+	for _, addr := range []common.Address{ev.Winner, ev.Loser} {
+		if _, ok := lis.stakingKeys[addr]; ok {
+			// The challenge is over so any progress we saved is no longer needed
+			if err := chain.checkpointer.DeleteChallengeProgress(ev.ChallengeContract, addr); err != nil {
+				log.Println("Failed to delete challenge progress", err)
+			}
+		}
+	}
+
+	lis.resolveChallenge(ev)
 	lis.challengeStakerIfPossible(ctx, chain, ev.Winner)
 }
 
@@ -527,14 +710,21 @@ func (lis *ValidatorChainListener) PrunableLeafs(ctx context.Context, observer *
 func (lis *ValidatorChainListener) MootableStakes(ctx context.Context, observer *ChainObserver, params []recoverStakeMootedParams) {
 	// Anyone can moot any stake
 	for _, moot := range params {
+		if !lis.shouldRecover(moot.addr) {
+			continue
+		}
+		moot := moot
 		go func() {
-			lis.actor.RecoverStakeMooted(
+			err := lis.actor.RecoverStakeMooted(
 				ctx,
 				moot.ancestorHash,
 				moot.addr,
 				moot.lcProof,
 				moot.stProof,
 			)
+			if err != nil {
+				log.Println("Failed to recover mooted stake", err)
+			}
 		}()
 	}
 }
@@ -542,12 +732,19 @@ func (lis *ValidatorChainListener) MootableStakes(ctx context.Context, observer
 func (lis *ValidatorChainListener) OldStakes(ctx context.Context, observer *ChainObserver, params []recoverStakeOldParams) {
 	// Anyone can remove an old stake
 	for _, old := range params {
+		if !lis.shouldRecover(old.addr) {
+			continue
+		}
+		old := old
 		go func() {
-			lis.actor.RecoverStakeOld(
+			err := lis.actor.RecoverStakeOld(
 				ctx,
 				old.addr,
 				old.proof,
 			)
+			if err != nil {
+				log.Println("Failed to recover old stake", err)
+			}
 		}()
 	}
 }
@@ -573,11 +770,42 @@ func (lis *ValidatorChainListener) lostChallenge(arbbridge.ChallengeCompletedEve
 func (lis *ValidatorChainListener) wonChallenge(arbbridge.ChallengeCompletedEvent)  {}
 func (lis *ValidatorChainListener) SawAssertion(context.Context, *ChainObserver, arbbridge.AssertedEvent) {
 }
-func (lis *ValidatorChainListener) ConfirmedNode(context.Context, *ChainObserver, arbbridge.ConfirmedEvent) {
+func (lis *ValidatorChainListener) ConfirmedNode(ctx context.Context, chain *ChainObserver, ev arbbridge.ConfirmedEvent) {
+	// Stakes sitting on or behind the latest confirmed node can be withdrawn
+	retiring := 0
+	for stakingAddress, stakingKey := range lis.stakingKeys {
+		staker := chain.nodeGraph.stakers.Get(stakingAddress)
+		if staker == nil || !staker.challenge.IsZero() {
+			continue
+		}
+		proof := GeneratePathProof(staker.location, chain.nodeGraph.latestConfirmed)
+		if proof == nil {
+			continue
+		}
+		lis.Lock()
+		stakeCtx := lis.stakeContext(chain, stakingAddress)
+		// Don't count stakes we've already decided to retire
+		stakeCtx.StakedKeys -= retiring
+		stakeCtx.CommittedCapital.Mul(stakeCtx.StakeRequirement, big.NewInt(int64(stakeCtx.StakedKeys)))
+		retire := lis.stakingPolicy.RetireStake(stakeCtx)
+		lis.Unlock()
+		if !retire || !lis.shouldRecover(stakingAddress) {
+			continue
+		}
+		retiring++
+		log.Println("Staking policy is retiring the stake of", stakingAddress)
+		contract := stakingKey.contract
+		go func() {
+			err := contract.RecoverStakeConfirmed(ctx, proof)
+			if err != nil {
+				log.Println("Failed to recover confirmed stake", err)
+			}
+		}()
+	}
 }
 func (lis *ValidatorChainListener) PrunedLeaf(context.Context, *ChainObserver, arbbridge.PrunedEvent) {
 }
 func (lis *ValidatorChainListener) MessageDelivered(context.Context, *ChainObserver, arbbridge.MessageDeliveredEvent) {
 }
-func (lis *ValidatorChainListener) AdvancedKnownAssertion(context.Context, *ChainObserver, *protocol.ExecutionAssertion, common.Hash) {
+func (lis *ValidatorChainListener) AdvancedKnownAssertion(context.Context, *ChainObserver, *protocol.ExecutionAssertion, common.Hash, common.Hash) {
 }
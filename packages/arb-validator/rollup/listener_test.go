@@ -0,0 +1,56 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/challenges"
+)
+
+func TestChallengeOutcomesPruned(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lis := NewValidatorChainListener(ctx, common.Address{1}, nil)
+	chal := &Challenge{asserter: common.Address{2}, challenger: common.Address{3}, contract: common.Address{4}}
+
+	lis.openChallenge(chal.contract)
+	lis.reportChallengeOutcome(chal, ChallengeAsserter, "execution", challenges.ChallengeAsserterWon, nil)
+	lis.reportChallengeOutcome(chal, ChallengeChallenger, "execution", challenges.ChallengeContinuing, errors.New("failed"))
+	if outcomes := lis.ChallengeOutcomes(chal.contract); len(outcomes) != 2 {
+		t.Fatal("expected both outcomes but got", outcomes)
+	}
+
+	lis.resolveChallenge(arbbridge.ChallengeCompletedEvent{
+		Winner:            chal.asserter,
+		Loser:             chal.challenger,
+		ChallengeContract: chal.contract,
+	})
+	if outcomes := lis.ChallengeOutcomes(chal.contract); len(outcomes) != 0 {
+		t.Error("kept outcomes of a resolved challenge", outcomes)
+	}
+
+	// A participant which finishes after the challenge was resolved is only logged
+	lis.reportChallengeOutcome(chal, ChallengeAsserter, "execution", challenges.ChallengeAsserterWon, nil)
+	if len(lis.challengeOutcomes) != 0 {
+		t.Error("recorded an outcome of a resolved challenge", lis.challengeOutcomes)
+	}
+}
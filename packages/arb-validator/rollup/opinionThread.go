@@ -64,6 +64,8 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 		assertionPreparedChan := make(chan *preparedAssertion, 20)
 		preparingAssertions := make(map[common.Hash]bool)
 		preparedAssertions := make(map[common.Hash]*preparedAssertion)
+		pool := newExecutionPool(ctx, defaultExecutionWorkers())
+		var speculative *speculation
 
 		updateCurrent := func() {
 			currentOpinion := chain.calculatedValidNode
@@ -71,14 +73,7 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 			log.Println("Building opinion on top of", currentHash)
 			successorHashes := [4]common.Hash{}
 			copy(successorHashes[:], currentOpinion.successorHashes[:])
-			successor := func() *Node {
-				for _, successor := range currentOpinion.successorHashes {
-					if successor != zeroBytes32 {
-						return chain.nodeGraph.nodeFromHash[successor]
-					}
-				}
-				return nil
-			}()
+			successor := chain.disputableSuccessor(currentOpinion)
 
 			if successor == nil {
 				panic("Node has no successor")
@@ -103,20 +98,19 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 			} else {
 				params := successor.disputable.AssertionParams.Clone()
 				claim := successor.disputable.AssertionClaim.Clone()
-				prevInboxCount := new(big.Int).Set(currentOpinion.vmProtoData.InboxCount)
-				afterInboxTopHeight := new(big.Int).Add(prevInboxCount, params.ImportedMessageCount)
-				afterInboxTopVal, err := chain.inbox.GetHashAtIndex(afterInboxTopHeight)
-				var afterInboxTop *common.Hash
-				if err == nil {
-					afterInboxTop = &afterInboxTopVal
-				}
-				inbox, _ := chain.inbox.GenerateVMInbox(currentOpinion.vmProtoData.InboxTop, params.ImportedMessageCount.Uint64())
-				messagesVal := inbox.AsValue()
-				nextMachine = currentOpinion.machine.Clone()
+				var job *executionJob
+				newOpinion, job = chain.startSuccessorCheck(pool, currentOpinion, currentOpinion.machine, successor)
 
 				chain.RUnlock()
 
-				newOpinion, validExecution = getNodeOpinion(params, claim, afterInboxTop, inbox.Hash(), messagesVal, nextMachine)
+				if job != nil {
+					var err error
+					newOpinion, validExecution, nextMachine, err = getNodeOpinion(ctx, params, claim, job)
+					if err != nil {
+						chain.RLock()
+						return
+					}
+				}
 			}
 			// Reset prepared
 			preparingAssertions = make(map[common.Hash]bool)
@@ -142,7 +136,7 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 				chain.RLock()
 				if newOpinion == valprotocol.ValidChildType {
 					for _, lis := range chain.listeners {
-						lis.AdvancedKnownAssertion(ctx, chain, validExecution, correctNode.assertionTxHash)
+						lis.AdvancedKnownAssertion(ctx, chain, validExecution, correctNode.assertionTxHash, correctNode.hash)
 					}
 				}
 				for _, listener := range chain.listeners {
@@ -161,6 +155,7 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 				preparedAssertions[prepped.leafHash] = prepped
 			case <-ticker.C:
 				chain.RLock()
+				speculative = chain.speculate(pool, preparingAssertions, speculative)
 				// Catch up to current head
 				for !chain.nodeGraph.leaves.IsLeaf(chain.calculatedValidNode) {
 					updateCurrent()
@@ -183,17 +178,19 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 					if chain.calculatedValidNode.machine != nil &&
 						chain.calculatedValidNode.machine.IsBlocked(chain.latestBlockId.Height, newMessages) == nil {
 						preparingAssertions[chain.calculatedValidNode.hash] = true
-						go func() {
-							assertionPreparedChan <- chain.prepareAssertion()
-						}()
+						go func(spec *speculation) {
+							prepared := chain.prepareAssertion(ctx, pool, spec)
+							if prepared != nil {
+								assertionPreparedChan <- prepared
+							}
+						}(speculative)
 					}
 				} else {
 					prepared, isPrepared := preparedAssertions[chain.calculatedValidNode.hash]
 					if isPrepared && chain.nodeGraph.leaves.IsLeaf(chain.calculatedValidNode) {
 						startTime := prepared.params.TimeBounds.Start
 						endTime := prepared.params.TimeBounds.End
-						endCushion := common.NewTimeBlocks(new(big.Int).Add(chain.latestBlockId.Height.AsInt(), big.NewInt(3)))
-						if chain.latestBlockId.Height.Cmp(startTime) >= 0 && endCushion.Cmp(endTime) <= 0 {
+						if timeBoundsUsable(prepared.params.TimeBounds, chain.latestBlockId.Height) {
 							for _, lis := range chain.listeners {
 								lis.AssertionPrepared(ctx, chain, prepared.Clone())
 							}
@@ -212,7 +209,144 @@ func (chain *ChainObserver) startOpinionUpdateThread(ctx context.Context) {
 	}()
 }
 
-func (chain *ChainObserver) prepareAssertion() *preparedAssertion {
+// disputableSuccessor returns any successor of node, all of which share the
+// same disputable assertion. Must be called with chain locked.
+func (chain *ChainObserver) disputableSuccessor(node *Node) *Node {
+	for _, successor := range node.successorHashes {
+		if successor != zeroBytes32 {
+			return chain.nodeGraph.nodeFromHash[successor]
+		}
+	}
+	return nil
+}
+
+// startSuccessorCheck begins checking the disputable assertion that follows
+// node, assuming node's machine is mach. If the claim can be rejected without
+// running the machine, the returned job is nil and the returned type is the
+// correct successor. Must be called with chain locked.
+func (chain *ChainObserver) startSuccessorCheck(
+	pool *executionPool,
+	node *Node,
+	mach machine.Machine,
+	successor *Node,
+) (valprotocol.ChildType, *executionJob) {
+	params := successor.disputable.AssertionParams
+	claim := successor.disputable.AssertionClaim
+	afterInboxTopHeight := new(big.Int).Add(node.vmProtoData.InboxCount, params.ImportedMessageCount)
+	afterInboxTop, err := chain.inbox.GetHashAtIndex(afterInboxTopHeight)
+	if err != nil || claim.AfterInboxTop != afterInboxTop {
+		return valprotocol.InvalidInboxTopChildType, nil
+	}
+	inbox, _ := chain.inbox.GenerateVMInbox(node.vmProtoData.InboxTop, params.ImportedMessageCount.Uint64())
+	if inbox.Hash() != claim.ImportedMessagesSlice {
+		return valprotocol.InvalidMessagesChildType, nil
+	}
+	return valprotocol.ValidChildType, pool.execute(mach, params.NumSteps, params.TimeBounds, inbox.AsValue(), 0)
+}
+
+type assertionExecution struct {
+	job             *executionJob
+	timeBounds      *protocol.TimeBoundsBlocks
+	afterInboxTop   common.Hash
+	newMessageCount *big.Int
+	importedSlice   common.Hash
+}
+
+// startAssertionExecution begins running our next assertion on top of a node
+// with the given state and machine. Must be called with chain locked.
+func (chain *ChainObserver) startAssertionExecution(
+	pool *executionPool,
+	beforeState *valprotocol.VMProtoData,
+	mach machine.Machine,
+) *assertionExecution {
+	newMessageCount := new(big.Int).Sub(chain.inbox.TopCount(), beforeState.InboxCount)
+	inbox, _ := chain.inbox.GenerateVMInbox(beforeState.InboxTop, newMessageCount.Uint64())
+	timeBounds := chain.currentTimeBounds()
+	timeBoundsLength := new(big.Int).Sub(timeBounds.End.AsInt(), timeBounds.Start.AsInt())
+	runBlocks := new(big.Int).Div(timeBoundsLength, big.NewInt(10))
+	runDuration := common.NewTimeBlocks(runBlocks).Duration()
+	return &assertionExecution{
+		job:             pool.execute(mach, chain.nodeGraph.params.MaxExecutionSteps, timeBounds, inbox.AsValue(), runDuration),
+		timeBounds:      timeBounds,
+		afterInboxTop:   chain.inbox.GetTopHash(),
+		newMessageCount: newMessageCount,
+		importedSlice:   inbox.Hash(),
+	}
+}
+
+// timeBoundsUsable reports whether an assertion with the given time bounds
+// can still be made at the given height, leaving a few blocks for it to be
+// included
+func timeBoundsUsable(timeBounds *protocol.TimeBoundsBlocks, height *common.TimeBlocks) bool {
+	endCushion := common.NewTimeBlocks(new(big.Int).Add(height.AsInt(), big.NewInt(3)))
+	return height.Cmp(timeBounds.Start) >= 0 && endCushion.Cmp(timeBounds.End) <= 0
+}
+
+// speculation is our own next assertion, run ahead of time on top of a node
+type speculation struct {
+	nodeHash  common.Hash
+	execution *assertionExecution
+}
+
+// speculationUsable reports whether spec can still be asserted on top of
+// node, which is the case until new messages arrive or its time bounds
+// expire. Must be called with chain locked.
+func (chain *ChainObserver) speculationUsable(spec *speculation, node *Node) bool {
+	return spec != nil &&
+		spec.nodeHash == node.hash &&
+		spec.execution.afterInboxTop == chain.inbox.GetTopHash() &&
+		timeBoundsUsable(spec.execution.timeBounds, chain.latestBlockId.Height)
+}
+
+// speculate starts executions that the opinion thread is likely to need
+// soon. It walks forward from the calculated valid node through successors
+// whose checks have already finished, starting the check for the first
+// unresolved successor. Alongside it runs our own next assertion from the
+// furthest node reached, which is what we'll assert if that successor turns
+// out to be invalid or doesn't exist. That execution is returned and kept
+// until it's no longer usable, so it isn't restarted with new time bounds on
+// every block, and none is started for a node we're already preparing an
+// assertion on. Must be called with chain locked.
+func (chain *ChainObserver) speculate(pool *executionPool, preparing map[common.Hash]bool, spec *speculation) *speculation {
+	node := chain.calculatedValidNode
+	mach := node.machine
+	for depth := 0; depth < maxSpeculationDepth && node != nil && mach != nil; depth++ {
+		successor := chain.disputableSuccessor(node)
+		if successor == nil || successor.disputable == nil {
+			break
+		}
+		opinion, job := chain.startSuccessorCheck(pool, node, mach, successor)
+		if job != nil {
+			if !job.finished() {
+				break
+			}
+			if executionMatchesClaim(successor.disputable.AssertionParams, successor.disputable.AssertionClaim, job.result) {
+				mach = job.result.machine
+			} else {
+				opinion = valprotocol.InvalidExecutionChildType
+			}
+		}
+		node = chain.nodeGraph.nodeFromHash[node.successorHashes[opinion]]
+	}
+	if node == nil || mach == nil || !chain.atHead {
+		return nil
+	}
+	if preparing[node.hash] || chain.speculationUsable(spec, node) {
+		return spec
+	}
+	newMessages := node.vmProtoData.InboxTop != chain.inbox.GetTopHash()
+	if mach.IsBlocked(chain.latestBlockId.Height, newMessages) != nil {
+		return nil
+	}
+	return &speculation{
+		nodeHash:  node.hash,
+		execution: chain.startAssertionExecution(pool, node.vmProtoData, mach),
+	}
+}
+
+// prepareAssertion runs our next assertion on top of the calculated valid
+// node, reusing spec if it was run on top of that node and is still usable
+func (chain *ChainObserver) prepareAssertion(ctx context.Context, pool *executionPool, spec *speculation) *preparedAssertion {
 	chain.RLock()
 	currentOpinion := chain.calculatedValidNode
 	currentOpinionHash := currentOpinion.hash
@@ -222,28 +356,31 @@ func (chain *ChainObserver) prepareAssertion() *preparedAssertion {
 	prevChildType := currentOpinion.linkType
 	beforeState := currentOpinion.vmProtoData.Clone()
 	if !chain.nodeGraph.leaves.IsLeaf(currentOpinion) {
+		chain.RUnlock()
 		return nil
 	}
-	afterInboxTop := chain.inbox.GetTopHash()
 	beforeInboxTop := beforeState.InboxTop
-	newMessageCount := new(big.Int).Sub(chain.inbox.TopCount(), beforeState.InboxCount)
-
-	inbox, _ := chain.inbox.GenerateVMInbox(beforeInboxTop, newMessageCount.Uint64())
-	messagesVal := inbox.AsValue()
-	mach := currentOpinion.machine.Clone()
-	timeBounds := chain.currentTimeBounds()
+	beforeHash := currentOpinion.machine.Hash()
+	var execution *assertionExecution
+	if chain.speculationUsable(spec, currentOpinion) {
+		execution = spec.execution
+	} else {
+		execution = chain.startAssertionExecution(pool, beforeState, currentOpinion.machine)
+	}
+	timeBounds := execution.timeBounds
+	afterInboxTop := execution.afterInboxTop
+	newMessageCount := execution.newMessageCount
 	log.Println("timeBounds: ", timeBounds.Start.String(), timeBounds.End.String())
-	maxSteps := chain.nodeGraph.params.MaxExecutionSteps
 	currentHeight := chain.latestBlockId.Height.Clone()
-	timeBoundsLength := new(big.Int).Sub(timeBounds.End.AsInt(), timeBounds.Start.AsInt())
-	runBlocks := new(big.Int).Div(timeBoundsLength, big.NewInt(10))
-	runDuration := common.NewTimeBlocks(runBlocks).Duration()
-	log.Println("Asserting for up to", runBlocks, " blocks")
 	chain.RUnlock()
 
-	beforeHash := mach.Hash()
-
-	assertion, stepsRun := mach.ExecuteAssertion(maxSteps, timeBounds, messagesVal, runDuration)
+	result, err := execution.job.wait(ctx)
+	if err != nil {
+		return nil
+	}
+	assertion := result.assertion
+	stepsRun := result.stepsRun
+	mach := result.machine
 
 	afterHash := mach.Hash()
 
@@ -270,7 +407,7 @@ func (chain *ChainObserver) prepareAssertion() *preparedAssertion {
 		}
 		claim = &valprotocol.AssertionClaim{
 			AfterInboxTop:         afterInboxTop,
-			ImportedMessagesSlice: inbox.Hash(),
+			ImportedMessagesSlice: execution.importedSlice,
 			AssertionStub:         valprotocol.NewExecutionAssertionStubFromAssertion(assertion),
 		}
 	} else {
@@ -299,30 +436,27 @@ func (chain *ChainObserver) prepareAssertion() *preparedAssertion {
 	}
 }
 
+func executionMatchesClaim(
+	params *valprotocol.AssertionParams,
+	claim *valprotocol.AssertionClaim,
+	result *executionResult,
+) bool {
+	return params.NumSteps == result.stepsRun &&
+		claim.AssertionStub.Equals(valprotocol.NewExecutionAssertionStubFromAssertion(result.assertion))
+}
+
 func getNodeOpinion(
+	ctx context.Context,
 	params *valprotocol.AssertionParams,
 	claim *valprotocol.AssertionClaim,
-	afterInboxTop *common.Hash,
-	calculatedMessagesSlice common.Hash,
-	messagesVal value.TupleValue,
-	mach machine.Machine,
-) (valprotocol.ChildType, *protocol.ExecutionAssertion) {
-	if afterInboxTop == nil || claim.AfterInboxTop != *afterInboxTop {
-		return valprotocol.InvalidInboxTopChildType, nil
-	}
-	if calculatedMessagesSlice != claim.ImportedMessagesSlice {
-		return valprotocol.InvalidMessagesChildType, nil
+	job *executionJob,
+) (valprotocol.ChildType, *protocol.ExecutionAssertion, machine.Machine, error) {
+	result, err := job.wait(ctx)
+	if err != nil {
+		return 0, nil, nil, err
 	}
-
-	assertion, stepsRun := mach.ExecuteAssertion(
-		params.NumSteps,
-		params.TimeBounds,
-		messagesVal,
-		0,
-	)
-	if params.NumSteps != stepsRun || !claim.AssertionStub.Equals(valprotocol.NewExecutionAssertionStubFromAssertion(assertion)) {
-		return valprotocol.InvalidExecutionChildType, nil
+	if !executionMatchesClaim(params, claim, result) {
+		return valprotocol.InvalidExecutionChildType, nil, nil, nil
 	}
-
-	return valprotocol.ValidChildType, assertion
+	return valprotocol.ValidChildType, result.assertion, result.machine, nil
 }
@@ -0,0 +1,80 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestSpeculationReused(t *testing.T) {
+	chain, err := setUpChain(common.Address{9}, "dummy", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool := newExecutionPool(ctx, 1)
+	setHeight := func(height int64) {
+		chain.Lock()
+		chain.atHead = true
+		chain.latestBlockId = &common.BlockId{Height: common.NewTimeBlocks(big.NewInt(height))}
+		chain.Unlock()
+	}
+	speculate := func(preparing map[common.Hash]bool, spec *speculation) *speculation {
+		chain.RLock()
+		defer chain.RUnlock()
+		return chain.speculate(pool, preparing, spec)
+	}
+
+	setHeight(10)
+	leaf := chain.calculatedValidNode.hash
+	spec := speculate(map[common.Hash]bool{}, nil)
+	if spec == nil || spec.nodeHash != leaf {
+		t.Fatal("didn't speculate on top of the latest valid node")
+	}
+
+	// A new block doesn't restart an execution which is still usable
+	setHeight(11)
+	if next := speculate(map[common.Hash]bool{}, spec); next != spec {
+		t.Error("restarted the speculative execution on a new block")
+	}
+	if next := speculate(map[common.Hash]bool{leaf: true}, nil); next != nil {
+		t.Error("speculated on a node which is already being prepared")
+	}
+
+	prepared := chain.prepareAssertion(ctx, pool, spec)
+	if prepared == nil {
+		t.Fatal("failed to prepare assertion")
+	}
+	if !prepared.params.TimeBounds.Equals(spec.execution.timeBounds) {
+		t.Error("prepared assertion didn't reuse the speculative execution")
+	}
+
+	// Once its time bounds are about to expire it's run again
+	setHeight(10 + int64(chain.nodeGraph.params.MaxTimeBoundsWidth))
+	next := speculate(map[common.Hash]bool{}, spec)
+	if next == nil || next == spec {
+		t.Error("kept an expired speculative execution")
+	}
+	if prepared := chain.prepareAssertion(ctx, pool, spec); prepared == nil || prepared.params.TimeBounds.Equals(spec.execution.timeBounds) {
+		t.Error("prepared assertion used an expired speculative execution")
+	}
+}
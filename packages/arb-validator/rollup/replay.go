@@ -0,0 +1,192 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// ReplayMismatch is a field of an assertion claim that doesn't match the
+// result of executing the assertion
+type ReplayMismatch struct {
+	Field    string
+	Claimed  interface{}
+	Computed interface{}
+}
+
+func (m ReplayMismatch) String() string {
+	return fmt.Sprintf("%v: claimed %v, computed %v", m.Field, m.Claimed, m.Computed)
+}
+
+// ReplayReport holds the inputs used to replay a node's assertion along with
+// every way in which the execution disagreed with the node's claim
+type ReplayReport struct {
+	NodeHash   common.Hash
+	LinkType   valprotocol.ChildType
+	StartHash  common.Hash
+	Params     *valprotocol.AssertionParams
+	Claim      *valprotocol.AssertionClaim
+	StepsRun   uint64
+	Assertion  *valprotocol.ExecutionAssertionStub
+	Mismatches []ReplayMismatch
+}
+
+// ReplayNode reconstructs the inputs the opinion thread used when checking
+// the assertion which created the given node and executes it again. If the
+// previous node's machine isn't in the checkpoint, it's rebuilt by replaying
+// the valid assertions after the closest ancestor whose machine is known.
+func (chain *ChainObserver) ReplayNode(nodeHash common.Hash) (*ReplayReport, error) {
+	chain.RLock()
+	defer chain.RUnlock()
+
+	node, ok := chain.nodeGraph.nodeFromHash[nodeHash]
+	if !ok {
+		return nil, fmt.Errorf("node %v isn't in the checkpointed node graph", nodeHash)
+	}
+	if node.prev == nil || node.disputable == nil {
+		return nil, fmt.Errorf("node %v has no assertion to replay", nodeHash)
+	}
+	mach, err := chain.machineAtNode(node.prev)
+	if err != nil {
+		return nil, err
+	}
+
+	params := node.disputable.AssertionParams
+	claim := node.disputable.AssertionClaim
+	report := &ReplayReport{
+		NodeHash:  nodeHash,
+		LinkType:  node.linkType,
+		StartHash: mach.Hash(),
+		Params:    params,
+		Claim:     claim,
+	}
+
+	afterInboxTopHeight := new(big.Int).Add(node.prev.vmProtoData.InboxCount, params.ImportedMessageCount)
+	afterInboxTop, err := chain.inbox.GetHashAtIndex(afterInboxTopHeight)
+	if err != nil {
+		return nil, err
+	}
+	report.addMismatch("AfterInboxTop", claim.AfterInboxTop, afterInboxTop)
+
+	inbox, err := chain.inbox.GenerateVMInbox(node.prev.vmProtoData.InboxTop, params.ImportedMessageCount.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	report.addMismatch("ImportedMessagesSlice", claim.ImportedMessagesSlice, inbox.Hash())
+
+	assertion, stepsRun := mach.ExecuteAssertion(params.NumSteps, params.TimeBounds, inbox.AsValue(), 0)
+	stub := valprotocol.NewExecutionAssertionStubFromAssertion(assertion)
+	report.StepsRun = stepsRun
+	report.Assertion = stub
+	report.addMismatch("NumSteps", params.NumSteps, stepsRun)
+	report.addMismatch("AfterHash", claim.AssertionStub.AfterHash, stub.AfterHash)
+	report.addMismatch("DidInboxInsn", claim.AssertionStub.DidInboxInsn, stub.DidInboxInsn)
+	report.addMismatch("NumGas", claim.AssertionStub.NumGas, stub.NumGas)
+	report.addMismatch("FirstMessageHash", claim.AssertionStub.FirstMessageHash, stub.FirstMessageHash)
+	report.addMismatch("LastMessageHash", claim.AssertionStub.LastMessageHash, stub.LastMessageHash)
+	report.addMismatch("FirstLogHash", claim.AssertionStub.FirstLogHash, stub.FirstLogHash)
+	report.addMismatch("LastLogHash", claim.AssertionStub.LastLogHash, stub.LastLogHash)
+	return report, nil
+}
+
+func (r *ReplayReport) addMismatch(field string, claimed, computed interface{}) {
+	if claimed != computed {
+		r.Mismatches = append(r.Mismatches, ReplayMismatch{
+			Field:    field,
+			Claimed:  claimed,
+			Computed: computed,
+		})
+	}
+}
+
+// machineAtNode returns a copy of the machine in the state given by node.
+// Must be called with chain locked.
+func (chain *ChainObserver) machineAtNode(node *Node) (machine.Machine, error) {
+	plan, err := chain.planReplay(node)
+	if err != nil {
+		return nil, err
+	}
+	return plan.run()
+}
+
+// replayPlan holds copies of everything needed to rebuild a node's machine
+// from its closest ancestor with a known machine, so that the replay can
+// run without holding the chain lock
+type replayPlan struct {
+	mach  machine.Machine
+	steps []replayStep
+}
+
+type replayStep struct {
+	nodeHash    common.Hash
+	params      *valprotocol.AssertionParams
+	inbox       value.TupleValue
+	machineHash common.Hash
+}
+
+// planReplay collects the ancestor machine and the assertions to replay on
+// top of it to reach node. Must be called with chain locked.
+func (chain *ChainObserver) planReplay(node *Node) (*replayPlan, error) {
+	var path []*Node
+	ancestor := node
+	for ancestor.machine == nil {
+		if ancestor.prev == nil {
+			return nil, fmt.Errorf("no machine is known for node %v or its ancestors", node.hash)
+		}
+		path = append(path, ancestor)
+		ancestor = ancestor.prev
+	}
+	plan := &replayPlan{mach: ancestor.machine.Clone()}
+	for i := len(path) - 1; i >= 0; i-- {
+		next := path[i]
+		if next.linkType != valprotocol.ValidChildType {
+			// Invalid children leave the machine where their parent had it
+			continue
+		}
+		params := next.disputable.AssertionParams
+		inbox, err := chain.inbox.GenerateVMInbox(next.prev.vmProtoData.InboxTop, params.ImportedMessageCount.Uint64())
+		if err != nil {
+			return nil, fmt.Errorf("inbox for ancestor %v is no longer available: %v", next.hash, err)
+		}
+		plan.steps = append(plan.steps, replayStep{
+			nodeHash:    next.hash,
+			params:      params,
+			inbox:       inbox.AsValue(),
+			machineHash: next.vmProtoData.MachineHash,
+		})
+	}
+	return plan, nil
+}
+
+// run executes the planned assertions, checking that each one reaches the
+// machine its node claims
+func (p *replayPlan) run() (machine.Machine, error) {
+	mach := p.mach
+	for _, step := range p.steps {
+		mach.ExecuteAssertion(step.params.NumSteps, step.params.TimeBounds, step.inbox, 0)
+		if mach.Hash() != step.machineHash {
+			return nil, fmt.Errorf("replaying ancestor %v gave machine %v instead of %v", step.nodeHash, mach.Hash(), step.machineHash)
+		}
+	}
+	return mach, nil
+}
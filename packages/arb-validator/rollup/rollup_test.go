@@ -26,6 +26,7 @@ import (
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
@@ -38,6 +39,7 @@ var dummyRollupAddress1 = common.Address{1}
 var dummyRollupAddress2 = common.Address{2}
 var dummyRollupAddress3 = common.Address{3}
 var dummyRollupAddress4 = common.Address{4}
+var dummyRollupAddress5 = common.Address{5}
 
 var contractPath string = "../contract.ao"
 
@@ -279,3 +281,78 @@ func createOneStaker(chain *ChainObserver, stakerAddr common.Address, nodeHash c
 		NodeHash: nodeHash,
 	})
 }
+
+func TestReplayNode(t *testing.T) {
+	chain, err := setUpChain(dummyRollupAddress5, "dummy", contractPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, afterFirst := assertFromMachine(chain, chain.nodeGraph.latestConfirmed, chain.nodeGraph.latestConfirmed.machine, nil)
+	report, err := chain.ReplayNode(first.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Mismatches) != 0 {
+		t.Error("correct assertion had mismatches", report.Mismatches)
+	}
+
+	// first has no machine, so replaying its successor has to rebuild it
+	second, _ := assertFromMachine(chain, first, afterFirst, func(stub *valprotocol.ExecutionAssertionStub) {
+		stub.NumGas++
+	})
+	report, err = chain.ReplayNode(second.hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.StartHash != afterFirst.Hash() {
+		t.Error("replay started from the wrong machine")
+	}
+	if len(report.Mismatches) != 1 || report.Mismatches[0].Field != "NumGas" {
+		t.Error("expected only a gas mismatch, got", report.Mismatches)
+	}
+}
+
+// assertFromMachine makes an assertion on top of baseNode by running a copy
+// of mach, optionally tampering with the claim, and returns the valid child
+// along with the machine after execution
+func assertFromMachine(
+	chain *ChainObserver,
+	baseNode *Node,
+	mach machine.Machine,
+	tamper func(*valprotocol.ExecutionAssertionStub),
+) (*Node, machine.Machine) {
+	mach = mach.Clone()
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocks(big.NewInt(0)),
+		End:   common.NewTimeBlocks(big.NewInt(1000)),
+	}
+	assertion, stepsRun := mach.ExecuteAssertion(10, timeBounds, value.NewEmptyTuple(), 0)
+	assertionStub := valprotocol.NewExecutionAssertionStubFromAssertion(assertion)
+	if tamper != nil {
+		tamper(assertionStub)
+	}
+	assertionParams := &valprotocol.AssertionParams{
+		NumSteps:             stepsRun,
+		TimeBounds:           timeBounds,
+		ImportedMessageCount: big.NewInt(0),
+	}
+	assertionClaim := &valprotocol.AssertionClaim{
+		AfterInboxTop:         chain.inbox.GetTopHash(),
+		ImportedMessagesSlice: value.NewEmptyTuple().Hash(),
+		AssertionStub:         assertionStub,
+	}
+	disputableNode := valprotocol.NewDisputableNode(
+		assertionParams,
+		assertionClaim,
+		chain.inbox.GetTopHash(),
+		big.NewInt(0),
+	)
+	chain.nodeGraph.CreateNodesOnAssert(
+		baseNode,
+		disputableNode,
+		common.NewTimeBlocks(big.NewInt(10)),
+		common.Hash{},
+	)
+	return baseNode.GetSuccessor(chain.nodeGraph.NodeGraph, valprotocol.ValidChildType), mach
+}
@@ -0,0 +1,209 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valprotocol"
+)
+
+// StakeContext describes the capital a ValidatorChainListener currently has
+// committed when it considers placing or withdrawing a stake
+type StakeContext struct {
+	Staker           common.Address
+	CurrentBlock     *common.TimeBlocks
+	StakeRequirement *big.Int
+	// StakedKeys counts our staking keys that are staked or have a stake
+	// transaction in flight
+	StakedKeys       int
+	CommittedCapital *big.Int
+}
+
+// ChallengeContext describes a challenge opportunity between two stakers
+type ChallengeContext struct {
+	Asserter   common.Address
+	Challenger common.Address
+	// Participant is our staking key in the conflict, or nil if we would
+	// be starting a challenge between two other stakers
+	Participant  *common.Address
+	ConflictType valprotocol.ChildType
+	// Confident is set when the side we would back has been validated
+	// locally up to the calculated valid node
+	Confident      bool
+	CurrentTime    common.TimeTicks
+	Deadline       common.TimeTicks
+	OpenChallenges int
+}
+
+// TimeRemaining returns how long is left before the challenge deadline
+func (c ChallengeContext) TimeRemaining() common.TimeTicks {
+	return common.TimeTicks{Val: new(big.Int).Sub(c.Deadline.Val, c.CurrentTime.Val)}
+}
+
+// StakingPolicy decides when a ValidatorChainListener commits capital and
+// which conflicts it is willing to act on
+type StakingPolicy interface {
+	// StakeAmount returns the capital to commit for a new stake, or nil to
+	// hold off for now
+	StakeAmount(StakeContext) *big.Int
+
+	// StakeRetryDelay is how long to wait for a placed stake to show up on
+	// chain before trying again
+	StakeRetryDelay() *common.TimeBlocks
+
+	// RetireStake reports whether a staker sitting on a confirmed node should
+	// recover its stake rather than keep it down
+	RetireStake(StakeContext) bool
+
+	ShouldChallenge(ChallengeContext) bool
+
+	// RecoveryInterval is the minimum time between recovery transactions
+	// for the same stake
+	RecoveryInterval() *common.TimeBlocks
+}
+
+// RiskPolicy is a StakingPolicy with explicit limits on committed capital and
+// challenge exposure. A zero or nil limit is treated as unlimited.
+type RiskPolicy struct {
+	MaxStakes         int
+	MaxCapital        *big.Int
+	MaxOpenChallenges int
+	// MinChallengeWindow is the least time that must remain before the
+	// deadline for us to start a challenge
+	MinChallengeWindow *common.TimeBlocks
+	RequireConfidence  bool
+	RetryDelay         *common.TimeBlocks
+	Recovery           *common.TimeBlocks
+}
+
+// DefaultStakingPolicy stakes whenever possible and challenges every
+// opportunity it finds
+func DefaultStakingPolicy() RiskPolicy {
+	return RiskPolicy{
+		RetryDelay: common.NewTimeBlocksInt(3),
+		Recovery:   common.NewTimeBlocksInt(10),
+	}
+}
+
+func (p RiskPolicy) WithMaxStakes(count int) RiskPolicy {
+	ret := p
+	ret.MaxStakes = count
+	return ret
+}
+
+func (p RiskPolicy) WithMaxCapital(amount *big.Int) RiskPolicy {
+	ret := p
+	ret.MaxCapital = amount
+	return ret
+}
+
+func (p RiskPolicy) WithMaxOpenChallenges(count int) RiskPolicy {
+	ret := p
+	ret.MaxOpenChallenges = count
+	return ret
+}
+
+func (p RiskPolicy) WithMinChallengeWindow(window *common.TimeBlocks) RiskPolicy {
+	ret := p
+	ret.MinChallengeWindow = window
+	return ret
+}
+
+func (p RiskPolicy) WithRequireConfidence(require bool) RiskPolicy {
+	ret := p
+	ret.RequireConfidence = require
+	return ret
+}
+
+func (p RiskPolicy) WithRetryDelay(delay *common.TimeBlocks) RiskPolicy {
+	ret := p
+	ret.RetryDelay = delay
+	return ret
+}
+
+func (p RiskPolicy) WithRecoveryInterval(interval *common.TimeBlocks) RiskPolicy {
+	ret := p
+	ret.Recovery = interval
+	return ret
+}
+
+func (p RiskPolicy) Validate() error {
+	if p.MaxStakes < 0 {
+		return errors.New("max stakes can't be negative")
+	}
+	if p.MaxCapital != nil && p.MaxCapital.Sign() < 0 {
+		return errors.New("max capital can't be negative")
+	}
+	if p.MaxOpenChallenges < 0 {
+		return errors.New("max open challenges can't be negative")
+	}
+	if p.MinChallengeWindow != nil && p.MinChallengeWindow.AsInt().Sign() < 0 {
+		return errors.New("min challenge window can't be negative")
+	}
+	if p.RetryDelay == nil || p.RetryDelay.AsInt().Sign() <= 0 {
+		return errors.New("stake retry delay must be at least one block")
+	}
+	if p.Recovery == nil || p.Recovery.AsInt().Sign() <= 0 {
+		return errors.New("recovery interval must be at least one block")
+	}
+	return nil
+}
+
+func (p RiskPolicy) overLimit(stakes int, capital *big.Int) bool {
+	if p.MaxStakes > 0 && stakes > p.MaxStakes {
+		return true
+	}
+	return p.MaxCapital != nil && capital.Cmp(p.MaxCapital) > 0
+}
+
+func (p RiskPolicy) StakeAmount(ctx StakeContext) *big.Int {
+	newCapital := new(big.Int).Add(ctx.CommittedCapital, ctx.StakeRequirement)
+	if p.overLimit(ctx.StakedKeys+1, newCapital) {
+		return nil
+	}
+	return ctx.StakeRequirement
+}
+
+func (p RiskPolicy) StakeRetryDelay() *common.TimeBlocks {
+	return p.RetryDelay
+}
+
+func (p RiskPolicy) RetireStake(ctx StakeContext) bool {
+	return p.overLimit(ctx.StakedKeys, ctx.CommittedCapital)
+}
+
+func (p RiskPolicy) ShouldChallenge(ctx ChallengeContext) bool {
+	if p.RequireConfidence && !ctx.Confident {
+		return false
+	}
+	if p.MaxOpenChallenges > 0 && ctx.OpenChallenges >= p.MaxOpenChallenges {
+		return false
+	}
+	if p.MinChallengeWindow != nil {
+		if ctx.TimeRemaining().Cmp(common.TicksFromBlockNum(p.MinChallengeWindow)) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p RiskPolicy) RecoveryInterval() *common.TimeBlocks {
+	return p.Recovery
+}
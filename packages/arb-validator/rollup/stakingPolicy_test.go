@@ -0,0 +1,82 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+)
+
+func TestRiskPolicyStaking(t *testing.T) {
+	stakeCtx := StakeContext{
+		StakeRequirement: big.NewInt(10),
+		StakedKeys:       1,
+		CommittedCapital: big.NewInt(10),
+	}
+
+	if DefaultStakingPolicy().StakeAmount(stakeCtx).Cmp(big.NewInt(10)) != 0 {
+		t.Error("default policy should stake the requirement")
+	}
+	if DefaultStakingPolicy().RetireStake(stakeCtx) {
+		t.Error("default policy shouldn't retire stakes")
+	}
+
+	policy := DefaultStakingPolicy().WithMaxStakes(1)
+	if policy.StakeAmount(stakeCtx) != nil {
+		t.Error("policy staked past max stakes")
+	}
+	if policy.RetireStake(stakeCtx) {
+		t.Error("policy retired a stake within its limits")
+	}
+
+	policy = DefaultStakingPolicy().WithMaxCapital(big.NewInt(15))
+	if policy.StakeAmount(stakeCtx) != nil {
+		t.Error("policy staked past max capital")
+	}
+	stakeCtx.StakedKeys = 2
+	stakeCtx.CommittedCapital = big.NewInt(20)
+	if !policy.RetireStake(stakeCtx) {
+		t.Error("policy didn't retire stake over max capital")
+	}
+}
+
+func TestRiskPolicyChallenge(t *testing.T) {
+	chalCtx := ChallengeContext{
+		Confident:      false,
+		CurrentTime:    common.TicksFromBlockNum(common.NewTimeBlocksInt(10)),
+		Deadline:       common.TicksFromBlockNum(common.NewTimeBlocksInt(15)),
+		OpenChallenges: 2,
+	}
+
+	if !DefaultStakingPolicy().ShouldChallenge(chalCtx) {
+		t.Error("default policy should challenge every opportunity")
+	}
+	if DefaultStakingPolicy().WithRequireConfidence(true).ShouldChallenge(chalCtx) {
+		t.Error("policy challenged without confidence")
+	}
+	if DefaultStakingPolicy().WithMaxOpenChallenges(2).ShouldChallenge(chalCtx) {
+		t.Error("policy challenged past max open challenges")
+	}
+	if DefaultStakingPolicy().WithMinChallengeWindow(common.NewTimeBlocksInt(6)).ShouldChallenge(chalCtx) {
+		t.Error("policy challenged too close to the deadline")
+	}
+	if !DefaultStakingPolicy().WithMinChallengeWindow(common.NewTimeBlocksInt(5)).ShouldChallenge(chalCtx) {
+		t.Error("policy refused challenge with enough time remaining")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollup
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/structures"
+)
+
+// ValidState is a copy of the latest known valid machine together with the
+// position in the inbox that it has read up to
+type ValidState struct {
+	Machine    machine.Machine
+	InboxTop   common.Hash
+	InboxCount *big.Int
+}
+
+// InboxSegment is a run of consecutive inbox messages along with the inbox
+// hashes immediately before and after it
+type InboxSegment struct {
+	BeforeTop common.Hash
+	AfterTop  common.Hash
+	Count     uint64
+	Inbox     *structures.VMInbox
+}
+
+// InboxCounts is how far into the inbox the chain has progressed
+type InboxCounts struct {
+	// Delivered is the number of messages delivered to the inbox on L1
+	Delivered *big.Int
+	// Asserted is the number of messages read by the latest valid node
+	Asserted *big.Int
+	// Confirmed is the number of messages read by the latest confirmed node
+	Confirmed *big.Int
+}
+
+func (chain *ChainObserver) InboxCounts() *InboxCounts {
+	chain.RLock()
+	defer chain.RUnlock()
+	return &InboxCounts{
+		Delivered: new(big.Int).Set(chain.inbox.TopCount()),
+		Asserted:  new(big.Int).Set(chain.calculatedValidNode.vmProtoData.InboxCount),
+		Confirmed: new(big.Int).Set(chain.nodeGraph.latestConfirmed.vmProtoData.InboxCount),
+	}
+}
+
+func (chain *ChainObserver) LatestValidState() *ValidState {
+	chain.RLock()
+	defer chain.RUnlock()
+	node := chain.calculatedValidNode
+	return &ValidState{
+		Machine:    node.machine.Clone(),
+		InboxTop:   node.vmProtoData.InboxTop,
+		InboxCount: new(big.Int).Set(node.vmProtoData.InboxCount),
+	}
+}
+
+// InboxSegment returns up to maxCount of the messages delivered after the
+// first beforeCount messages of the inbox
+func (chain *ChainObserver) InboxSegment(beforeCount *big.Int, maxCount uint64) (*InboxSegment, error) {
+	chain.RLock()
+	defer chain.RUnlock()
+	available := new(big.Int).Sub(chain.inbox.TopCount(), beforeCount)
+	if available.Sign() < 0 {
+		return nil, fmt.Errorf("inbox only has %v messages, not %v", chain.inbox.TopCount(), beforeCount)
+	}
+	count := maxCount
+	if available.IsUint64() && available.Uint64() < count {
+		count = available.Uint64()
+	}
+	beforeTop, err := chain.inbox.GetHashAtIndex(beforeCount)
+	if err != nil {
+		return nil, err
+	}
+	afterTop, err := chain.inbox.GetHashAtIndex(new(big.Int).Add(beforeCount, new(big.Int).SetUint64(count)))
+	if err != nil {
+		return nil, err
+	}
+	inbox, err := chain.inbox.GenerateVMInbox(beforeTop, count)
+	if err != nil {
+		return nil, err
+	}
+	return &InboxSegment{
+		BeforeTop: beforeTop,
+		AfterTop:  afterTop,
+		Count:     count,
+		Inbox:     inbox,
+	}, nil
+}
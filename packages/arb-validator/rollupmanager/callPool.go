@@ -0,0 +1,167 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollupmanager
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// ErrCallPoolBusy is returned instead of queueing a call when every worker is
+// busy and the queue is full
+var ErrCallPoolBusy = errors.New("validator is busy: too many calls are already running or queued")
+
+// callChunkSteps is the number of steps a call runs between checks of its
+// context and deadline
+const callChunkSteps = 100000
+
+// CallPoolConfig limits the resources used by read only calls
+type CallPoolConfig struct {
+	// Workers is the number of calls that may execute at once
+	Workers int
+	// QueueDepth is the number of calls that may wait for a worker before
+	// further calls are rejected with ErrCallPoolBusy
+	QueueDepth int
+	// MaxSteps is the step limit for calls that don't request a lower one
+	MaxSteps uint64
+	// MaxTime is the wall time after which a call is stopped
+	MaxTime time.Duration
+}
+
+// DefaultCallPoolConfig uses half of the CPUs for calls so that the opinion
+// thread is never starved
+func DefaultCallPoolConfig() CallPoolConfig {
+	workers := runtime.NumCPU() / 2
+	if workers < 1 {
+		workers = 1
+	}
+	return CallPoolConfig{
+		Workers:    workers,
+		QueueDepth: 64,
+		// Calls are normally limited by wall time, so use a massive max steps
+		// as an approximation to infinity
+		MaxSteps: 10000000000000000,
+		MaxTime:  time.Second * 60,
+	}
+}
+
+// Validate checks that the pool can run at least one call
+func (c CallPoolConfig) Validate() error {
+	if c.Workers < 1 {
+		return errors.New("call pool needs at least one worker")
+	}
+	if c.QueueDepth < 0 {
+		return errors.New("call queue depth can't be negative")
+	}
+	if c.MaxSteps == 0 || c.MaxTime <= 0 {
+		return errors.New("call step and time limits must be positive")
+	}
+	return nil
+}
+
+// CallPool executes calls on a bounded number of workers
+type CallPool struct {
+	config  CallPoolConfig
+	workers chan struct{}
+	slots   chan struct{}
+}
+
+func NewCallPool(config CallPoolConfig) *CallPool {
+	return &CallPool{
+		config:  config,
+		workers: make(chan struct{}, config.Workers),
+		slots:   make(chan struct{}, config.Workers+config.QueueDepth),
+	}
+}
+
+// CallSetup prepares the machine, inbox and time bounds for a call
+type CallSetup func() (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, error)
+
+// Execute prepares a call with setup once a worker is free and runs it for at
+// most maxSteps steps, or the pool's step limit if maxSteps is zero or higher
+// than it. It returns ErrCallPoolBusy without running anything if the queue is
+// full, and stops early with the context's error if ctx is cancelled.
+func (p *CallPool) Execute(ctx context.Context, maxSteps uint64, setup CallSetup) (*protocol.ExecutionAssertion, uint64, error) {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		return nil, 0, ErrCallPoolBusy
+	}
+	defer func() { <-p.slots }()
+
+	select {
+	case p.workers <- struct{}{}:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	defer func() { <-p.workers }()
+
+	deadline := time.Now().Add(p.config.MaxTime)
+	mach, inbox, timeBounds, err := setup()
+	if err != nil {
+		return nil, 0, err
+	}
+	if maxSteps == 0 || maxSteps > p.config.MaxSteps {
+		maxSteps = p.config.MaxSteps
+	}
+	return executeCall(ctx, mach, timeBounds, inbox, maxSteps, deadline)
+}
+
+// executeCall runs the machine in chunks so that it can be abandoned between
+// them. The inbox is only given to the machine until it's been read, which
+// matches executing the whole call as a single assertion.
+func executeCall(
+	ctx context.Context,
+	mach machine.Machine,
+	timeBounds *protocol.TimeBoundsBlocks,
+	inbox value.TupleValue,
+	maxSteps uint64,
+	deadline time.Time,
+) (*protocol.ExecutionAssertion, uint64, error) {
+	result := &protocol.ExecutionAssertion{}
+	var totalSteps uint64
+	for totalSteps < maxSteps && time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return nil, totalSteps, err
+		}
+		chunkSteps := maxSteps - totalSteps
+		if chunkSteps > callChunkSteps {
+			chunkSteps = callChunkSteps
+		}
+		assertion, numSteps := mach.ExecuteAssertion(chunkSteps, timeBounds, inbox, 0)
+		totalSteps += numSteps
+		result.DidInboxInsn = result.DidInboxInsn || assertion.DidInboxInsn
+		result.NumGas += assertion.NumGas
+		result.OutMsgs = append(result.OutMsgs, assertion.OutMsgs...)
+		result.Logs = append(result.Logs, assertion.Logs...)
+		if assertion.DidInboxInsn {
+			inbox = value.NewEmptyTuple()
+		}
+		if numSteps < chunkSteps {
+			// The machine is blocked, halted or has errored
+			break
+		}
+	}
+	result.AfterHash = mach.Hash()
+	return result, totalSteps, nil
+}
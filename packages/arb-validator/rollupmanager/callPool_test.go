@@ -0,0 +1,153 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollupmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// countingMachine halts after running a fixed number of steps, reading its
+// inbox on the first step and emitting a log for every assertion
+type countingMachine struct {
+	machine.Machine
+	stepsLeft  uint64
+	inboxReads int
+}
+
+func (m *countingMachine) Hash() common.Hash {
+	return common.Hash{}
+}
+
+func (m *countingMachine) ExecuteAssertion(
+	maxSteps uint64,
+	timeBounds *protocol.TimeBoundsBlocks,
+	inbox value.TupleValue,
+	maxWallTime time.Duration,
+) (*protocol.ExecutionAssertion, uint64) {
+	steps := maxSteps
+	if steps > m.stepsLeft {
+		steps = m.stepsLeft
+	}
+	m.stepsLeft -= steps
+	didInbox := steps > 0 && !inbox.Equal(value.NewEmptyTuple())
+	if didInbox {
+		m.inboxReads++
+	}
+	logs := []value.Value{value.NewInt64Value(int64(steps))}
+	return protocol.NewExecutionAssertion(common.Hash{}, didInbox, steps, nil, logs), steps
+}
+
+func callInbox() value.TupleValue {
+	return value.NewTuple2(value.NewEmptyTuple(), value.NewInt64Value(1))
+}
+
+func callTimeBounds() *protocol.TimeBoundsBlocks {
+	return &protocol.TimeBoundsBlocks{common.NewTimeBlocksInt(0), common.NewTimeBlocksInt(0)}
+}
+
+func TestExecuteCallChunks(t *testing.T) {
+	mach := &countingMachine{stepsLeft: callChunkSteps*2 + 5}
+	deadline := time.Now().Add(time.Minute)
+	assertion, steps, err := executeCall(context.Background(), mach, callTimeBounds(), callInbox(), 1000000000, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps != callChunkSteps*2+5 || assertion.NumGas != steps {
+		t.Error("call ran", steps, "steps using", assertion.NumGas, "gas")
+	}
+	if len(assertion.Logs) != 3 {
+		t.Error("expected a log from each chunk but got", len(assertion.Logs))
+	}
+	if mach.inboxReads != 1 || !assertion.DidInboxInsn {
+		t.Error("inbox was read", mach.inboxReads, "times")
+	}
+}
+
+func TestExecuteCallLimits(t *testing.T) {
+	deadline := time.Now().Add(time.Minute)
+	mach := &countingMachine{stepsLeft: callChunkSteps * 10}
+	_, steps, err := executeCall(context.Background(), mach, callTimeBounds(), callInbox(), callChunkSteps+1, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps != callChunkSteps+1 {
+		t.Error("step limited call ran", steps, "steps")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := executeCall(ctx, mach, callTimeBounds(), callInbox(), 1000000000, deadline); err != context.Canceled {
+		t.Error("expected cancelled call to fail but got", err)
+	}
+}
+
+func TestCallPoolBusy(t *testing.T) {
+	pool := NewCallPool(CallPoolConfig{
+		Workers:    1,
+		QueueDepth: 1,
+		MaxSteps:   callChunkSteps,
+		MaxTime:    time.Minute,
+	})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blockingSetup := func() (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, error) {
+		close(started)
+		<-release
+		return &countingMachine{stepsLeft: 10}, callInbox(), callTimeBounds(), nil
+	}
+	done := make(chan error)
+	go func() {
+		_, _, err := pool.Execute(context.Background(), 0, blockingSetup)
+		done <- err
+	}()
+	<-started
+
+	// The second call waits for the worker until it gives up
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	if _, _, err := pool.Execute(ctx, 0, nil); err != context.DeadlineExceeded {
+		t.Error("expected queued call to time out but got", err)
+	}
+	cancel()
+
+	// With the queue slot taken by a waiting call there's no room for more
+	pool.slots <- struct{}{}
+	if _, _, err := pool.Execute(context.Background(), 0, nil); err != ErrCallPoolBusy {
+		t.Error("expected a full pool to be busy but got", err)
+	}
+	<-pool.slots
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	_, steps, err := pool.Execute(context.Background(), 0, func() (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, error) {
+		return &countingMachine{stepsLeft: callChunkSteps * 2}, callInbox(), callTimeBounds(), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps != callChunkSteps {
+		t.Error("pool step limit wasn't applied, ran", steps)
+	}
+}
@@ -26,9 +26,11 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/machine"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/arbbridge"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/checkpointing"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
 )
@@ -46,9 +48,11 @@ type Manager struct {
 	listenerAddChan chan rollup.ChainListener
 	actionChan      chan func(*rollup.ChainObserver)
 	ckpFac          checkpointing.RollupCheckpointerFactory
+	maxReorgDepth   *big.Int
+	calls           *CallPool
 }
 
-const defaultMaxReorgDepth = 100
+const DefaultMaxReorgDepth = 100
 
 func CreateManager(
 	rollupAddr common.Address,
@@ -56,7 +60,26 @@ func CreateManager(
 	aoFilePath string,
 	dbPath string,
 ) (*Manager, error) {
-	return CreateManagerAdvanced(
+	return CreateManagerWithReorgDepth(
+		rollupAddr,
+		clnt,
+		aoFilePath,
+		dbPath,
+		big.NewInt(DefaultMaxReorgDepth),
+	)
+}
+
+// CreateManagerWithReorgDepth creates a manager that keeps enough
+// checkpoints to survive an L1 reorg of up to maxReorgDepth blocks, and
+// treats anything deeper than that as final
+func CreateManagerWithReorgDepth(
+	rollupAddr common.Address,
+	clnt arbbridge.ArbClient,
+	aoFilePath string,
+	dbPath string,
+	maxReorgDepth *big.Int,
+) (*Manager, error) {
+	man, err := CreateManagerAdvanced(
 		context.Background(),
 		rollupAddr,
 		true,
@@ -65,10 +88,15 @@ func CreateManager(
 			rollupAddr,
 			aoFilePath,
 			dbPath,
-			big.NewInt(defaultMaxReorgDepth),
+			maxReorgDepth,
 			false,
 		),
 	)
+	if err != nil {
+		return nil, err
+	}
+	man.SetMaxReorgDepth(maxReorgDepth)
+	return man, nil
 }
 
 func CreateManagerAdvanced(
@@ -84,6 +112,8 @@ func CreateManagerAdvanced(
 		listenerAddChan: make(chan rollup.ChainListener, 10),
 		actionChan:      make(chan func(*rollup.ChainObserver), 10),
 		ckpFac:          ckpFac,
+		maxReorgDepth:   big.NewInt(DefaultMaxReorgDepth),
+		calls:           NewCallPool(DefaultCallPoolConfig()),
 	}
 	go func() {
 		for {
@@ -240,31 +270,171 @@ func (man *Manager) AddListener(listener rollup.ChainListener) {
 	man.Unlock()
 }
 
-func (man *Manager) ExecuteCall(messages value.TupleValue, maxTime time.Duration) (*protocol.ExecutionAssertion, uint64) {
+// SetMaxReorgDepth sets how many L1 blocks deep an event must be before
+// results based on it are reported as final. It should match the reorg depth
+// the manager's checkpointer was created with.
+func (man *Manager) SetMaxReorgDepth(depth *big.Int) {
+	man.Lock()
+	man.maxReorgDepth = new(big.Int).Set(depth)
+	man.Unlock()
+}
+
+func (man *Manager) MaxReorgDepth() *big.Int {
+	man.Lock()
+	defer man.Unlock()
+	return new(big.Int).Set(man.maxReorgDepth)
+}
+
+type callResult struct {
+	assertion *protocol.ExecutionAssertion
+	numSteps  uint64
+	base      rollup.CallBase
+	err       error
+}
+
+// SetCallPoolConfig replaces the pool which limits the resources used by
+// calls. Calls already running finish on the old pool.
+func (man *Manager) SetCallPoolConfig(config CallPoolConfig) {
+	man.Lock()
+	man.calls = NewCallPool(config)
+	man.Unlock()
+}
+
+// callSetup prepares the machine, inbox and time bounds for a call along
+// with the state the call is based on
+type callSetup func(*rollup.ChainObserver) (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, rollup.CallBase, error)
+
+// ExecuteCall runs the given messages on the latest known valid machine with
+// time bounds at the current block
+func (man *Manager) ExecuteCall(ctx context.Context, messages value.TupleValue, maxSteps uint64) (*protocol.ExecutionAssertion, uint64, rollup.CallBase, error) {
+	return man.runCall(ctx, maxSteps, func(chain *rollup.ChainObserver) (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, rollup.CallBase, error) {
+		mach, base := chain.LatestCallState()
+		return mach, messages, currentTimeBounds(chain), base, nil
+	})
+}
+
+// ExecutePendingCall runs msg on the latest known valid machine after first
+// delivering every message which has reached the inbox on L1 but hasn't been
+// asserted yet
+func (man *Manager) ExecutePendingCall(ctx context.Context, msg message.Message, maxSteps uint64) (*protocol.ExecutionAssertion, uint64, rollup.CallBase, error) {
+	return man.runCall(ctx, maxSteps, func(chain *rollup.ChainObserver) (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, rollup.CallBase, error) {
+		mach, inbox, base, err := chain.PendingCallState()
+		if err != nil {
+			return nil, value.TupleValue{}, nil, rollup.CallBase{}, err
+		}
+		inbox.DeliverMessage(msg)
+		return mach, inbox.AsValue(), currentTimeBounds(chain), base, nil
+	})
+}
+
+// ExecuteCallAtNode runs the given messages on the machine in the state given
+// by the node with the given hash
+func (man *Manager) ExecuteCallAtNode(
+	ctx context.Context,
+	nodeHash common.Hash,
+	messages value.TupleValue,
+	maxSteps uint64,
+) (*protocol.ExecutionAssertion, uint64, rollup.CallBase, error) {
+	return man.runCall(ctx, maxSteps, func(chain *rollup.ChainObserver) (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, rollup.CallBase, error) {
+		mach, base, err := chain.MachineAfterNode(nodeHash)
+		return mach, messages, currentTimeBounds(chain), base, err
+	})
+}
+
+// ExecuteCallAtHeight runs the given messages on the latest known valid
+// machine as of the given L1 block height with time bounds pinned to that
+// height
+func (man *Manager) ExecuteCallAtHeight(
+	ctx context.Context,
+	height *common.TimeBlocks,
+	messages value.TupleValue,
+	maxSteps uint64,
+) (*protocol.ExecutionAssertion, uint64, rollup.CallBase, error) {
+	return man.runCall(ctx, maxSteps, func(chain *rollup.ChainObserver) (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, rollup.CallBase, error) {
+		mach, base, err := chain.MachineAtHeight(ctx, man.client, height)
+		return mach, messages, &protocol.TimeBoundsBlocks{height, height}, base, err
+	})
+}
+
+// runCall prepares and executes a call on the call pool in the background so
+// that restoring or replaying the machine doesn't block the chain. It also
+// returns the state the call was based on.
+func (man *Manager) runCall(
+	ctx context.Context,
+	maxSteps uint64,
+	setup callSetup,
+) (*protocol.ExecutionAssertion, uint64, rollup.CallBase, error) {
+	man.Lock()
+	calls := man.calls
+	man.Unlock()
+	retChan := make(chan callResult, 1)
+	action := func(chain *rollup.ChainObserver) {
+		go func() {
+			var base rollup.CallBase
+			assertion, numSteps, err := calls.Execute(ctx, maxSteps, func() (machine.Machine, value.TupleValue, *protocol.TimeBoundsBlocks, error) {
+				mach, inbox, timeBounds, callBase, err := setup(chain)
+				base = callBase
+				return mach, inbox, timeBounds, err
+			})
+			retChan <- callResult{assertion, numSteps, base, err}
+		}()
+	}
+	// The manager may be busy or restarting, so give up as soon as the
+	// caller does rather than waiting for it
+	select {
+	case man.actionChan <- action:
+	case <-ctx.Done():
+		return nil, 0, rollup.CallBase{}, ctx.Err()
+	}
+	select {
+	case ret := <-retChan:
+		return ret.assertion, ret.numSteps, ret.base, ret.err
+	case <-ctx.Done():
+		return nil, 0, rollup.CallBase{}, ctx.Err()
+	}
+}
+
+func currentTimeBounds(chain *rollup.ChainObserver) *protocol.TimeBoundsBlocks {
+	latestTime := chain.CurrentBlockId().Height
+	return &protocol.TimeBoundsBlocks{latestTime, latestTime}
+}
+
+// LatestValidState returns a copy of the latest known valid machine along
+// with the inbox position it has read up to
+func (man *Manager) LatestValidState() *rollup.ValidState {
+	retChan := make(chan *rollup.ValidState, 1)
+	man.actionChan <- func(chain *rollup.ChainObserver) {
+		retChan <- chain.LatestValidState()
+	}
+	return <-retChan
+}
+
+// InboxCounts returns how many inbox messages have been delivered, asserted
+// and confirmed
+func (man *Manager) InboxCounts() *rollup.InboxCounts {
+	retChan := make(chan *rollup.InboxCounts, 1)
+	man.actionChan <- func(chain *rollup.ChainObserver) {
+		retChan <- chain.InboxCounts()
+	}
+	return <-retChan
+}
+
+// InboxSegment returns up to maxCount of the messages delivered to the inbox
+// after the first beforeCount
+func (man *Manager) InboxSegment(beforeCount *big.Int, maxCount uint64) (*rollup.InboxSegment, error) {
 	retChan := make(chan struct {
-		*protocol.ExecutionAssertion
-		uint64
+		*rollup.InboxSegment
+		error
 	}, 1)
 	man.actionChan <- func(chain *rollup.ChainObserver) {
-		mach := chain.LatestKnownValidMachine()
-		latestTime := chain.CurrentBlockId().Height
-		timeBounds := &protocol.TimeBoundsBlocks{latestTime, latestTime}
-		go func() {
-			assertion, numSteps := mach.ExecuteAssertion(
-				// Call execution is only limited by wall time, so use a massive max steps as an approximation to infinity
-				10000000000000000,
-				timeBounds,
-				messages,
-				maxTime,
-			)
-			retChan <- struct {
-				*protocol.ExecutionAssertion
-				uint64
-			}{assertion, numSteps}
-		}()
+		segment, err := chain.InboxSegment(beforeCount, maxCount)
+		retChan <- struct {
+			*rollup.InboxSegment
+			error
+		}{segment, err}
 	}
 	ret := <-retChan
-	return ret.ExecutionAssertion, ret.uint64
+	return ret.InboxSegment, ret.error
 }
 
 func (man *Manager) CurrentBlockId() *common.BlockId {
@@ -0,0 +1,48 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package rollupmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+)
+
+func TestRunCallCancelledWhileBusy(t *testing.T) {
+	// Nothing reads actions, as if the manager were busy or restarting
+	man := &Manager{
+		actionChan: make(chan func(*rollup.ChainObserver)),
+		calls:      NewCallPool(DefaultCallPoolConfig()),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := man.runCall(ctx, 0, nil); err != context.Canceled {
+		t.Error("expected cancelled call to fail but got", err)
+	}
+
+	// The action is accepted but the chain never runs it
+	man.actionChan = make(chan func(*rollup.ChainObserver), 1)
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-man.actionChan
+		cancel()
+	}()
+	if _, _, _, err := man.runCall(ctx, 0, nil); err != context.Canceled {
+		t.Error("expected call abandoned by its caller to fail but got", err)
+	}
+}
@@ -84,6 +84,22 @@ func (e evilRollupCheckpointer) RestoreLatestState(
 	)
 }
 
+func (e evilRollupCheckpointer) RestoreStateAtHeight(
+	ctx context.Context,
+	clnt arbbridge.ArbClient,
+	height *common.TimeBlocks,
+	unmarshalFunc func([]byte, checkpointing.RestoreContext) error,
+) error {
+	return e.cp.RestoreStateAtHeight(
+		ctx,
+		clnt,
+		height,
+		func(contents []byte, resCtx checkpointing.RestoreContext) error {
+			return unmarshalFunc(contents, &evilRestoreContext{resCtx})
+		},
+	)
+}
+
 type evilRestoreContext struct {
 	rc checkpointing.RestoreContext
 }
@@ -107,3 +123,19 @@ func (e evilRollupCheckpointer) GetInitialMachine() (machine.Machine, error) {
 func (e evilRollupCheckpointer) AsyncSaveCheckpoint(blockId *common.BlockId, contents []byte, cpCtx checkpointing.CheckpointContext, closeWhenDone chan struct{}) {
 	e.cp.AsyncSaveCheckpoint(blockId, contents, cpCtx, closeWhenDone)
 }
+
+func (e evilRollupCheckpointer) SaveChallengeProgress(contract common.Address, participant common.Address, contents []byte, cpCtx checkpointing.CheckpointContext) error {
+	return e.cp.SaveChallengeProgress(contract, participant, contents, cpCtx)
+}
+
+func (e evilRollupCheckpointer) RestoreChallengeProgress(contract common.Address, participant common.Address) ([]byte, checkpointing.RestoreContext, error) {
+	contents, resCtx, err := e.cp.RestoreChallengeProgress(contract, participant)
+	if contents == nil || err != nil {
+		return contents, resCtx, err
+	}
+	return contents, &evilRestoreContext{resCtx}, nil
+}
+
+func (e evilRollupCheckpointer) DeleteChallengeProgress(contract common.Address, participant common.Address) error {
+	return e.cp.DeleteChallengeProgress(contract, participant)
+}
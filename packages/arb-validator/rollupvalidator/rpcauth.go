@@ -0,0 +1,106 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// jsonHandler wraps the JSON-RPC service with bearer token authentication
+// and the configured CORS policy
+func jsonHandler(service http.Handler, config RPCConfig) http.Handler {
+	r := mux.NewRouter()
+	r.Handle("/", requireBearerToken(service, config.AuthToken)).Methods("GET", "POST", "OPTIONS")
+
+	headersOk := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"})
+	originsOk := handlers.AllowedOrigins(config.CORSOrigins)
+	methodsOk := handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "OPTIONS"})
+	return handlers.CORS(headersOk, originsOk, methodsOk)(r)
+}
+
+// grpcServerOptions sets up TLS and bearer token authentication for the
+// gRPC server
+func grpcServerOptions(config RPCConfig) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if config.tlsEnabled() {
+		creds, err := credentials.NewServerTLSFromFile(config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	if config.AuthToken != "" {
+		opts = append(opts, grpc.UnaryInterceptor(bearerTokenInterceptor(config.AuthToken)))
+	}
+	return opts, nil
+}
+
+func checkBearerToken(header string, token string) bool {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(header[len(prefix):]), []byte(token)) == 1
+}
+
+// requireBearerToken rejects requests which don't carry the given token in
+// their Authorization header. CORS preflight requests are answered before
+// reaching this handler so browsers can still discover the allowed headers.
+func requireBearerToken(next http.Handler, token string) http.Handler {
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !checkBearerToken(r.Header.Get("Authorization"), token) {
+			http.Error(w, "invalid or missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bearerTokenInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		}
+		authorized := false
+		for _, header := range md.Get("authorization") {
+			if checkBearerToken(header, token) {
+				authorized = true
+				break
+			}
+		}
+		if !authorized {
+			return nil, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rollupvalidator
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testToken = "secret"
+
+var authCases = []struct {
+	name   string
+	header string
+	ok     bool
+}{
+	{"missing", "", false},
+	{"wrong", "Bearer wrong", false},
+	{"unprefixed", testToken, false},
+	{"correct", "Bearer " + testToken, true},
+}
+
+func testRPCConfig() RPCConfig {
+	config := DefaultRPCConfig()
+	config.AuthToken = testToken
+	config.CORSOrigins = []string{"https://allowed.example"}
+	return config
+}
+
+func TestJSONBearerToken(t *testing.T) {
+	service := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(jsonHandler(service, testRPCConfig()))
+	defer server.Close()
+
+	for _, tc := range authCases {
+		req, err := http.NewRequest("POST", server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		expected := http.StatusUnauthorized
+		if tc.ok {
+			expected = http.StatusOK
+		}
+		if resp.StatusCode != expected {
+			t.Errorf("%v token: expected status %v but got %v", tc.name, expected, resp.StatusCode)
+		}
+	}
+}
+
+func TestJSONCORSOrigins(t *testing.T) {
+	service := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(jsonHandler(service, testRPCConfig()))
+	defer server.Close()
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest("OPTIONS", server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "Authorization")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// Preflight requests are answered without a token so browsers can
+	// learn that Authorization is allowed
+	resp := preflight("https://allowed.example")
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected preflight to succeed but got status %v", resp.StatusCode)
+	}
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "https://allowed.example" {
+		t.Errorf("expected allowed origin to be echoed but got %q", origin)
+	}
+
+	resp = preflight("https://other.example")
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "" {
+		t.Errorf("expected other origin to be refused but got %q", origin)
+	}
+}
+
+func TestGRPCBearerToken(t *testing.T) {
+	opts, err := grpcServerOptions(testRPCConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	for _, tc := range authCases {
+		ctx := context.Background()
+		if tc.header != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tc.header)
+		}
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if tc.ok && err != nil {
+			t.Errorf("%v token: expected success but got %v", tc.name, err)
+		}
+		if !tc.ok && status.Code(err) != codes.Unauthenticated {
+			t.Errorf("%v token: expected unauthenticated but got %v", tc.name, err)
+		}
+	}
+}
+
+func TestDefaultRPCConfigDisablesGRPC(t *testing.T) {
+	config := DefaultRPCConfig()
+	if config.GRPCPort != "" {
+		t.Errorf("expected gRPC to be disabled by default but it's on port %v", config.GRPCPort)
+	}
+	if err := config.Validate(); err != nil {
+		t.Error(err)
+	}
+}
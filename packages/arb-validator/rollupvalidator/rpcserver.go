@@ -17,18 +17,18 @@
 package rollupvalidator
 
 import (
-	"context"
-	"log"
+	"errors"
+	"net"
 	"net/http"
-	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valmessage"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupmanager"
 
-	"github.com/gorilla/handlers"
-	"github.com/gorilla/mux"
 	"github.com/gorilla/rpc"
 	"github.com/gorilla/rpc/json"
+
+	"google.golang.org/grpc"
 )
 
 // Server provides an interface for interacting with a a running coordinator
@@ -36,39 +36,126 @@ type RPCServer struct {
 	*Server
 }
 
-func LaunchRPC(man *rollupmanager.Manager, port string) error {
-	server, err := NewRPCServer(man, time.Second*60)
+// RPCConfig controls how LaunchRPC exposes the validator's JSON-RPC and
+// gRPC interfaces
+type RPCConfig struct {
+	// JSONPort is the port the gorilla JSON-RPC server listens on
+	JSONPort string
+	// GRPCPort is the port the RollupValidator gRPC service listens on. An
+	// empty port disables the gRPC server. Set AuthToken before exposing it
+	// outside the host since it's otherwise unauthenticated.
+	GRPCPort string
+	// TLSCertFile and TLSKeyFile enable TLS for both servers when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// AuthToken, if set, must be given by every request as a bearer token
+	AuthToken string
+	// CORSOrigins lists the origins allowed to make JSON-RPC requests
+	CORSOrigins []string
+	// IndexPath is where the transaction and log index is stored. If it's
+	// empty, the index is kept in memory and rebuilt on every start.
+	IndexPath string
+	// Calls limits how many calls run at once and how long each may take
+	Calls rollupmanager.CallPoolConfig
+	// UnanimousResults, if set, delivers the assertions co-signed by every
+	// unanimous validator. Their transactions are reported with UNANIMOUS
+	// finality until they're asserted on chain.
+	UnanimousResults <-chan valmessage.FinalizedAssertion
+}
+
+// DefaultRPCConfig serves JSON-RPC on 1235 without TLS or authentication
+// and accepts requests from any origin. gRPC is disabled unless a port is
+// given.
+func DefaultRPCConfig() RPCConfig {
+	return RPCConfig{
+		JSONPort:    "1235",
+		CORSOrigins: []string{"*"},
+		Calls:       rollupmanager.DefaultCallPoolConfig(),
+	}
+}
+
+func (c RPCConfig) tlsEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// Validate checks that the TLS options were given together
+func (c RPCConfig) Validate() error {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("tls requires both a certificate and a key file")
+	}
+	if c.JSONPort == "" {
+		return errors.New("json rpc port must be set")
+	}
+	return c.Calls.Validate()
+}
+
+// LaunchRPC serves the validator over JSON-RPC and, if a gRPC port is
+// configured, gRPC. It only returns once one of the servers fails.
+func LaunchRPC(man *rollupmanager.Manager, config RPCConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	man.SetCallPoolConfig(config.Calls)
+	server, err := NewRPCServer(man, config.IndexPath, config.UnanimousResults)
 	if err != nil {
 		return err
 	}
 
-	// Run server
+	errChan := make(chan error, 2)
+	if config.GRPCPort != "" {
+		grpcServer, err := newGRPCServer(server.Server, config)
+		if err != nil {
+			return err
+		}
+		lis, err := net.Listen("tcp", ":"+config.GRPCPort)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errChan <- grpcServer.Serve(lis)
+		}()
+	}
+	go func() {
+		errChan <- serveJSON(server, config)
+	}()
+	return <-errChan
+}
+
+func serveJSON(server *RPCServer, config RPCConfig) error {
 	s := rpc.NewServer()
 	s.RegisterCodec(json.NewCodec(), "application/json")
 	s.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 
 	if err := s.RegisterService(server, "Validator"); err != nil {
-		log.Fatal(err)
+		return err
 	}
-	r := mux.NewRouter()
-	r.Handle("/", s).Methods("GET", "POST", "OPTIONS")
+	handler := jsonHandler(s, config)
 
-	headersOk := handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization"})
-	originsOk := handlers.AllowedOrigins([]string{"*"})
-	methodsOk := handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "OPTIONS"})
+	if config.tlsEnabled() {
+		return http.ListenAndServeTLS(":"+config.JSONPort, config.TLSCertFile, config.TLSKeyFile, handler)
+	}
+	return http.ListenAndServe(":"+config.JSONPort, handler)
+}
 
-	return http.ListenAndServe(":"+port, handlers.CORS(headersOk, originsOk, methodsOk)(r))
+func newGRPCServer(server *Server, config RPCConfig) (*grpc.Server, error) {
+	opts, err := grpcServerOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	grpcServer := grpc.NewServer(opts...)
+	validatorserver.RegisterRollupValidatorServer(grpcServer, server)
+	return grpcServer, nil
 }
 
 // NewServer returns a new instance of the Server class
-func NewRPCServer(man *rollupmanager.Manager, maxCallTime time.Duration) (*RPCServer, error) {
-	server, err := NewServer(man, maxCallTime)
+func NewRPCServer(man *rollupmanager.Manager, indexPath string, unanimousResults <-chan valmessage.FinalizedAssertion) (*RPCServer, error) {
+	server, err := NewServer(man, indexPath, unanimousResults)
 	return &RPCServer{server}, err
 }
 
 // FindLogs takes a set of parameters and return the list of all logs that match the query
 func (m *RPCServer) FindLogs(r *http.Request, args *validatorserver.FindLogsArgs, reply *validatorserver.FindLogsReply) error {
-	ret, err := m.Server.FindLogs(context.Background(), args)
+	ret, err := m.Server.FindLogs(r.Context(), args)
 	if ret != nil {
 		*reply = *ret
 	}
@@ -77,7 +164,7 @@ func (m *RPCServer) FindLogs(r *http.Request, args *validatorserver.FindLogsArgs
 
 // GetMessageResult returns the value output by the VM in response to the message with the given hash
 func (m *RPCServer) GetMessageResult(r *http.Request, args *validatorserver.GetMessageResultArgs, reply *validatorserver.GetMessageResultReply) error {
-	ret, err := m.Server.GetMessageResult(context.Background(), args)
+	ret, err := m.Server.GetMessageResult(r.Context(), args)
 	if ret != nil {
 		*reply = *ret
 	}
@@ -86,7 +173,7 @@ func (m *RPCServer) GetMessageResult(r *http.Request, args *validatorserver.GetM
 
 // GetAssertionCount returns the total number of finalized assertions
 func (m *RPCServer) GetAssertionCount(r *http.Request, args *validatorserver.GetAssertionCountArgs, reply *validatorserver.GetAssertionCountReply) error {
-	ret, err := m.Server.GetAssertionCount(context.Background(), args)
+	ret, err := m.Server.GetAssertionCount(r.Context(), args)
 	if ret != nil {
 		*reply = *ret
 	}
@@ -95,7 +182,16 @@ func (m *RPCServer) GetAssertionCount(r *http.Request, args *validatorserver.Get
 
 // GetVMInfo returns current metadata about this VM
 func (m *RPCServer) GetVMInfo(r *http.Request, args *validatorserver.GetVMInfoArgs, reply *validatorserver.GetVMInfoReply) error {
-	ret, err := m.Server.GetVMInfo(context.Background(), args)
+	ret, err := m.Server.GetVMInfo(r.Context(), args)
+	if ret != nil {
+		*reply = *ret
+	}
+	return err
+}
+
+// GetInboxCount returns how far the chain has read into its inbox
+func (m *RPCServer) GetInboxCount(r *http.Request, args *validatorserver.GetInboxCountArgs, reply *validatorserver.GetInboxCountReply) error {
+	ret, err := m.Server.GetInboxCount(r.Context(), args)
 	if ret != nil {
 		*reply = *ret
 	}
@@ -104,7 +200,7 @@ func (m *RPCServer) GetVMInfo(r *http.Request, args *validatorserver.GetVMInfoAr
 
 // CallMessage takes a request from a client to process in a temporary context and return the result
 func (m *RPCServer) CallMessage(r *http.Request, args *validatorserver.CallMessageArgs, reply *validatorserver.CallMessageReply) error {
-	ret, err := m.Server.CallMessage(context.Background(), args)
+	ret, err := m.Server.CallMessage(r.Context(), args)
 	if ret != nil {
 		*reply = *ret
 	}
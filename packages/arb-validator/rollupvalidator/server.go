@@ -24,21 +24,25 @@ import (
 	"log"
 	"math/big"
 	"strconv"
-	"time"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/message"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valmessage"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollupmanager"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/txdb"
 )
 
 //go:generate bash -c "protoc -I$(go list -f '{{ .Dir }}' -m github.com/offchainlabs/arbitrum/packages/arb-validator) -I. --go_out=paths=source_relative:. *.proto"
@@ -48,21 +52,43 @@ type Server struct {
 	rollupAddress common.Address
 	tracker       *txTracker
 	man           *rollupmanager.Manager
-	maxCallTime   time.Duration
 }
 
-// NewServer returns a new instance of the Server class
-func NewServer(man *rollupmanager.Manager, maxCallTime time.Duration) (*Server, error) {
-	completedAssertionChan := make(chan rollup.FinalizedAssertion)
-	assertionListener := &rollup.AssertionListener{completedAssertionChan}
+// NewServer returns a new instance of the Server class. Transactions and logs
+// are indexed in a database at indexPath so they're still available after a
+// restart. If indexPath is empty, the index is kept in memory. Assertions
+// received on unanimousResults, which may be nil, are indexed as UNANIMOUS.
+func NewServer(man *rollupmanager.Manager, indexPath string, unanimousResults <-chan valmessage.FinalizedAssertion) (*Server, error) {
+	finalityDepth := common.NewTimeBlocks(man.MaxReorgDepth())
+	var db *txdb.TxDB
+	if indexPath == "" {
+		db = txdb.NewMemory(finalityDepth)
+	} else {
+		var err error
+		db, err = txdb.Open(indexPath, finalityDepth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	assertionListener := &rollup.AssertionListener{
+		CompletedAssertionChan: make(chan rollup.FinalizedAssertion),
+		PendingAssertionChan:   make(chan rollup.PendingAssertion),
+		ConfirmedAssertionChan: make(chan rollup.ConfirmedAssertion),
+	}
 	man.AddListener(assertionListener)
 
-	tracker := newTxTracker(man.RollupAddress)
+	tracker := newTxTracker(db, man.RollupAddress)
 	go func() {
-		tracker.handleTxResults(assertionListener.CompletedAssertionChan)
+		tracker.handleTxResults(
+			assertionListener.CompletedAssertionChan,
+			assertionListener.PendingAssertionChan,
+			assertionListener.ConfirmedAssertionChan,
+			unanimousResults,
+		)
 	}()
 
-	return &Server{man.RollupAddress, tracker, man, maxCallTime}, nil
+	return &Server{man.RollupAddress, tracker, man}, nil
 }
 
 // FindLogs takes a set of parameters and return the list of all logs that match the query
@@ -90,16 +116,17 @@ func (m *Server) FindLogs(ctx context.Context, args *validatorserver.FindLogsArg
 		return nil, err
 	}
 
+	blockHeight := m.man.CurrentBlockId().Height
 	var logsChan <-chan []*validatorserver.LogInfo
 	if args.ToHeight == "latest" {
-		logsChan = m.tracker.FindLogs(&fromHeight, nil, addressInt, topics)
+		logsChan = m.tracker.FindLogs(&fromHeight, nil, addressInt, topics, blockHeight)
 	} else {
 		toHeight, err := strconv.ParseInt(args.ToHeight[2:], 16, 64)
 		if err != nil {
 			fmt.Println("FindLogs error4", err)
 			return nil, err
 		}
-		logsChan = m.tracker.FindLogs(&fromHeight, &toHeight, addressInt, topics)
+		logsChan = m.tracker.FindLogs(&fromHeight, &toHeight, addressInt, topics, blockHeight)
 	}
 
 	ret := <-logsChan
@@ -116,25 +143,8 @@ func (m *Server) GetMessageResult(ctx context.Context, args *validatorserver.Get
 	}
 	txHash := common.Hash{}
 	copy(txHash[:], txHashBytes)
-	resultChan := m.tracker.TxInfo(txHash)
-
-	txInfo := <-resultChan
-	if !txInfo.Found {
-		return &validatorserver.GetMessageResultReply{
-			Found: false,
-		}, nil
-	}
-
-	var buf bytes.Buffer
-	_ = value.MarshalValue(txInfo.RawVal, &buf) // error can only occur from writes and bytes.Buffer is safe
-	return &validatorserver.GetMessageResultReply{
-		Found:         true,
-		RawVal:        hexutil.Encode(buf.Bytes()),
-		LogPreHash:    txInfo.LogsPreHash,
-		LogPostHash:   txInfo.LogsPostHash,
-		LogValHashes:  txInfo.LogsValHashes,
-		OnChainTxHash: txInfo.OnChainTxHash,
-	}, nil
+	resultChan := m.tracker.TxInfo(txHash, m.man.CurrentBlockId().Height)
+	return <-resultChan, nil
 }
 
 // GetAssertionCount returns the total number of finalized assertions
@@ -152,6 +162,16 @@ func (m *Server) GetVMInfo(ctx context.Context, args *validatorserver.GetVMInfoA
 	}, nil
 }
 
+// GetInboxCount returns how far the chain has read into its inbox
+func (m *Server) GetInboxCount(ctx context.Context, args *validatorserver.GetInboxCountArgs) (*validatorserver.GetInboxCountReply, error) {
+	counts := m.man.InboxCounts()
+	return &validatorserver.GetInboxCountReply{
+		Delivered: hexutil.EncodeBig(counts.Delivered),
+		Asserted:  hexutil.EncodeBig(counts.Asserted),
+		Confirmed: hexutil.EncodeBig(counts.Confirmed),
+	}, nil
+}
+
 // CallMessage takes a request from a client to process in a temporary context and return the result
 func (m *Server) CallMessage(ctx context.Context, args *validatorserver.CallMessageArgs) (*validatorserver.CallMessageReply, error) {
 	log.Println("CallMessage", args.Data)
@@ -174,19 +194,59 @@ func (m *Server) CallMessage(ctx context.Context, args *validatorserver.CallMess
 	var sender common.Address
 	copy(sender[:], senderBytes)
 
+	if args.NodeHash != "" && args.BlockHeight != "" {
+		return nil, errors.New("call can't specify both a node hash and a block height")
+	}
+
+	var height *common.TimeBlocks
+	switch args.BlockHeight {
+	case "", "latest", "pending":
+	default:
+		heightInt, err := hexutil.DecodeBig(args.BlockHeight)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block height %v: %v", args.BlockHeight, err)
+		}
+		height = common.NewTimeBlocks(heightInt)
+	}
+
 	msg := message.Call{
 		To:       contractAddress,
 		From:     sender,
 		Data:     dataBytes,
-		BlockNum: m.man.CurrentBlockId().Height,
+		BlockNum: height,
+	}
+	if msg.BlockNum == nil {
+		msg.BlockNum = m.man.CurrentBlockId().Height
 	}
-
-	callingMessage := message.DeliveredValue(msg)
 
 	messageStack := protocol.NewMessageStack()
-	messageStack.AddMessage(callingMessage)
-
-	assertion, steps := m.man.ExecuteCall(messageStack.GetValue(), m.maxCallTime)
+	messageStack.AddMessage(message.DeliveredValue(msg))
+
+	var assertion *protocol.ExecutionAssertion
+	var steps uint64
+	var base rollup.CallBase
+	switch {
+	case args.NodeHash != "":
+		nodeHashBytes, err := hexutil.Decode(args.NodeHash)
+		if err != nil {
+			return nil, err
+		}
+		var nodeHash common.Hash
+		copy(nodeHash[:], nodeHashBytes)
+		assertion, steps, base, err = m.man.ExecuteCallAtNode(ctx, nodeHash, messageStack.GetValue(), args.MaxSteps)
+	case args.BlockHeight == "pending":
+		assertion, steps, base, err = m.man.ExecutePendingCall(ctx, msg, args.MaxSteps)
+	case height != nil:
+		assertion, steps, base, err = m.man.ExecuteCallAtHeight(ctx, height, messageStack.GetValue(), args.MaxSteps)
+	default:
+		assertion, steps, base, err = m.man.ExecuteCall(ctx, messageStack.GetValue(), args.MaxSteps)
+	}
+	if err == rollupmanager.ErrCallPoolBusy {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	log.Println("Executed call for", steps, "steps")
 
@@ -209,6 +269,25 @@ func (m *Server) CallMessage(ctx context.Context, args *validatorserver.CallMess
 	var buf bytes.Buffer
 	_ = value.MarshalValue(result, &buf) // error can only occur from writes and bytes.Buffer is safe
 	return &validatorserver.CallMessageReply{
-		RawVal: hexutil.Encode(buf.Bytes()),
+		RawVal:   hexutil.Encode(buf.Bytes()),
+		Finality: m.callFinality(base),
 	}, nil
 }
+
+// callFinality is the finality of the state a call ran on. It's as final as
+// the assertion which created its node, which is at least confirmed if the
+// node was.
+func (m *Server) callFinality(base rollup.CallBase) validatorserver.Finality {
+	if base.Pending {
+		return validatorserver.Finality_PENDING
+	}
+	indexed := <-m.tracker.NodeFinality(base.NodeHash, m.man.CurrentBlockId().Height)
+	finality := validatorserver.Finality_ASSERTED
+	if indexed.found {
+		finality = indexed.finality
+	}
+	if base.Confirmed && finality < validatorserver.Finality_CONFIRMED {
+		finality = validatorserver.Finality_CONFIRMED
+	}
+	return finality
+}
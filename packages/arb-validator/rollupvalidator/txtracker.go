@@ -19,7 +19,6 @@ package rollupvalidator
 import (
 	"log"
 	"math/big"
-	"strconv"
 
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
 
@@ -27,9 +26,11 @@ import (
 
 	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
 	"github.com/offchainlabs/arbitrum/packages/arb-util/hashing"
-	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/valmessage"
 	"github.com/offchainlabs/arbitrum/packages/arb-validator/rollup"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator/txdb"
 )
 
 type validatorRequest interface {
@@ -40,99 +41,47 @@ type assertionCountRequest struct {
 }
 
 type txRequest struct {
-	txHash     common.Hash
-	resultChan chan<- txInfo
+	txHash      common.Hash
+	blockHeight *common.TimeBlocks
+	resultChan  chan<- *validatorserver.GetMessageResultReply
 }
 
-type findLogsRequest struct {
-	fromHeight *int64
-	toHeight   *int64
-	address    *big.Int
-	topics     []common.Hash
-
-	resultChan chan<- []*validatorserver.LogInfo
-}
-
-type logsInfo struct {
-	msg  evm.EthBridgeMessage
-	Logs []evm.Log
-}
-
-type txInfo struct {
-	Found          bool
-	assertionIndex int
-	RawVal         value.Value
-	LogsPreHash    string
-	LogsPostHash   string
-	LogsValHashes  []string
-	OnChainTxHash  string
+type nodeFinalityRequest struct {
+	nodeHash    common.Hash
+	blockHeight *common.TimeBlocks
+	resultChan  chan<- nodeFinality
 }
 
-type assertionInfo struct {
-	TxLogs            []logsInfo
-	LogsAccHashes     []string
-	LogsValHashes     []string
-	SequenceNum       uint64
-	BeforeHash        common.Hash
-	OriginalInboxHash common.Hash
+type nodeFinality struct {
+	finality validatorserver.Finality
+	found    bool
 }
 
-type logResponse struct {
-	Log evm.Log
-	Msg evm.EthBridgeMessage
-}
-
-func (a *assertionInfo) FindLogs(address *big.Int, topics []common.Hash) []logResponse {
-	logs := make([]logResponse, 0)
-	for _, txLogs := range a.TxLogs {
-		for _, evmLog := range txLogs.Logs {
-			if address != nil && !value.NewIntValue(address).Equal(evmLog.ContractID) {
-				continue
-			}
-
-			if len(topics) > len(evmLog.Topics) {
-				continue
-			}
-
-			match := true
-			for i, topic := range topics {
-				if topic != evmLog.Topics[i] {
-					match = false
-					break
-				}
-			}
-			if match {
-				logs = append(logs, logResponse{evmLog, txLogs.msg})
-			}
-		}
-	}
-	return logs
-}
+type findLogsRequest struct {
+	fromHeight  *int64
+	toHeight    *int64
+	address     *big.Int
+	topics      []common.Hash
+	blockHeight *common.TimeBlocks
 
-func newAssertionInfo() *assertionInfo {
-	return &assertionInfo{}
+	resultChan chan<- []*validatorserver.LogInfo
 }
 
 type txTracker struct {
-	txRequestIndex int
-	transactions   map[common.Hash]txInfo
-	assertionInfo  []*assertionInfo
-	accountNonces  map[common.Address]uint64
-	vmID           common.Address
-	requests       chan validatorRequest
+	db       *txdb.TxDB
+	vmID     common.Address
+	requests chan validatorRequest
 }
 
 func newTxTracker(
+	db *txdb.TxDB,
 	vmID common.Address,
 ) *txTracker {
 	requests := make(chan validatorRequest, 100)
 	return &txTracker{
-		txRequestIndex: 0,
-		transactions:   make(map[common.Hash]txInfo),
-		assertionInfo:  make([]*assertionInfo, 0),
-		accountNonces:  make(map[common.Address]uint64),
-		vmID:           vmID,
-		requests:       requests,
+		db:       db,
+		vmID:     vmID,
+		requests: requests,
 	}
 }
 
@@ -142,9 +91,17 @@ func (tr *txTracker) AssertionCount() <-chan int {
 	return req
 }
 
-func (tr *txTracker) TxInfo(txHash common.Hash) <-chan txInfo {
-	req := make(chan txInfo, 1)
-	tr.requests <- txRequest{txHash, req}
+func (tr *txTracker) TxInfo(txHash common.Hash, blockHeight *common.TimeBlocks) <-chan *validatorserver.GetMessageResultReply {
+	req := make(chan *validatorserver.GetMessageResultReply, 1)
+	tr.requests <- txRequest{txHash, blockHeight, req}
+	return req
+}
+
+// NodeFinality returns the finality of the assertion which created the node,
+// if it's indexed
+func (tr *txTracker) NodeFinality(nodeHash common.Hash, blockHeight *common.TimeBlocks) <-chan nodeFinality {
+	req := make(chan nodeFinality, 1)
+	tr.requests <- nodeFinalityRequest{nodeHash, blockHeight, req}
 	return req
 }
 
@@ -153,57 +110,83 @@ func (tr *txTracker) FindLogs(
 	toHeight *int64,
 	address *big.Int,
 	topics []common.Hash,
+	blockHeight *common.TimeBlocks,
 ) <-chan []*validatorserver.LogInfo {
 	req := make(chan []*validatorserver.LogInfo, 1)
-	tr.requests <- findLogsRequest{fromHeight, toHeight, address, topics, req}
+	tr.requests <- findLogsRequest{fromHeight, toHeight, address, topics, blockHeight, req}
 	return req
 }
 
+func (tr *txTracker) processConfirmedAssertion(confirmed rollup.ConfirmedAssertion) {
+	if err := tr.db.ConfirmNode(confirmed.NodeHash, confirmed.BlockId.Height); err != nil {
+		log.Println("Failed to record confirmation of", confirmed.NodeHash, err)
+	}
+}
+
 func (tr *txTracker) processFinalizedAssertion(assertion rollup.FinalizedAssertion) {
-	info := newAssertionInfo()
+	info := txdb.Assertion{
+		NodeHash:     assertion.NodeHash,
+		PrevNodeHash: assertion.PrevNodeHash,
+	}
+	info.Txs, info.Logs = tr.assertionResults(assertion.Assertion, assertion.OnChainTxHash)
+	if err := tr.db.AddAssertion(info); err != nil {
+		log.Println("Failed to index assertion", assertion.NodeHash, err)
+	}
+}
+
+// processPendingAssertion serves the results of the assertion we're about to
+// make until it, or another assertion, is added
+func (tr *txTracker) processPendingAssertion(assertion rollup.PendingAssertion) {
+	info := txdb.Assertion{PrevNodeHash: assertion.PrevNodeHash}
+	info.Txs, info.Logs = tr.assertionResults(assertion.Assertion, common.Hash{})
+	tr.db.SetPending(info)
+}
+
+// processUnanimousAssertion records the results of an assertion which every
+// unanimous validator signed so they're served before it's on chain
+func (tr *txTracker) processUnanimousAssertion(assertion valmessage.FinalizedAssertion) {
+	sequenceNum := assertion.ProposalResults.SequenceNum
+	txs, _ := tr.assertionResults(assertion.Assertion, common.Hash{})
+	if err := tr.db.AddUnanimous(sequenceNum, txs); err != nil {
+		log.Println("Failed to index unanimous assertion", sequenceNum, err)
+	}
+}
+
+func (tr *txTracker) assertionResults(assertion *protocol.ExecutionAssertion, onChainTxHash common.Hash) ([]txdb.Tx, []txdb.Log) {
+	var txs []txdb.Tx
+	var txLogs []txdb.Log
 
 	zero := common.Hash{}
 	logsPreHash := hexutil.Encode(zero[:])
 
-	disputableTxHash := hexutil.Encode(assertion.OnChainTxHash[:])
+	disputableTxHash := hexutil.Encode(onChainTxHash[:])
 
-	logs := assertion.Assertion.Logs
-	info.LogsValHashes = make([]string, 0, len(logs))
-	info.LogsAccHashes = make([]string, 0, len(logs))
+	logs := assertion.Logs
+	logsValHashes := make([]string, 0, len(logs))
+	logsAccHashes := make([]string, 0, len(logs))
 	acc := common.Hash{}
 	for _, logsVal := range logs {
 		logsValHash := logsVal.Hash()
-		info.LogsValHashes = append(info.LogsValHashes,
+		logsValHashes = append(logsValHashes,
 			hexutil.Encode(logsValHash[:]))
 		acc = hashing.SoliditySHA3(
 			hashing.Bytes32(acc),
 			hashing.Bytes32(logsValHash),
 		)
-		info.LogsAccHashes = append(info.LogsAccHashes,
+		logsAccHashes = append(logsAccHashes,
 			hexutil.Encode(acc.Bytes()))
 	}
 
 	var logsPostHash string
 	if len(logs) > 0 {
-		logsPostHash = info.LogsAccHashes[len(info.LogsAccHashes)-1]
+		logsPostHash = logsAccHashes[len(logsAccHashes)-1]
 	} else {
 		logsPostHash = hexutil.Encode(zero[:])
 	}
 
 	for i, logVal := range logs {
 		if i > 0 {
-			logsPreHash = info.LogsAccHashes[i-1] // Previous acc hash
-		}
-		logsValHashes := info.LogsValHashes[i+1:] // log acc hashes after logVal
-
-		txInfo := txInfo{
-			Found:          true,
-			assertionIndex: len(tr.assertionInfo),
-			RawVal:         logVal,
-			LogsPreHash:    logsPreHash,
-			LogsPostHash:   logsPostHash,
-			LogsValHashes:  logsValHashes,
-			OnChainTxHash:  disputableTxHash,
+			logsPreHash = logsAccHashes[i-1] // Previous acc hash
 		}
 
 		evmVal, err := evm.ProcessLog(logVal, tr.vmID)
@@ -211,82 +194,89 @@ func (tr *txTracker) processFinalizedAssertion(assertion rollup.FinalizedAsserti
 			log.Printf("VM produced invalid evm result: %v\n", err)
 			continue
 		}
+		msg := evmVal.GetEthMsg()
 		switch evmVal := evmVal.(type) {
 		case evm.Stop:
-			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs})
+			for _, evmLog := range evmVal.Logs {
+				txLogs = append(txLogs, txdb.Log{TxHash: msg.TxHash, Log: evmLog})
+			}
 		case evm.Return:
-			info.TxLogs = append(info.TxLogs, logsInfo{evmVal.Msg, evmVal.Logs})
+			for _, evmLog := range evmVal.Logs {
+				txLogs = append(txLogs, txdb.Log{TxHash: msg.TxHash, Log: evmLog})
+			}
 		case evm.Revert:
 			log.Printf("*********** evm.Revert occurred with message \"%v\"\n", string(evmVal.ReturnVal))
 		}
 
-		msg := evmVal.GetEthMsg()
 		log.Println("Coordinator got response for", hexutil.Encode(msg.TxHash[:]))
-		tr.transactions[msg.TxHash] = txInfo
+		txs = append(txs, txdb.Tx{
+			TxHash:        msg.TxHash,
+			RawVal:        logVal,
+			LogsPreHash:   logsPreHash,
+			LogsPostHash:  logsPostHash,
+			LogsValHashes: logsValHashes[i+1:], // log acc hashes after logVal
+			OnChainTxHash: disputableTxHash,
+		})
 	}
-	tr.assertionInfo = append(tr.assertionInfo, info)
+	return txs, txLogs
 }
 
 func (tr *txTracker) processRequest(request validatorRequest) {
 	switch request := request.(type) {
 	case assertionCountRequest:
-		request.resultChan <- len(tr.assertionInfo) - 1
+		request.resultChan <- int(tr.db.AssertionCount()) - 1
 	case txRequest:
-		tx, ok := tr.transactions[request.txHash]
-		if ok {
-			request.resultChan <- tx
-		} else {
-			request.resultChan <- txInfo{Found: false}
+		if err := tr.db.UpdateFinal(request.blockHeight); err != nil {
+			log.Println("Failed to update finality", err)
 		}
-	case findLogsRequest:
-		startHeight := int64(0)
-		endHeight := int64(len(tr.assertionInfo))
-		if request.fromHeight != nil && *request.fromHeight > int64(0) {
-			startHeight = *request.fromHeight
+		reply, err := tr.db.TxInfo(request.txHash)
+		if err != nil {
+			log.Println("Failed to look up tx", request.txHash, err)
+			reply = &validatorserver.GetMessageResultReply{Found: false}
 		}
-		if request.toHeight != nil {
-			altEndHeight := *request.toHeight + 1
-			if endHeight > altEndHeight {
-				endHeight = altEndHeight
-			}
+		request.resultChan <- reply
+	case nodeFinalityRequest:
+		if err := tr.db.UpdateFinal(request.blockHeight); err != nil {
+			log.Println("Failed to update finality", err)
 		}
-		logs := make([]*validatorserver.LogInfo, 0)
-		if startHeight >= int64(len(tr.assertionInfo)) {
-			request.resultChan <- logs
-			break
+		finality, found, err := tr.db.NodeFinality(request.nodeHash)
+		if err != nil {
+			log.Println("Failed to look up node", request.nodeHash, err)
 		}
-		assertions := tr.assertionInfo[startHeight:endHeight]
-
-		for i, assertion := range assertions {
-			assertionLogs := assertion.FindLogs(request.address, request.topics)
-			for j, evmLog := range assertionLogs {
-				addressBytes := evmLog.Log.ContractID.ToBytes()
-				topicStrings := make([]string, 0, len(evmLog.Log.Topics))
-				for _, topic := range evmLog.Log.Topics {
-					topicStrings = append(topicStrings, hexutil.Encode(topic[:]))
-				}
-
-				logs = append(logs, &validatorserver.LogInfo{
-					Address:          hexutil.Encode(addressBytes[12:]),
-					BlockHash:        hexutil.Encode(evmLog.Msg.TxHash[:]),
-					BlockNumber:      "0x" + strconv.FormatInt(startHeight+int64(i), 16),
-					Data:             hexutil.Encode(evmLog.Log.Data[:]),
-					LogIndex:         "0x" + strconv.FormatInt(int64(j), 16),
-					Topics:           topicStrings,
-					TransactionIndex: "0x0",
-					TransactionHash:  hexutil.Encode(evmLog.Msg.TxHash[:]),
-				})
-			}
+		request.resultChan <- nodeFinality{finality, found}
+	case findLogsRequest:
+		if err := tr.db.UpdateFinal(request.blockHeight); err != nil {
+			log.Println("Failed to update finality", err)
+		}
+		logs, err := tr.db.FindLogs(request.fromHeight, request.toHeight, request.address, request.topics)
+		if err != nil {
+			log.Println("Failed to find logs", err)
+			logs = make([]*validatorserver.LogInfo, 0)
 		}
 		request.resultChan <- logs
 	}
 }
 
-func (tr *txTracker) handleTxResults(completedCalls chan rollup.FinalizedAssertion) {
+func (tr *txTracker) handleTxResults(
+	completedCalls chan rollup.FinalizedAssertion,
+	pendingAssertions chan rollup.PendingAssertion,
+	confirmedAssertions chan rollup.ConfirmedAssertion,
+	unanimousAssertions <-chan valmessage.FinalizedAssertion,
+) {
 	for {
 		select {
 		case finalizedAssertion := <-completedCalls:
 			tr.processFinalizedAssertion(finalizedAssertion)
+		case pendingAssertion := <-pendingAssertions:
+			tr.processPendingAssertion(pendingAssertion)
+		case confirmedAssertion := <-confirmedAssertions:
+			tr.processConfirmedAssertion(confirmedAssertion)
+		case unanimousAssertion, ok := <-unanimousAssertions:
+			if !ok {
+				unanimousAssertions = nil
+				break
+			}
+			tr.processUnanimousAssertion(unanimousAssertion)
 		case request := <-tr.requests:
 			tr.processRequest(request)
 		}
@@ -0,0 +1,109 @@
+/*
+* Copyright 2020, Offchain Labs, Inc.
+*
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+*    http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package testmachine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/aoasm"
+	"github.com/offchainlabs/arbitrum/packages/arb-avm-go/code"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/protocol"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+)
+
+// opcodeSetup holds the instructions run before an opcode so that it finds
+// valid operands. Every program starts by pushing three ints which covers
+// the remaining opcodes.
+var opcodeSetup = map[value.Opcode]string{
+	code.JUMP:   "nop @next",
+	code.CJUMP:  "nop 1\nnop @next",
+	code.ERRSET: "nop @next",
+	code.AUXPOP: "nop 1\nauxpush",
+	code.TGET:   "nop Tuple(4, 5)\nnop 1",
+	code.TSET:   "nop 6\nnop Tuple(4, 5)\nnop 1",
+	code.TLEN:   "nop Tuple(4, 5)",
+}
+
+func opcodeProgram(op value.Opcode) string {
+	var sb strings.Builder
+	sb.WriteString(".code\n    nop 3\n    nop 2\n    nop 1\n")
+	if setup, ok := opcodeSetup[op]; ok {
+		for _, line := range strings.Split(setup, "\n") {
+			sb.WriteString("    " + line + "\n")
+		}
+	}
+	sb.WriteString(fmt.Sprintf("    %v\nnext:\n    halt\n.static Tuple()\n", code.InstructionNames[op]))
+	return sb.String()
+}
+
+// TestOpcodeGasParity runs every opcode on both the Go and C++ machines and
+// checks that they charge the same gas
+func TestOpcodeGasParity(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gasparity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	timeBounds := &protocol.TimeBoundsBlocks{
+		Start: common.NewTimeBlocksInt(0),
+		End:   common.NewTimeBlocksInt(100),
+	}
+	for op := range code.GasSchedule {
+		name := code.InstructionNames[op]
+		t.Run(name, func(t *testing.T) {
+			prog, err := aoasm.Assemble(strings.NewReader(opcodeProgram(op)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			codeFile := filepath.Join(dir, name+".ao")
+			f, err := os.Create(codeFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := prog.Write(f); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			m, err := New(codeFile, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			inbox := value.NewEmptyTuple()
+			a1, steps1 := m.cppmachine.ExecuteAssertion(100, timeBounds, inbox, 0)
+			a2, steps2 := m.gomachine.ExecuteAssertion(100, timeBounds, inbox, 0)
+			if steps1 != steps2 {
+				t.Errorf("C++ machine ran %v steps but Go machine ran %v", steps1, steps2)
+			}
+			if a1.NumGas != a2.NumGas {
+				t.Errorf("C++ machine used %v gas but Go machine used %v", a1.NumGas, a2.NumGas)
+			}
+			if a2.NumGas != m.gomachine.GasUsed() {
+				t.Errorf("Go machine reported %v gas used but assertion had %v", m.gomachine.GasUsed(), a2.NumGas)
+			}
+		})
+	}
+}
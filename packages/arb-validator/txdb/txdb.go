@@ -0,0 +1,808 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package txdb is a persistent index of the transactions and logs produced by
+// the assertions a validator has accepted. Assertions are numbered in the
+// order they were added and that number is reported as the block height of
+// their logs. The results of the assertion the validator is about to make are
+// also served, at the next height, until an assertion is added. Transactions
+// in assertions co-signed by every unanimous validator are kept until an
+// assertion on chain with the same transaction is final.
+package txdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+)
+
+var (
+	metadataKey           = []byte("metadata")
+	assertionPrefix       = []byte("a")
+	nodePrefix            = []byte("n")
+	txPrefix              = []byte("t")
+	logPrefix             = []byte("l")
+	addressPrefix         = []byte("A")
+	topicPrefix           = []byte("T")
+	confirmationPrefix    = []byte("c")
+	unmatchedConfirmation = []byte("u")
+	unanimousPrefix       = []byte("U")
+)
+
+// Tx is the result of a transaction executed in an assertion
+type Tx struct {
+	TxHash        common.Hash
+	RawVal        value.Value
+	LogsPreHash   string
+	LogsPostHash  string
+	LogsValHashes []string
+	OnChainTxHash string
+}
+
+// Log is an EVM log emitted by a transaction
+type Log struct {
+	TxHash common.Hash
+	Log    evm.Log
+}
+
+// Assertion holds everything the index records about an assertion.
+// PrevNodeHash is the node created by the previous valid assertion on the
+// same branch and is used to detect that the chain was rolled back.
+type Assertion struct {
+	NodeHash     common.Hash
+	PrevNodeHash common.Hash
+	Txs          []Tx
+	Logs         []Log
+}
+
+type metadata struct {
+	assertionCount uint64
+	confirmedIndex int64
+	finalIndex     int64
+}
+
+// TxDB indexes assertions by node, their transactions by hash and their logs
+// by height, address and topic. It isn't safe for concurrent use.
+type TxDB struct {
+	db ethdb.KeyValueStore
+	// finalityDepth is how many L1 blocks must pass after a confirmation
+	// before we consider it safe from reorgs
+	finalityDepth *common.TimeBlocks
+	meta          metadata
+	// pending holds the results of an assertion which isn't on chain yet.
+	// It's only kept in memory since it's recomputed after a restart.
+	pending *Assertion
+}
+
+// Open returns an index stored in a leveldb database at the given path,
+// creating it if it doesn't exist
+func Open(path string, finalityDepth *common.TimeBlocks) (*TxDB, error) {
+	db, err := leveldb.New(path, 16, 16, "")
+	if err != nil {
+		return nil, err
+	}
+	return New(db, finalityDepth)
+}
+
+// NewMemory returns an index which is lost when the process exits
+func NewMemory(finalityDepth *common.TimeBlocks) *TxDB {
+	txdb, _ := New(memorydb.New(), finalityDepth) // an empty store has no metadata to fail on
+	return txdb
+}
+
+// New returns an index backed by the given store, picking up where any
+// previous index in it left off
+func New(db ethdb.KeyValueStore, finalityDepth *common.TimeBlocks) (*TxDB, error) {
+	txdb := &TxDB{
+		db:            db,
+		finalityDepth: finalityDepth,
+		meta: metadata{
+			assertionCount: 0,
+			confirmedIndex: -1,
+			finalIndex:     -1,
+		},
+	}
+	has, err := db.Has(metadataKey)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		data, err := db.Get(metadataKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) != 24 {
+			return nil, errors.New("corrupt tx index metadata")
+		}
+		txdb.meta.assertionCount = binary.BigEndian.Uint64(data[0:])
+		txdb.meta.confirmedIndex = int64(binary.BigEndian.Uint64(data[8:]))
+		txdb.meta.finalIndex = int64(binary.BigEndian.Uint64(data[16:]))
+	}
+	return txdb, nil
+}
+
+// Close closes the underlying store
+func (txdb *TxDB) Close() error {
+	return txdb.db.Close()
+}
+
+// AssertionCount returns the number of assertions in the index
+func (txdb *TxDB) AssertionCount() uint64 {
+	return txdb.meta.assertionCount
+}
+
+// AddAssertion appends the assertion to the index. If the assertion doesn't
+// follow the most recently added one, the chain was reorganized and every
+// assertion after its predecessor is removed first.
+func (txdb *TxDB) AddAssertion(assertion Assertion) error {
+	// Whether or not this is the pending assertion, any pending results are
+	// now out of date
+	txdb.pending = nil
+	if err := txdb.rollbackForAssertion(assertion); err != nil {
+		return err
+	}
+
+	index := txdb.meta.assertionCount
+	batch := txdb.db.NewBatch()
+	record := make([]byte, 0, 32*(1+len(assertion.Txs)))
+	record = append(record, assertion.NodeHash[:]...)
+	for _, tx := range assertion.Txs {
+		record = append(record, tx.TxHash[:]...)
+		data, err := marshalTx(index, tx)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(txKey(tx.TxHash), data); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(assertionKey(index), record); err != nil {
+		return err
+	}
+	if err := batch.Put(nodeKey(assertion.NodeHash), encodeUint64(index)); err != nil {
+		return err
+	}
+
+	for i, evmLog := range assertion.Logs {
+		logIndex := uint64(i)
+		data, err := marshalLog(index, logIndex, evmLog)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(logKey(index, logIndex), data); err != nil {
+			return err
+		}
+		if err := batch.Put(addressKey(evmLog.Log.ContractID, index, logIndex), nil); err != nil {
+			return err
+		}
+		for pos, topic := range evmLog.Log.Topics {
+			if err := batch.Put(topicKey(pos, topic, index, logIndex), nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	meta := txdb.meta
+	meta.assertionCount++
+	unmatchedKey := unmatchedConfirmationKey(assertion.NodeHash)
+	heightData, err := txdb.getOptional(unmatchedKey)
+	if err != nil {
+		return err
+	}
+	if heightData != nil {
+		if err := batch.Delete(unmatchedKey); err != nil {
+			return err
+		}
+		if err := txdb.confirm(batch, &meta, int64(index), heightData); err != nil {
+			return err
+		}
+	}
+	return txdb.commit(batch, meta)
+}
+
+// AddUnanimous records the transactions of the unanimous assertion with the
+// given sequence number, which are reported with UNANIMOUS finality until an
+// assertion on chain includes them
+func (txdb *TxDB) AddUnanimous(sequenceNum uint64, txs []Tx) error {
+	batch := txdb.db.NewBatch()
+	for _, tx := range txs {
+		data, err := marshalTx(sequenceNum, tx)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(unanimousKey(tx.TxHash), data); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// SetPending serves the results of an assertion which this validator
+// computed but which isn't on chain yet, with PENDING finality, until the
+// next assertion is added. It replaces any previous pending results.
+func (txdb *TxDB) SetPending(assertion Assertion) {
+	txdb.pending = &assertion
+}
+
+// rollbackForAssertion removes any assertions which the given one replaces
+func (txdb *TxDB) rollbackForAssertion(assertion Assertion) error {
+	if txdb.meta.assertionCount == 0 {
+		return nil
+	}
+	prevIndex, found, err := txdb.nodeIndex(assertion.PrevNodeHash)
+	if err != nil {
+		return err
+	}
+	if found {
+		return txdb.rollback(prevIndex + 1)
+	}
+	// The predecessor isn't indexed, either because it's been pruned or it
+	// came before the index was created. If we've already seen this node, it's
+	// being replayed after a restart.
+	index, found, err := txdb.nodeIndex(assertion.NodeHash)
+	if err != nil {
+		return err
+	}
+	if found {
+		return txdb.rollback(index)
+	}
+	return nil
+}
+
+// rollback removes all assertions at or after the given index
+func (txdb *TxDB) rollback(count uint64) error {
+	if count >= txdb.meta.assertionCount {
+		return nil
+	}
+	batch := txdb.db.NewBatch()
+	for index := count; index < txdb.meta.assertionCount; index++ {
+		record, err := txdb.db.Get(assertionKey(index))
+		if err != nil {
+			return err
+		}
+		if len(record)%32 != 0 || len(record) == 0 {
+			return fmt.Errorf("corrupt tx index record for assertion %v", index)
+		}
+		var nodeHash common.Hash
+		copy(nodeHash[:], record)
+		if err := batch.Delete(nodeKey(nodeHash)); err != nil {
+			return err
+		}
+		for i := 32; i < len(record); i += 32 {
+			var txHash common.Hash
+			copy(txHash[:], record[i:])
+			if err := batch.Delete(txKey(txHash)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(assertionKey(index)); err != nil {
+			return err
+		}
+		if err := batch.Delete(confirmationKey(index)); err != nil {
+			return err
+		}
+		if err := txdb.deleteLogs(batch, index); err != nil {
+			return err
+		}
+	}
+
+	meta := txdb.meta
+	meta.assertionCount = count
+	if meta.confirmedIndex >= int64(count) {
+		meta.confirmedIndex = int64(count) - 1
+	}
+	if meta.finalIndex >= int64(count) {
+		meta.finalIndex = int64(count) - 1
+	}
+	return txdb.commit(batch, meta)
+}
+
+func (txdb *TxDB) deleteLogs(batch ethdb.Batch, index uint64) error {
+	prefix := append(append([]byte{}, logPrefix...), encodeUint64(index)...)
+	it := txdb.db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	for it.Next() {
+		logIndex := binary.BigEndian.Uint64(it.Key()[len(prefix):])
+		contractID, logInfo, err := unmarshalLog(it.Value())
+		if err != nil {
+			return err
+		}
+		if err := batch.Delete(addressKey(contractID, index, logIndex)); err != nil {
+			return err
+		}
+		for pos, topicString := range logInfo.Topics {
+			topicBytes, err := hexutil.Decode(topicString)
+			if err != nil {
+				return err
+			}
+			var topic common.Hash
+			copy(topic[:], topicBytes)
+			if err := batch.Delete(topicKey(pos, topic, index, logIndex)); err != nil {
+				return err
+			}
+		}
+		if err := batch.Delete(copyBytes(it.Key())); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// ConfirmNode records that the node created by an assertion was confirmed in
+// the L1 block at the given height. Confirming a node confirms every
+// assertion before it. The node's assertion may be added after it's
+// confirmed.
+func (txdb *TxDB) ConfirmNode(nodeHash common.Hash, blockHeight *common.TimeBlocks) error {
+	heightData := blockHeight.AsInt().Bytes()
+	index, found, err := txdb.nodeIndex(nodeHash)
+	if err != nil {
+		return err
+	}
+	batch := txdb.db.NewBatch()
+	meta := txdb.meta
+	if found {
+		if err := txdb.confirm(batch, &meta, int64(index), heightData); err != nil {
+			return err
+		}
+	} else {
+		if err := batch.Put(unmatchedConfirmationKey(nodeHash), heightData); err != nil {
+			return err
+		}
+	}
+	return txdb.commit(batch, meta)
+}
+
+func (txdb *TxDB) confirm(batch ethdb.Batch, meta *metadata, index int64, heightData []byte) error {
+	if index <= meta.confirmedIndex {
+		return nil
+	}
+	meta.confirmedIndex = index
+	// Confirmations that aren't yet final are kept in the order they occurred
+	return batch.Put(confirmationKey(uint64(index)), heightData)
+}
+
+// UpdateFinal marks assertions confirmed deep enough below the given L1
+// height as final
+func (txdb *TxDB) UpdateFinal(blockHeight *common.TimeBlocks) error {
+	if blockHeight == nil {
+		return nil
+	}
+	batch := txdb.db.NewBatch()
+	meta := txdb.meta
+	it := txdb.db.NewIteratorWithPrefix(confirmationPrefix)
+	for it.Next() {
+		if !txdb.isFinal(it.Value(), blockHeight) {
+			break
+		}
+		meta.finalIndex = int64(binary.BigEndian.Uint64(it.Key()[len(confirmationPrefix):]))
+		if err := batch.Delete(copyBytes(it.Key())); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := txdb.pruneUnanimous(batch, txdb.meta.finalIndex+1, meta.finalIndex+1); err != nil {
+		return err
+	}
+	pruned, err := txdb.pruneUnmatchedConfirmations(batch, blockHeight)
+	if err != nil {
+		return err
+	}
+	if meta == txdb.meta && pruned == 0 {
+		return nil
+	}
+	return txdb.commit(batch, meta)
+}
+
+// pruneUnanimous drops the unanimous results of transactions in the
+// assertions from start up to end, which have just become final on chain
+func (txdb *TxDB) pruneUnanimous(batch ethdb.Batch, start int64, end int64) error {
+	for index := start; index < end; index++ {
+		record, err := txdb.db.Get(assertionKey(uint64(index)))
+		if err != nil {
+			return err
+		}
+		for i := 32; i < len(record); i += 32 {
+			var txHash common.Hash
+			copy(txHash[:], record[i:])
+			if err := batch.Delete(unanimousKey(txHash)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// pruneUnmatchedConfirmations drops confirmations of nodes whose assertion
+// still hasn't been added once the confirmation is final so they don't
+// accumulate forever. Any later confirmation covers everything before it.
+func (txdb *TxDB) pruneUnmatchedConfirmations(batch ethdb.Batch, blockHeight *common.TimeBlocks) (int, error) {
+	pruned := 0
+	it := txdb.db.NewIteratorWithPrefix(unmatchedConfirmation)
+	defer it.Release()
+	for it.Next() {
+		if !txdb.isFinal(it.Value(), blockHeight) {
+			continue
+		}
+		if err := batch.Delete(copyBytes(it.Key())); err != nil {
+			return 0, err
+		}
+		pruned++
+	}
+	return pruned, it.Error()
+}
+
+// isFinal checks whether a confirmation at the encoded L1 height is at least
+// finalityDepth blocks below the given height
+func (txdb *TxDB) isFinal(heightData []byte, blockHeight *common.TimeBlocks) bool {
+	confHeight := new(big.Int).SetBytes(heightData)
+	finalHeight := new(big.Int).Add(confHeight, txdb.finalityDepth.AsInt())
+	return blockHeight.AsInt().Cmp(finalHeight) >= 0
+}
+
+func (txdb *TxDB) finality(index uint64) validatorserver.Finality {
+	switch {
+	case index >= txdb.meta.assertionCount:
+		return validatorserver.Finality_PENDING
+	case int64(index) <= txdb.meta.finalIndex:
+		return validatorserver.Finality_L1_FINAL
+	case int64(index) <= txdb.meta.confirmedIndex:
+		return validatorserver.Finality_CONFIRMED
+	default:
+		return validatorserver.Finality_ASSERTED
+	}
+}
+
+// NodeFinality returns the finality of the assertion which created the node
+// with the given hash. found is false if no indexed assertion created it.
+func (txdb *TxDB) NodeFinality(nodeHash common.Hash) (finality validatorserver.Finality, found bool, err error) {
+	index, found, err := txdb.nodeIndex(nodeHash)
+	if err != nil || !found {
+		return validatorserver.Finality_PENDING, false, err
+	}
+	return txdb.finality(index), true, nil
+}
+
+// TxInfo returns the result of the transaction with the given hash, or a
+// reply with Found unset if the transaction isn't in the index
+func (txdb *TxDB) TxInfo(txHash common.Hash) (*validatorserver.GetMessageResultReply, error) {
+	data, err := txdb.getOptional(txKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		reply, index, err := unmarshalTx(txHash, data)
+		if err != nil {
+			return nil, err
+		}
+		reply.Finality = txdb.finality(index)
+		return reply, nil
+	}
+	data, err = txdb.getOptional(unanimousKey(txHash))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		reply, _, err := unmarshalTx(txHash, data)
+		if err != nil {
+			return nil, err
+		}
+		reply.Finality = validatorserver.Finality_UNANIMOUS
+		return reply, nil
+	}
+	return txdb.pendingTxInfo(txHash), nil
+}
+
+func (txdb *TxDB) pendingTxInfo(txHash common.Hash) *validatorserver.GetMessageResultReply {
+	if txdb.pending != nil {
+		for _, tx := range txdb.pending.Txs {
+			if tx.TxHash == txHash {
+				reply := txReply(tx)
+				reply.Found = true
+				reply.Finality = txdb.finality(txdb.meta.assertionCount)
+				return reply
+			}
+		}
+	}
+	return &validatorserver.GetMessageResultReply{Found: false}
+}
+
+// FindLogs returns the logs emitted by assertions between fromHeight and
+// toHeight inclusive whose contract is address and whose leading topics
+// match topics. A nil bound or address isn't used to filter. Pending logs
+// are at the height after the last assertion, and are UNANIMOUS if their
+// transaction is in a unanimous assertion.
+func (txdb *TxDB) FindLogs(
+	fromHeight *int64,
+	toHeight *int64,
+	address *big.Int,
+	topics []common.Hash,
+) ([]*validatorserver.LogInfo, error) {
+	start := uint64(0)
+	if fromHeight != nil && *fromHeight > 0 {
+		start = uint64(*fromHeight)
+	}
+	end := txdb.meta.assertionCount
+	if toHeight != nil && *toHeight+1 < int64(end) {
+		if *toHeight < 0 {
+			return []*validatorserver.LogInfo{}, nil
+		}
+		end = uint64(*toHeight + 1)
+	}
+
+	// Scan whichever index narrows the search the most, then check each
+	// candidate against the remaining filters
+	var prefix []byte
+	switch {
+	case address != nil:
+		prefix = addressKey(value.NewIntValue(address), 0, 0)
+	case len(topics) > 0:
+		prefix = topicKey(0, topics[0], 0, 0)
+	default:
+		prefix = logKey(0, 0)
+	}
+	prefix = prefix[:len(prefix)-16]
+
+	logs := make([]*validatorserver.LogInfo, 0)
+	it := txdb.db.NewIteratorWithStart(append(copyBytes(prefix), encodeUint64(start)...))
+	defer it.Release()
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+16 || string(key[:len(prefix)]) != string(prefix) {
+			break
+		}
+		index := binary.BigEndian.Uint64(key[len(prefix):])
+		if index >= end {
+			break
+		}
+		logIndex := binary.BigEndian.Uint64(key[len(prefix)+8:])
+		logInfo, err := txdb.getLog(index, logIndex)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesTopics(logInfo, topics) {
+			continue
+		}
+		logInfo.Finality = txdb.finality(index)
+		logs = append(logs, logInfo)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	pendingIndex := txdb.meta.assertionCount
+	if txdb.pending == nil || start > pendingIndex || (toHeight != nil && *toHeight < int64(pendingIndex)) {
+		return logs, nil
+	}
+	for i, evmLog := range txdb.pending.Logs {
+		if address != nil && evmLog.Log.ContractID.BigInt().Cmp(address) != 0 {
+			continue
+		}
+		logInfo := newLogInfo(pendingIndex, uint64(i), evmLog)
+		if !matchesTopics(logInfo, topics) {
+			continue
+		}
+		logInfo.Finality = txdb.finality(pendingIndex)
+		unanimous, err := txdb.db.Has(unanimousKey(evmLog.TxHash))
+		if err != nil {
+			return nil, err
+		}
+		if unanimous {
+			logInfo.Finality = validatorserver.Finality_UNANIMOUS
+		}
+		logs = append(logs, logInfo)
+	}
+	return logs, nil
+}
+
+func matchesTopics(logInfo *validatorserver.LogInfo, topics []common.Hash) bool {
+	if len(topics) > len(logInfo.Topics) {
+		return false
+	}
+	for i, topic := range topics {
+		if logInfo.Topics[i] != hexutil.Encode(topic[:]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (txdb *TxDB) getLog(index uint64, logIndex uint64) (*validatorserver.LogInfo, error) {
+	data, err := txdb.db.Get(logKey(index, logIndex))
+	if err != nil {
+		return nil, err
+	}
+	_, logInfo, err := unmarshalLog(data)
+	return logInfo, err
+}
+
+func (txdb *TxDB) nodeIndex(nodeHash common.Hash) (uint64, bool, error) {
+	data, err := txdb.getOptional(nodeKey(nodeHash))
+	if err != nil || data == nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+func (txdb *TxDB) getOptional(key []byte) ([]byte, error) {
+	has, err := txdb.db.Has(key)
+	if err != nil || !has {
+		return nil, err
+	}
+	return txdb.db.Get(key)
+}
+
+// commit writes the batch along with the updated metadata and only then
+// makes the new metadata visible
+func (txdb *TxDB) commit(batch ethdb.Batch, meta metadata) error {
+	data := make([]byte, 24)
+	binary.BigEndian.PutUint64(data[0:], meta.assertionCount)
+	binary.BigEndian.PutUint64(data[8:], uint64(meta.confirmedIndex))
+	binary.BigEndian.PutUint64(data[16:], uint64(meta.finalIndex))
+	if err := batch.Put(metadataKey, data); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	txdb.meta = meta
+	return nil
+}
+
+func txReply(tx Tx) *validatorserver.GetMessageResultReply {
+	return &validatorserver.GetMessageResultReply{
+		RawVal:        hexutil.Encode(value.MarshalValueToBytes(tx.RawVal)),
+		LogPreHash:    tx.LogsPreHash,
+		LogPostHash:   tx.LogsPostHash,
+		LogValHashes:  tx.LogsValHashes,
+		OnChainTxHash: tx.OnChainTxHash,
+	}
+}
+
+func marshalTx(index uint64, tx Tx) ([]byte, error) {
+	var buf []byte
+	data, err := proto.Marshal(txReply(tx))
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, encodeUint64(index)...)
+	return append(buf, data...), nil
+}
+
+func unmarshalTx(txHash common.Hash, data []byte) (*validatorserver.GetMessageResultReply, uint64, error) {
+	if len(data) < 8 {
+		return nil, 0, fmt.Errorf("corrupt tx index record for tx %v", txHash)
+	}
+	reply := &validatorserver.GetMessageResultReply{}
+	if err := proto.Unmarshal(data[8:], reply); err != nil {
+		return nil, 0, err
+	}
+	reply.Found = true
+	return reply, binary.BigEndian.Uint64(data), nil
+}
+
+// marshalLog stores the full contract ID along with the log since LogInfo
+// only holds the low 20 bytes that make up the address
+func marshalLog(index uint64, logIndex uint64, evmLog Log) ([]byte, error) {
+	addressBytes := evmLog.Log.ContractID.ToBytes()
+	data, err := proto.Marshal(newLogInfo(index, logIndex, evmLog))
+	if err != nil {
+		return nil, err
+	}
+	return append(addressBytes[:], data...), nil
+}
+
+func newLogInfo(index uint64, logIndex uint64, evmLog Log) *validatorserver.LogInfo {
+	addressBytes := evmLog.Log.ContractID.ToBytes()
+	topicStrings := make([]string, 0, len(evmLog.Log.Topics))
+	for _, topic := range evmLog.Log.Topics {
+		topicStrings = append(topicStrings, hexutil.Encode(topic[:]))
+	}
+	return &validatorserver.LogInfo{
+		Address:          hexutil.Encode(addressBytes[12:]),
+		BlockHash:        hexutil.Encode(evmLog.TxHash[:]),
+		BlockNumber:      "0x" + strconv.FormatUint(index, 16),
+		Data:             hexutil.Encode(evmLog.Log.Data[:]),
+		LogIndex:         "0x" + strconv.FormatUint(logIndex, 16),
+		Topics:           topicStrings,
+		TransactionIndex: "0x0",
+		TransactionHash:  hexutil.Encode(evmLog.TxHash[:]),
+	}
+}
+
+func unmarshalLog(data []byte) (value.IntValue, *validatorserver.LogInfo, error) {
+	if len(data) < 32 {
+		return value.IntValue{}, nil, errors.New("corrupt tx index log record")
+	}
+	contractID := value.NewIntValue(new(big.Int).SetBytes(data[:32]))
+	logInfo := &validatorserver.LogInfo{}
+	if err := proto.Unmarshal(data[32:], logInfo); err != nil {
+		return value.IntValue{}, nil, err
+	}
+	return contractID, logInfo, nil
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte{}, b...)
+}
+
+func encodeUint64(i uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, i)
+	return buf
+}
+
+func makeKey(parts ...[]byte) []byte {
+	var key []byte
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+func assertionKey(index uint64) []byte {
+	return makeKey(assertionPrefix, encodeUint64(index))
+}
+
+func nodeKey(nodeHash common.Hash) []byte {
+	return makeKey(nodePrefix, nodeHash[:])
+}
+
+func txKey(txHash common.Hash) []byte {
+	return makeKey(txPrefix, txHash[:])
+}
+
+func logKey(index uint64, logIndex uint64) []byte {
+	return makeKey(logPrefix, encodeUint64(index), encodeUint64(logIndex))
+}
+
+func addressKey(contractID value.IntValue, index uint64, logIndex uint64) []byte {
+	contractBytes := contractID.ToBytes()
+	return makeKey(addressPrefix, contractBytes[:], encodeUint64(index), encodeUint64(logIndex))
+}
+
+func topicKey(pos int, topic common.Hash, index uint64, logIndex uint64) []byte {
+	return makeKey(topicPrefix, []byte{byte(pos)}, topic[:], encodeUint64(index), encodeUint64(logIndex))
+}
+
+func confirmationKey(index uint64) []byte {
+	return makeKey(confirmationPrefix, encodeUint64(index))
+}
+
+func unmatchedConfirmationKey(nodeHash common.Hash) []byte {
+	return makeKey(unmatchedConfirmation, nodeHash[:])
+}
+
+func unanimousKey(txHash common.Hash) []byte {
+	return makeKey(unanimousPrefix, txHash[:])
+}
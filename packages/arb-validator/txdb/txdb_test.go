@@ -0,0 +1,421 @@
+/*
+ * Copyright 2020, Offchain Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txdb
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+
+	"github.com/offchainlabs/arbitrum/packages/arb-util/common"
+	"github.com/offchainlabs/arbitrum/packages/arb-util/value"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/evm"
+	"github.com/offchainlabs/arbitrum/packages/arb-validator-core/validatorserver"
+)
+
+var (
+	contractA = big.NewInt(0xa)
+	contractB = big.NewInt(0xb)
+	topicX    = common.Hash{1}
+	topicY    = common.Hash{2}
+)
+
+func makeAssertion(node byte, prev byte, contract *big.Int, topics ...common.Hash) Assertion {
+	txHash := common.Hash{0xff, node}
+	return Assertion{
+		NodeHash:     common.Hash{node},
+		PrevNodeHash: common.Hash{prev},
+		Txs: []Tx{{
+			TxHash:        txHash,
+			RawVal:        value.NewInt64Value(int64(node)),
+			OnChainTxHash: hexutil.Encode(txHash[:]),
+		}},
+		Logs: []Log{{
+			TxHash: txHash,
+			Log: evm.Log{
+				ContractID: value.NewIntValue(contract),
+				Data:       []byte{node},
+				Topics:     topics,
+			},
+		}},
+	}
+}
+
+func addAssertions(t *testing.T, db *TxDB, assertions ...Assertion) {
+	t.Helper()
+	for _, assertion := range assertions {
+		if err := db.AddAssertion(assertion); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func findLogs(t *testing.T, db *TxDB, from, to *int64, address *big.Int, topics ...common.Hash) []*validatorserver.LogInfo {
+	t.Helper()
+	logs, err := db.FindLogs(from, to, address, topics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logs
+}
+
+func txFound(t *testing.T, db *TxDB, node byte) *validatorserver.GetMessageResultReply {
+	t.Helper()
+	reply, err := db.TxInfo(common.Hash{0xff, node})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reply
+}
+
+func TestFindLogs(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA, topicX),
+		makeAssertion(2, 1, contractB, topicX, topicY),
+		makeAssertion(3, 2, contractA, topicY),
+	)
+
+	if logs := findLogs(t, db, nil, nil, nil); len(logs) != 3 {
+		t.Fatal("expected 3 logs but got", len(logs))
+	}
+	logs := findLogs(t, db, nil, nil, contractA)
+	if len(logs) != 2 || logs[0].BlockNumber != "0x0" || logs[1].BlockNumber != "0x2" {
+		t.Fatal("wrong logs for contract", logs)
+	}
+	if logs[0].Address != "0x000000000000000000000000000000000000000a" {
+		t.Error("wrong log address", logs[0].Address)
+	}
+	logs = findLogs(t, db, nil, nil, nil, topicX)
+	if len(logs) != 2 || logs[0].BlockNumber != "0x0" || logs[1].BlockNumber != "0x1" {
+		t.Fatal("wrong logs for topic", logs)
+	}
+	if logs := findLogs(t, db, nil, nil, contractB, topicX, topicY); len(logs) != 1 {
+		t.Fatal("expected 1 log matching both topics but got", len(logs))
+	}
+	if logs := findLogs(t, db, nil, nil, contractA, topicX, topicY); len(logs) != 0 {
+		t.Fatal("expected no logs with too many topics but got", len(logs))
+	}
+	from, to := int64(1), int64(1)
+	logs = findLogs(t, db, &from, &to, nil)
+	if len(logs) != 1 || logs[0].BlockNumber != "0x1" {
+		t.Fatal("wrong logs for height range", logs)
+	}
+	from = 5
+	if logs := findLogs(t, db, &from, nil, nil); len(logs) != 0 {
+		t.Fatal("expected no logs past the last assertion but got", len(logs))
+	}
+}
+
+func TestTxInfo(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	assertion := makeAssertion(1, 0, contractA)
+	assertion.Txs[0].LogsValHashes = []string{"0x01", "0x02"}
+	addAssertions(t, db, assertion)
+
+	reply := txFound(t, db, 1)
+	if !reply.Found || reply.Finality != validatorserver.Finality_ASSERTED {
+		t.Fatal("expected asserted tx but got", reply)
+	}
+	rawVal, err := hexutil.Decode(reply.RawVal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := value.UnmarshalValue(bytes.NewReader(rawVal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !value.NewInt64Value(1).Equal(val) {
+		t.Error("wrong raw value", reply.RawVal)
+	}
+	if len(reply.LogValHashes) != 2 || reply.LogValHashes[1] != "0x02" {
+		t.Error("wrong log value hashes", reply.LogValHashes)
+	}
+	if reply := txFound(t, db, 2); reply.Found {
+		t.Error("found tx which wasn't added")
+	}
+}
+
+func TestFinality(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	// A confirmation can arrive before the assertion it confirms
+	if err := db.ConfirmNode(common.Hash{2}, common.NewTimeBlocksInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA),
+		makeAssertion(2, 1, contractA),
+		makeAssertion(3, 2, contractA),
+	)
+	expected := []validatorserver.Finality{
+		validatorserver.Finality_CONFIRMED,
+		validatorserver.Finality_CONFIRMED,
+		validatorserver.Finality_ASSERTED,
+	}
+	for i, finality := range expected {
+		if reply := txFound(t, db, byte(i+1)); reply.Finality != finality {
+			t.Error("assertion", i, "had finality", reply.Finality, "instead of", finality)
+		}
+	}
+
+	if err := db.UpdateFinal(common.NewTimeBlocksInt(109)); err != nil {
+		t.Fatal(err)
+	}
+	if reply := txFound(t, db, 1); reply.Finality != validatorserver.Finality_CONFIRMED {
+		t.Error("assertion became final too early")
+	}
+	if err := db.UpdateFinal(common.NewTimeBlocksInt(110)); err != nil {
+		t.Fatal(err)
+	}
+	logs := findLogs(t, db, nil, nil, nil)
+	if logs[1].Finality != validatorserver.Finality_L1_FINAL || logs[2].Finality != validatorserver.Finality_ASSERTED {
+		t.Error("wrong log finality", logs)
+	}
+}
+
+func TestPruneUnmatchedConfirmations(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	// Node 9 is never added, so its confirmation can't be matched
+	if err := db.ConfirmNode(common.Hash{9}, common.NewTimeBlocksInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ConfirmNode(common.Hash{2}, common.NewTimeBlocksInt(105)); err != nil {
+		t.Fatal(err)
+	}
+	unmatched := func() int {
+		count := 0
+		it := db.db.NewIteratorWithPrefix(unmatchedConfirmation)
+		defer it.Release()
+		for it.Next() {
+			count++
+		}
+		return count
+	}
+
+	if err := db.UpdateFinal(common.NewTimeBlocksInt(109)); err != nil {
+		t.Fatal(err)
+	}
+	if count := unmatched(); count != 2 {
+		t.Fatal("expected 2 unmatched confirmations before finality but had", count)
+	}
+	if err := db.UpdateFinal(common.NewTimeBlocksInt(110)); err != nil {
+		t.Fatal(err)
+	}
+	if count := unmatched(); count != 1 {
+		t.Fatal("expected final unmatched confirmation to be pruned but had", count)
+	}
+
+	// The confirmation that isn't final yet still applies once its
+	// assertion arrives
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA),
+		makeAssertion(2, 1, contractA),
+	)
+	if reply := txFound(t, db, 2); reply.Finality != validatorserver.Finality_CONFIRMED {
+		t.Error("pending unmatched confirmation was lost")
+	}
+	if count := unmatched(); count != 0 {
+		t.Error("expected matched confirmation to be removed but had", count)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA, topicX),
+		makeAssertion(2, 1, contractA, topicX),
+		makeAssertion(3, 2, contractB, topicY),
+	)
+	if err := db.ConfirmNode(common.Hash{3}, common.NewTimeBlocksInt(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reorg replaces the assertions after node 1
+	addAssertions(t, db, makeAssertion(4, 1, contractB, topicX))
+	if db.AssertionCount() != 2 {
+		t.Fatal("expected 2 assertions after rollback but had", db.AssertionCount())
+	}
+	if txFound(t, db, 2).Found || txFound(t, db, 3).Found {
+		t.Error("rolled back txes are still indexed")
+	}
+	if reply := txFound(t, db, 4); !reply.Found || reply.Finality != validatorserver.Finality_ASSERTED {
+		t.Error("wrong result for new tx", reply)
+	}
+	if reply := txFound(t, db, 1); reply.Finality != validatorserver.Finality_CONFIRMED {
+		t.Error("confirmation of surviving assertion was lost")
+	}
+	if logs := findLogs(t, db, nil, nil, nil, topicY); len(logs) != 0 {
+		t.Error("rolled back logs are still indexed", logs)
+	}
+	logs := findLogs(t, db, nil, nil, contractB)
+	if len(logs) != 1 || logs[0].Data != "0x04" || logs[0].BlockNumber != "0x1" {
+		t.Error("wrong logs after rollback", logs)
+	}
+}
+
+func TestRestart(t *testing.T) {
+	store := memorydb.New()
+	db, err := New(store, common.NewTimeBlocksInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA),
+		makeAssertion(2, 1, contractA),
+		makeAssertion(3, 2, contractA),
+	)
+	if err := db.ConfirmNode(common.Hash{1}, common.NewTimeBlocksInt(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = New(store, common.NewTimeBlocksInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.AssertionCount() != 3 {
+		t.Fatal("expected 3 assertions after restart but had", db.AssertionCount())
+	}
+	if reply := txFound(t, db, 1); !reply.Found || reply.Finality != validatorserver.Finality_CONFIRMED {
+		t.Error("wrong result after restart", reply)
+	}
+
+	// After restoring from a checkpoint the validator replays assertions it
+	// had already reported, possibly without knowing their predecessor
+	addAssertions(t, db,
+		makeAssertion(2, 0xee, contractA),
+		makeAssertion(3, 2, contractA),
+	)
+	if db.AssertionCount() != 3 {
+		t.Fatal("replaying assertions changed the count to", db.AssertionCount())
+	}
+	if logs := findLogs(t, db, nil, nil, nil); len(logs) != 3 || logs[2].Data != "0x03" {
+		t.Error("wrong logs after replay", logs)
+	}
+}
+
+func TestPendingResults(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	addAssertions(t, db, makeAssertion(1, 0, contractA, topicX))
+	db.SetPending(makeAssertion(2, 1, contractB, topicY))
+
+	if reply := txFound(t, db, 2); !reply.Found || reply.Finality != validatorserver.Finality_PENDING {
+		t.Error("wrong result for pending tx", reply)
+	}
+	if reply := txFound(t, db, 1); reply.Finality != validatorserver.Finality_ASSERTED {
+		t.Error("wrong result for asserted tx", reply)
+	}
+	logs := findLogs(t, db, nil, nil, nil)
+	if len(logs) != 2 || logs[1].Finality != validatorserver.Finality_PENDING || logs[1].BlockNumber != "0x1" {
+		t.Fatal("wrong logs with a pending assertion", logs)
+	}
+	if logs := findLogs(t, db, nil, nil, contractB, topicY); len(logs) != 1 || logs[0].Data != "0x02" {
+		t.Error("pending logs weren't filtered", logs)
+	}
+	if logs := findLogs(t, db, nil, nil, contractA, topicY); len(logs) != 0 {
+		t.Error("pending logs weren't filtered", logs)
+	}
+	to := int64(0)
+	if logs := findLogs(t, db, nil, &to, nil); len(logs) != 1 {
+		t.Error("pending logs are past the end of the range", logs)
+	}
+
+	// Once the assertion is made, its results are no longer pending
+	addAssertions(t, db, makeAssertion(2, 1, contractB, topicY))
+	if reply := txFound(t, db, 2); !reply.Found || reply.Finality != validatorserver.Finality_ASSERTED {
+		t.Error("wrong result once the pending tx was asserted", reply)
+	}
+	if logs := findLogs(t, db, nil, nil, nil); len(logs) != 2 || logs[1].Finality != validatorserver.Finality_ASSERTED {
+		t.Error("asserted log is still pending", logs)
+	}
+
+	// Pending results which another assertion replaced are dropped
+	db.SetPending(makeAssertion(3, 2, contractA))
+	addAssertions(t, db, makeAssertion(4, 2, contractA))
+	if reply := txFound(t, db, 3); reply.Found {
+		t.Error("found tx of a replaced pending assertion", reply)
+	}
+}
+
+func TestNodeFinality(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	addAssertions(t, db,
+		makeAssertion(1, 0, contractA),
+		makeAssertion(2, 1, contractA),
+	)
+	if err := db.ConfirmNode(common.Hash{1}, common.NewTimeBlocksInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[byte]validatorserver.Finality{
+		1: validatorserver.Finality_CONFIRMED,
+		2: validatorserver.Finality_ASSERTED,
+	}
+	for node, finality := range expected {
+		actual, found, err := db.NodeFinality(common.Hash{node})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual != finality {
+			t.Error("node", node, "had finality", actual, "instead of", finality)
+		}
+	}
+	if _, found, err := db.NodeFinality(common.Hash{3}); err != nil || found {
+		t.Error("found finality of a node which wasn't added", err)
+	}
+}
+
+func TestUnanimousResults(t *testing.T) {
+	db := NewMemory(common.NewTimeBlocksInt(10))
+	addAssertions(t, db, makeAssertion(1, 0, contractA))
+	unanimous := makeAssertion(2, 1, contractB, topicY)
+	if err := db.AddUnanimous(1, unanimous.Txs); err != nil {
+		t.Fatal(err)
+	}
+	if reply := txFound(t, db, 2); !reply.Found || reply.Finality != validatorserver.Finality_UNANIMOUS || reply.RawVal != hexutil.Encode(value.MarshalValueToBytes(value.NewInt64Value(2))) {
+		t.Error("wrong result for unanimous tx", reply)
+	}
+
+	// Our pending assertion reads the same message
+	db.SetPending(unanimous)
+	if reply := txFound(t, db, 2); reply.Finality != validatorserver.Finality_UNANIMOUS {
+		t.Error("pending result hid the unanimous one", reply)
+	}
+	if logs := findLogs(t, db, nil, nil, contractB); len(logs) != 1 || logs[0].Finality != validatorserver.Finality_UNANIMOUS {
+		t.Error("wrong finality for log of unanimous tx", logs)
+	}
+
+	// Once it's on chain the assertion's finality is reported, and the
+	// unanimous result is dropped after that's final
+	addAssertions(t, db, unanimous)
+	if reply := txFound(t, db, 2); reply.Finality != validatorserver.Finality_ASSERTED {
+		t.Error("wrong result once the unanimous tx was asserted", reply)
+	}
+	if err := db.ConfirmNode(common.Hash{2}, common.NewTimeBlocksInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.UpdateFinal(common.NewTimeBlocksInt(110)); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.db.Has(unanimousKey(common.Hash{0xff, 2})); err != nil || has {
+		t.Error("kept the unanimous result of a final transaction", err)
+	}
+	if reply := txFound(t, db, 2); reply.Finality != validatorserver.Finality_L1_FINAL {
+		t.Error("wrong result once the unanimous tx was final", reply)
+	}
+}